@@ -2,48 +2,356 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	"workflow-code-test/api/internal/api/middleware"
 	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/internal/handler"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/internal/seed"
 	"workflow-code-test/api/internal/service"
+	"workflow-code-test/api/internal/workflow"
 	"workflow-code-test/api/pkg/db"
 	"workflow-code-test/api/pkg/log"
+	"workflow-code-test/api/pkg/mailer"
 	"workflow-code-test/api/pkg/models"
 	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/checkpoint"
 	"workflow-code-test/api/pkg/node/condition"
 	"workflow-code-test/api/pkg/node/email"
 	"workflow-code-test/api/pkg/node/end"
 	"workflow-code-test/api/pkg/node/form"
 	"workflow-code-test/api/pkg/node/integration"
+	"workflow-code-test/api/pkg/node/integration/weather"
 	"workflow-code-test/api/pkg/node/start"
+	"workflow-code-test/api/pkg/node/state"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/jackc/pgx/v5/pgxpool"
+	mail "gopkg.in/gomail.v2"
 )
 
-// Register all node types
-func registerNodeTypes(registry *node.Registry) {
+// Register all node types; repo backs email alert deduplication and state nodes, and
+// sender, when set, delivers emails, with retries re-sending transient failures
+func registerNodeTypes(registry *node.Registry, repo repository.WorkflowRepository, sender mailer.Sender, retries *mailer.RetryQueue) {
     registry.Register(models.NodeTypeStart, start.NewNode)
     registry.Register(models.NodeTypeForm, form.NewNode)
     registry.Register(models.NodeTypeIntegration, integration.NewNode)
     registry.Register(models.NodeTypeCondition, condition.NewNode)
-    registry.Register(models.NodeTypeEmail, email.NewNode)
+    registry.Register(models.NodeTypeEmail, email.NewNodeFactory(email.FactoryConfig{
+        DefaultTemplate: defaultEmailTemplateFromEnv(),
+        History:         repo,
+        DedupWindow:     durationFromEnv("ALERT_DEDUP_WINDOW", 0),
+        Disabled:        emailDisabledFromEnv(),
+        Sender:          sender,
+        Retries:         retries,
+    }))
     registry.Register(models.NodeTypeEnd, end.NewNode)
+    registry.Register(models.NodeTypeState, state.NewNodeFactory(repo))
+    registry.Register(models.NodeTypeCheckpoint, checkpoint.NewNode)
     // New node types can be easily added here
 }
 
-func setupAPI(apiRouter *mux.Router, dbPool *pgxpool.Pool, engine *execution.Engine) {
+func setupAPI(mainRouter, apiRouter *mux.Router, dbPool *pgxpool.Pool, engine *execution.Engine, reloadNodeTypes func() []models.NodeType) {
 	svc, err := service.NewService(dbPool, engine)
 	if err != nil {
 		slog.Error("Failed to create service", "error", err)
 		return
 	}
-	svc.LoadRoutes(apiRouter, false) // isProduction=false
+	isProduction := os.Getenv("APP_ENV") == "production"
+	svc.ExecuteLimiter = executeLimiterFromEnv()
+	svc.Handler.Service.SetMaxConcurrentExecutions(maxConcurrentExecutionsFromEnv())
+	svc.Handler.Service.SetAllowedNodeTypes(allowedNodeTypesFromEnv())
+	svc.Handler.Service.SetCallbackConfig(callbackConfigFromEnv(isProduction))
+	if limit, ok := maxStepOutputBytesFromEnv(); ok {
+		svc.Handler.Service.SetMaxStepOutputBytes(limit)
+	}
+	models.InputValidation = inputValidationProfileFromEnv()
+	mailer.MaxRenderedSize = positiveIntFromEnv("EMAIL_MAX_RENDERED_BYTES", mailer.DefaultMaxRenderedSize)
+	integration.SetMaxConcurrentRequests(maxConcurrentWeatherRequestsFromEnv())
+	weather.SetSecretQueryParams(secretQueryParamsFromEnv())
+	if err := mailer.SetFromName(os.Getenv("MAILER_FROM_NAME")); err != nil {
+		slog.Warn("Ignoring invalid MAILER_FROM_NAME", "error", err)
+	}
+	svc.Handler.Weather = defaultWeatherLookup()
+	svc.Handler.ReloadNodeTypes = reloadNodeTypes
+	svc.Handler.Metrics = engine.Metrics()
+	svc.AdminToken = os.Getenv("ADMIN_TOKEN")
+	svc.EnableDebugExecution = os.Getenv("ENABLE_DEBUG_EXECUTION") == "true"
+	svc.RequestTimeout = durationFromEnv("REQUEST_TIMEOUT", svc.RequestTimeout)
+	svc.ExecuteTimeout = durationFromEnv("EXECUTE_TIMEOUT", svc.ExecuteTimeout)
+	svc.LoadRoutes(apiRouter, isProduction)
+	svc.LoadMetricsRoutes(mainRouter)
+}
+
+// defaultWeatherLookup builds the GET /weather lookup from the default workflow's
+// weather node, so it uses the same endpoint and cities
+func defaultWeatherLookup() handler.WeatherLookup {
+	for _, model := range seed.DefaultWorkflow().Nodes {
+		if model.ID != string(models.NodeIDWeatherAPI) {
+			continue
+		}
+		n, err := integration.NewNode(model)
+		if err != nil {
+			slog.Error("Failed to configure weather lookup", "error", err)
+			return nil
+		}
+		return n.(*integration.Node)
+	}
+	return nil
+}
+
+// seedDefaultWorkflow stores the demo weather alert workflow if it isn't already present
+func seedDefaultWorkflow(dbPool *pgxpool.Pool) {
+	repo := repository.NewWorkflowRepository(dbPool)
+	id, created, err := seed.DefaultWorkflowIfMissing(context.Background(), repo)
+	if err != nil {
+		slog.Error("Failed to seed default workflow", "error", err)
+		return
+	}
+	if created {
+		slog.Info("Seeded default workflow", "id", id, "execute", "POST /api/v1/workflows/"+id+"/execute")
+		return
+	}
+	slog.Info("Default workflow already exists", "id", id)
+}
+
+// executeLimiterFromEnv builds the per-workflow execute rate limiter from
+// EXECUTE_RATE_LIMIT (executions per minute) and EXECUTE_RATE_BURST.
+// Rate limiting is disabled when EXECUTE_RATE_LIMIT is unset or not positive.
+func executeLimiterFromEnv() *middleware.RateLimiter {
+	perMinute, err := strconv.Atoi(os.Getenv("EXECUTE_RATE_LIMIT"))
+	if err != nil || perMinute <= 0 {
+		return nil
+	}
+	burst, err := strconv.Atoi(os.Getenv("EXECUTE_RATE_BURST"))
+	if err != nil || burst <= 0 {
+		burst = perMinute
+	}
+	slog.Info("Execute rate limiting enabled", "perMinute", perMinute, "burst", burst)
+	return middleware.NewRateLimiter(perMinute, burst)
+}
+
+// maxConcurrentExecutionsFromEnv reads MAX_CONCURRENT_EXECUTIONS; executions are
+// unlimited when it is unset or not positive.
+func maxConcurrentExecutionsFromEnv() int {
+	limit, err := strconv.Atoi(os.Getenv("MAX_CONCURRENT_EXECUTIONS"))
+	if err != nil || limit <= 0 {
+		return 0
+	}
+	slog.Info("Concurrent execution limit enabled", "max", limit)
+	return limit
+}
+
+// allowedNodeTypesFromEnv reads ALLOWED_NODE_TYPES, a comma-separated list such as
+// "start,form,condition,email,end". Every registered type is allowed when it is unset.
+func allowedNodeTypesFromEnv() []models.NodeType {
+	var types []models.NodeType
+	for _, name := range strings.Split(os.Getenv("ALLOWED_NODE_TYPES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			types = append(types, models.NodeType(name))
+		}
+	}
+	if len(types) > 0 {
+		slog.Info("Node type allowlist enabled", "types", types)
+	}
+	return types
+}
+
+// maxConcurrentWeatherRequestsFromEnv reads WEATHER_MAX_CONCURRENT_REQUESTS; weather
+// API requests are unlimited when it is unset or not positive.
+func maxConcurrentWeatherRequestsFromEnv() int {
+	limit, err := strconv.Atoi(os.Getenv("WEATHER_MAX_CONCURRENT_REQUESTS"))
+	if err != nil || limit <= 0 {
+		return 0
+	}
+	slog.Info("Weather API concurrency limit enabled", "max", limit)
+	return limit
+}
+
+// secretQueryParamsFromEnv reads WEATHER_SECRET_QUERY_PARAMS, a comma-separated list
+// of query parameters to redact from weather URLs on top of the defaults
+func secretQueryParamsFromEnv() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv("WEATHER_SECRET_QUERY_PARAMS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// inputValidationProfileFromEnv reads INPUT_VALIDATION_PROFILE, a JSON object whose
+// fields override the default profile, e.g. {"minThreshold": -50} or
+// {"maxNameLength": 200}. The default is
+// used when it is unset or invalid.
+func inputValidationProfileFromEnv() models.InputValidationProfile {
+	profile := models.DefaultInputValidationProfile()
+	raw := os.Getenv("INPUT_VALIDATION_PROFILE")
+	if raw == "" {
+		return profile
+	}
+	if err := json.Unmarshal([]byte(raw), &profile); err != nil {
+		slog.Warn("Ignoring invalid INPUT_VALIDATION_PROFILE", "error", err)
+		return models.DefaultInputValidationProfile()
+	}
+	if err := profile.Validate(); err != nil {
+		slog.Warn("Ignoring invalid INPUT_VALIDATION_PROFILE", "error", err)
+		return models.DefaultInputValidationProfile()
+	}
+	slog.Info("Input validation profile configured", "profile", profile)
+	return profile
+}
+
+// defaultEmailTemplateFromEnv returns the template used by email nodes created without
+// one. EMAIL_DEFAULT_SUBJECT and EMAIL_DEFAULT_BODY override the built-in text, and
+// EMAIL_REQUIRE_TEMPLATE=true disables the fallback.
+func defaultEmailTemplateFromEnv() mailer.EmailTemplate {
+	if os.Getenv("EMAIL_REQUIRE_TEMPLATE") == "true" {
+		return mailer.EmailTemplate{}
+	}
+	template := email.DefaultTemplate()
+	if subject := os.Getenv("EMAIL_DEFAULT_SUBJECT"); subject != "" {
+		template.Subject = subject
+	}
+	if body := os.Getenv("EMAIL_DEFAULT_BODY"); body != "" {
+		template.Body = body
+	}
+	return template
+}
+
+// emailDisabledFromEnv reports whether EMAIL_ENABLED=false turns email sending off
+func emailDisabledFromEnv() bool {
+	if os.Getenv("EMAIL_ENABLED") != "false" {
+		return false
+	}
+	slog.Warn("Email sending disabled; email nodes will record suppressed emails")
+	return true
+}
+
+// smtpSenderFromEnv builds the SMTP sender from SMTP_HOST, SMTP_PORT (default 587),
+// SMTP_USERNAME and SMTP_PASSWORD, with the dial retried per SMTP_DIAL_ATTEMPTS and
+// SMTP_DIAL_BASE_DELAY. Emails are stub-sent when SMTP_HOST is unset.
+func smtpSenderFromEnv() *mailer.SMTPSender {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	port := 587
+	if raw := os.Getenv("SMTP_PORT"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			slog.Warn("Ignoring invalid SMTP_PORT", "value", raw)
+		} else {
+			port = value
+		}
+	}
+	attempts := mailer.DefaultDialAttempts
+	if raw := os.Getenv("SMTP_DIAL_ATTEMPTS"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			slog.Warn("Ignoring invalid SMTP_DIAL_ATTEMPTS", "value", raw)
+		} else {
+			attempts = value
+		}
+	}
+	baseDelay := durationFromEnv("SMTP_DIAL_BASE_DELAY", mailer.DefaultDialBaseDelay)
+	dialer := mail.NewDialer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+	slog.Info("SMTP email delivery enabled", "host", host, "port", port, "dialAttempts", attempts)
+	return mailer.NewSMTPSender(dialer, attempts, baseDelay)
+}
+
+// emailRetryQueueFromEnv builds the queue that re-sends emails through sender after
+// transient delivery failures, sized by EMAIL_RETRY_QUEUE_CAPACITY and retried per
+// EMAIL_RETRY_MAX_ATTEMPTS and EMAIL_RETRY_BASE_DELAY
+func emailRetryQueueFromEnv(sender mailer.Sender) *mailer.RetryQueue {
+	capacity := positiveIntFromEnv("EMAIL_RETRY_QUEUE_CAPACITY", mailer.DefaultRetryQueueCapacity)
+	maxAttempts := positiveIntFromEnv("EMAIL_RETRY_MAX_ATTEMPTS", mailer.DefaultRetryMaxAttempts)
+	baseDelay := durationFromEnv("EMAIL_RETRY_BASE_DELAY", mailer.DefaultRetryBaseDelay)
+	slog.Info("Email retry queue enabled", "capacity", capacity, "maxAttempts", maxAttempts, "baseDelay", baseDelay)
+	return mailer.NewRetryQueue(mailer.SendVia(sender), capacity, maxAttempts, baseDelay)
+}
+
+// positiveIntFromEnv reads a positive integer from the named variable, returning
+// fallback when it is unset or invalid
+func positiveIntFromEnv(name string, fallback int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		slog.Warn("Ignoring invalid "+name, "value", raw)
+		return fallback
+	}
+	return value
+}
+
+// accessLogEnabledFromEnv reports whether requests are logged; ACCESS_LOG_ENABLED=false turns it off
+func accessLogEnabledFromEnv() bool {
+	return os.Getenv("ACCESS_LOG_ENABLED") != "false"
+}
+
+// durationFromEnv reads a duration such as "45s" from the named variable, returning
+// fallback when it is unset or invalid. "0" disables the corresponding timeout.
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		slog.Warn("Ignoring invalid duration", "variable", name, "value", raw)
+		return fallback
+	}
+	return value
+}
+
+// maxStepOutputBytesFromEnv reads MAX_STEP_OUTPUT_BYTES. It reports false when the
+// variable is unset or invalid so the service default applies; 0 stores outputs in full.
+func maxStepOutputBytesFromEnv() (int, bool) {
+	raw := os.Getenv("MAX_STEP_OUTPUT_BYTES")
+	if raw == "" {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 {
+		slog.Warn("Ignoring invalid MAX_STEP_OUTPUT_BYTES", "value", raw)
+		return 0, false
+	}
+	slog.Info("Step output cap configured", "maxBytes", limit)
+	return limit, true
+}
+
+// callbackConfigFromEnv reads CALLBACK_SECRET, which signs execution callbacks and
+// enables callbackUrl, and the CALLBACK_ATTEMPTS and CALLBACK_BASE_DELAY retry
+// settings. Production deployments only call back over https.
+func callbackConfigFromEnv(isProduction bool) workflow.CallbackConfig {
+	config := workflow.CallbackConfig{
+		Secret:       os.Getenv("CALLBACK_SECRET"),
+		RequireHTTPS: isProduction,
+		BaseDelay:    durationFromEnv("CALLBACK_BASE_DELAY", workflow.DefaultCallbackBaseDelay),
+	}
+	if raw := os.Getenv("CALLBACK_ATTEMPTS"); raw != "" {
+		attempts, err := strconv.Atoi(raw)
+		if err != nil || attempts < 1 {
+			slog.Warn("Ignoring invalid CALLBACK_ATTEMPTS", "value", raw)
+		} else {
+			config.Attempts = attempts
+		}
+	}
+	if config.Secret != "" {
+		slog.Info("Execution callbacks enabled", "httpsOnly", isProduction)
+	}
+	return config
 }
 
 func main() {
@@ -60,20 +368,54 @@ func main() {
 	}
 	defer db.Disconnect()
 	dbPool := db.GetPool()
+	if os.Getenv("SEED_DEFAULT_WORKFLOW") == "true" {
+		seedDefaultWorkflow(dbPool)
+	}
 	nodeRegistry := node.NewRegistry()
-	registerNodeTypes(nodeRegistry)
+	repo := repository.NewWorkflowRepository(dbPool)
+	// The sender, with its pooled connection, and the retry queue outlive node type
+	// reloads. Stub-sent emails never fail, so there is only a queue with a sender.
+	var sender mailer.Sender
+	var retries *mailer.RetryQueue
+	retryCtx, stopRetries := context.WithCancel(context.Background())
+	if smtpSender := smtpSenderFromEnv(); smtpSender != nil {
+		sender = smtpSender
+		defer smtpSender.Close()
+		retries = emailRetryQueueFromEnv(sender)
+		if restored, err := retries.Restore(retryCtx, repo); err != nil {
+			slog.Error("Failed to restore queued emails", "error", err)
+		} else if restored > 0 {
+			slog.Info("Restored queued emails", "count", restored)
+		}
+		go retries.Run(retryCtx)
+	}
+	// Deferred after Close, so the worker stops before the connection closes
+	defer stopRetries()
+	registerNodeTypes(nodeRegistry, repo, sender, retries)
 	engine := execution.NewEngine(nodeRegistry)
+	// Re-running registration re-reads its settings and swaps in the new factories
+	reloadNodeTypes := func() []models.NodeType {
+		return nodeRegistry.Reload(func(registry *node.Registry) { registerNodeTypes(registry, repo, sender, retries) })
+	}
 	// Setup router
 	mainRouter := mux.NewRouter()
+	mainRouter.NotFoundHandler = middleware.NotFound()
+	mainRouter.MethodNotAllowedHandler = middleware.MethodNotAllowed()
 	apiRouter := mainRouter.PathPrefix("/api/v1").Subrouter()
-	setupAPI(apiRouter, dbPool, engine)
+	setupAPI(mainRouter, apiRouter, dbPool, engine, reloadNodeTypes)
+	var handler http.Handler = mainRouter
+	if accessLogEnabledFromEnv() {
+		handler = middleware.AccessLog(slog.Default())(handler)
+	}
+	handler = middleware.RequestID(handler)
 	// Configure CORS
 	corsHandler := handlers.CORS(
 		handlers.AllowedOrigins([]string{"http://localhost:3003"}), // Frontend URL
 		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization", middleware.RequestIDHeader}),
+		handlers.ExposedHeaders([]string{middleware.RequestIDHeader}),
 		handlers.AllowCredentials(),
-	)(mainRouter)
+	)(handler)
 
 	srv := &http.Server{
 		Addr:    ":8080",
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		requestID      string
+		expectedStatus int
+	}{
+		{
+			name:           "explicit status",
+			handler:        func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusCreated) },
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "implicit ok from write",
+			handler:        func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "nothing written",
+			handler:        func(w http.ResponseWriter, r *http.Request) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "first status wins",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "not found", http.StatusNotFound)
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "caller request id",
+			handler:        func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			requestID:      "req-123",
+			expectedStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+			handler := RequestID(AccessLog(logger)(tt.handler))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/workflows/wf-1/execute?debug=true", nil)
+			if tt.requestID != "" {
+				r.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var entry map[string]any
+			if !assert.NoError(t, json.Unmarshal(logs.Bytes(), &entry), logs.String()) {
+				return
+			}
+			assert.Equal(t, "HTTP request", entry["msg"])
+			assert.Equal(t, "POST", entry["method"])
+			assert.Equal(t, "/api/v1/workflows/wf-1/execute", entry["path"])
+			assert.Equal(t, float64(tt.expectedStatus), entry["status"])
+			assert.Contains(t, entry, "latencyMs")
+			assert.NotEmpty(t, entry["requestId"])
+			assert.Equal(t, w.Header().Get(RequestIDHeader), entry["requestId"])
+			if tt.requestID != "" {
+				assert.Equal(t, tt.requestID, entry["requestId"])
+			}
+		})
+	}
+}
+
+func TestRequestIDReplacesInvalidIDs(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	for _, id := range []string{"", "has spaces", string(bytes.Repeat([]byte("a"), maxRequestIDLength+1))} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(RequestIDHeader, id)
+		handler.ServeHTTP(w, r)
+
+		assert.NotEqual(t, id, seen)
+		assert.Len(t, seen, 36, "a generated uuid")
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterErrorsAreJSON(t *testing.T) {
+	router := mux.NewRouter()
+	router.NotFoundHandler = NotFound()
+	router.MethodNotAllowedHandler = MethodNotAllowed()
+	api := router.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/operators", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"unknown path", "GET", "/api/v1/unknown", http.StatusNotFound, "not found"},
+		{"outside api prefix", "GET", "/nowhere", http.StatusNotFound, "not found"},
+		{"wrong method", "DELETE", "/api/v1/operators", http.StatusMethodNotAllowed, "method not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, map[string]string{"error": tt.expectedError}, body)
+		})
+	}
+}
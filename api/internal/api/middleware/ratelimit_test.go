@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(60, 2) // one token per second
+	limiter.now = func() time.Time { return now }
+
+	allowed, _ := limiter.Allow("workflow-1")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("workflow-1")
+	assert.True(t, allowed)
+
+	allowed, retryAfter := limiter.Allow("workflow-1")
+	assert.False(t, allowed, "Burst should be exhausted")
+	assert.Equal(t, time.Second, retryAfter)
+
+	// Other workflows have their own bucket
+	allowed, _ = limiter.Allow("workflow-2")
+	assert.True(t, allowed)
+
+	// Tokens refill over time
+	now = now.Add(time.Second)
+	allowed, _ = limiter.Allow("workflow-1")
+	assert.True(t, allowed)
+}
+
+func TestRateLimiterDropsIdleBuckets(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(60, 2) // refills in two seconds
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		limiter.Allow(fmt.Sprintf("workflow-%d", i))
+	}
+	allowed, _ := limiter.Allow("active")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("active")
+	assert.True(t, allowed)
+	assert.Len(t, limiter.buckets, 101)
+
+	// Only the bucket still refilling survives the next sweep
+	now = now.Add(1500 * time.Millisecond)
+	allowed, _ = limiter.Allow("active")
+	assert.True(t, allowed)
+	now = now.Add(time.Second)
+	allowed, _ = limiter.Allow("active")
+	assert.True(t, allowed)
+	assert.Len(t, limiter.buckets, 1)
+	allowed, _ = limiter.Allow("active")
+	assert.False(t, allowed, "Bucket in use keeps its state")
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := NewRateLimiter(1, 3)
+	router := mux.NewRouter()
+	router.Handle("/workflows/{id}/execute", RateLimit(limiter, "id")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))).Methods("POST")
+
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 10; i++ {
+		last = httptest.NewRecorder()
+		router.ServeHTTP(last, httptest.NewRequest("POST", "/workflows/abc/execute", nil))
+		if last.Code == http.StatusTooManyRequests {
+			break
+		}
+		assert.Equal(t, http.StatusOK, last.Code)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, last.Code, "Rapid repeated executes should be rate limited")
+	assert.NotEmpty(t, last.Header().Get("Retry-After"))
+}
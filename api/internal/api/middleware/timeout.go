@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// DefaultRequestTimeout bounds how long an API request may run unless its route overrides it
+const DefaultRequestTimeout = 30 * time.Second
+
+// Timeout cancels each request's context after timeout and responds with 504 Gateway
+// Timeout if the handler hasn't finished by then. Routes whose mux name is in
+// routeTimeouts use that duration instead; a duration of zero or less exempts the
+// route entirely. Handler output is buffered so a late write can't follow the 504,
+// until the handler flushes: streamed responses such as NDJSON then go straight to
+// the client, and a timeout after that point cuts the stream short instead.
+func Timeout(timeout time.Duration, routeTimeouts map[string]time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := timeout
+			if route := mux.CurrentRoute(r); route != nil {
+				if override, ok := routeTimeouts[route.GetName()]; ok {
+					limit = override
+				}
+			}
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), limit)
+			defer cancel()
+
+			tw := &timeoutWriter{w: w, header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				tw.finish()
+			case <-ctx.Done():
+				if tw.expire() {
+					writeJSONError(w, http.StatusGatewayTimeout, "request timed out")
+				}
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers a handler's response until it is known to have finished in
+// time, or until the handler flushes it
+type timeoutWriter struct {
+	w       http.ResponseWriter
+	mu      sync.Mutex
+	header  http.Header
+	body    bytes.Buffer
+	status  int
+	expired bool
+	// committed is set once the response head has been sent to w; later writes
+	// pass straight through
+	committed bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	if tw.committed {
+		return tw.w.Write(p)
+	}
+	return tw.body.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired || tw.status != 0 {
+		return
+	}
+	tw.status = status
+}
+
+// Flush implements http.Flusher
+func (tw *timeoutWriter) Flush() {
+	_ = tw.FlushError()
+}
+
+// FlushError sends the response so far to the client and flushes the underlying
+// writer. http.ResponseController prefers it to Flush so errors reach the handler.
+func (tw *timeoutWriter) FlushError() error {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired {
+		return http.ErrHandlerTimeout
+	}
+	tw.commit()
+	return http.NewResponseController(tw.w).Flush()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.w
+}
+
+// expire discards any further writes from the handler. It reports whether nothing
+// has been sent yet, so the response can still be replaced with a 504.
+func (tw *timeoutWriter) expire() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.expired = true
+	return !tw.committed
+}
+
+// finish sends whatever the handler left buffered once it has returned
+func (tw *timeoutWriter) finish() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.commit()
+}
+
+// commit copies the buffered head and body to the underlying writer; tw.mu must be held
+func (tw *timeoutWriter) commit() {
+	if tw.committed {
+		return
+	}
+	tw.committed = true
+	for key, values := range tw.header {
+		tw.w.Header()[key] = values
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	tw.w.WriteHeader(tw.status)
+	tw.w.Write(tw.body.Bytes())
+	tw.body.Reset()
+}
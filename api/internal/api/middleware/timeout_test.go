@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	slow := func(delay time.Duration) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+				w.Header().Set("X-Finished", "true")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"ok":true}`))
+			case <-r.Context().Done():
+			}
+		}
+	}
+
+	router := mux.NewRouter()
+	router.Use(Timeout(20*time.Millisecond, map[string]time.Duration{
+		"execute":   200 * time.Millisecond,
+		"unbounded": 0,
+	}))
+	router.Handle("/slow", slow(time.Second))
+	router.Handle("/fast", slow(0))
+	router.Handle("/execute", slow(50*time.Millisecond)).Name("execute")
+	router.Handle("/execute-hung", slow(time.Second)).Name("execute")
+	router.Handle("/unbounded", slow(50*time.Millisecond)).Name("unbounded")
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"slow handler times out", "/slow", http.StatusGatewayTimeout},
+		{"fast handler response is passed through", "/fast", http.StatusCreated},
+		{"route with a longer timeout completes", "/execute", http.StatusCreated},
+		{"route with a longer timeout still times out", "/execute-hung", http.StatusGatewayTimeout},
+		{"exempt route completes", "/unbounded", http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusGatewayTimeout {
+				assert.JSONEq(t, `{"error":"request timed out"}`, rec.Body.String())
+				assert.Empty(t, rec.Header().Get("X-Finished"))
+			} else {
+				assert.JSONEq(t, `{"ok":true}`, rec.Body.String())
+				assert.Equal(t, "true", rec.Header().Get("X-Finished"))
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareStreamsFlushedOutput(t *testing.T) {
+	stream := func(release <-chan struct{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Write([]byte("{\"line\":1}\n"))
+			http.NewResponseController(w).Flush()
+			select {
+			case <-release:
+				w.Write([]byte("{\"line\":2}\n"))
+			case <-r.Context().Done():
+			}
+		}
+	}
+	release := make(chan struct{})
+
+	router := mux.NewRouter()
+	router.Use(Timeout(100*time.Millisecond, map[string]time.Duration{"hung": 20 * time.Millisecond}))
+	router.Handle("/stream", stream(release))
+	router.Handle("/stream-hung", stream(nil)).Name("hung")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	t.Run("flushed lines reach the client while the handler runs", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+		reader := bufio.NewReader(resp.Body)
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "{\"line\":1}\n", line)
+
+		close(release)
+		line, err = reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "{\"line\":2}\n", line)
+	})
+
+	t.Run("timeout after a flush ends the stream without a 504", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/stream-hung")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var lines []string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		assert.Equal(t, []string{`{"line":1}`}, lines)
+	})
+}
@@ -0,0 +1,54 @@
+package execution
+
+import (
+	"context"
+	"maps"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// reusedOutputKey marks a step whose output was reused from an earlier run of the
+// same node in the execution
+const reusedOutputKey = "reusedOutput"
+
+// execute runs a node with its inputs for this run. Node types implementing
+// node.CacheableNode get the completed output of an earlier run of the same node
+// with the same cache key back instead of executing again.
+func (s *runState) execute(ctx context.Context, nodeID string, current node.Node) (node.NodeOutputs, error) {
+	inputs := s.inputsFor(nodeID, current)
+	cacheable, ok := current.(node.CacheableNode)
+	if !ok {
+		return current.Execute(ctx, inputs)
+	}
+	key, ok := cacheable.CacheKey(inputs)
+	if !ok {
+		return current.Execute(ctx, inputs)
+	}
+
+	cacheKey := nodeID + "\x00" + key
+	if outputs, found := s.outputCache[cacheKey]; found {
+		inputs.Log().Debug("Reusing node output from earlier in the execution")
+		return reusedOutputs(outputs), nil
+	}
+	outputs, err := current.Execute(ctx, inputs)
+	if err == nil && outputs.Status == models.StatusCompleted {
+		s.outputCache[cacheKey] = outputs
+	}
+	return outputs, err
+}
+
+// reusedOutputs copies cached outputs for a new step, marking them as reused and
+// timing the step from now so it doesn't count the original run's duration
+func reusedOutputs(cached node.NodeOutputs) node.NodeOutputs {
+	outputs := cached
+	outputs.Data = maps.Clone(cached.Data)
+	if outputs.Data == nil {
+		outputs.Data = make(map[string]any)
+	}
+	outputs.Data[reusedOutputKey] = true
+	now := time.Now().Format(time.RFC3339)
+	outputs.StartedAt = now
+	outputs.EndedAt = now
+	return outputs
+}
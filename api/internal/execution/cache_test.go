@@ -0,0 +1,107 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/form"
+	"workflow-code-test/api/pkg/node/integration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingNode counts its executions
+type countingNode struct {
+	stubNode
+	runs *int
+}
+
+func (n *countingNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	*n.runs++
+	return n.stubNode.Execute(ctx, inputs)
+}
+
+// cacheableCountingNode opts in to output caching, keyed on a shared value so
+// tests can change its inputs mid-run
+type cacheableCountingNode struct {
+	countingNode
+}
+
+func (n *cacheableCountingNode) CacheKey(inputs node.NodeInputs) (string, bool) {
+	return fmt.Sprint(inputs.NodeData["unit"]), true
+}
+
+func TestExecuteReusesCacheableNodeOutput(t *testing.T) {
+	var calls atomic.Int32
+	weatherAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 25.0}}`)
+	}))
+	defer weatherAPI.Close()
+
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeForm, form.NewNode)
+	registry.Register(models.NodeTypeIntegration, integration.NewNode)
+	workflow := &models.Workflow{
+		ID: "cache-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "weather-api", Type: models.NodeTypeIntegration, Data: models.NodeData{Metadata: map[string]any{
+				"apiEndpoint": weatherAPI.URL,
+				"options":     []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+				"cacheTtlMs":  0, // skip the weather response cache so every run would call the API
+			}}},
+		},
+	}
+	input := models.WorkflowInput{Name: "Test User", Email: "test@example.com", City: "Sydney",
+		Operator: models.OperatorGreaterThan, Threshold: 20}
+
+	execution, err := NewEngine(registry).ExecuteSequence(context.Background(), workflow, input,
+		[]string{"start", "form", "weather-api", "weather-api"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+	assert.Equal(t, int32(1), calls.Load(), "re-entered weather node should not call the API again")
+	assert.Len(t, execution.Steps, 4)
+	first, second := execution.Steps[2], execution.Steps[3]
+	assert.NotContains(t, first.Output, reusedOutputKey)
+	assert.Equal(t, true, second.Output[reusedOutputKey])
+	assert.Equal(t, first.Output["temperature"], second.Output["temperature"])
+}
+
+func TestExecuteCacheKeyAndOptIn(t *testing.T) {
+	var cachedRuns, plainRuns int
+	registry := newTestRegistry()
+	registry.Register(models.NodeType("cached"), func(model models.Node) (node.Node, error) {
+		return &cacheableCountingNode{countingNode{stubNode: stubNode{nodeType: "cached"}, runs: &cachedRuns}}, nil
+	})
+	registry.Register(models.NodeType("plain"), func(model models.Node) (node.Node, error) {
+		return &countingNode{stubNode: stubNode{nodeType: "plain"}, runs: &plainRuns}, nil
+	})
+	registry.Register(models.NodeType("sharer"), newStubFactory("sharer", map[string]node.NodeOutputs{
+		"sharer": {Shared: map[string]any{"unit": "fahrenheit"}},
+	}))
+	workflow := &models.Workflow{
+		ID: "cache-key-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "cached", Type: "cached"},
+			{ID: "plain", Type: "plain"},
+			{ID: "sharer", Type: "sharer"},
+		},
+	}
+
+	execution, err := NewEngine(registry).ExecuteSequence(context.Background(), workflow, models.WorkflowInput{},
+		[]string{"start", "cached", "cached", "plain", "plain", "sharer", "cached"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+	assert.Equal(t, 2, cachedRuns, "a changed cache key should run the node again")
+	assert.Equal(t, 2, plainRuns, "nodes that don't opt in always run")
+}
@@ -0,0 +1,38 @@
+package execution
+
+import (
+	"log/slog"
+	"workflow-code-test/api/pkg/models"
+)
+
+// continueOnErrorKey is the node metadata flag that lets a run carry on past the node's failure
+const continueOnErrorKey = "continueOnError"
+
+// CanContinueOnError reports whether a run may carry on after a node of this type
+// fails. Integration and condition nodes gate the flow, so later nodes would act on
+// missing data or take an arbitrary branch; their failures always stop the run.
+func CanContinueOnError(nodeType models.NodeType) bool {
+	switch nodeType {
+	case models.NodeTypeIntegration, models.NodeTypeCondition:
+		return false
+	default:
+		return true
+	}
+}
+
+// continueOnErrorNodes returns the IDs of nodes whose failures don't stop the run.
+// The flag is ignored, with a warning, on node types that gate the flow.
+func continueOnErrorNodes(workflow *models.Workflow, logger *slog.Logger) map[string]bool {
+	nodes := make(map[string]bool)
+	for _, n := range workflow.Nodes {
+		if enabled, _ := n.Data.Metadata[continueOnErrorKey].(bool); !enabled {
+			continue
+		}
+		if !CanContinueOnError(n.Type) {
+			logger.Warn("Ignoring continueOnError on a node that gates the workflow", "nodeId", n.ID, "nodeType", n.Type)
+			continue
+		}
+		nodes[n.ID] = true
+	}
+	return nodes
+}
@@ -0,0 +1,65 @@
+package execution
+
+import (
+	"strings"
+	"workflow-code-test/api/pkg/mailer"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// hiddenStepVariables are output keys left out of step variables because they hold
+// the email address: the form node's email and its formData, which repeats it
+var hiddenStepVariables = map[string]bool{
+	string(models.OutputKeyEmail): true,
+	"formData":                    true,
+}
+
+// stepVariables returns the values a step's label and description placeholders are
+// filled from: the run's input, the outputs of the steps before it in the order they
+// ran, the values shared so far and then the node's own output, each taking precedence
+// over those before it. Output values can also be named by their node, as
+// "<nodeId>.<key>". The email address is left out, as step labels are stored.
+func (s *runState) stepVariables(previous []models.ExecutionStep, nodeID string, outputs node.NodeOutputs) map[string]any {
+	variables := make(map[string]any)
+	for name, value := range map[string]string{"name": s.input.Name, "city": s.input.City, "operator": string(s.input.Operator)} {
+		if value != "" {
+			variables[name] = value
+		}
+	}
+	if s.input.Operator != "" {
+		variables["threshold"] = s.input.Threshold
+	}
+
+	addOutput := func(id string, data map[string]any) {
+		for key, value := range data {
+			if hiddenStepVariables[key] {
+				continue
+			}
+			variables[key] = value
+			variables[id+"."+key] = value
+		}
+	}
+	for _, step := range previous {
+		if prior, ok := s.priorOutputs[step.NodeID]; ok {
+			addOutput(step.NodeID, prior.Data)
+		}
+	}
+	for key, value := range s.nodeData {
+		if !hiddenStepVariables[key] {
+			variables[key] = value
+		}
+	}
+	addOutput(nodeID, outputs.Data)
+	return variables
+}
+
+// fillStepPlaceholders fills {{variable}} placeholders in the step's label and
+// description from the run so far. Placeholders without a value are left as they are.
+func (s *runState) fillStepPlaceholders(step *models.ExecutionStep, previous []models.ExecutionStep, outputs node.NodeOutputs) {
+	if !strings.Contains(step.Label, "{{") && !strings.Contains(step.Description, "{{") {
+		return
+	}
+	variables := s.stepVariables(previous, step.NodeID, outputs)
+	step.Label, _ = mailer.RenderTemplate(step.Label, variables)
+	step.Description, _ = mailer.RenderTemplate(step.Description, variables)
+}
@@ -2,7 +2,12 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strconv"
 	"time"
 	"workflow-code-test/api/pkg/models"
 	"workflow-code-test/api/pkg/node"
@@ -13,108 +18,326 @@ import (
 
 // Engine executes workflows
 type Engine struct {
-	registry *node.Registry
+	registry     *node.Registry
+	hooks        HookRunner
+	nodeTimeouts map[models.NodeType]time.Duration
+	metrics      *Metrics
 }
 
 // NewEngine creates a workflow execution engine
 func NewEngine(registry *node.Registry) *Engine {
 	return &Engine{
-		registry: registry,
+		registry:     registry,
+		hooks:        defaultHookRunner{client: &http.Client{}},
+		nodeTimeouts: DefaultNodeTimeouts(),
+		metrics:      newMetrics(),
 	}
 }
 
-// Execute runs a workflow from start to finish
-func (e *Engine) Execute(ctx context.Context, workflow *models.Workflow, input models.WorkflowInput) (*models.WorkflowExecution, error) {
-	// Record start time
-	startTime := time.Now()
-	startTimeStr := startTime.Format(time.RFC3339)
-	
-	// Initialize workflow execution
-	execution := &models.WorkflowExecution{
-		ID:         uuid.New().String(),
-		WorkflowID: workflow.ID,
-		ExecutedAt: startTime,
-		Status:     models.StatusRunning,
-		StartTime:  startTimeStr,
-		Steps:      make([]models.ExecutionStep, 0),
-		Metadata:   models.JSONB{
-			"workflowVersion": workflow.Version, 
-			"triggeredBy":     input.Name, 
-		},
+// ValidateNodes builds every node through its registered factory and validates its
+// configuration, so a definition that can't run is rejected before it is stored.
+// Errors for all failing nodes are returned together.
+func (e *Engine) ValidateNodes(nodes []models.Node) error {
+	var errs []error
+	for _, nodeModel := range nodes {
+		n, err := e.registry.Create(nodeModel)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", nodeModel.ID, err))
+			continue
+		}
+		if err := n.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", nodeModel.ID, err))
+		}
 	}
+	return errors.Join(errs...)
+}
+
+// Execute runs a workflow from start to finish. The workflow's pre hooks fire before
+// the start node and its post hooks once the run has finished, whichever branch it took.
+func (e *Engine) Execute(ctx context.Context, workflow *models.Workflow, input models.WorkflowInput) (*models.WorkflowExecution, error) {
+	execution := newExecution(workflow, input)
 
 	// Initialize workflow routing structures
-	nodes, edges, startNodeID, err := e.initializeWorkflow(workflow)
+	nodes, edges, defaults, startNodeID, err := e.initializeWorkflow(workflow)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store node outputs for access by subsequent nodes
-	priorOutputs := make(map[string]node.NodeOutputs)
-	nodeData := make(map[string]any) // For storing intermediate data across nodes
+	// Logger shared by all nodes in this execution
+	executionLogger := slog.Default().With("workflowId", workflow.ID, "executionId", execution.ID)
+	state := newRunState(workflow.ID, input, executionLogger)
+
+	return e.run(ctx, workflow, execution, state, nodes, edges, defaults, startNodeID, executionLogger)
+}
+
+// run fires the workflow's hooks around its nodes, starting at startNodeID with the
+// given state, and reports the run's progress
+func (e *Engine) run(
+	ctx context.Context,
+	workflow *models.Workflow,
+	execution *models.WorkflowExecution,
+	state *runState,
+	nodes map[string]node.Node,
+	edges map[string]map[string]string,
+	defaults map[string][]string,
+	startNodeID string,
+	executionLogger *slog.Logger,
+) (*models.WorkflowExecution, error) {
+	progress := progressFrom(ctx)
+	progress.Started(ctx, execution)
+
+	// A failed pre hook that must fail the execution stops it before any node runs
+	if err := e.runHooks(ctx, workflow, execution, models.HookStagePre, executionLogger); err != nil {
+		failExecution(execution, &models.ExecutionErrorSummary{Message: err.Error()})
+	} else if err := e.runNodes(ctx, workflow, state, execution, nodes, edges, defaults, startNodeID, executionLogger); err != nil {
+		// The caller gets no execution, but whoever is following the run sees it end
+		failExecution(execution, &models.ExecutionErrorSummary{Message: err.Error()})
+		e.metrics.observeExecution(execution)
+		progress.Finished(ctx, execution)
+		return nil, err
+	}
+
+	// Post hooks see the final status and whether the run was too slow; a failing one can
+	// still fail a completed or partial run
+	recordSLA(workflow, execution)
+	if err := e.runHooks(ctx, workflow, execution, models.HookStagePost, executionLogger); err != nil && execution.Status != models.StatusFailed {
+		failExecution(execution, &models.ExecutionErrorSummary{Message: err.Error()})
+	}
+	e.metrics.observeExecution(execution)
+	progress.Finished(ctx, execution)
+
+	return execution, nil
+}
+
+// runNodes walks the workflow from startNodeID, recording each step on execution
+// after any it already has and finishing it once the end node runs or a node fails.
+// The branches of a node with parallelBranches set run side by side when they are
+// independent; their steps are recorded branch by branch once they have all finished.
+func (e *Engine) runNodes(
+	ctx context.Context,
+	workflow *models.Workflow,
+	state *runState,
+	execution *models.WorkflowExecution,
+	nodes map[string]node.Node,
+	edges map[string]map[string]string,
+	defaults map[string][]string,
+	startNodeID string,
+	executionLogger *slog.Logger,
+) error {
+	continueOnError := continueOnErrorNodes(workflow, executionLogger)
+	parallel := parallelNodes(workflow, executionLogger)
+	timeouts := e.timeoutsFor(workflow, executionLogger)
+	// A resumed run carries on from the setbacks of the steps it carried over
+	continuedAfter := stringList(execution.Metadata["continuedAfterFailure"])
+	suppressed := stringList(execution.Metadata["suppressedNodes"])
+	
+	// recordStep records a node's run as the execution's next step and in the run's
+	// state, reporting whether its failure stops the run
+	recordStep := func(nodeID string, current node.Node, outputs node.NodeOutputs, err error, duration time.Duration) (models.ExecutionStep, bool) {
+		step := e.createExecutionStep(current, nodeID, outputs, state, execution.Steps)
+		step.StepNumber = len(execution.Steps) + 1
+		e.metrics.observeNode(step.NodeType, duration, err != nil || outputs.Status == models.StatusFailed)
+		if explaining(ctx) {
+			recordExplanation(execution, &step, outputs)
+		}
+		execution.Steps = append(execution.Steps, step)
+		progressFrom(ctx).StepCompleted(ctx, execution, step)
+		state.record(nodeID, outputs, err)
+
+		// Non-critical nodes may opt to let the run carry on past their failure
+		if err != nil || outputs.Status == models.StatusFailed {
+			if !continueOnError[nodeID] {
+				return step, true
+			}
+			executionLogger.Warn("Node failed, continuing execution", "nodeId", nodeID, "error", err)
+			continuedAfter = append(continuedAfter, nodeID)
+			execution.Metadata["continuedAfterFailure"] = continuedAfter
+		} else if outputs.Suppressed {
+			suppressed = append(suppressed, nodeID)
+			execution.Metadata["suppressedNodes"] = suppressed
+		}
+		return step, false
+	}
 	
 	// Execute nodes in sequence
 	currentNodeID := startNodeID
-	stepNumber := 1
 	
 	for {
 		// Get and validate current node
 		currentNode := nodes[currentNodeID]
 		if currentNode == nil {
-			return nil, fmt.Errorf("node %s not found in workflow", currentNodeID)
+			return fmt.Errorf("node %s not found in workflow", currentNodeID)
 		}
 
-		// Execute node
-		nodeInputs := node.NodeInputs{
-			WorkflowInput: input,
-			NodeData:      nodeData,
-			PriorOutputs:  priorOutputs,
-		}
-		outputs, err := currentNode.Execute(ctx, nodeInputs)
+		// Execute node within its timeout
+		nodeCtx, cancel := withNodeTimeout(ctx, timeouts, currentNodeID)
+		nodeStarted := time.Now()
+		outputs, err := state.execute(nodeCtx, currentNodeID, currentNode)
+		nodeDuration := time.Since(nodeStarted)
+		cancel()
 		
-		// Record execution step
-		step := e.createExecutionStep(currentNode, currentNodeID, outputs, workflow)
-		step.StepNumber = stepNumber
-		execution.Steps = append(execution.Steps, step)
-		stepNumber++
-		priorOutputs[currentNodeID] = outputs
-
-		// Handle errors or failed steps
-		if err != nil || outputs.Status == models.StatusFailed {
-			execution.Status = models.StatusFailed
-			endTime := time.Now()
-			execution.EndTime = endTime.Format(time.RFC3339)
-			startTime, _ := time.Parse(time.RFC3339, execution.StartTime)
-			execution.TotalDuration = endTime.Sub(startTime).Milliseconds()
-			return execution, nil
+		// Record execution step and handle errors or failed steps
+		if step, stop := recordStep(currentNodeID, currentNode, outputs, err, nodeDuration); stop {
+			keepSharedData(execution, state)
+			failExecution(execution, stepErrorSummary(step, err))
+			return nil
 		}
 
-		// Check if workflow is complete
+		// Check if workflow is complete; setbacks along the way make it partial.
+		// Only the end node finishes a run; checkpoints summarize it and carry on.
 		if currentNode.Type() == models.NodeTypeEnd {
-			execution.Status = models.StatusCompleted
-			endTime := time.Now()
-			execution.EndTime = endTime.Format(time.RFC3339)
-			startTime, _ := time.Parse(time.RFC3339, execution.StartTime)
-			execution.TotalDuration = endTime.Sub(startTime).Milliseconds()
+			status := models.StatusCompleted
+			if len(continuedAfter) > 0 || len(suppressed) > 0 {
+				status = models.StatusPartial
+			}
+			finishExecution(execution, status)
 			break
 		}
 
+		// Run independent branches side by side and carry on from where they join.
+		// A branch failure that stops the run is reported once its siblings finish.
+		if parallel[currentNodeID] {
+			if fan, ok := parallelFanOut(currentNodeID, nodes, defaults); ok {
+				var failedStep *models.ExecutionStep
+				var failedErr error
+				for _, branch := range e.runBranches(ctx, state, nodes, fan, timeouts, continueOnError) {
+					for _, ran := range branch {
+						step, stop := recordStep(ran.nodeID, nodes[ran.nodeID], ran.outputs, ran.err, ran.duration)
+						if stop && failedStep == nil {
+							failedStep, failedErr = &step, ran.err
+						}
+					}
+				}
+				if failedStep != nil {
+					keepSharedData(execution, state)
+					failExecution(execution, stepErrorSummary(*failedStep, failedErr))
+					return nil
+				}
+				currentNodeID = fan.join
+				continue
+			}
+			executionLogger.Warn("Running the highest-priority branch only, as the branches aren't independent", "nodeId", currentNodeID)
+		}
+
 		// Find next node
-		nextNodeID, err := e.findNextNode(currentNode, currentNodeID, outputs, edges)
+		nextNodeID, err := e.findNextNode(currentNode, currentNodeID, outputs, edges, defaults)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		
 		currentNodeID = nextNodeID
 	}
 
+	return nil
+}
+
+// ExecuteSequence runs the given nodes in exactly the given order, ignoring edges and
+// conditional routing. It is a diagnostic aid for isolating nodes: each node still
+// receives the outputs of the nodes run before it and steps are recorded as usual.
+func (e *Engine) ExecuteSequence(ctx context.Context, workflow *models.Workflow, input models.WorkflowInput, order []string) (*models.WorkflowExecution, error) {
+	execution := newExecution(workflow, input)
+	execution.Metadata["forcedOrder"] = order
+
+	nodes, _, _, _, err := e.initializeWorkflow(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	executionLogger := slog.Default().With("workflowId", workflow.ID, "executionId", execution.ID, "forcedOrder", true)
+	state := newRunState(workflow.ID, input, executionLogger)
+	timeouts := e.timeoutsFor(workflow, executionLogger)
+	var suppressed []string
+
+	for i, nodeID := range order {
+		currentNode := nodes[nodeID]
+		if currentNode == nil {
+			return nil, fmt.Errorf("node %s not found in workflow", nodeID)
+		}
+
+		nodeCtx, cancel := withNodeTimeout(ctx, timeouts, nodeID)
+		outputs, err := state.execute(nodeCtx, nodeID, currentNode)
+		cancel()
+
+		step := e.createExecutionStep(currentNode, nodeID, outputs, state, execution.Steps)
+		step.StepNumber = i + 1
+		if explaining(ctx) {
+			recordExplanation(execution, &step, outputs)
+		}
+		execution.Steps = append(execution.Steps, step)
+		state.record(nodeID, outputs, err)
+
+		if err != nil || outputs.Status == models.StatusFailed {
+			failExecution(execution, stepErrorSummary(step, err))
+			return execution, nil
+		}
+		if outputs.Suppressed {
+			suppressed = append(suppressed, nodeID)
+			execution.Metadata["suppressedNodes"] = suppressed
+		}
+	}
+
+	if len(suppressed) > 0 {
+		finishExecution(execution, models.StatusPartial)
+		return execution, nil
+	}
+	finishExecution(execution, models.StatusCompleted)
 	return execution, nil
 }
 
+// newExecution starts a running execution record for a workflow
+func newExecution(workflow *models.Workflow, input models.WorkflowInput) *models.WorkflowExecution {
+	startTime := time.Now()
+	return &models.WorkflowExecution{
+		ID:         uuid.New().String(),
+		WorkflowID: workflow.ID,
+		ExecutedAt: startTime,
+		Status:     models.StatusRunning,
+		StartTime:  startTime.Format(time.RFC3339),
+		EnqueuedAt: &startTime, // the service replaces this with when it accepted the request
+		StartedAt:  &startTime,
+		Steps:      make([]models.ExecutionStep, 0),
+		Metadata:   models.JSONB{
+			"workflowVersion": workflow.Version, 
+			"triggeredBy":     input.Name, 
+		},
+	}
+}
+
+// finishExecution records the final status, end time, total duration and KPIs
+func finishExecution(execution *models.WorkflowExecution, status models.Status) {
+	execution.Status = status
+	endTime := time.Now()
+	execution.EndTime = endTime.Format(time.RFC3339)
+	execution.EndedAt = &endTime
+	startTime, _ := time.Parse(time.RFC3339, execution.StartTime)
+	execution.TotalDuration = endTime.Sub(startTime).Milliseconds()
+	execution.Metadata[kpisKey] = computeKPIs(execution)
+}
+
+// failExecution finishes a run as failed, recording why
+func failExecution(execution *models.WorkflowExecution, summary *models.ExecutionErrorSummary) {
+	execution.ErrorSummary = summary
+	finishExecution(execution, models.StatusFailed)
+}
+
+// stepErrorSummary describes a failed step, preferring the error its node recorded
+// over the one it returned
+func stepErrorSummary(step models.ExecutionStep, err error) *models.ExecutionErrorSummary {
+	message := step.Error
+	if message == "" && err != nil {
+		message = err.Error()
+	}
+	if message == "" {
+		message = "node failed"
+	}
+	return &models.ExecutionErrorSummary{NodeID: step.NodeID, NodeType: step.NodeType, Message: message}
+}
+
 // initializeWorkflow sets up all node instances and connection maps
 func (e *Engine) initializeWorkflow(workflow *models.Workflow) (
 	nodes map[string]node.Node,
 	edges map[string]map[string]string,
+	defaults map[string][]string,
 	startNodeID string,
 	err error) {
 	
@@ -123,7 +346,7 @@ func (e *Engine) initializeWorkflow(workflow *models.Workflow) (
 	for _, nodeModel := range workflow.Nodes {
 		n, err := e.registry.Create(nodeModel)
 		if err != nil {
-			return nil, nil, "", fmt.Errorf("failed to create node %s: %w", nodeModel.ID, err)
+			return nil, nil, nil, "", fmt.Errorf("failed to create node %s: %w", nodeModel.ID, err)
 		}
 		nodes[nodeModel.ID] = n
 		
@@ -134,46 +357,78 @@ func (e *Engine) initializeWorkflow(workflow *models.Workflow) (
 	}
 	
 	if startNodeID == "" {
-		return nil, nil, "", fmt.Errorf("no start node found in workflow")
+		return nil, nil, nil, "", fmt.Errorf("no start node found in workflow")
 	}
 	
-	// Build unified edge routing map
-	// Key: sourceNodeID, Value: map[routeKey]targetNodeID
-	// For regular edges, routeKey is empty string
-	// For conditional edges, routeKey is "true" or "false"
+	// Build edge routing maps
+	// edges: sourceNodeID -> map[routeKey]targetNodeID for conditional edges,
+	// where routeKey is "true" or "false"
+	// defaults: sourceNodeID -> targets of its unlabelled edges, highest priority
+	// first, skipping edges whose target isn't a node of this workflow
 	edges = make(map[string]map[string]string)
+	unlabelled := make([]models.Edge, 0, len(workflow.Edges))
 	
 	for _, edge := range workflow.Edges {
+		if edge.SourceHandle == "" {
+			unlabelled = append(unlabelled, edge)
+			continue
+		}
 		if edges[edge.Source] == nil {
 			edges[edge.Source] = make(map[string]string)
 		}
-		
-		routeKey := edge.SourceHandle // Empty for regular edges, "true"/"false" for conditional edges
-		edges[edge.Source][routeKey] = edge.Target
-		
-		// Configure condition nodes with their routes
-		if routeKey == "true" || routeKey == "false" {
-			if node, ok := nodes[edge.Source]; ok && node.Type() == models.NodeTypeCondition {
-				if condNode, ok := node.(*condition.Node); ok {
-					if routeKey == "true" {
-						condNode.SetTrueRoute(edge.Target)
-					} else {
-						condNode.SetFalseRoute(edge.Target)
-					}
-				}
-			}
+		edges[edge.Source][edge.SourceHandle] = edge.Target
+	}
+	
+	models.SortEdgesByPriority(unlabelled)
+	defaults = make(map[string][]string)
+	for _, edge := range unlabelled {
+		if _, exists := nodes[edge.Target]; !exists {
+			slog.Warn("Skipping edge to unknown node", "workflowId", workflow.ID, "edgeId", edge.ID, "target", edge.Target)
+			continue
 		}
+		defaults[edge.Source] = append(defaults[edge.Source], edge.Target)
 	}
 	
-	return nodes, edges, startNodeID, nil
+	// Configure condition nodes with their routes. Edges are authoritative: any
+	// route read from node metadata is replaced by the true/false edge, or cleared
+	// when the node has no such edge.
+	for nodeID, n := range nodes {
+		if condNode, ok := n.(*condition.Node); ok {
+			applyConditionRoutes(workflow.ID, nodeID, condNode, edges[nodeID])
+		}
+	}
+	
+	return nodes, edges, defaults, startNodeID, nil
+}
+
+// applyConditionRoutes points a condition node at the targets of its true/false edges,
+// warning when routes from its metadata disagree so stale definitions can be fixed
+func applyConditionRoutes(workflowID, nodeID string, condNode *condition.Node, routes map[string]string) {
+	trueRoute, falseRoute := routes["true"], routes["false"]
+	metadataTrue, metadataFalse := condNode.Routes()
+	if (metadataTrue != "" && metadataTrue != trueRoute) || (metadataFalse != "" && metadataFalse != falseRoute) {
+		slog.Warn("Ignoring condition routes in node metadata that disagree with edges",
+			"workflowId", workflowID,
+			"nodeId", nodeID,
+			"metadataTrueRoute", metadataTrue,
+			"metadataFalseRoute", metadataFalse,
+			"edgeTrueRoute", trueRoute,
+			"edgeFalseRoute", falseRoute,
+		)
+	}
+	condNode.SetTrueRoute(trueRoute)
+	condNode.SetFalseRoute(falseRoute)
 }
 
-// createExecutionStep creates an execution step record from node outputs
+// createExecutionStep creates an execution step record from node outputs, filling
+// placeholders in its label and description from the run's state and the steps
+// recorded before it
 func (e *Engine) createExecutionStep(
 	node node.Node, 
 	nodeID string, 
 	outputs node.NodeOutputs,
-	_ *models.Workflow) models.ExecutionStep {
+	state *runState,
+	previous []models.ExecutionStep) models.ExecutionStep {
 	
 	// Parse timestamps to calculate duration
 	startTime, _ := time.Parse(time.RFC3339, outputs.StartedAt)
@@ -181,8 +436,8 @@ func (e *Engine) createExecutionStep(
 	duration := endTime.Sub(startTime).Milliseconds()
 	
 	status := models.StatusCompleted
-	if outputs.Status == models.StatusFailed {
-		status = models.StatusFailed
+	if outputs.Status == models.StatusFailed || outputs.Status == models.StatusQueued {
+		status = outputs.Status
 	}
 	
 	// Extract error message if present
@@ -193,22 +448,34 @@ func (e *Engine) createExecutionStep(
 		}
 	}
 	
+	// Record the output's schema on the step; the data itself is shared with later nodes
+	output := maps.Clone(models.JSONB(outputs.Data))
+	if output == nil {
+		output = models.JSONB{}
+	}
+	output[models.OutputSchemaVersionKey] = models.OutputSchemaVersion
+	
 	step := models.ExecutionStep{
 		NodeID:      nodeID,
 		NodeType:    node.Type(),
 		Status:      status,
 		Duration:    duration,
-		Output:      outputs.Data,
+		Output:      output,
 		Timestamp:   outputs.StartedAt,
 		Error:       errorMsg,
 		StartedAt:   outputs.StartedAt,  // Keep for internal use
 		EndedAt:     outputs.EndedAt,    // Keep for internal use
 	}
 	
-	// Use the node's current base information (may have been updated during execution)
+	// Use the node's current base information (may have been updated during execution),
+	// preferring a label the node derived from runtime data
 	baseInfo := node.GetBaseInfo()
 	step.Label = baseInfo.Label
+	if outputs.DisplayLabel != "" {
+		step.Label = outputs.DisplayLabel
+	}
 	step.Description = baseInfo.Description
+	state.fillStepPlaceholders(&step, previous, outputs)
 	
 	return step
 }
@@ -218,31 +485,32 @@ func (e *Engine) findNextNode(
 	currentNode node.Node, 
 	currentNodeID string, 
 	outputs node.NodeOutputs, 
-	edges map[string]map[string]string) (string, error) {
+	edges map[string]map[string]string,
+	defaults map[string][]string) (string, error) {
 	
 	// Check if NextNodeID is explicitly set (from condition nodes)
 	if outputs.NextNodeID != "" {
 		return outputs.NextNodeID, nil
 	}
 	
-	// Handle node types that use specific routing
+	// Condition nodes without an explicit next node route on the result they recorded.
+	// They never fall through to an unlabelled edge, which could send a failed check down
+	// the wrong branch.
 	if currentNode.Type() == models.NodeTypeCondition {
-		// Determine route based on condition result
-		routeKey := ""
-		if conditionMet, ok := outputs.Data["conditionMet"].(bool); ok && conditionMet {
-			routeKey = "true"
-		} else {
-			routeKey = "false"
+		conditionMet, ok := condition.Result(outputs.Data)
+		if !ok {
+			return "", fmt.Errorf("condition node %s did not record a result", currentNodeID)
 		}
-		
+		routeKey := strconv.FormatBool(conditionMet)
 		if nextNode, exists := edges[currentNodeID][routeKey]; exists {
 			return nextNode, nil
 		}
+		return "", fmt.Errorf("condition node %s has no %s route", currentNodeID, routeKey)
 	}
 	
-	// Default to first available edge
-	if nextNode, exists := edges[currentNodeID][""]; exists {
-		return nextNode, nil
+	// Otherwise follow the highest-priority unlabelled edge
+	if targets := defaults[currentNodeID]; len(targets) > 0 {
+		return targets[0], nil
 	}
 	
 	// No valid edge found
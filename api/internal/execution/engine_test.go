@@ -0,0 +1,884 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/checkpoint"
+	"workflow-code-test/api/pkg/node/condition"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/form"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubNode returns canned outputs so engine behaviour can be tested without real nodes
+type stubNode struct {
+	node.BaseNode
+	nodeType models.NodeType
+	outputs  node.NodeOutputs
+}
+
+func (n *stubNode) Type() models.NodeType { return n.nodeType }
+
+func (n *stubNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *stubNode) Validate() error { return nil }
+
+func (n *stubNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	outputs := n.outputs
+	if outputs.Status == "" {
+		outputs.Status = models.StatusCompleted
+	}
+	return outputs, nil
+}
+
+// newStubFactory registers a node type whose instances return the outputs for their ID
+func newStubFactory(nodeType models.NodeType, outputs map[string]node.NodeOutputs) node.NodeFactory {
+	return func(model models.Node) (node.Node, error) {
+		return &stubNode{
+			BaseNode: node.BaseNode{ID: model.ID, Label: model.Data.Label, Description: model.Data.Description},
+			nodeType: nodeType,
+			outputs:  outputs[model.ID],
+		}, nil
+	}
+}
+
+// newTestRegistry registers the real start and end nodes
+func newTestRegistry() *node.Registry {
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+	return registry
+}
+
+func TestExecuteUsesDisplayLabel(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{}, DisplayLabel: "Weather for Sydney"},
+		"plain":       {Data: map[string]any{}},
+	}))
+
+	workflow := &models.Workflow{
+		ID: "label-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "weather-api", Type: models.NodeTypeIntegration, Data: models.NodeData{Label: "Weather API"}},
+			{ID: "plain", Type: models.NodeTypeIntegration, Data: models.NodeData{Label: "Plain Node"}},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "weather-api"},
+			{ID: "e2", Source: "weather-api", Target: "plain"},
+			{ID: "e3", Source: "plain", Target: "end"},
+		},
+	}
+
+	execution, err := NewEngine(registry).Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Len(t, execution.Steps, 4)
+	assert.Equal(t, "Weather for Sydney", execution.Steps[1].Label)
+	assert.Equal(t, "Plain Node", execution.Steps[2].Label)
+}
+
+func TestExecuteFillsStepDescriptionPlaceholders(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeCondition, condition.NewNode)
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 31.0, "location": "Sydney"}},
+	}))
+
+	workflow := chainWorkflow(
+		models.Node{ID: "weather-api", Type: models.NodeTypeIntegration, Data: models.NodeData{
+			Label:       "Weather for {{city}}",
+			Description: "Fetch current temperature for {{city}}",
+		}},
+		models.Node{ID: "check", Type: models.NodeTypeCondition, Data: models.NodeData{
+			Label:       "Check {{weather-api.location}}",
+			Description: "Is {{temperature}} {{operator}} {{threshold}}? Notify {{email}} {{unknown}}",
+			Metadata: map[string]any{
+				"conditionField": "temperature", "threshold": 30.0, "operator": "greater_than",
+			},
+		}},
+	)
+	workflow.Edges[2].SourceHandle = "true"
+	input := models.WorkflowInput{Email: "alice@example.com", City: "Sydney", Operator: models.OperatorGreaterThan, Threshold: 30}
+
+	execution, err := NewEngine(registry).Execute(context.Background(), workflow, input)
+	assert.NoError(t, err)
+	if assert.Len(t, execution.Steps, 4) {
+		assert.Equal(t, "Weather for Sydney", execution.Steps[1].Label)
+		assert.Equal(t, "Fetch current temperature for Sydney", execution.Steps[1].Description)
+		assert.Equal(t, "Check Sydney", execution.Steps[2].Label)
+		// Placeholders with no value, and the email address, are left as written
+		assert.Equal(t, "Is 31.0 greater_than 30.0? Notify {{email}} {{unknown}}", execution.Steps[2].Description)
+	}
+}
+
+func TestExecuteStepPlaceholdersHideEmail(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeForm, form.NewNode)
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 31.0}},
+	}))
+
+	leaky := "{{email}} {{formData}} {{form.email}} {{form.formData}}"
+	workflow := chainWorkflow(
+		models.Node{ID: "form", Type: models.NodeTypeForm, Data: models.NodeData{Label: "Form for {{city}}", Description: leaky}},
+		models.Node{ID: "weather-api", Type: models.NodeTypeIntegration, Data: models.NodeData{Label: "Alert {{name}}", Description: leaky}},
+	)
+	input := models.WorkflowInput{Name: "Alice", Email: "alice@example.com", City: "Sydney"}
+
+	execution, err := NewEngine(registry).Execute(context.Background(), workflow, input)
+	assert.NoError(t, err)
+	if assert.Len(t, execution.Steps, 4) {
+		assert.Equal(t, "Form for Sydney", execution.Steps[1].Label)
+		assert.Equal(t, "Alert Alice", execution.Steps[2].Label)
+		for _, step := range execution.Steps {
+			assert.NotContains(t, step.Label, "alice@example.com")
+			assert.NotContains(t, step.Description, "alice@example.com")
+		}
+		assert.Equal(t, leaky, execution.Steps[1].Description)
+		assert.Equal(t, leaky, execution.Steps[2].Description)
+	}
+}
+
+func TestExecuteRecordsOutputSchemaVersion(t *testing.T) {
+	data := map[string]any{"temperature": 28.0}
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: data},
+	}))
+
+	execution, err := NewEngine(registry).Execute(context.Background(), chainWorkflow(
+		models.Node{ID: "weather-api", Type: models.NodeTypeIntegration},
+	), models.WorkflowInput{})
+	assert.NoError(t, err)
+	for _, step := range execution.Steps {
+		assert.Equal(t, models.OutputSchemaVersion, step.Output[models.OutputSchemaVersionKey], step.NodeID)
+	}
+	assert.Equal(t, 28.0, execution.Steps[1].Output["temperature"])
+	// The node's own data, which later nodes read, is left as it was
+	assert.Equal(t, map[string]any{"temperature": 28.0}, data)
+}
+
+func TestExecuteRecordsTimestamps(t *testing.T) {
+	workflow := &models.Workflow{
+		ID: "timed-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+	}
+
+	execution, err := NewEngine(newTestRegistry()).Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	if assert.NotNil(t, execution.EnqueuedAt) && assert.NotNil(t, execution.StartedAt) && assert.NotNil(t, execution.EndedAt) {
+		assert.False(t, execution.StartedAt.Before(*execution.EnqueuedAt))
+		assert.False(t, execution.EndedAt.Before(*execution.StartedAt))
+	}
+}
+
+func TestExecuteChainedConditions(t *testing.T) {
+	// Temperature check, then wind check, each with its own threshold and routes
+	newWorkflow := func() *models.Workflow {
+		return &models.Workflow{
+			ID: "chained-workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "weather-api", Type: models.NodeTypeIntegration},
+				{ID: "temp-check", Type: models.NodeTypeCondition, Data: models.NodeData{Metadata: map[string]any{
+					"conditionField": "temperature", "threshold": 30.0, "operator": "greater_than",
+				}}},
+				{ID: "wind-check", Type: models.NodeTypeCondition, Data: models.NodeData{Metadata: map[string]any{
+					"conditionField": "windspeed", "threshold": 40.0, "operator": "greater_than",
+				}}},
+				{ID: "alert", Type: models.NodeTypeEnd},
+				{ID: "calm", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "weather-api"},
+				{ID: "e2", Source: "weather-api", Target: "temp-check"},
+				{ID: "e3", Source: "temp-check", Target: "wind-check", SourceHandle: "true"},
+				{ID: "e4", Source: "temp-check", Target: "calm", SourceHandle: "false"},
+				{ID: "e5", Source: "wind-check", Target: "alert", SourceHandle: "true"},
+				{ID: "e6", Source: "wind-check", Target: "calm", SourceHandle: "false"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		temperature  float64
+		windspeed    float64
+		expectedPath []string
+	}{
+		{"hot and windy", 35, 60, []string{"start", "weather-api", "temp-check", "wind-check", "alert"}},
+		{"hot and still", 35, 10, []string{"start", "weather-api", "temp-check", "wind-check", "calm"}},
+		{"mild", 20, 60, []string{"start", "weather-api", "temp-check", "calm"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry()
+			registry.Register(models.NodeTypeCondition, condition.NewNode)
+			registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+				"weather-api": {Data: map[string]any{"temperature": tt.temperature, "windspeed": tt.windspeed}},
+			}))
+
+			// The workflow input's comparison is overridden by each condition node
+			input := models.WorkflowInput{Threshold: 0, Operator: models.OperatorLessThan}
+			execution, err := NewEngine(registry).Execute(context.Background(), newWorkflow(), input)
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, execution.Status)
+
+			var path []string
+			for _, step := range execution.Steps {
+				path = append(path, step.NodeID)
+			}
+			assert.Equal(t, tt.expectedPath, path)
+		})
+	}
+}
+
+func TestEdgesOverrideMetadataRoutes(t *testing.T) {
+	// Metadata still carries routes from an older version of the workflow; the
+	// edges now point the true branch at "alert" and the false branch at "calm"
+	workflow := &models.Workflow{
+		ID: "stale-routes-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "weather-api", Type: models.NodeTypeIntegration},
+			{ID: "condition", Type: models.NodeTypeCondition, Data: models.NodeData{Metadata: map[string]any{
+				"conditionExpression": "temperature > 30",
+				"threshold":           30.0,
+				"operator":            "greater_than",
+				"trueRoute":           "calm",
+				"falseRoute":          "removed-node",
+			}}},
+			{ID: "alert", Type: models.NodeTypeEnd},
+			{ID: "calm", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "weather-api"},
+			{ID: "e2", Source: "weather-api", Target: "condition"},
+			{ID: "e3", Source: "condition", Target: "alert", SourceHandle: "true"},
+			{ID: "e4", Source: "condition", Target: "calm", SourceHandle: "false"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		temperature float64
+		expectedEnd string
+	}{
+		{"true branch follows edge", 35, "alert"},
+		{"false branch follows edge", 20, "calm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry()
+			registry.Register(models.NodeTypeCondition, condition.NewNode)
+			registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+				"weather-api": {Data: map[string]any{"temperature": tt.temperature}},
+			}))
+			engine := NewEngine(registry)
+
+			nodes, _, _, _, err := engine.initializeWorkflow(workflow)
+			assert.NoError(t, err)
+			trueRoute, falseRoute := nodes["condition"].(*condition.Node).Routes()
+			assert.Equal(t, "alert", trueRoute)
+			assert.Equal(t, "calm", falseRoute)
+
+			execution, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, execution.Status)
+			assert.Equal(t, tt.expectedEnd, execution.Steps[len(execution.Steps)-1].NodeID)
+		})
+	}
+
+	t.Run("metadata route without a matching edge is cleared", func(t *testing.T) {
+		registry := newTestRegistry()
+		registry.Register(models.NodeTypeCondition, condition.NewNode)
+		registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, nil))
+
+		unwired := *workflow
+		unwired.Edges = workflow.Edges[:3] // no false edge
+		nodes, _, _, _, err := NewEngine(registry).initializeWorkflow(&unwired)
+		assert.NoError(t, err)
+		trueRoute, falseRoute := nodes["condition"].(*condition.Node).Routes()
+		assert.Equal(t, "alert", trueRoute)
+		assert.Empty(t, falseRoute)
+	})
+}
+
+func TestFindNextNodeConditionFallback(t *testing.T) {
+	engine := NewEngine(newTestRegistry())
+	conditionNode := &stubNode{nodeType: models.NodeTypeCondition}
+	edges := map[string]map[string]string{
+		"condition": {"true": "email", "false": "end"},
+		"partial":   {"true": "email"},
+	}
+	defaults := map[string][]string{"partial": {"end"}}
+	withResult := func(result any) node.NodeOutputs {
+		return node.NodeOutputs{Data: map[string]any{"conditionResult": map[string]any{"result": result}}}
+	}
+
+	t.Run("explicit next node wins", func(t *testing.T) {
+		outputs := withResult(false)
+		outputs.NextNodeID = "email"
+		next, err := engine.findNextNode(conditionNode, "condition", outputs, edges, defaults)
+		assert.NoError(t, err)
+		assert.Equal(t, "email", next)
+	})
+
+	t.Run("true result follows the true edge", func(t *testing.T) {
+		next, err := engine.findNextNode(conditionNode, "condition", withResult(true), edges, defaults)
+		assert.NoError(t, err)
+		assert.Equal(t, "email", next)
+	})
+
+	t.Run("false result follows the false edge", func(t *testing.T) {
+		next, err := engine.findNextNode(conditionNode, "condition", withResult(false), edges, defaults)
+		assert.NoError(t, err)
+		assert.Equal(t, "end", next)
+	})
+
+	t.Run("legacy conditionMet is not a result", func(t *testing.T) {
+		outputs := node.NodeOutputs{Data: map[string]any{"conditionMet": true}}
+		_, err := engine.findNextNode(conditionNode, "condition", outputs, edges, defaults)
+		assert.ErrorContains(t, err, "did not record a result")
+	})
+
+	t.Run("missing route is an error rather than the default edge", func(t *testing.T) {
+		_, err := engine.findNextNode(conditionNode, "partial", withResult(false), edges, defaults)
+		assert.ErrorContains(t, err, "has no false route")
+	})
+}
+
+func TestExecuteFollowsEdgePriority(t *testing.T) {
+	newWorkflow := func(edges ...models.Edge) *models.Workflow {
+		return &models.Workflow{
+			ID: "priority-workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "primary", Type: models.NodeTypeIntegration},
+				{ID: "fallback", Type: models.NodeTypeIntegration},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: append(edges,
+				models.Edge{ID: "e-primary", Source: "primary", Target: "end"},
+				models.Edge{ID: "e-fallback", Source: "fallback", Target: "end"},
+			),
+		}
+	}
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, nil))
+	engine := NewEngine(registry)
+
+	tests := []struct {
+		name     string
+		edges    []models.Edge
+		expected string
+	}{
+		{
+			name: "highest priority wins regardless of order",
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "fallback", Priority: 1},
+				{ID: "e2", Source: "start", Target: "primary", Priority: 5},
+			},
+			expected: "primary",
+		},
+		{
+			name: "edge to an unknown node is skipped",
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "missing", Priority: 10},
+				{ID: "e2", Source: "start", Target: "fallback", Priority: 1},
+			},
+			expected: "fallback",
+		},
+		{
+			name: "without priorities the last edge is followed",
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "primary"},
+				{ID: "e2", Source: "start", Target: "fallback"},
+			},
+			expected: "fallback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution, err := engine.Execute(context.Background(), newWorkflow(tt.edges...), models.WorkflowInput{})
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, execution.Status)
+			assert.Len(t, execution.Steps, 3)
+			assert.Equal(t, tt.expected, execution.Steps[1].NodeID)
+		})
+	}
+}
+
+func TestExecuteExplainMode(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeCondition, condition.NewNode)
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 35.0, "message": "Fetched weather for Sydney"}},
+	}))
+	registry.Register(models.NodeTypeEmail, newStubFactory(models.NodeTypeEmail, map[string]node.NodeOutputs{
+		"email": {Data: map[string]any{}, Explanation: "Email sent because the condition was met"},
+	}))
+	workflow := &models.Workflow{
+		ID: "explain-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "weather-api", Type: models.NodeTypeIntegration},
+			{ID: "condition", Type: models.NodeTypeCondition},
+			{ID: "email", Type: models.NodeTypeEmail},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "weather-api"},
+			{ID: "e2", Source: "weather-api", Target: "condition"},
+			{ID: "e3", Source: "condition", Target: "email", SourceHandle: "true"},
+			{ID: "e4", Source: "condition", Target: "end", SourceHandle: "false"},
+			{ID: "e5", Source: "email", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{City: "Sydney", Threshold: 30, Operator: models.OperatorGreaterThan}
+	engine := NewEngine(registry)
+
+	t.Run("steps and the execution carry explanations", func(t *testing.T) {
+		execution, err := engine.Execute(WithExplain(context.Background()), workflow, input)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+
+		explanations := make(map[string]models.StepExplanation)
+		for _, entry := range execution.Explanation {
+			explanations[entry.NodeID] = entry
+		}
+		assert.Contains(t, explanations["condition"].Explanation, "so the true route to email was taken")
+		assert.Equal(t, 3, explanations["condition"].StepNumber)
+		assert.Equal(t, models.NodeTypeCondition, explanations["condition"].NodeType)
+		assert.Equal(t, "Email sent because the condition was met", explanations["email"].Explanation)
+		assert.Equal(t, "Fetched weather for Sydney", explanations["weather-api"].Explanation, "falls back to the message")
+		assert.Equal(t, "Run started for Sydney", explanations["start"].Explanation)
+
+		assert.Equal(t, "Email sent because the condition was met", execution.Steps[3].Output["explanation"])
+		assert.Contains(t, execution.Steps[2].Output["explanation"], "true route")
+	})
+
+	t.Run("explanations are left out by default", func(t *testing.T) {
+		execution, err := engine.Execute(context.Background(), workflow, input)
+		assert.NoError(t, err)
+		assert.Nil(t, execution.Explanation)
+		for _, step := range execution.Steps {
+			assert.NotContains(t, step.Output, "explanation")
+		}
+	})
+}
+
+func TestExecuteSequence(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeCondition, condition.NewNode)
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 5.0}},
+		"broken":      {Data: map[string]any{"error": "boom"}, Status: models.StatusFailed},
+	}))
+
+	workflow := &models.Workflow{
+		ID: "sequence-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "weather-api", Type: models.NodeTypeIntegration},
+			{ID: "condition", Type: models.NodeTypeCondition},
+			{ID: "broken", Type: models.NodeTypeIntegration},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "weather-api"},
+			{ID: "e2", Source: "weather-api", Target: "condition"},
+			{ID: "e3", Source: "condition", Target: "broken", SourceHandle: "true"},
+			{ID: "e4", Source: "condition", Target: "end", SourceHandle: "false"},
+		},
+	}
+	input := models.WorkflowInput{Threshold: 10, Operator: models.OperatorLessThan}
+	engine := NewEngine(registry)
+
+	t.Run("nodes run in the given order regardless of edges", func(t *testing.T) {
+		// The condition is met, but its true route is ignored
+		execution, err := engine.ExecuteSequence(context.Background(), workflow, input, []string{"weather-api", "condition", "end"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		assert.Len(t, execution.Steps, 3)
+		assert.Equal(t, "condition", execution.Steps[1].NodeID)
+		assert.Equal(t, 2, execution.Steps[1].StepNumber)
+		assert.Equal(t, true, execution.Steps[1].Output["conditionResult"].(map[string]any)["result"])
+		assert.Equal(t, []string{"weather-api", "condition", "end"}, execution.Metadata["forcedOrder"])
+	})
+
+	t.Run("a failed node stops the sequence", func(t *testing.T) {
+		execution, err := engine.ExecuteSequence(context.Background(), workflow, input, []string{"start", "broken", "end"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusFailed, execution.Status)
+		assert.Len(t, execution.Steps, 2)
+		assert.Equal(t, "boom", execution.Steps[1].Error)
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		_, err := engine.ExecuteSequence(context.Background(), workflow, input, []string{"start", "missing"})
+		assert.ErrorContains(t, err, "node missing not found")
+	})
+}
+
+func TestExecuteContinueOnError(t *testing.T) {
+	newWorkflow := func(failingType models.NodeType, metadata map[string]any) *models.Workflow {
+		return &models.Workflow{
+			ID: "continue-workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "flaky", Type: failingType, Data: models.NodeData{Metadata: metadata}},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "flaky"},
+				{ID: "e2", Source: "flaky", Target: "end"},
+			},
+		}
+	}
+	failed := map[string]node.NodeOutputs{
+		"flaky": {Data: map[string]any{"error": "webhook unreachable"}, Status: models.StatusFailed},
+	}
+
+	tests := []struct {
+		name           string
+		nodeType       models.NodeType
+		metadata       map[string]any
+		expectedStatus models.Status
+		expectedSteps  int
+	}{
+		{"failure aborts by default", models.NodeTypeEmail, nil, models.StatusFailed, 2},
+		{"flagged node lets the run finish as partial", models.NodeTypeEmail, map[string]any{"continueOnError": true}, models.StatusPartial, 3},
+		{"flag set to false aborts", models.NodeTypeEmail, map[string]any{"continueOnError": false}, models.StatusFailed, 2},
+		{"integration node is not eligible", models.NodeTypeIntegration, map[string]any{"continueOnError": true}, models.StatusFailed, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry()
+			registry.Register(tt.nodeType, newStubFactory(tt.nodeType, failed))
+
+			execution, err := NewEngine(registry).Execute(context.Background(), newWorkflow(tt.nodeType, tt.metadata), models.WorkflowInput{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, execution.Status)
+			assert.Len(t, execution.Steps, tt.expectedSteps)
+			// The failure is always recorded on its step
+			assert.Equal(t, models.StatusFailed, execution.Steps[1].Status)
+
+			if tt.expectedStatus == models.StatusPartial {
+				assert.Equal(t, []string{"flaky"}, execution.Metadata["continuedAfterFailure"])
+			} else {
+				assert.NotContains(t, execution.Metadata, "continuedAfterFailure")
+			}
+		})
+	}
+}
+
+func TestExecutePartialWhenNotificationSuppressed(t *testing.T) {
+	workflow := &models.Workflow{
+		ID: "suppressed-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "email", Type: models.NodeTypeEmail},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "email"},
+			{ID: "e2", Source: "email", Target: "end"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		outputs        node.NodeOutputs
+		expectedStatus models.Status
+	}{
+		{"sent", node.NodeOutputs{Data: map[string]any{"message": "Email sent"}, Status: models.StatusCompleted}, models.StatusCompleted},
+		{"suppressed", node.NodeOutputs{Data: map[string]any{"message": "Email suppressed"}, Status: models.StatusCompleted, Suppressed: true}, models.StatusPartial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry()
+			registry.Register(models.NodeTypeEmail, newStubFactory(models.NodeTypeEmail, map[string]node.NodeOutputs{"email": tt.outputs}))
+			engine := NewEngine(registry)
+
+			execution, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, execution.Status)
+			assert.Equal(t, models.StatusCompleted, execution.Steps[1].Status, "the step itself still completed")
+
+			sequence, err := engine.ExecuteSequence(context.Background(), workflow, models.WorkflowInput{}, []string{"start", "email", "end"})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sequence.Status)
+
+			if tt.expectedStatus == models.StatusPartial {
+				assert.Equal(t, []string{"email"}, execution.Metadata["suppressedNodes"])
+			} else {
+				assert.NotContains(t, execution.Metadata, "suppressedNodes")
+			}
+		})
+	}
+}
+
+func TestExecuteRecordsQueuedStep(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeEmail, newStubFactory(models.NodeTypeEmail, map[string]node.NodeOutputs{
+		"email": {Data: map[string]any{"message": "Email queued for retry"}, Status: models.StatusQueued},
+	}))
+	engine := NewEngine(registry)
+	workflow := &models.Workflow{
+		ID: "queued-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "email", Type: models.NodeTypeEmail},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "email"},
+			{ID: "e2", Source: "email", Target: "end"},
+		},
+	}
+
+	// The email goes out later, so the run still completes
+	execution, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+	assert.Equal(t, models.StatusQueued, execution.Steps[1].Status)
+}
+
+func TestExecuteContinuesPastCheckpoint(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeCheckpoint, checkpoint.NewNode)
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 28.5}, Shared: map[string]any{"temperature": 28.5}},
+	}))
+	registry.Register(models.NodeTypeEmail, newStubFactory(models.NodeTypeEmail, map[string]node.NodeOutputs{
+		"email": {Data: map[string]any{"message": "Email sent"}},
+	}))
+
+	workflow := &models.Workflow{
+		ID: "checkpoint-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "weather-api", Type: models.NodeTypeIntegration},
+			{ID: "fetched", Type: models.NodeTypeCheckpoint, Data: models.NodeData{Label: "Weather fetched"}},
+			{ID: "email", Type: models.NodeTypeEmail},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "weather-api"},
+			{ID: "e2", Source: "weather-api", Target: "fetched"},
+			{ID: "e3", Source: "fetched", Target: "email"},
+			{ID: "e4", Source: "email", Target: "end"},
+		},
+	}
+
+	execution, err := NewEngine(registry).Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+	if !assert.Len(t, execution.Steps, 5, "the run should carry on past the checkpoint") {
+		return
+	}
+	checkpointStep := execution.Steps[2]
+	assert.Equal(t, models.NodeTypeCheckpoint, checkpointStep.NodeType)
+	assert.Equal(t, models.StatusCompleted, checkpointStep.Status)
+	assert.Equal(t, map[string]any{
+		"message":        "Checkpoint Weather fetched reached",
+		"completedNodes": []string{"start", "weather-api"},
+		"shared":         map[string]any{"temperature": 28.5},
+	}, checkpointStep.Output["summary"])
+	assert.Equal(t, models.NodeTypeEmail, execution.Steps[3].NodeType)
+	assert.Equal(t, models.NodeTypeEnd, execution.Steps[4].NodeType)
+}
+
+// recordingProgress notes each progress call as "<event> <detail>"
+type recordingProgress struct {
+	events []string
+}
+
+func (p *recordingProgress) Started(ctx context.Context, execution *models.WorkflowExecution) {
+	p.events = append(p.events, "started "+string(execution.Status))
+}
+
+func (p *recordingProgress) StepCompleted(ctx context.Context, execution *models.WorkflowExecution, step models.ExecutionStep) {
+	p.events = append(p.events, fmt.Sprintf("step %d %s of %d", step.StepNumber, step.NodeID, len(execution.Steps)))
+}
+
+func (p *recordingProgress) Finished(ctx context.Context, execution *models.WorkflowExecution) {
+	p.events = append(p.events, "finished "+string(execution.Status))
+}
+
+func TestExecuteReportsProgress(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 28.5}},
+	}))
+
+	t.Run("completed run", func(t *testing.T) {
+		workflow := &models.Workflow{
+			ID: "progress-workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "weather-api", Type: models.NodeTypeIntegration},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "weather-api"},
+				{ID: "e2", Source: "weather-api", Target: "end"},
+			},
+		}
+
+		progress := &recordingProgress{}
+		execution, err := NewEngine(registry).Execute(WithProgress(context.Background(), progress), workflow, models.WorkflowInput{})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		assert.Equal(t, []string{
+			"started running",
+			"step 1 start of 1",
+			"step 2 weather-api of 2",
+			"step 3 end of 3",
+			"finished completed",
+		}, progress.events)
+	})
+
+	t.Run("run the engine gives up on", func(t *testing.T) {
+		workflow := &models.Workflow{
+			ID: "dead-end-workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "weather-api", Type: models.NodeTypeIntegration},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{{ID: "e1", Source: "start", Target: "weather-api"}},
+		}
+
+		progress := &recordingProgress{}
+		_, err := NewEngine(registry).Execute(WithProgress(context.Background(), progress), workflow, models.WorkflowInput{})
+		assert.Error(t, err)
+		assert.Equal(t, []string{
+			"started running",
+			"step 1 start of 1",
+			"step 2 weather-api of 2",
+			"finished failed",
+		}, progress.events)
+	})
+}
+
+func TestExecuteRecordsErrorSummary(t *testing.T) {
+	failingHook := func(stage models.HookStage) []models.ExecutionHook {
+		return []models.ExecutionHook{{Stage: stage, Type: models.HookTypeWebhook, URL: "https://hooks.test/down", FailExecution: true}}
+	}
+	newEngine := func(weather node.NodeOutputs) *Engine {
+		registry := newTestRegistry()
+		registry.Register(models.NodeTypeCondition, condition.NewNode)
+		registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{"weather-api": weather}))
+		engine := NewEngine(registry)
+		engine.SetHookRunner(&recordingHookRunner{failing: map[string]bool{"https://hooks.test/down": true}})
+		return engine
+	}
+	healthy := node.NodeOutputs{Data: map[string]any{"temperature": 35.0}}
+
+	tests := []struct {
+		name     string
+		weather  node.NodeOutputs
+		hooks    []models.ExecutionHook
+		expected *models.ExecutionErrorSummary
+	}{
+		{
+			name:     "completed run has none",
+			weather:  healthy,
+			expected: nil,
+		},
+		{
+			name:     "integration node failure",
+			weather:  node.NodeOutputs{Data: map[string]any{"error": "Weather API error: status 503"}, Status: models.StatusFailed},
+			expected: &models.ExecutionErrorSummary{NodeID: "weather-api", NodeType: models.NodeTypeIntegration, Message: "Weather API error: status 503"},
+		},
+		{
+			name:     "condition node failure",
+			weather:  node.NodeOutputs{Data: map[string]any{"windspeed": 12.0}},
+			expected: &models.ExecutionErrorSummary{NodeID: "check", NodeType: models.NodeTypeCondition, Message: "Failed to get temperature"},
+		},
+		{
+			name:     "failure without a recorded error",
+			weather:  node.NodeOutputs{Data: map[string]any{}, Status: models.StatusFailed},
+			expected: &models.ExecutionErrorSummary{NodeID: "weather-api", NodeType: models.NodeTypeIntegration, Message: "node failed"},
+		},
+		{
+			name:     "pre hook failure",
+			weather:  healthy,
+			hooks:    failingHook(models.HookStagePre),
+			expected: &models.ExecutionErrorSummary{Message: "pre hook webhook failed: hook unreachable"},
+		},
+		{
+			name:     "post hook failure",
+			weather:  healthy,
+			hooks:    failingHook(models.HookStagePost),
+			expected: &models.ExecutionErrorSummary{Message: "post hook webhook failed: hook unreachable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution, err := newEngine(tt.weather).Execute(context.Background(), newHookWorkflow(tt.hooks), models.WorkflowInput{Operator: models.OperatorGreaterThan})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, execution.ErrorSummary)
+			if tt.expected == nil {
+				assert.Equal(t, models.StatusCompleted, execution.Status)
+			} else {
+				assert.Equal(t, models.StatusFailed, execution.Status)
+			}
+		})
+	}
+
+	t.Run("forced order", func(t *testing.T) {
+		failed := node.NodeOutputs{Data: map[string]any{"error": "Weather API error: status 503"}, Status: models.StatusFailed}
+		execution, err := newEngine(failed).ExecuteSequence(context.Background(), newHookWorkflow(nil), models.WorkflowInput{}, []string{"start", "weather-api", "alert"})
+		assert.NoError(t, err)
+		assert.Equal(t, &models.ExecutionErrorSummary{NodeID: "weather-api", NodeType: models.NodeTypeIntegration, Message: "Weather API error: status 503"}, execution.ErrorSummary)
+	})
+}
+
+func TestCanContinueOnError(t *testing.T) {
+	assert.True(t, CanContinueOnError(models.NodeTypeEmail))
+	assert.True(t, CanContinueOnError(models.NodeTypeForm))
+	assert.False(t, CanContinueOnError(models.NodeTypeIntegration))
+	assert.False(t, CanContinueOnError(models.NodeTypeCondition))
+}
+
+func TestValidateNodes(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeCondition, condition.NewNode)
+	engine := NewEngine(registry)
+
+	valid := []models.Node{
+		{ID: "start", Type: models.NodeTypeStart},
+		{ID: "end", Type: models.NodeTypeEnd},
+	}
+	assert.NoError(t, engine.ValidateNodes(valid))
+
+	err := engine.ValidateNodes([]models.Node{
+		{ID: "start", Type: models.NodeTypeStart},
+		{ID: "mystery", Type: models.NodeType("mystery")},
+		{ID: "check", Type: models.NodeTypeCondition},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "node mystery")
+	assert.Contains(t, err.Error(), "node check")
+	assert.NotContains(t, err.Error(), "node start")
+}
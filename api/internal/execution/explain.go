@@ -0,0 +1,55 @@
+package execution
+
+import (
+	"context"
+	"maps"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// explanationKey is the step output key holding a node's explanation in explained runs
+const explanationKey = "explanation"
+
+// explainContextKey marks a context whose runs record explanations
+type explainContextKey struct{}
+
+// WithExplain returns a context under which the engine records, for each step, a
+// human-readable explanation of what the node did and why
+func WithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainContextKey{}, true)
+}
+
+// explaining reports whether runs under ctx record explanations
+func explaining(ctx context.Context) bool {
+	enabled, _ := ctx.Value(explainContextKey{}).(bool)
+	return enabled
+}
+
+// recordExplanation adds the step's explanation to its output and to the execution's
+// list. Nodes that don't explain themselves fall back to their error when they failed
+// and to their message otherwise; steps with neither are left out.
+func recordExplanation(execution *models.WorkflowExecution, step *models.ExecutionStep, outputs node.NodeOutputs) {
+	explanation := outputs.Explanation
+	if explanation == "" && step.Status == models.StatusFailed {
+		explanation = step.Error
+	}
+	if explanation == "" {
+		explanation, _ = outputs.Data["message"].(string)
+	}
+	if explanation == "" {
+		return
+	}
+
+	// The output map is shared with later nodes' inputs, so the copy in the step gets the key
+	step.Output = maps.Clone(step.Output)
+	if step.Output == nil {
+		step.Output = models.JSONB{}
+	}
+	step.Output[explanationKey] = explanation
+	execution.Explanation = append(execution.Explanation, models.StepExplanation{
+		StepNumber:  step.StepNumber,
+		NodeID:      step.NodeID,
+		NodeType:    step.NodeType,
+		Explanation: explanation,
+	})
+}
@@ -0,0 +1,125 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+	"workflow-code-test/api/pkg/mailer"
+	"workflow-code-test/api/pkg/models"
+)
+
+// hooksKey is the execution metadata key holding the outcome of each hook
+const hooksKey = "hooks"
+
+// hookTimeout bounds each webhook hook request
+const hookTimeout = 10 * time.Second
+
+// HookEvent describes the run a hook is notifying about
+type HookEvent struct {
+	Stage       models.HookStage `json:"stage"`
+	WorkflowID  string           `json:"workflowId"`
+	ExecutionID string           `json:"executionId"`
+	Status      models.Status    `json:"status"` // running for pre hooks, the final status for post hooks
+	TriggeredBy string           `json:"triggeredBy,omitempty"`
+	SLABreached bool             `json:"slaBreached,omitempty"` // post hooks of runs longer than the workflow's maxDurationMs
+}
+
+// HookRunner delivers a workflow's execution hooks
+type HookRunner interface {
+	Run(ctx context.Context, hook models.ExecutionHook, event HookEvent) error
+}
+
+// hookEmailTemplate is sent by email hooks
+var hookEmailTemplate = mailer.EmailTemplate{
+	Subject: "Workflow {{workflowId}} {{stage}}-execution: {{status}}",
+	Body:    "Execution {{executionId}} of workflow {{workflowId}} is {{status}}.",
+}
+
+// defaultHookRunner POSTs webhook hooks as JSON and stub-sends email hooks
+type defaultHookRunner struct {
+	client *http.Client
+}
+
+// Run delivers a single hook
+func (r defaultHookRunner) Run(ctx context.Context, hook models.ExecutionHook, event HookEvent) error {
+	switch hook.Type {
+	case models.HookTypeWebhook:
+		return r.post(ctx, hook.URL, event)
+	case models.HookTypeEmail:
+		_, err := mailer.PrepareAndStubSendEmail(hook.To, map[string]any{
+			"stage":       string(event.Stage),
+			"workflowId":  event.WorkflowID,
+			"executionId": event.ExecutionID,
+			"status":      string(event.Status),
+		}, hookEmailTemplate)
+		return err
+	default:
+		return fmt.Errorf("unsupported hook type: %q", hook.Type)
+	}
+}
+
+// post sends event to url and treats any non-2xx response as a failure
+func (r defaultHookRunner) post(ctx context.Context, url string, event HookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook event: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create hook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("hook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SetHookRunner replaces how the engine delivers execution hooks
+func (e *Engine) SetHookRunner(runner HookRunner) {
+	e.hooks = runner
+}
+
+// runHooks fires the workflow's hooks for stage in order and records each outcome
+// in the execution metadata. Failures are logged; it returns the first failure of
+// a hook marked to fail the execution.
+func (e *Engine) runHooks(ctx context.Context, workflow *models.Workflow, execution *models.WorkflowExecution, stage models.HookStage, logger *slog.Logger) error {
+	var failure error
+	event := HookEvent{
+		Stage:       stage,
+		WorkflowID:  workflow.ID,
+		ExecutionID: execution.ID,
+		Status:      execution.Status,
+	}
+	event.TriggeredBy, _ = execution.Metadata["triggeredBy"].(string)
+	event.SLABreached, _ = execution.Metadata[slaBreachedKey].(bool)
+
+	for _, hook := range workflow.Hooks {
+		if hook.Stage != stage {
+			continue
+		}
+		result := map[string]any{"stage": string(hook.Stage), "type": string(hook.Type), "status": string(models.StatusCompleted)}
+		if err := e.hooks.Run(ctx, hook, event); err != nil {
+			logger.Warn("Execution hook failed", "stage", hook.Stage, "type", hook.Type, "failExecution", hook.FailExecution, "error", err)
+			result["status"] = string(models.StatusFailed)
+			result["error"] = err.Error()
+			if hook.FailExecution && failure == nil {
+				failure = fmt.Errorf("%s hook %s failed: %w", hook.Stage, hook.Type, err)
+			}
+		}
+		results, _ := execution.Metadata[hooksKey].([]map[string]any)
+		execution.Metadata[hooksKey] = append(results, result)
+	}
+	return failure
+}
@@ -0,0 +1,154 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/condition"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingHookRunner records the events it is given and fails hooks to failing URLs
+type recordingHookRunner struct {
+	events  []HookEvent
+	failing map[string]bool
+}
+
+func (r *recordingHookRunner) Run(ctx context.Context, hook models.ExecutionHook, event HookEvent) error {
+	r.events = append(r.events, event)
+	if r.failing[hook.URL] {
+		return errors.New("hook unreachable")
+	}
+	return nil
+}
+
+// newHookWorkflow routes on temperature to one of two end nodes
+func newHookWorkflow(hooks []models.ExecutionHook) *models.Workflow {
+	return &models.Workflow{
+		ID: "hook-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "weather-api", Type: models.NodeTypeIntegration},
+			{ID: "check", Type: models.NodeTypeCondition, Data: models.NodeData{Metadata: map[string]any{
+				"conditionField": "temperature", "threshold": 30.0, "operator": "greater_than",
+			}}},
+			{ID: "alert", Type: models.NodeTypeEnd},
+			{ID: "calm", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "weather-api"},
+			{ID: "e2", Source: "weather-api", Target: "check"},
+			{ID: "e3", Source: "check", Target: "alert", SourceHandle: "true"},
+			{ID: "e4", Source: "check", Target: "calm", SourceHandle: "false"},
+		},
+		Hooks: hooks,
+	}
+}
+
+func newHookEngine(temperature float64, runner HookRunner) *Engine {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeCondition, condition.NewNode)
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": temperature}},
+	}))
+	engine := NewEngine(registry)
+	engine.SetHookRunner(runner)
+	return engine
+}
+
+func TestExecuteHooksFireOnEveryBranch(t *testing.T) {
+	hooks := []models.ExecutionHook{
+		{Stage: models.HookStagePost, Type: models.HookTypeWebhook, URL: "https://hooks.test/post"},
+		{Stage: models.HookStagePre, Type: models.HookTypeWebhook, URL: "https://hooks.test/pre"},
+	}
+
+	for name, temperature := range map[string]float64{"condition met": 35, "condition not met": 20} {
+		t.Run(name, func(t *testing.T) {
+			runner := &recordingHookRunner{}
+			execution, err := newHookEngine(temperature, runner).Execute(context.Background(), newHookWorkflow(hooks), models.WorkflowInput{Name: "Ann"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, execution.Status)
+			assert.Len(t, runner.events, 2)
+			assert.Equal(t, HookEvent{Stage: models.HookStagePre, WorkflowID: "hook-workflow", ExecutionID: execution.ID,
+				Status: models.StatusRunning, TriggeredBy: "Ann"}, runner.events[0])
+			assert.Equal(t, models.HookStagePost, runner.events[1].Stage)
+			assert.Equal(t, models.StatusCompleted, runner.events[1].Status)
+			assert.Len(t, execution.Metadata[hooksKey], 2)
+		})
+	}
+}
+
+func TestExecuteHookFailures(t *testing.T) {
+	failingURL := "https://hooks.test/down"
+	tests := []struct {
+		name           string
+		hook           models.ExecutionHook
+		expectedStatus models.Status
+		expectedSteps  int
+	}{
+		{"failed pre hook is only logged", models.ExecutionHook{Stage: models.HookStagePre, Type: models.HookTypeWebhook, URL: failingURL},
+			models.StatusCompleted, 4},
+		{"failed post hook is only logged", models.ExecutionHook{Stage: models.HookStagePost, Type: models.HookTypeWebhook, URL: failingURL},
+			models.StatusCompleted, 4},
+		{"failed pre hook stops the run", models.ExecutionHook{Stage: models.HookStagePre, Type: models.HookTypeWebhook, URL: failingURL, FailExecution: true},
+			models.StatusFailed, 0},
+		{"failed post hook fails the run", models.ExecutionHook{Stage: models.HookStagePost, Type: models.HookTypeWebhook, URL: failingURL, FailExecution: true},
+			models.StatusFailed, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordingHookRunner{failing: map[string]bool{failingURL: true}}
+			post := models.ExecutionHook{Stage: models.HookStagePost, Type: models.HookTypeEmail, To: "ops@example.com"}
+			workflow := newHookWorkflow([]models.ExecutionHook{tt.hook, post})
+
+			execution, err := newHookEngine(35, runner).Execute(context.Background(), workflow, models.WorkflowInput{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, execution.Status)
+			assert.Len(t, execution.Steps, tt.expectedSteps)
+			// The other post hook still fires and sees the failure a pre hook caused
+			last := runner.events[len(runner.events)-1]
+			assert.Equal(t, models.HookStagePost, last.Stage)
+			if tt.hook.Stage == models.HookStagePre && tt.hook.FailExecution {
+				assert.Equal(t, models.StatusFailed, last.Status)
+			}
+			results := execution.Metadata[hooksKey].([]map[string]any)
+			assert.Equal(t, string(models.StatusFailed), results[0]["status"])
+			assert.Equal(t, "hook unreachable", results[0]["error"])
+		})
+	}
+}
+
+func TestDefaultHookRunner(t *testing.T) {
+	var received HookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	runner := defaultHookRunner{client: server.Client()}
+	event := HookEvent{Stage: models.HookStagePost, WorkflowID: "wf-1", ExecutionID: "exec-1", Status: models.StatusCompleted}
+
+	err := runner.Run(context.Background(), models.ExecutionHook{Type: models.HookTypeWebhook, URL: server.URL + "/ok"}, event)
+	assert.NoError(t, err)
+	assert.Equal(t, event, received)
+
+	err = runner.Run(context.Background(), models.ExecutionHook{Type: models.HookTypeWebhook, URL: server.URL + "/fail"}, event)
+	assert.EqualError(t, err, "hook returned status 500")
+
+	err = runner.Run(context.Background(), models.ExecutionHook{Type: models.HookTypeEmail, To: "ops@example.com"}, event)
+	assert.NoError(t, err)
+}
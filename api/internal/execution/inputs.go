@@ -0,0 +1,77 @@
+package execution
+
+import (
+	"log/slog"
+	"maps"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/condition"
+)
+
+// runState is the state carried between the steps of one run. It builds each
+// node's inputs around the same maps rather than copying them per step, and
+// records what each step leaves behind for the nodes after it.
+type runState struct {
+	workflowID   string
+	input        models.WorkflowInput
+	logger       *slog.Logger
+	priorOutputs map[string]node.NodeOutputs
+	nodeData     map[string]any
+	outputCache  map[string]node.NodeOutputs // completed outputs of cacheable nodes; see execute
+	condition    string                      // latest node to record a condition result
+}
+
+// newRunState starts the shared state for a run
+func newRunState(workflowID string, input models.WorkflowInput, logger *slog.Logger) *runState {
+	return &runState{
+		workflowID:   workflowID,
+		input:        input,
+		logger:       logger,
+		priorOutputs: make(map[string]node.NodeOutputs),
+		nodeData:     make(map[string]any),
+		outputCache:  make(map[string]node.NodeOutputs),
+	}
+}
+
+// inputsFor returns the inputs for the given node
+func (s *runState) inputsFor(nodeID string, current node.Node) node.NodeInputs {
+	return node.NodeInputs{
+		WorkflowID:      s.workflowID,
+		WorkflowInput:   s.input,
+		NodeData:        s.nodeData,
+		PriorOutputs:    s.priorOutputs,
+		ConditionNodeID: s.condition,
+		Logger:          s.logger.With("nodeId", nodeID, "nodeType", current.Type()),
+	}
+}
+
+// record stores a step's outputs for later nodes and merges the values it
+// shared into NodeData when the step completed. A step that recorded a condition
+// result puts the run on that condition's branch.
+func (s *runState) record(nodeID string, outputs node.NodeOutputs, err error) {
+	s.priorOutputs[nodeID] = outputs
+	if _, ok := condition.Result(outputs.Data); ok {
+		s.condition = nodeID
+	}
+	if err != nil || outputs.Status != models.StatusCompleted {
+		return
+	}
+	for key, value := range outputs.Shared {
+		s.nodeData[key] = value
+	}
+}
+
+// fork returns a copy of the state for a branch run alongside others, so the branch
+// reads and records outputs without racing its siblings. The outputs themselves are
+// shared, as nodes only read them.
+func (s *runState) fork() *runState {
+	return &runState{
+		workflowID:   s.workflowID,
+		input:        s.input,
+		logger:       s.logger,
+		priorOutputs: maps.Clone(s.priorOutputs),
+		nodeData:     maps.Clone(s.nodeData),
+		outputCache:  maps.Clone(s.outputCache),
+		condition:    s.condition,
+	}
+}
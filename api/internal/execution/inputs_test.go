@@ -0,0 +1,88 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/condition"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStateRecord(t *testing.T) {
+	state := newRunState("workflow-1", models.WorkflowInput{Name: "tester"}, slog.Default())
+	weatherNode := &stubNode{nodeType: models.NodeTypeIntegration}
+
+	state.record("first", node.NodeOutputs{Status: models.StatusCompleted, Shared: map[string]any{"temperature": 20.0, "windspeed": 5.0}}, nil)
+	state.record("second", node.NodeOutputs{Status: models.StatusCompleted, Shared: map[string]any{"temperature": 25.0}}, nil)
+	state.record("failed", node.NodeOutputs{Status: models.StatusFailed, Shared: map[string]any{"temperature": 99.0}}, nil)
+	state.record("errored", node.NodeOutputs{Status: models.StatusCompleted, Shared: map[string]any{"windspeed": 99.0}}, errors.New("boom"))
+
+	inputs := state.inputsFor("next", weatherNode)
+	assert.Equal(t, "workflow-1", inputs.WorkflowID)
+	assert.Equal(t, "tester", inputs.WorkflowInput.Name)
+	assert.Len(t, inputs.PriorOutputs, 4, "failed steps are still visible as prior outputs")
+
+	// Later completed steps win; failed steps share nothing
+	temperature, ok := inputs.Shared("temperature")
+	assert.True(t, ok)
+	assert.Equal(t, 25.0, temperature)
+	windspeed, _ := inputs.Shared("windspeed")
+	assert.Equal(t, 5.0, windspeed)
+
+	// Inputs are built around the run's maps rather than copies of them
+	state.record("later", node.NodeOutputs{Status: models.StatusCompleted, Shared: map[string]any{"humidity": 40.0}}, nil)
+	_, ok = inputs.Shared("humidity")
+	assert.True(t, ok)
+}
+
+func TestRunStateTracksConditionBranch(t *testing.T) {
+	state := newRunState("workflow-1", models.WorkflowInput{}, slog.Default())
+	emailNode := &stubNode{nodeType: models.NodeTypeEmail}
+	assert.Empty(t, state.inputsFor("email", emailNode).ConditionNodeID, "no condition has run")
+
+	state.record("hot-check", node.NodeOutputs{Status: models.StatusCompleted,
+		Data: map[string]any{"conditionResult": map[string]any{"result": true}}}, nil)
+	state.record("log", node.NodeOutputs{Status: models.StatusCompleted, Data: map[string]any{"message": "logged"}}, nil)
+	assert.Equal(t, "hot-check", state.inputsFor("email", emailNode).ConditionNodeID, "later nodes stay on its branch")
+
+	state.record("wind-check", node.NodeOutputs{Status: models.StatusCompleted,
+		Data: map[string]any{"conditionResult": map[string]any{"result": false}}}, nil)
+	assert.Equal(t, "wind-check", state.inputsFor("email", emailNode).ConditionNodeID)
+	assert.Equal(t, "wind-check", state.fork().inputsFor("email", emailNode).ConditionNodeID)
+}
+
+func TestConditionReadsSharedData(t *testing.T) {
+	// The weather node isn't called "weather-api", so only shared data reaches the condition
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeCondition, condition.NewNode)
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"sydney-weather": {Data: map[string]any{"temperature": 35.0}, Shared: map[string]any{"temperature": 35.0}},
+	}))
+
+	workflow := &models.Workflow{
+		ID: "shared-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "sydney-weather", Type: models.NodeTypeIntegration},
+			{ID: "hot-check", Type: models.NodeTypeCondition},
+			{ID: "alert", Type: models.NodeTypeEnd},
+			{ID: "calm", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "sydney-weather"},
+			{ID: "e2", Source: "sydney-weather", Target: "hot-check"},
+			{ID: "e3", Source: "hot-check", Target: "alert", SourceHandle: "true"},
+			{ID: "e4", Source: "hot-check", Target: "calm", SourceHandle: "false"},
+		},
+	}
+
+	input := models.WorkflowInput{Threshold: 30, Operator: models.OperatorGreaterThan}
+	execution, err := NewEngine(registry).Execute(context.Background(), workflow, input)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+	assert.Equal(t, "alert", execution.Steps[len(execution.Steps)-1].NodeID)
+}
@@ -0,0 +1,43 @@
+package execution
+
+import "workflow-code-test/api/pkg/models"
+
+// kpisKey is the execution metadata key holding the run's KPIs
+const kpisKey = "kpis"
+
+// computeKPIs summarizes a finished execution for dashboards: its total duration,
+// the slowest step, whether an alert email went out and the temperature it acted on
+func computeKPIs(execution *models.WorkflowExecution) map[string]any {
+	kpis := map[string]any{
+		"totalDuration": execution.TotalDuration,
+		"alertSent":     false,
+	}
+
+	var slowest *models.ExecutionStep
+	for i := range execution.Steps {
+		step := &execution.Steps[i]
+		if slowest == nil || step.Duration > slowest.Duration {
+			slowest = step
+		}
+
+		switch step.NodeType {
+		case models.NodeTypeEmail:
+			// Emails skipped because the condition wasn't met have no content
+			if _, sent := step.Output["emailContent"]; sent && step.Status == models.StatusCompleted {
+				kpis["alertSent"] = true
+			}
+		case models.NodeTypeIntegration:
+			if temperature, ok := step.Output[string(models.OutputKeyTemperature)].(float64); ok {
+				if _, seen := kpis["temperature"]; !seen {
+					kpis["temperature"] = temperature
+				}
+			}
+		}
+	}
+	if slowest != nil {
+		kpis["slowestNodeId"] = slowest.NodeID
+		kpis["slowestNodeDuration"] = slowest.Duration
+	}
+
+	return kpis
+}
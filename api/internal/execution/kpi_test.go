@@ -0,0 +1,65 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeKPIs(t *testing.T) {
+	execution := &models.WorkflowExecution{
+		TotalDuration: 420,
+		Steps: []models.ExecutionStep{
+			{NodeID: "start", NodeType: models.NodeTypeStart, Status: models.StatusCompleted, Duration: 1},
+			{NodeID: "weather-api", NodeType: models.NodeTypeIntegration, Status: models.StatusCompleted, Duration: 300,
+				Output: models.JSONB{"temperature": 31.5}},
+			{NodeID: "email", NodeType: models.NodeTypeEmail, Status: models.StatusCompleted, Duration: 80,
+				Output: models.JSONB{"emailContent": map[string]any{"to": "ops@example.com"}}},
+		},
+	}
+
+	kpis := computeKPIs(execution)
+	assert.Equal(t, int64(420), kpis["totalDuration"])
+	assert.Equal(t, true, kpis["alertSent"])
+	assert.Equal(t, 31.5, kpis["temperature"])
+	assert.Equal(t, "weather-api", kpis["slowestNodeId"])
+	assert.Equal(t, int64(300), kpis["slowestNodeDuration"])
+
+	t.Run("skipped email is not an alert", func(t *testing.T) {
+		kpis := computeKPIs(&models.WorkflowExecution{Steps: []models.ExecutionStep{
+			{NodeID: "email", NodeType: models.NodeTypeEmail, Status: models.StatusCompleted, Output: models.JSONB{"message": "skipped"}},
+		}})
+		assert.Equal(t, false, kpis["alertSent"])
+		assert.NotContains(t, kpis, "temperature")
+	})
+}
+
+func TestExecuteRecordsKPIs(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 18.0}},
+	}))
+
+	workflow := &models.Workflow{
+		ID: "kpi-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "weather-api", Type: models.NodeTypeIntegration},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "weather-api"},
+			{ID: "e2", Source: "weather-api", Target: "end"},
+		},
+	}
+
+	execution, err := NewEngine(registry).Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	kpis, ok := execution.Metadata["kpis"].(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, 18.0, kpis["temperature"])
+	assert.Equal(t, false, kpis["alertSent"])
+}
@@ -0,0 +1,153 @@
+package execution
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"workflow-code-test/api/pkg/models"
+)
+
+// durationBuckets are the histogram upper bounds, in seconds, for run and node durations
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Metrics counts the runs an engine has finished and times them and their nodes.
+// It is kept in memory and written in the Prometheus text exposition format.
+type Metrics struct {
+	mu                sync.Mutex
+	executions        map[models.Status]uint64
+	executionDuration histogram
+	nodeDurations     map[models.NodeType]*histogram
+	nodeFailures      map[models.NodeType]uint64
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{
+		executions:        make(map[models.Status]uint64),
+		executionDuration: newHistogram(),
+		nodeDurations:     make(map[models.NodeType]*histogram),
+		nodeFailures:      make(map[models.NodeType]uint64),
+	}
+}
+
+// Metrics returns the engine's execution metrics
+func (e *Engine) Metrics() *Metrics {
+	return e.metrics
+}
+
+// observeExecution counts a finished run by status and records how long it took
+func (m *Metrics) observeExecution(execution *models.WorkflowExecution) {
+	var duration time.Duration
+	if execution.StartedAt != nil && execution.EndedAt != nil {
+		duration = execution.EndedAt.Sub(*execution.StartedAt)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.executions[execution.Status]++
+	m.executionDuration.observe(duration.Seconds())
+}
+
+// observeNode records how long a node took to run and whether it failed
+func (m *Metrics) observeNode(nodeType models.NodeType, duration time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.nodeDurations[nodeType]
+	if !ok {
+		created := newHistogram()
+		h = &created
+		m.nodeDurations[nodeType] = h
+	}
+	h.observe(duration.Seconds())
+	if failed {
+		m.nodeFailures[nodeType]++
+	}
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := bufio.NewWriter(w)
+	writeHeader(out, "workflow_executions_total", "counter", "Workflow executions finished, by final status.")
+	for _, status := range sortedKeys(m.executions) {
+		fmt.Fprintf(out, "workflow_executions_total{status=%s} %d\n", quoteLabel(string(status)), m.executions[status])
+	}
+
+	writeHeader(out, "workflow_execution_duration_seconds", "histogram", "How long workflow executions took to run.")
+	m.executionDuration.write(out, "workflow_execution_duration_seconds", "")
+
+	writeHeader(out, "workflow_node_duration_seconds", "histogram", "How long nodes took to run, by node type.")
+	for _, nodeType := range sortedKeys(m.nodeDurations) {
+		m.nodeDurations[nodeType].write(out, "workflow_node_duration_seconds", "node_type="+quoteLabel(string(nodeType)))
+	}
+
+	writeHeader(out, "workflow_node_failures_total", "counter", "Nodes that failed, by node type.")
+	for _, nodeType := range sortedKeys(m.nodeFailures) {
+		fmt.Fprintf(out, "workflow_node_failures_total{node_type=%s} %d\n", quoteLabel(string(nodeType)), m.nodeFailures[nodeType])
+	}
+	return out.Flush()
+}
+
+// histogram counts observations into durationBuckets
+type histogram struct {
+	buckets []uint64 // observations per bucket, not cumulative
+	count   uint64
+	sum     float64
+}
+
+func newHistogram() histogram {
+	return histogram{buckets: make([]uint64, len(durationBuckets))}
+}
+
+func (h *histogram) observe(value float64) {
+	h.count++
+	h.sum += value
+	if i, _ := slices.BinarySearch(durationBuckets, value); i < len(durationBuckets) {
+		h.buckets[i]++
+	}
+}
+
+// write emits the histogram's cumulative buckets, sum and count, with labels (already
+// formatted as name="value" pairs) added to each sample
+func (h *histogram) write(w io.Writer, name, labels string) {
+	prefix := labels
+	if prefix != "" {
+		prefix += ","
+	}
+	var cumulative uint64
+	for i, bound := range durationBuckets {
+		cumulative += h.buckets[i]
+		fmt.Fprintf(w, "%s_bucket{%sle=\"%s\"} %d\n", name, prefix, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, prefix, h.count)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+}
+
+func writeHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// quoteLabel quotes a label value, escaping what the exposition format requires
+func quoteLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
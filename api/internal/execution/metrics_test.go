@@ -0,0 +1,158 @@
+package execution
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	metricHelp   = regexp.MustCompile(`^# HELP ([a-zA-Z_:][a-zA-Z0-9_:]*) \S.*$`)
+	metricType   = regexp.MustCompile(`^# TYPE ([a-zA-Z_:][a-zA-Z0-9_:]*) (counter|gauge|histogram|summary|untyped)$`)
+	metricSample = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(?:[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\\n]|\\.)*",?)*\})? (\S+)$`)
+)
+
+// parsePrometheus checks text against the Prometheus exposition format: every sample
+// belongs to a family whose TYPE came first, values are numbers, and histogram
+// buckets are cumulative up to a +Inf bucket equal to the count. It returns the
+// samples keyed by name and labels.
+func parsePrometheus(text string) (map[string]float64, error) {
+	samples := make(map[string]float64)
+	types := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		switch {
+		case metricHelp.MatchString(text):
+		case strings.HasPrefix(text, "# TYPE "):
+			match := metricType.FindStringSubmatch(text)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: malformed TYPE: %q", line, text)
+			}
+			if _, seen := types[match[1]]; seen {
+				return nil, fmt.Errorf("line %d: duplicate TYPE for %s", line, match[1])
+			}
+			types[match[1]] = match[2]
+		default:
+			match := metricSample.FindStringSubmatch(text)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: malformed sample: %q", line, text)
+			}
+			value, err := strconv.ParseFloat(match[3], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad value: %w", line, err)
+			}
+			family := match[1]
+			if types[family] == "" {
+				family = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(family, "_bucket"), "_sum"), "_count")
+				if types[family] != "histogram" {
+					return nil, fmt.Errorf("line %d: sample %s has no TYPE", line, match[1])
+				}
+			}
+			samples[match[1]+match[2]] = value
+		}
+	}
+
+	// Buckets never decrease and the +Inf bucket holds every observation
+	for key, count := range samples {
+		name, labels, _ := strings.Cut(key, "{")
+		base, ok := strings.CutSuffix(name, "_count")
+		if !ok || types[base] != "histogram" {
+			continue
+		}
+		prefix := ""
+		if labels != "" {
+			prefix = strings.TrimSuffix(labels, "}") + ","
+		}
+		previous := 0.0
+		for _, bound := range append(formatBounds(), "+Inf") {
+			bucket, ok := samples[fmt.Sprintf(`%s_bucket{%sle="%s"}`, base, prefix, bound)]
+			if !ok {
+				return nil, fmt.Errorf("%s is missing bucket %s", key, bound)
+			}
+			if bucket < previous {
+				return nil, fmt.Errorf("%s bucket %s decreases", key, bound)
+			}
+			previous = bucket
+		}
+		if previous != count {
+			return nil, fmt.Errorf("%s +Inf bucket %v doesn't match count %v", key, previous, count)
+		}
+	}
+	return samples, scanner.Err()
+}
+
+func formatBounds() []string {
+	bounds := make([]string, len(durationBuckets))
+	for i, bound := range durationBuckets {
+		bounds[i] = strconv.FormatFloat(bound, 'g', -1, 64)
+	}
+	return bounds
+}
+
+func TestMetricsWritePrometheus(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 28.5}},
+		"broken-api":  {Data: map[string]any{"error": "Weather API error: status 503"}, Status: models.StatusFailed},
+	}))
+	engine := NewEngine(registry)
+
+	for _, weatherNode := range []string{"weather-api", "weather-api", "broken-api"} {
+		workflow := chainWorkflow(models.Node{ID: weatherNode, Type: models.NodeTypeIntegration})
+		_, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+		assert.NoError(t, err)
+	}
+
+	var out strings.Builder
+	assert.NoError(t, engine.Metrics().WritePrometheus(&out))
+	samples, err := parsePrometheus(out.String())
+	if !assert.NoError(t, err, out.String()) {
+		return
+	}
+
+	assert.Equal(t, 2.0, samples[`workflow_executions_total{status="completed"}`])
+	assert.Equal(t, 1.0, samples[`workflow_executions_total{status="failed"}`])
+	assert.Equal(t, 3.0, samples[`workflow_execution_duration_seconds_count`])
+	assert.Equal(t, 3.0, samples[`workflow_execution_duration_seconds_bucket{le="+Inf"}`])
+	assert.Equal(t, 3.0, samples[`workflow_node_duration_seconds_count{node_type="start"}`])
+	assert.Equal(t, 3.0, samples[`workflow_node_duration_seconds_count{node_type="integration"}`])
+	assert.Equal(t, 2.0, samples[`workflow_node_duration_seconds_count{node_type="end"}`])
+	assert.Equal(t, 1.0, samples[`workflow_node_failures_total{node_type="integration"}`])
+	assert.NotContains(t, samples, `workflow_node_failures_total{node_type="start"}`)
+}
+
+func TestMetricsWritePrometheusWhenEmpty(t *testing.T) {
+	var out strings.Builder
+	assert.NoError(t, newMetrics().WritePrometheus(&out))
+	samples, err := parsePrometheus(out.String())
+	assert.NoError(t, err, out.String())
+	assert.Equal(t, 0.0, samples["workflow_execution_duration_seconds_count"])
+	assert.Contains(t, out.String(), "# TYPE workflow_node_duration_seconds histogram\n")
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	h := newHistogram()
+	for _, d := range []time.Duration{time.Millisecond, 10 * time.Millisecond, 700 * time.Millisecond, 2 * time.Minute} {
+		h.observe(d.Seconds())
+	}
+
+	var out strings.Builder
+	h.write(&out, "test_seconds", `node_type="a\"b"`)
+	assert.Contains(t, out.String(), `test_seconds_bucket{node_type="a\"b",le="0.005"} 1`+"\n")
+	assert.Contains(t, out.String(), `test_seconds_bucket{node_type="a\"b",le="0.01"} 2`+"\n")
+	assert.Contains(t, out.String(), `test_seconds_bucket{node_type="a\"b",le="1"} 3`+"\n")
+	assert.Contains(t, out.String(), `test_seconds_bucket{node_type="a\"b",le="60"} 3`+"\n")
+	assert.Contains(t, out.String(), `test_seconds_bucket{node_type="a\"b",le="+Inf"} 4`+"\n")
+	assert.Contains(t, out.String(), `test_seconds_count{node_type="a\"b"} 4`+"\n")
+	assert.Equal(t, `"a\\b\"c\n"`, quoteLabel("a\\b\"c\n"))
+}
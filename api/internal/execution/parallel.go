@@ -0,0 +1,161 @@
+package execution
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// parallelBranchesKey is the node metadata flag that runs the branches of the node's
+// unlabelled edges side by side rather than following only the highest-priority one
+const parallelBranchesKey = "parallelBranches"
+
+// fanOut is a node's branches that can run side by side. Each branch is the chain
+// of nodes from one of its unlabelled edges, highest priority first, up to the join
+// where the branches meet.
+type fanOut struct {
+	branches [][]string
+	join     string
+}
+
+// branchRun is one node run of a fan-out branch, kept until the branches have all
+// finished so it can be recorded in order
+type branchRun struct {
+	nodeID   string
+	outputs  node.NodeOutputs
+	err      error
+	duration time.Duration
+}
+
+// parallelNodes returns the IDs of nodes whose branches run side by side. The flag
+// is ignored, with a warning, on condition nodes, which take a single route.
+func parallelNodes(workflow *models.Workflow, logger *slog.Logger) map[string]bool {
+	nodes := make(map[string]bool)
+	for _, n := range workflow.Nodes {
+		if enabled, _ := n.Data.Metadata[parallelBranchesKey].(bool); !enabled {
+			continue
+		}
+		if n.Type == models.NodeTypeCondition {
+			logger.Warn("Ignoring parallelBranches on a condition node", "nodeId", n.ID)
+			continue
+		}
+		nodes[n.ID] = true
+	}
+	return nodes
+}
+
+// parallelFanOut works out from the edge graph whether the branches leaving nodeID
+// are independent: each must be a chain of nodes with a single unlabelled edge, and
+// the chains must meet at one join without sharing any node before it. A node shared
+// by two branches would otherwise run twice, and a condition or a further fan-out
+// inside a branch could route it away from the join.
+func parallelFanOut(nodeID string, nodes map[string]node.Node, defaults map[string][]string) (fanOut, bool) {
+	targets := defaults[nodeID]
+	if len(targets) < 2 {
+		return fanOut{}, false
+	}
+
+	paths := make([][]string, 0, len(targets))
+	for _, target := range targets {
+		path, ok := branchPath(nodeID, target, nodes, defaults)
+		if !ok {
+			return fanOut{}, false
+		}
+		paths = append(paths, path)
+	}
+
+	// The join is the first node of the first branch that every branch reaches
+	var join string
+	for _, candidate := range paths[0] {
+		reached := true
+		for _, path := range paths[1:] {
+			if !slices.Contains(path, candidate) {
+				reached = false
+				break
+			}
+		}
+		if reached {
+			join = candidate
+			break
+		}
+	}
+	if join == "" {
+		return fanOut{}, false
+	}
+
+	fan := fanOut{join: join}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		branch := path[:slices.Index(path, join)]
+		for _, id := range branch {
+			if seen[id] {
+				return fanOut{}, false
+			}
+			seen[id] = true
+		}
+		fan.branches = append(fan.branches, branch)
+	}
+	return fan, true
+}
+
+// branchPath follows the chain of nodes from target, the start of a branch leaving
+// source. It ends at, and includes, the first node that doesn't simply lead on to one
+// other: an end or condition node, or one with several unlabelled edges or none.
+// Chains that loop back on themselves or to source aren't branches.
+func branchPath(source, target string, nodes map[string]node.Node, defaults map[string][]string) ([]string, bool) {
+	visited := map[string]bool{source: true}
+	var path []string
+	for nodeID := target; ; nodeID = defaults[nodeID][0] {
+		n := nodes[nodeID]
+		if n == nil || visited[nodeID] {
+			return nil, false
+		}
+		visited[nodeID] = true
+		path = append(path, nodeID)
+
+		if n.Type() == models.NodeTypeEnd || n.Type() == models.NodeTypeCondition || len(defaults[nodeID]) != 1 {
+			return path, true
+		}
+	}
+}
+
+// runBranches runs each branch of the fan-out in its own goroutine, on a fork of the
+// run's state so no branch sees or races another's outputs. A branch stops at a node
+// whose failure would stop the run; the others carry on to the join. The runs are
+// returned per branch, in the fan-out's order.
+func (e *Engine) runBranches(
+	ctx context.Context,
+	state *runState,
+	nodes map[string]node.Node,
+	fan fanOut,
+	timeouts map[string]time.Duration,
+	continueOnError map[string]bool,
+) [][]branchRun {
+	runs := make([][]branchRun, len(fan.branches))
+	var wg sync.WaitGroup
+	for i, branch := range fan.branches {
+		wg.Add(1)
+		go func(branchState *runState) {
+			defer wg.Done()
+			for _, nodeID := range branch {
+				nodeCtx, cancel := withNodeTimeout(ctx, timeouts, nodeID)
+				nodeStarted := time.Now()
+				outputs, err := branchState.execute(nodeCtx, nodeID, nodes[nodeID])
+				nodeDuration := time.Since(nodeStarted)
+				cancel()
+
+				runs[i] = append(runs[i], branchRun{nodeID: nodeID, outputs: outputs, err: err, duration: nodeDuration})
+				branchState.record(nodeID, outputs, err)
+				if (err != nil || outputs.Status == models.StatusFailed) && !continueOnError[nodeID] {
+					return
+				}
+			}
+		}(state.fork())
+	}
+	wg.Wait()
+	return runs
+}
@@ -0,0 +1,240 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rendezvousNode only completes once every node sharing its WaitGroup has started,
+// so a run completes it only when those nodes run side by side
+type rendezvousNode struct {
+	node.BaseNode
+	arrived *sync.WaitGroup
+	fail    bool
+}
+
+func (n *rendezvousNode) Type() models.NodeType { return models.NodeTypeEmail }
+
+func (n *rendezvousNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *rendezvousNode) Validate() error { return nil }
+
+func (n *rendezvousNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	n.arrived.Done()
+	met := make(chan struct{})
+	go func() {
+		n.arrived.Wait()
+		close(met)
+	}()
+
+	select {
+	case <-met:
+	case <-time.After(2 * time.Second):
+		return node.NodeOutputs{Data: map[string]any{"error": "ran alone"}, Status: models.StatusFailed}, errors.New("ran alone")
+	}
+	if n.fail {
+		return node.NodeOutputs{Data: map[string]any{"error": "delivery failed"}, Status: models.StatusFailed}, errors.New("delivery failed")
+	}
+	return node.NodeOutputs{
+		Data:   map[string]any{"sent": n.ID},
+		Status: models.StatusCompleted,
+		Shared: map[string]any{n.ID: true},
+	}, nil
+}
+
+// fanOutWorkflow fans out from form to an email branch, which goes on through an
+// audit node, and a webhook branch, which join at a checkpoint before the end
+func fanOutWorkflow() *models.Workflow {
+	return &models.Workflow{
+		ID: "fan-out-workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm, Data: models.NodeData{Metadata: map[string]any{"parallelBranches": true}}},
+			{ID: "email", Type: models.NodeTypeEmail},
+			{ID: "audit", Type: models.NodeTypeIntegration},
+			{ID: "webhook", Type: models.NodeTypeEmail},
+			{ID: "summary", Type: models.NodeTypeCheckpoint},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "webhook", Priority: 1},
+			{ID: "e3", Source: "form", Target: "email", Priority: 2},
+			{ID: "e4", Source: "email", Target: "audit"},
+			{ID: "e5", Source: "audit", Target: "summary"},
+			{ID: "e6", Source: "webhook", Target: "summary"},
+			{ID: "e7", Source: "summary", Target: "end"},
+		},
+	}
+}
+
+// fanOutRegistry registers the nodes of fanOutWorkflow, recording the inputs the
+// join ran with. The branch nodes wait for each other on arrived.
+func fanOutRegistry(arrived *sync.WaitGroup, failing string, summaryRuns *[]node.NodeInputs) *node.Registry {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeForm, newStubFactory(models.NodeTypeForm, map[string]node.NodeOutputs{
+		"form": {Data: map[string]any{"city": "Sydney"}, Shared: map[string]any{"city": "Sydney"}},
+	}))
+	registry.Register(models.NodeTypeIntegration, newStubFactory(models.NodeTypeIntegration, map[string]node.NodeOutputs{
+		"audit": {Data: map[string]any{"audited": true}},
+	}))
+	registry.Register(models.NodeTypeEmail, func(model models.Node) (node.Node, error) {
+		return &rendezvousNode{BaseNode: node.BaseNode{ID: model.ID}, arrived: arrived, fail: model.ID == failing}, nil
+	})
+	registry.Register(models.NodeTypeCheckpoint, func(model models.Node) (node.Node, error) {
+		return &inputsNode{
+			BaseNode: node.BaseNode{ID: model.ID},
+			nodeType: models.NodeTypeCheckpoint,
+			outputs:  node.NodeOutputs{Data: map[string]any{}, Status: models.StatusCompleted},
+			runs:     summaryRuns,
+		}, nil
+	})
+	return registry
+}
+
+func stepNodeIDs(execution *models.WorkflowExecution) []string {
+	var nodeIDs []string
+	for _, step := range execution.Steps {
+		nodeIDs = append(nodeIDs, step.NodeID)
+	}
+	return nodeIDs
+}
+
+func TestExecuteRunsParallelBranchesConcurrently(t *testing.T) {
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	var summaryRuns []node.NodeInputs
+	engine := NewEngine(fanOutRegistry(&arrived, "", &summaryRuns))
+
+	execution, err := engine.Execute(context.Background(), fanOutWorkflow(), models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+
+	// Branch steps are recorded whole, highest-priority branch first, whichever finished first
+	assert.Equal(t, []string{"start", "form", "email", "audit", "webhook", "summary", "end"}, stepNodeIDs(execution))
+	for i, step := range execution.Steps {
+		assert.Equal(t, i+1, step.StepNumber)
+	}
+
+	// The join sees what every branch left behind
+	if assert.Len(t, summaryRuns, 1) {
+		inputs := summaryRuns[0]
+		assert.Equal(t, "Sydney", inputs.NodeData["city"])
+		assert.Equal(t, true, inputs.NodeData["email"])
+		assert.Equal(t, true, inputs.NodeData["webhook"])
+		assert.Equal(t, true, inputs.PriorOutputs["audit"].Data["audited"])
+		assert.Equal(t, "webhook", inputs.PriorOutputs["webhook"].Data["sent"])
+	}
+}
+
+func TestExecuteParallelBranchFailure(t *testing.T) {
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	var summaryRuns []node.NodeInputs
+	engine := NewEngine(fanOutRegistry(&arrived, "email", &summaryRuns))
+
+	execution, err := engine.Execute(context.Background(), fanOutWorkflow(), models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, execution.Status)
+	if assert.NotNil(t, execution.ErrorSummary) {
+		assert.Equal(t, "email", execution.ErrorSummary.NodeID)
+	}
+
+	// The failed branch stops, its sibling still finishes, and the run doesn't reach the join
+	assert.Equal(t, []string{"start", "form", "email", "webhook"}, stepNodeIDs(execution))
+	assert.Empty(t, summaryRuns)
+	assert.Equal(t, map[string]any{"city": "Sydney", "webhook": true}, execution.Metadata[sharedDataKey])
+}
+
+func TestExecuteFallsBackToPriorityForDependentBranches(t *testing.T) {
+	workflow := fanOutWorkflow()
+	// The webhook branch no longer joins the email branch
+	workflow.Edges = slices.Delete(workflow.Edges, 5, 6)
+	var summaryRuns []node.NodeInputs
+	var arrived sync.WaitGroup
+	arrived.Add(1)
+	engine := NewEngine(fanOutRegistry(&arrived, "", &summaryRuns))
+
+	execution, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+	assert.Equal(t, []string{"start", "form", "email", "audit", "summary", "end"}, stepNodeIDs(execution))
+}
+
+func TestParallelFanOut(t *testing.T) {
+	registry := fanOutRegistry(nil, "", nil)
+	engine := NewEngine(registry)
+
+	tests := []struct {
+		name     string
+		change   func(*models.Workflow)
+		expected fanOut
+		ok       bool
+	}{
+		{
+			name:     "independent branches",
+			change:   func(*models.Workflow) {},
+			expected: fanOut{branches: [][]string{{"email", "audit"}, {"webhook"}}, join: "summary"},
+			ok:       true,
+		},
+		{
+			name:     "branches meeting at the end node",
+			change:   func(w *models.Workflow) { w.Edges[4].Target, w.Edges[5].Target = "end", "end" },
+			expected: fanOut{branches: [][]string{{"email", "audit"}, {"webhook"}}, join: "end"},
+			ok:       true,
+		},
+		{
+			name:     "branches meeting within another",
+			change:   func(w *models.Workflow) { w.Edges[5].Target = "audit" },
+			expected: fanOut{branches: [][]string{{"email"}, {"webhook"}}, join: "audit"},
+			ok:       true,
+		},
+		{
+			name: "branches sharing a node before the join",
+			change: func(w *models.Workflow) {
+				w.Edges = append(w.Edges, models.Edge{ID: "e8", Source: "form", Target: "audit"})
+			},
+			ok: false,
+		},
+		{
+			name:   "branch that doesn't join",
+			change: func(w *models.Workflow) { w.Edges = slices.Delete(w.Edges, 5, 6) },
+			ok:     false,
+		},
+		{
+			name:   "branch looping back to the fan-out",
+			change: func(w *models.Workflow) { w.Edges[5].Target = "form" },
+			ok:     false,
+		},
+		{
+			name: "single branch",
+			change: func(w *models.Workflow) {
+				w.Edges = append(w.Edges[:1], w.Edges[2:]...)
+			},
+			ok: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflow := fanOutWorkflow()
+			tt.change(workflow)
+			nodes, _, defaults, _, err := engine.initializeWorkflow(workflow)
+			assert.NoError(t, err)
+
+			fan, ok := parallelFanOut("form", nodes, defaults)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, fan)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package execution
+
+import (
+	"context"
+	"workflow-code-test/api/pkg/models"
+)
+
+// Progress is told about a run while it happens, so it can be stored and read back
+// before the run finishes. The engine calls it from the run's own goroutine.
+type Progress interface {
+	// Started is called once the workflow's nodes are built, before any hook or node runs
+	Started(ctx context.Context, execution *models.WorkflowExecution)
+	// StepCompleted is called after each step is recorded on execution
+	StepCompleted(ctx context.Context, execution *models.WorkflowExecution, step models.ExecutionStep)
+	// Finished is called with the final status, including when the engine gives up
+	// on the run with an error
+	Finished(ctx context.Context, execution *models.WorkflowExecution)
+}
+
+// progressContextKey holds the Progress of runs under a context
+type progressContextKey struct{}
+
+// WithProgress returns a context under which Execute reports its run to progress
+func WithProgress(ctx context.Context, progress Progress) context.Context {
+	return context.WithValue(ctx, progressContextKey{}, progress)
+}
+
+// progressFrom returns the Progress of runs under ctx, or one that ignores them
+func progressFrom(ctx context.Context) Progress {
+	if progress, ok := ctx.Value(progressContextKey{}).(Progress); ok && progress != nil {
+		return progress
+	}
+	return noProgress{}
+}
+
+type noProgress struct{}
+
+func (noProgress) Started(context.Context, *models.WorkflowExecution)                             {}
+func (noProgress) StepCompleted(context.Context, *models.WorkflowExecution, models.ExecutionStep) {}
+func (noProgress) Finished(context.Context, *models.WorkflowExecution)                            {}
@@ -0,0 +1,126 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// sharedDataKey is the execution metadata key under which a run that failed at a node
+// keeps the values its completed steps shared, so resuming it can hand them on
+const sharedDataKey = "sharedData"
+
+// resumedFromKey is the execution metadata key naming the failed execution a run resumed
+const resumedFromKey = "resumedFrom"
+
+// ErrNotResumable is returned for an execution that didn't fail at a node
+var ErrNotResumable = errors.New("execution cannot be resumed")
+
+// Resume runs a failed execution again as a new execution, starting at the node it
+// failed at. The steps before that node are carried over rather than run again:
+// their stored outputs are the prior outputs the remaining nodes see, with input's
+// email in place of the form's masked one, and the values they shared are restored
+// from the failed run's metadata. Hooks fire as for any run.
+func (e *Engine) Resume(ctx context.Context, workflow *models.Workflow, input models.WorkflowInput, previous *models.WorkflowExecution) (*models.WorkflowExecution, error) {
+	failedNodeID, carried, err := resumePoint(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, edges, defaults, _, err := e.initializeWorkflow(workflow)
+	if err != nil {
+		return nil, err
+	}
+	if nodes[failedNodeID] == nil {
+		return nil, fmt.Errorf("%w: node %s is no longer in the workflow", ErrNotResumable, failedNodeID)
+	}
+
+	execution := newExecution(workflow, input)
+	execution.Metadata[resumedFromKey] = previous.ID
+	executionLogger := slog.Default().With("workflowId", workflow.ID, "executionId", execution.ID, "resumedFrom", previous.ID)
+
+	state := newRunState(workflow.ID, input, executionLogger)
+	if shared, ok := previous.Metadata[sharedDataKey].(map[string]any); ok {
+		maps.Copy(state.nodeData, shared)
+	}
+	for _, step := range carried {
+		step.ExecutionID = ""
+		if step.NodeType == models.NodeTypeForm {
+			step.Output = withEmail(step.Output, input.Email)
+		}
+		state.record(step.NodeID, node.NodeOutputs{Data: models.UpgradeOutput(step.NodeType, step.Output), Status: step.Status}, nil)
+		execution.Steps = append(execution.Steps, step)
+	}
+	for _, key := range []string{"continuedAfterFailure", "suppressedNodes"} {
+		if nodeIDs := stringList(previous.Metadata[key]); len(nodeIDs) > 0 {
+			execution.Metadata[key] = nodeIDs
+		}
+	}
+
+	return e.run(ctx, workflow, execution, state, nodes, edges, defaults, failedNodeID, executionLogger)
+}
+
+// resumePoint returns the node a failed execution stopped at and the steps before it.
+// Only a run whose last step failed can be resumed; one that failed in a hook or
+// between nodes has no node to resume from.
+func resumePoint(previous *models.WorkflowExecution) (string, []models.ExecutionStep, error) {
+	if previous.Status != models.StatusFailed {
+		return "", nil, fmt.Errorf("%w: execution is %s, not failed", ErrNotResumable, previous.Status)
+	}
+	if len(previous.Steps) == 0 {
+		return "", nil, fmt.Errorf("%w: execution has no steps", ErrNotResumable)
+	}
+	last := previous.Steps[len(previous.Steps)-1]
+	if last.Status != models.StatusFailed || last.NodeID == "" {
+		return "", nil, fmt.Errorf("%w: execution did not fail at a node", ErrNotResumable)
+	}
+	return last.NodeID, previous.Steps[:len(previous.Steps)-1], nil
+}
+
+// withEmail returns a carried form step's output with email in place of the stored
+// address, which is masked, so later nodes such as email nodes send to the address
+// the resumed run was given. output isn't modified.
+func withEmail(output models.JSONB, email string) models.JSONB {
+	if email == "" || output == nil {
+		return output
+	}
+	output = maps.Clone(output)
+	if _, ok := output["email"]; ok {
+		output["email"] = email
+	}
+	if formData, ok := output["formData"].(map[string]any); ok {
+		formData = maps.Clone(formData)
+		formData["email"] = email
+		output["formData"] = formData
+	}
+	return output
+}
+
+// keepSharedData records the values shared so far on a run about to fail at a node
+func keepSharedData(execution *models.WorkflowExecution, state *runState) {
+	if len(state.nodeData) > 0 {
+		execution.Metadata[sharedDataKey] = maps.Clone(state.nodeData)
+	}
+}
+
+// stringList reads a list of strings from execution metadata, whether it was set by
+// the engine or decoded from storage
+func stringList(value any) []string {
+	switch list := value.(type) {
+	case []string:
+		return append([]string(nil), list...)
+	case []any:
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
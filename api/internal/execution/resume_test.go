@@ -0,0 +1,194 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyWeatherNode fails its first run, as an unavailable weather service would, and
+// shares a temperature on every run after that
+type flakyWeatherNode struct {
+	node.BaseNode
+	calls *int
+}
+
+func (n *flakyWeatherNode) Type() models.NodeType { return models.NodeTypeIntegration }
+
+func (n *flakyWeatherNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *flakyWeatherNode) Validate() error { return nil }
+
+func (n *flakyWeatherNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	*n.calls++
+	if *n.calls == 1 {
+		return node.NodeOutputs{Data: map[string]any{"message": "weather service unavailable"}, Status: models.StatusFailed},
+			errors.New("weather service unavailable")
+	}
+	return node.NodeOutputs{
+		Data:   map[string]any{"temperature": 31.0},
+		Status: models.StatusCompleted,
+		Shared: map[string]any{"temperature": 31.0},
+	}, nil
+}
+
+// inputsNode records the inputs it ran with
+type inputsNode struct {
+	node.BaseNode
+	nodeType models.NodeType
+	outputs  node.NodeOutputs
+	runs     *[]node.NodeInputs
+}
+
+func (n *inputsNode) Type() models.NodeType { return n.nodeType }
+
+func (n *inputsNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *inputsNode) Validate() error { return nil }
+
+func (n *inputsNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	*n.runs = append(*n.runs, inputs)
+	return n.outputs, nil
+}
+
+func TestResumePastFailedIntegrationNode(t *testing.T) {
+	var weatherCalls int
+	var formRuns, emailRuns []node.NodeInputs
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeForm, func(model models.Node) (node.Node, error) {
+		return &inputsNode{
+			BaseNode: node.BaseNode{ID: model.ID},
+			nodeType: models.NodeTypeForm,
+			outputs: node.NodeOutputs{
+				Data:   map[string]any{"city": "Sydney"},
+				Status: models.StatusCompleted,
+				Shared: map[string]any{"city": "Sydney"},
+			},
+			runs: &formRuns,
+		}, nil
+	})
+	registry.Register(models.NodeTypeIntegration, func(model models.Node) (node.Node, error) {
+		return &flakyWeatherNode{BaseNode: node.BaseNode{ID: model.ID}, calls: &weatherCalls}, nil
+	})
+	registry.Register(models.NodeTypeEmail, func(model models.Node) (node.Node, error) {
+		return &inputsNode{
+			BaseNode: node.BaseNode{ID: model.ID},
+			nodeType: models.NodeTypeEmail,
+			outputs:  node.NodeOutputs{Data: map[string]any{"message": "sent"}, Status: models.StatusCompleted},
+			runs:     &emailRuns,
+		}, nil
+	})
+	workflow := chainWorkflow(
+		models.Node{ID: "form", Type: models.NodeTypeForm},
+		models.Node{ID: "weather-api", Type: models.NodeTypeIntegration},
+		models.Node{ID: "email", Type: models.NodeTypeEmail},
+	)
+	engine := NewEngine(registry)
+
+	failed, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+	assert.Len(t, failed.Steps, 3)
+	assert.Equal(t, "weather-api", failed.ErrorSummary.NodeID)
+	assert.Equal(t, map[string]any{"city": "Sydney"}, failed.Metadata[sharedDataKey])
+
+	// Metadata goes through storage as JSON
+	encoded, err := json.Marshal(failed.Metadata)
+	assert.NoError(t, err)
+	failed.Metadata = models.JSONB{}
+	assert.NoError(t, json.Unmarshal(encoded, &failed.Metadata))
+
+	resumed, err := engine.Resume(context.Background(), workflow, models.WorkflowInput{}, failed)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, resumed) {
+		return
+	}
+	assert.NotEqual(t, failed.ID, resumed.ID)
+	assert.Equal(t, models.StatusCompleted, resumed.Status)
+	assert.Nil(t, resumed.ErrorSummary)
+	assert.Equal(t, failed.ID, resumed.Metadata[resumedFromKey])
+
+	// The steps before the failed node are carried over, not run again
+	var nodeIDs []string
+	for i, step := range resumed.Steps {
+		nodeIDs = append(nodeIDs, step.NodeID)
+		assert.Equal(t, i+1, step.StepNumber)
+	}
+	assert.Equal(t, []string{"start", "form", "weather-api", "email", "end"}, nodeIDs)
+	assert.Len(t, formRuns, 1)
+	assert.Equal(t, 2, weatherCalls)
+	assert.Equal(t, models.StatusCompleted, resumed.Steps[2].Status)
+
+	// Nodes after the resumed one see the carried outputs and shared values
+	if assert.Len(t, emailRuns, 1) {
+		inputs := emailRuns[0]
+		assert.Equal(t, "Sydney", inputs.NodeData["city"])
+		assert.Equal(t, 31.0, inputs.NodeData["temperature"])
+		assert.Equal(t, "Sydney", inputs.PriorOutputs["form"].Data["city"])
+		assert.Equal(t, 31.0, inputs.PriorOutputs["weather-api"].Data["temperature"])
+	}
+}
+
+func TestResumeCarriesOverContinuedFailures(t *testing.T) {
+	var weatherCalls int
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeEmail, newStubFactory(models.NodeTypeEmail, map[string]node.NodeOutputs{
+		"webhook": {Data: map[string]any{"error": "webhook unreachable"}, Status: models.StatusFailed},
+	}))
+	registry.Register(models.NodeTypeIntegration, func(model models.Node) (node.Node, error) {
+		return &flakyWeatherNode{BaseNode: node.BaseNode{ID: model.ID}, calls: &weatherCalls}, nil
+	})
+	workflow := chainWorkflow(
+		models.Node{ID: "webhook", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: map[string]any{"continueOnError": true}}},
+		models.Node{ID: "weather-api", Type: models.NodeTypeIntegration},
+	)
+	engine := NewEngine(registry)
+
+	failed, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+
+	resumed, err := engine.Resume(context.Background(), workflow, models.WorkflowInput{}, failed)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, resumed) {
+		return
+	}
+	// The earlier setback still makes the resumed run partial
+	assert.Equal(t, models.StatusPartial, resumed.Status)
+	assert.Equal(t, []string{"webhook"}, resumed.Metadata["continuedAfterFailure"])
+}
+
+func TestResumeRejectsExecutionsWithoutFailedNode(t *testing.T) {
+	workflow := chainWorkflow()
+	engine := NewEngine(newTestRegistry())
+
+	completed, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		previous *models.WorkflowExecution
+	}{
+		{"completed run", completed},
+		{"failed before any node", &models.WorkflowExecution{ID: "failed-hook", Status: models.StatusFailed}},
+		{"failed after its last step", &models.WorkflowExecution{ID: "failed-post-hook", Status: models.StatusFailed, Steps: completed.Steps}},
+		{"failed node no longer in the workflow", &models.WorkflowExecution{
+			ID:     "removed-node",
+			Status: models.StatusFailed,
+			Steps:  []models.ExecutionStep{{NodeID: "weather-api", StepNumber: 1, Status: models.StatusFailed}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resumed, err := engine.Resume(context.Background(), workflow, models.WorkflowInput{}, tt.previous)
+			assert.ErrorIs(t, err, ErrNotResumable)
+			assert.Nil(t, resumed)
+		})
+	}
+}
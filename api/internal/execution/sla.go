@@ -0,0 +1,17 @@
+package execution
+
+import "workflow-code-test/api/pkg/models"
+
+// slaBreachedKey is the execution metadata key flagging whether a run took longer
+// than its workflow's maxDurationMs
+const slaBreachedKey = "slaBreached"
+
+// recordSLA flags a finished run by whether its total duration exceeded the
+// workflow's expected maximum; a run taking exactly that long is within it. Runs of
+// workflows that declare none are left unflagged.
+func recordSLA(workflow *models.Workflow, execution *models.WorkflowExecution) {
+	if workflow.MaxDurationMs == nil {
+		return
+	}
+	execution.Metadata[slaBreachedKey] = execution.TotalDuration > *workflow.MaxDurationMs
+}
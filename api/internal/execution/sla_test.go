@@ -0,0 +1,90 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowNode takes at least its delay to run
+type slowNode struct {
+	node.BaseNode
+	delay time.Duration
+}
+
+func (n *slowNode) Type() models.NodeType { return models.NodeTypeForm }
+
+func (n *slowNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *slowNode) Validate() error { return nil }
+
+func (n *slowNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	time.Sleep(n.delay)
+	return node.NodeOutputs{Data: map[string]any{}, Status: models.StatusCompleted}, nil
+}
+
+func TestRecordSLA(t *testing.T) {
+	maxDuration := int64(1000)
+
+	tests := []struct {
+		name          string
+		maxDurationMs *int64
+		totalDuration int64
+		expected      any
+	}{
+		{"well within", &maxDuration, 10, false},
+		{"just under", &maxDuration, 999, false},
+		{"exactly at the limit", &maxDuration, 1000, false},
+		{"just over", &maxDuration, 1001, true},
+		{"no limit declared", nil, 60000, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution := &models.WorkflowExecution{TotalDuration: tt.totalDuration, Metadata: models.JSONB{}}
+			recordSLA(&models.Workflow{MaxDurationMs: tt.maxDurationMs}, execution)
+			assert.Equal(t, tt.expected, execution.Metadata[slaBreachedKey])
+		})
+	}
+}
+
+func TestExecuteFlagsSlowRuns(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(models.NodeTypeForm, func(model models.Node) (node.Node, error) {
+		return &slowNode{BaseNode: node.BaseNode{ID: model.ID}, delay: 5 * time.Millisecond}, nil
+	})
+
+	tests := []struct {
+		name          string
+		maxDurationMs int64
+		breached      bool
+	}{
+		{"slower than declared", 1, true},
+		{"within declared", time.Hour.Milliseconds(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordingHookRunner{}
+			engine := NewEngine(registry)
+			engine.SetHookRunner(runner)
+			workflow := chainWorkflow(models.Node{ID: "form", Type: models.NodeTypeForm})
+			workflow.MaxDurationMs = &tt.maxDurationMs
+			workflow.Hooks = []models.ExecutionHook{{Stage: models.HookStagePost, Type: models.HookTypeWebhook, URL: "https://ops.example.com/runs"}}
+
+			execution, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, execution.Status)
+			assert.Equal(t, tt.breached, execution.Metadata[slaBreachedKey])
+
+			// Post hooks can alert on slow runs
+			if assert.Len(t, runner.events, 1) {
+				assert.Equal(t, tt.breached, runner.events[0].SLABreached)
+			}
+		})
+	}
+}
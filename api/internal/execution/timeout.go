@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"workflow-code-test/api/pkg/models"
+)
+
+// timeoutMsKey is the node metadata setting that bounds how long the node may run,
+// in milliseconds. Zero lifts its type's default.
+const timeoutMsKey = "timeoutMs"
+
+// DefaultNodeTimeouts returns the timeout a node of each type gets when it doesn't
+// set timeoutMs. Nodes that call out over the network get a generous budget; the
+// rest finish instantly and get none beyond the request's own deadline.
+func DefaultNodeTimeouts() map[models.NodeType]time.Duration {
+	return map[models.NodeType]time.Duration{
+		models.NodeTypeIntegration: 30 * time.Second,
+		models.NodeTypeEmail:       30 * time.Second,
+	}
+}
+
+// SetNodeTimeouts replaces the per-type default node timeouts. Types left out, and
+// zero or negative durations, get no default.
+func (e *Engine) SetNodeTimeouts(timeouts map[models.NodeType]time.Duration) {
+	e.nodeTimeouts = make(map[models.NodeType]time.Duration, len(timeouts))
+	for nodeType, timeout := range timeouts {
+		if timeout > 0 {
+			e.nodeTimeouts[nodeType] = timeout
+		}
+	}
+}
+
+// timeoutsFor returns how long each node of the workflow may run: its own timeoutMs
+// when set, otherwise its type's default. Nodes without a limit are left out. An
+// invalid timeoutMs is ignored with a warning.
+func (e *Engine) timeoutsFor(workflow *models.Workflow, logger *slog.Logger) map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for _, n := range workflow.Nodes {
+		timeout := e.nodeTimeouts[n.Type]
+		if raw, ok := n.Data.Metadata[timeoutMsKey]; ok {
+			if ms, ok := timeoutMs(raw); ok {
+				timeout = time.Duration(ms) * time.Millisecond
+			} else {
+				logger.Warn("Ignoring invalid timeoutMs, using the node type's default", "nodeId", n.ID, "timeoutMs", raw)
+			}
+		}
+		if timeout > 0 {
+			timeouts[n.ID] = timeout
+		}
+	}
+	return timeouts
+}
+
+// timeoutMs reads a non-negative whole number of milliseconds from JSON-decoded or
+// Go-built metadata
+func timeoutMs(raw any) (int64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		if v < 0 || v != float64(int64(v)) {
+			return 0, false
+		}
+		return int64(v), true
+	case int:
+		return int64(v), v >= 0
+	case int64:
+		return v, v >= 0
+	default:
+		return 0, false
+	}
+}
+
+// withNodeTimeout bounds ctx by the node's timeout, if it has one
+func withNodeTimeout(ctx context.Context, timeouts map[string]time.Duration, nodeID string) (context.Context, context.CancelFunc) {
+	if timeout, ok := timeouts[nodeID]; ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
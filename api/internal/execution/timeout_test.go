@@ -0,0 +1,132 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// deadlineNode records the deadline it ran under, or waits for it when wait is set
+type deadlineNode struct {
+	node.BaseNode
+	nodeType  models.NodeType
+	wait      bool
+	deadlines map[string]time.Duration
+}
+
+func (n *deadlineNode) Type() models.NodeType { return n.nodeType }
+
+func (n *deadlineNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *deadlineNode) Validate() error { return nil }
+
+func (n *deadlineNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		n.deadlines[n.ID] = time.Until(deadline)
+	}
+	if n.wait {
+		<-ctx.Done()
+		return node.NodeOutputs{Data: map[string]any{"error": ctx.Err().Error()}, Status: models.StatusFailed}, nil
+	}
+	return node.NodeOutputs{Data: map[string]any{}, Status: models.StatusCompleted}, nil
+}
+
+func newDeadlineRegistry(deadlines map[string]time.Duration, waiting string) *node.Registry {
+	registry := newTestRegistry()
+	for _, nodeType := range []models.NodeType{models.NodeTypeIntegration, models.NodeTypeForm, models.NodeTypeEmail} {
+		registry.Register(nodeType, func(model models.Node) (node.Node, error) {
+			return &deadlineNode{BaseNode: node.BaseNode{ID: model.ID}, nodeType: model.Type, wait: model.ID == waiting, deadlines: deadlines}, nil
+		})
+	}
+	return registry
+}
+
+// chainWorkflow runs the given nodes in order between a start and an end node
+func chainWorkflow(nodes ...models.Node) *models.Workflow {
+	workflow := &models.Workflow{ID: "timeout-workflow"}
+	workflow.Nodes = append([]models.Node{{ID: "start", Type: models.NodeTypeStart}}, nodes...)
+	workflow.Nodes = append(workflow.Nodes, models.Node{ID: "end", Type: models.NodeTypeEnd})
+	for i := 1; i < len(workflow.Nodes); i++ {
+		workflow.Edges = append(workflow.Edges, models.Edge{ID: workflow.Nodes[i].ID, Source: workflow.Nodes[i-1].ID, Target: workflow.Nodes[i].ID})
+	}
+	return workflow
+}
+
+func withTimeoutMs(n models.Node, timeoutMs any) models.Node {
+	n.Data.Metadata = map[string]any{"timeoutMs": timeoutMs}
+	return n
+}
+
+func TestExecuteAppliesNodeTimeouts(t *testing.T) {
+	deadlines := make(map[string]time.Duration)
+	workflow := chainWorkflow(
+		models.Node{ID: "weather", Type: models.NodeTypeIntegration},
+		models.Node{ID: "form", Type: models.NodeTypeForm},
+		withTimeoutMs(models.Node{ID: "quick-email", Type: models.NodeTypeEmail}, 2000.0),
+		withTimeoutMs(models.Node{ID: "unbounded-weather", Type: models.NodeTypeIntegration}, 0.0),
+		withTimeoutMs(models.Node{ID: "bounded-form", Type: models.NodeTypeForm}, 3000),
+		withTimeoutMs(models.Node{ID: "invalid-email", Type: models.NodeTypeEmail}, "fast"),
+	)
+
+	execution, err := NewEngine(newDeadlineRegistry(deadlines, "")).Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, execution.Status)
+
+	// Defaults by type
+	assert.InDelta(t, 30*time.Second, deadlines["weather"], float64(time.Second))
+	assert.NotContains(t, deadlines, "form")
+	assert.InDelta(t, 30*time.Second, deadlines["invalid-email"], float64(time.Second))
+	// A node's own timeoutMs wins, and zero lifts the default
+	assert.InDelta(t, 2*time.Second, deadlines["quick-email"], float64(time.Second))
+	assert.NotContains(t, deadlines, "unbounded-weather")
+	assert.InDelta(t, 3*time.Second, deadlines["bounded-form"], float64(time.Second))
+}
+
+func TestSetNodeTimeouts(t *testing.T) {
+	deadlines := make(map[string]time.Duration)
+	workflow := chainWorkflow(
+		models.Node{ID: "weather", Type: models.NodeTypeIntegration},
+		models.Node{ID: "form", Type: models.NodeTypeForm},
+		models.Node{ID: "email", Type: models.NodeTypeEmail},
+	)
+
+	engine := NewEngine(newDeadlineRegistry(deadlines, ""))
+	engine.SetNodeTimeouts(map[models.NodeType]time.Duration{
+		models.NodeTypeIntegration: 5 * time.Second,
+		models.NodeTypeForm:        time.Second,
+		models.NodeTypeEmail:       0,
+	})
+	_, err := engine.Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.InDelta(t, 5*time.Second, deadlines["weather"], float64(time.Second))
+	assert.InDelta(t, time.Second, deadlines["form"], float64(time.Second))
+	assert.NotContains(t, deadlines, "email")
+}
+
+func TestExecuteFailsNodeThatOverrunsItsTimeout(t *testing.T) {
+	deadlines := make(map[string]time.Duration)
+	workflow := chainWorkflow(withTimeoutMs(models.Node{ID: "weather", Type: models.NodeTypeIntegration}, 20.0))
+
+	started := time.Now()
+	execution, err := NewEngine(newDeadlineRegistry(deadlines, "weather")).Execute(context.Background(), workflow, models.WorkflowInput{})
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.Equal(t, models.StatusFailed, execution.Status)
+	if assert.NotNil(t, execution.ErrorSummary) {
+		assert.Equal(t, "weather", execution.ErrorSummary.NodeID)
+		assert.Equal(t, context.DeadlineExceeded.Error(), execution.ErrorSummary.Message)
+	}
+}
+
+func TestDefaultNodeTimeouts(t *testing.T) {
+	defaults := DefaultNodeTimeouts()
+	assert.Positive(t, defaults[models.NodeTypeIntegration])
+	assert.Positive(t, defaults[models.NodeTypeEmail])
+	for _, nodeType := range []models.NodeType{models.NodeTypeStart, models.NodeTypeForm, models.NodeTypeEnd} {
+		assert.NotContains(t, defaults, nodeType)
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+)
+
+// maxAdhocRequestBytes caps the size of an ad-hoc workflow definition and its input
+const maxAdhocRequestBytes = 1 << 20
+
+// AdhocExecutionRequest is a workflow definition to run once without saving it
+type AdhocExecutionRequest struct {
+	Workflow *models.Workflow     `json:"workflow"`
+	Input    models.WorkflowInput `json:"input"`
+}
+
+// HandleExecuteAdhocWorkflow validates and runs the workflow definition in the
+// request body. Nothing is stored, so the execution is only returned.
+func (h *WorkflowHandler) HandleExecuteAdhocWorkflow(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("Handling ad-hoc workflow execution")
+
+	var request AdhocExecutionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdhocRequestBytes)).Decode(&request); err != nil {
+		slog.Error("Failed to decode request body", "error", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	execution, err := h.Service.ExecuteAdhocWorkflow(r.Context(), request.Workflow, request.Input)
+	if err != nil {
+		slog.Error("Failed to execute ad-hoc workflow", "error", err)
+		if errors.Is(err, workflow.ErrInvalidWorkflowStructure) || errors.Is(err, workflow.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, workflow.ErrTooManyExecutions) {
+			w.Header().Set("Retry-After", strconv.Itoa(executionRetryAfterSeconds))
+			http.Error(w, "Too many concurrent executions", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Failed to execute workflow", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeExecutionJSON(w, r, execution); err != nil {
+		slog.Error("Failed to write execution response", "error", err)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleExecuteAdhocWorkflow(t *testing.T) {
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+	service := workflow.NewWorkflowService(nil)
+	service.SetEngine(execution.NewEngine(registry))
+	h := NewWorkflowHandler(service)
+
+	input := `{"name": "Test User", "email": "test@example.com", "city": "Sydney", "operator": "greater_than", "threshold": 20}`
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "valid definition runs",
+			body: `{"workflow": {"name": "Scratch", "nodes": [{"id": "start", "type": "start"}, {"id": "end", "type": "end"}],
+				"edges": [{"id": "e1", "source": "start", "target": "end"}]}, "input": ` + input + `}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unstructured workflow",
+			body:           `{"workflow": {"name": "Broken", "nodes": [{"id": "start", "type": "start"}]}, "input": ` + input + `}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid input",
+			body: `{"workflow": {"name": "Scratch", "nodes": [{"id": "start", "type": "start"}, {"id": "end", "type": "end"}],
+				"edges": [{"id": "e1", "source": "start", "target": "end"}]}, "input": {"name": "Test User"}}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "missing workflow",
+			body:           `{"input": ` + input + `}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"workflow":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "oversized body",
+			body:           `{"workflow": {"name": "` + strings.Repeat("x", maxAdhocRequestBytes) + `"}}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/workflows/execute-adhoc", strings.NewReader(tt.body))
+
+			h.HandleExecuteAdhocWorkflow(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"status":"completed"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"workflow-code-test/api/pkg/models"
+)
+
+// NodeTypesResponse lists the node types workflows can use
+type NodeTypesResponse struct {
+	NodeTypes []models.NodeType `json:"nodeTypes"`
+}
+
+// HandleReloadNodeTypes re-registers every node factory, picking up configuration
+// such as feature flags changed since startup, and returns the registered types
+func (h *WorkflowHandler) HandleReloadNodeTypes(w http.ResponseWriter, r *http.Request) {
+	if h.ReloadNodeTypes == nil {
+		http.Error(w, "Node type reload is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	types := h.ReloadNodeTypes()
+	slog.Info("Reloaded node types", "types", types)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(NodeTypesResponse{NodeTypes: types})
+}
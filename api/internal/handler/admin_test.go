@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleReloadNodeTypes(t *testing.T) {
+	t.Run("returns the reloaded types", func(t *testing.T) {
+		registry := node.NewRegistry()
+		registry.Register(models.NodeTypeStart, nil)
+		reloads := 0
+		h := &WorkflowHandler{ReloadNodeTypes: func() []models.NodeType {
+			reloads++
+			return registry.Reload(func(r *node.Registry) {
+				r.Register(models.NodeTypeStart, nil)
+				r.Register(models.NodeTypeEnd, nil)
+			})
+		}}
+
+		rec := httptest.NewRecorder()
+		h.HandleReloadNodeTypes(rec, httptest.NewRequest(http.MethodPost, "/admin/node-types/reload", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, reloads)
+		var response NodeTypesResponse
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		assert.Equal(t, []models.NodeType{models.NodeTypeEnd, models.NodeTypeStart}, response.NodeTypes)
+	})
+
+	t.Run("unavailable without a reload function", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		(&WorkflowHandler{}).HandleReloadNodeTypes(rec, httptest.NewRequest(http.MethodPost, "/admin/node-types/reload", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node/condition"
+)
+
+// EvaluateConditionRequest is a value to compare as a condition node would
+type EvaluateConditionRequest struct {
+	Temperature *float64        `json:"temperature"`
+	Field       string          `json:"field"`
+	Unit        string          `json:"unit"`
+	Operator    models.Operator `json:"operator"`
+	Threshold   *float64        `json:"threshold"`
+}
+
+// HandleEvaluateCondition previews a condition result without running a
+// workflow, using the same evaluation as the condition node
+func (h *WorkflowHandler) HandleEvaluateCondition(w http.ResponseWriter, r *http.Request) {
+	var request EvaluateConditionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		slog.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if request.Temperature == nil || request.Threshold == nil {
+		http.Error(w, "temperature and threshold are required", http.StatusBadRequest)
+		return
+	}
+
+	// The temperature field carries the compared value, whichever field it is
+	evaluation, err := condition.Evaluate(request.Field, *request.Temperature, request.Operator, *request.Threshold)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Values are compared as given, so a unit other than the field's would be misleading
+	if request.Unit != "" && request.Unit != evaluation.Unit {
+		http.Error(w, fmt.Sprintf("unit for %s must be %s", evaluation.Field, evaluation.Unit), http.StatusBadRequest)
+		return
+	}
+	slog.Debug("Evaluated condition preview", "field", evaluation.Field, "operator", evaluation.Operator, "result", evaluation.Met)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(evaluation)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"workflow-code-test/api/pkg/node/condition"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleEvaluateCondition(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedResult bool
+	}{
+		{"condition fires", `{"temperature": 6, "operator": "less_than", "threshold": 10}`, http.StatusOK, true},
+		{"condition does not fire", `{"temperature": 16, "operator": "less_than", "threshold": 10}`, http.StatusOK, false},
+		{"windspeed field with matching unit", `{"temperature": 30, "field": "windspeed", "unit": "km/h", "operator": "greater_than", "threshold": 20}`, http.StatusOK, true},
+		{"unit mismatch", `{"temperature": 6, "unit": "°F", "operator": "less_than", "threshold": 10}`, http.StatusBadRequest, false},
+		{"unsupported operator", `{"temperature": 6, "operator": "about", "threshold": 10}`, http.StatusBadRequest, false},
+		{"missing threshold", `{"temperature": 6, "operator": "less_than"}`, http.StatusBadRequest, false},
+		{"invalid body", `not-json`, http.StatusBadRequest, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &WorkflowHandler{}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/condition/evaluate", strings.NewReader(tc.body))
+
+			h.HandleEvaluateCondition(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			var evaluation condition.Evaluation
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &evaluation))
+			assert.Equal(t, tc.expectedResult, evaluation.Met)
+			assert.NotEmpty(t, evaluation.Message)
+			assert.NotEmpty(t, evaluation.Emoji)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/gorilla/mux"
+)
+
+// HandleDebugExecuteWorkflow runs the nodes listed in ?order=a,b,c in that order,
+// ignoring edges and conditional routing. The body is the usual execute input.
+func (h *WorkflowHandler) HandleDebugExecuteWorkflow(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	order := parseNodeOrder(r.URL.Query().Get("order"))
+	slog.Debug("Handling forced-order workflow execution", "id", id, "order", order)
+
+	var input models.WorkflowInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		slog.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	execution, err := h.Service.ExecuteWorkflowInOrder(r.Context(), id, input, order)
+	if err != nil {
+		slog.Error("Failed to execute workflow in forced order", "error", err)
+		if errors.Is(err, workflow.ErrInvalidInput) || errors.Is(err, workflow.ErrInvalidExecutionOrder) ||
+			errors.Is(err, workflow.ErrInvalidWorkflowStructure) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, workflow.ErrWorkflowNotFound) {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, workflow.ErrTooManyExecutions) {
+			w.Header().Set("Retry-After", strconv.Itoa(executionRetryAfterSeconds))
+			http.Error(w, "Too many concurrent executions", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Failed to execute workflow", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeExecutionJSON(w, r, execution); err != nil {
+		slog.Error("Failed to write execution response", "error", err)
+	}
+}
+
+// parseNodeOrder splits a comma-separated list of node IDs, dropping empty entries
+func parseNodeOrder(value string) []string {
+	var order []string
+	for _, nodeID := range strings.Split(value, ",") {
+		if nodeID = strings.TrimSpace(nodeID); nodeID != "" {
+			order = append(order, nodeID)
+		}
+	}
+	return order
+}
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNodeOrder(t *testing.T) {
+	assert.Nil(t, parseNodeOrder(""))
+	assert.Equal(t, []string{"start", "weather-api"}, parseNodeOrder("start,weather-api"))
+	assert.Equal(t, []string{"form", "email"}, parseNodeOrder(" form , ,email,"))
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/gorilla/mux"
+)
+
+// dateLayout is the date-only form accepted for the from and to filters
+const dateLayout = "2006-01-02"
+
+// HandleSearchExecutions lists executions across all workflows, optionally filtered
+// by status, workflow and executed_at range
+func (h *WorkflowHandler) HandleSearchExecutions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	slog.Debug("Searching executions", "query", query.Encode())
+
+	opts := repository.SearchExecutionsOptions{
+		Status:     models.Status(query.Get("status")),
+		WorkflowID: query.Get("workflowId"),
+		Cursor:     query.Get("cursor"),
+	}
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		opts.Limit = value
+	}
+
+	var err error
+	if opts.From, err = parseTimeFilter(query.Get("from"), false); err != nil {
+		http.Error(w, fmt.Sprintf("from %v", err), http.StatusBadRequest)
+		return
+	}
+	if opts.To, err = parseTimeFilter(query.Get("to"), true); err != nil {
+		http.Error(w, fmt.Sprintf("to %v", err), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.Service.SearchExecutions(r.Context(), opts)
+	if err != nil {
+		slog.Error("Failed to search executions", "error", err)
+		if errors.Is(err, workflow.ErrInvalidExecutionFilter) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, workflow.ErrInvalidCursor) {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to search executions", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseTimeFilter parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC). A date used
+// as the exclusive upper bound is moved to the following midnight so the whole day is included.
+func parseTimeFilter(value string, upperBound bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	if upperBound {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return &parsed, nil
+}
+
+// HandleDiffExecutions compares two executions of a workflow given as ?a= and ?b=
+func (h *WorkflowHandler) HandleDiffExecutions(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	slog.Debug("Comparing executions", "id", id, "a", query.Get("a"), "b", query.Get("b"))
+
+	diff, err := h.Service.DiffExecutions(r.Context(), id, query.Get("a"), query.Get("b"))
+	if err != nil {
+		slog.Error("Failed to compare executions", "error", err)
+		if errors.Is(err, workflow.ErrInvalidExecutionFilter) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, workflow.ErrExecutionNotFound) {
+			http.Error(w, "Execution not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to compare executions", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(diff)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		upperBound bool
+		expected   *time.Time
+		expectErr  bool
+	}{
+		{name: "empty", value: ""},
+		{
+			name:     "timestamp",
+			value:    "2024-05-01T10:30:00Z",
+			expected: ptr(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:       "timestamp as upper bound is unchanged",
+			value:      "2024-05-01T10:30:00+10:00",
+			upperBound: true,
+			expected:   ptr(time.Date(2024, 5, 1, 0, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:     "date",
+			value:    "2024-05-01",
+			expected: ptr(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "date as upper bound covers the whole day",
+			value:      "2024-05-01",
+			upperBound: true,
+			expected:   ptr(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)),
+		},
+		{name: "invalid", value: "01/05/2024", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseTimeFilter(tt.value, tt.upperBound)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, parsed)
+				return
+			}
+			assert.True(t, tt.expected.Equal(*parsed), "got %v", parsed)
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
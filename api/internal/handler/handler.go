@@ -3,16 +3,30 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/internal/repository"
 	"workflow-code-test/api/internal/workflow"
 	"workflow-code-test/api/pkg/models"
 
 	"github.com/gorilla/mux"
 )
 
+// executionRetryAfterSeconds is suggested to clients turned away while executions are saturated
+const executionRetryAfterSeconds = 1
+
 type WorkflowHandler struct {
 	Service workflow.WorkflowService
+	// Weather serves GET /weather; the endpoint returns 503 when it is nil
+	Weather WeatherLookup
+	// ReloadNodeTypes re-registers node factories for the admin reload endpoint
+	// and returns the registered types; the endpoint returns 503 when it is nil
+	ReloadNodeTypes func() []models.NodeType
+	// Metrics adds execution counts and durations to GET /metrics when set
+	Metrics *execution.Metrics
 }
 
 func NewWorkflowHandler(service workflow.WorkflowService) *WorkflowHandler {
@@ -37,7 +51,27 @@ func (h *WorkflowHandler) HandleGetWorkflow(w http.ResponseWriter, r *http.Reque
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(workflowObj)
+	json.NewEncoder(w).Encode(workflowObj.WithoutSecrets())
+}
+
+func (h *WorkflowHandler) HandleValidateWorkflow(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	evaluateAllBranches := r.URL.Query().Get("evaluateAllBranches") == "true"
+	slog.Debug("Validating workflow", "id", id, "evaluateAllBranches", evaluateAllBranches)
+
+	result, err := h.Service.ValidateWorkflow(r.Context(), id, evaluateAllBranches)
+	if err != nil {
+		slog.Error("Failed to validate workflow", "error", err)
+		if errors.Is(err, workflow.ErrWorkflowNotFound) {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to validate workflow", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
 }
 
 func (h *WorkflowHandler) HandleExecuteWorkflow(w http.ResponseWriter, r *http.Request) {
@@ -51,28 +85,228 @@ func (h *WorkflowHandler) HandleExecuteWorkflow(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Validate the input
-	if err := input.Validate(); err != nil {
-		slog.Error("Invalid input", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	// ?explain=true has each step say what it did and why
+	ctx := r.Context()
+	if r.URL.Query().Get("explain") == "true" {
+		ctx = execution.WithExplain(ctx)
 	}
 
-	execution, err := h.Service.ExecuteWorkflow(r.Context(), id, input)
+	// Input is validated by the service once the workflow's default input has been merged in.
+	// A body that decodes but fails validation is well-formed, so it gets 422 rather than 400
+	result, err := h.Service.ExecuteWorkflow(ctx, id, input)
 	if err != nil {
 		slog.Error("Failed to execute workflow", "error", err)
-		if errors.Is(err, workflow.ErrInvalidInput) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if errors.Is(err, workflow.ErrInvalidInput) || errors.Is(err, workflow.ErrInvalidWorkflowStructure) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 			return
 		}
 		if errors.Is(err, workflow.ErrWorkflowNotFound) {
 			http.Error(w, "Workflow not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, workflow.ErrTooManyExecutions) {
+			w.Header().Set("Retry-After", strconv.Itoa(executionRetryAfterSeconds))
+			http.Error(w, "Too many concurrent executions", http.StatusServiceUnavailable)
+			return
+		}
 		http.Error(w, "Failed to execute workflow", http.StatusInternalServerError)
 		return
 	}
 
+	if err := writeExecutionJSON(w, r, result); err != nil {
+		slog.Error("Failed to write execution response", "error", err)
+	}
+}
+
+func (h *WorkflowHandler) HandleListExecutions(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	slog.Debug("Listing executions for workflow", "id", id)
+
+	opts := repository.ListExecutionsOptions{Cursor: query.Get("cursor"), InputHash: query.Get("inputHash")}
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		opts.Limit = value
+	}
+	// offset is still accepted for older clients but is ignored when a cursor is given
+	if offset := query.Get("offset"); offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		opts.Offset = value
+	}
+	if slaBreached := query.Get("slaBreached"); slaBreached != "" {
+		value, err := strconv.ParseBool(slaBreached)
+		if err != nil {
+			http.Error(w, "slaBreached must be true or false", http.StatusBadRequest)
+			return
+		}
+		opts.SLABreached = value
+	}
+
+	page, err := h.Service.ListExecutions(r.Context(), id, opts)
+	if err != nil {
+		slog.Error("Failed to list executions", "error", err)
+		if errors.Is(err, workflow.ErrInvalidCursor) {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, workflow.ErrInvalidExecutionFilter) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, workflow.ErrWorkflowNotFound) {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to list executions", http.StatusInternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(execution)
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *WorkflowHandler) HandleGetWorkflowStats(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	slog.Debug("Returning stats for workflow", "id", id)
+
+	window := 0
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		window = value
+	}
+
+	stats, err := h.Service.GetExecutionStats(r.Context(), id, window)
+	if err != nil {
+		slog.Error("Failed to get workflow stats", "error", err)
+		if errors.Is(err, workflow.ErrWorkflowNotFound) {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get workflow stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// HandleGetLatestExecution returns a workflow's most recent execution, or 404 when
+// it has never run
+func (h *WorkflowHandler) HandleGetLatestExecution(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	slog.Debug("Returning latest execution", "id", id)
+
+	execution, err := h.Service.GetLatestExecution(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, workflow.ErrExecutionNotFound) {
+			http.Error(w, "Execution not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("Failed to get latest execution", "error", err)
+		http.Error(w, "Failed to get execution", http.StatusInternalServerError)
+		return
+	}
+
+	if wantsNDJSON(r) {
+		if err := writeExecutionNDJSON(w, r, execution); err != nil {
+			slog.Error("Failed to stream execution response", "error", err)
+		}
+		return
+	}
+
+	if err := writeExecutionJSON(w, r, execution); err != nil {
+		slog.Error("Failed to write execution response", "error", err)
+	}
+}
+
+func (h *WorkflowHandler) HandleGetExecution(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, executionID := vars["id"], vars["executionId"]
+	slog.Debug("Returning execution", "id", id, "executionId", executionID)
+
+	execution, err := h.Service.GetExecution(r.Context(), id, executionID)
+	if err != nil {
+		slog.Error("Failed to get execution", "error", err)
+		if errors.Is(err, workflow.ErrExecutionNotFound) {
+			http.Error(w, "Execution not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get execution", http.StatusInternalServerError)
+		return
+	}
+
+	if wantsNDJSON(r) {
+		if err := writeExecutionNDJSON(w, r, execution); err != nil {
+			slog.Error("Failed to stream execution response", "error", err)
+		}
+		return
+	}
+
+	if err := writeExecutionJSON(w, r, execution); err != nil {
+		slog.Error("Failed to write execution response", "error", err)
+	}
+}
+
+// resumeRequest is the optional body of a resume request. The failed run's stored
+// email is masked, so a run that had one needs it given again.
+type resumeRequest struct {
+	Email string `json:"email"`
+}
+
+// HandleResumeExecution runs a failed execution again from the node it failed at
+func (h *WorkflowHandler) HandleResumeExecution(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, executionID := vars["id"], vars["executionId"]
+	slog.Debug("Resuming execution", "id", id, "executionId", executionID)
+
+	var body resumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		slog.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Service.ResumeExecution(r.Context(), id, executionID, body.Email)
+	if err != nil {
+		slog.Error("Failed to resume execution", "error", err)
+		if errors.Is(err, workflow.ErrExecutionNotFound) {
+			http.Error(w, "Execution not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, workflow.ErrWorkflowNotFound) {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, workflow.ErrExecutionNotResumable) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, workflow.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, workflow.ErrTooManyExecutions) {
+			w.Header().Set("Retry-After", strconv.Itoa(executionRetryAfterSeconds))
+			http.Error(w, "Too many concurrent executions", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Failed to resume execution", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeExecutionJSON(w, r, result); err != nil {
+		slog.Error("Failed to write execution response", "error", err)
+	}
 }
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// storedWorkflowRepository serves a single stored workflow; methods it doesn't
+// override panic through the nil embedded interface
+type storedWorkflowRepository struct {
+	repository.WorkflowRepository
+	workflow *models.Workflow
+}
+
+func (r *storedWorkflowRepository) Get(ctx context.Context, id string) (*models.Workflow, error) {
+	if id != r.workflow.ID {
+		return nil, repository.ErrWorkflowNotFound
+	}
+	return &models.Workflow{ID: r.workflow.ID, Name: r.workflow.Name}, nil
+}
+
+func (r *storedWorkflowRepository) GetNodes(ctx context.Context, workflowID string) ([]models.Node, error) {
+	return r.workflow.Nodes, nil
+}
+
+func (r *storedWorkflowRepository) GetEdges(ctx context.Context, workflowID string) ([]models.Edge, error) {
+	return r.workflow.Edges, nil
+}
+
+func (r *storedWorkflowRepository) CreateExecution(ctx context.Context, execution *models.WorkflowExecution) error {
+	return nil
+}
+
+func (r *storedWorkflowRepository) CreateExecutionStep(ctx context.Context, step *models.ExecutionStep) error {
+	return nil
+}
+
+func (r *storedWorkflowRepository) UpdateExecution(ctx context.Context, execution *models.WorkflowExecution) error {
+	return nil
+}
+
+func TestHandleExecuteWorkflowStatusCodes(t *testing.T) {
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+	service := workflow.NewWorkflowService(&storedWorkflowRepository{workflow: &models.Workflow{
+		ID:    "wf-1",
+		Name:  "Stored",
+		Nodes: []models.Node{{ID: "start", Type: models.NodeTypeStart}, {ID: "end", Type: models.NodeTypeEnd}},
+		Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+	}})
+	service.SetEngine(execution.NewEngine(registry))
+	h := NewWorkflowHandler(service)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "valid input",
+			body:           `{"name": "Test User", "email": "test@example.com", "city": "Sydney", "operator": "greater_than", "threshold": 20}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "malformed JSON",
+			body:           `{"name": "Test User",`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "wrong field type",
+			body:           `{"name": "Test User", "email": "test@example.com", "city": "Sydney", "operator": "greater_than", "threshold": "warm"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid operator",
+			body:           `{"name": "Test User", "email": "test@example.com", "city": "Sydney", "operator": "roughly", "threshold": 20}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "threshold out of range",
+			body:           `{"name": "Test User", "email": "test@example.com", "city": "Sydney", "operator": "greater_than", "threshold": 150}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "missing required field",
+			body:           `{"email": "test@example.com", "city": "Sydney", "operator": "greater_than", "threshold": 20}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid embedded workflow",
+			body: `{"name": "Test User", "email": "test@example.com", "city": "Sydney", "operator": "greater_than", "threshold": 20,
+				"workflow": {"id": "wf-1", "name": "Broken", "nodes": [{"id": "start", "type": "start"}]}}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/workflows/wf-1/execute", strings.NewReader(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"id": "wf-1"})
+
+			h.HandleExecuteWorkflow(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestHandleExecuteWorkflowExplain(t *testing.T) {
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+	service := workflow.NewWorkflowService(&storedWorkflowRepository{workflow: &models.Workflow{
+		ID:    "wf-1",
+		Name:  "Stored",
+		Nodes: []models.Node{{ID: "start", Type: models.NodeTypeStart}, {ID: "end", Type: models.NodeTypeEnd}},
+		Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+	}})
+	service.SetEngine(execution.NewEngine(registry))
+	h := NewWorkflowHandler(service)
+	body := `{"name": "Test User", "email": "test@example.com", "city": "Sydney", "operator": "greater_than", "threshold": 20}`
+
+	execute := func(target string) *models.WorkflowExecution {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", target, strings.NewReader(body))
+		r = mux.SetURLVars(r, map[string]string{"id": "wf-1"})
+		h.HandleExecuteWorkflow(w, r)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var result models.WorkflowExecution
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return &result
+	}
+
+	explained := execute("/api/v1/workflows/wf-1/execute?explain=true")
+	assert.Equal(t, []models.StepExplanation{
+		{StepNumber: 1, NodeID: "start", NodeType: models.NodeTypeStart, Explanation: "Run started for Sydney"},
+		{StepNumber: 2, NodeID: "end", NodeType: models.NodeTypeEnd, Explanation: "Reached the end of the workflow"},
+	}, explained.Explanation)
+	assert.Equal(t, "Reached the end of the workflow", explained.Steps[1].Output["explanation"])
+
+	plain := execute("/api/v1/workflows/wf-1/execute")
+	assert.Empty(t, plain.Explanation)
+	assert.NotContains(t, plain.Steps[1].Output, "explanation")
+}
+
+// storedExecutionsRepository adds stored executions to a stored workflow
+type storedExecutionsRepository struct {
+	storedWorkflowRepository
+	executions map[string]*models.WorkflowExecution
+}
+
+func (r *storedExecutionsRepository) GetExecution(ctx context.Context, id string) (*models.WorkflowExecution, error) {
+	execution, ok := r.executions[id]
+	if !ok {
+		return nil, repository.ErrExecutionNotFound
+	}
+	stored := *execution
+	return &stored, nil
+}
+
+func (r *storedExecutionsRepository) GetExecutionSteps(ctx context.Context, executionID string) ([]models.ExecutionStep, error) {
+	return r.executions[executionID].Steps, nil
+}
+
+func TestHandleResumeExecutionStatusCodes(t *testing.T) {
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+	storedInput := &models.WorkflowInput{Name: "Test User", Email: "t***@example.com", City: "Sydney", Operator: models.OperatorGreaterThan, Threshold: 20}
+	repo := &storedExecutionsRepository{
+		storedWorkflowRepository: storedWorkflowRepository{workflow: &models.Workflow{
+			ID:    "wf-1",
+			Name:  "Stored",
+			Nodes: []models.Node{{ID: "start", Type: models.NodeTypeStart}, {ID: "end", Type: models.NodeTypeEnd}},
+			Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+		}},
+		executions: map[string]*models.WorkflowExecution{
+			"failed": {ID: "failed", WorkflowID: "wf-1", Status: models.StatusFailed, Input: storedInput, Steps: []models.ExecutionStep{
+				{NodeID: "start", StepNumber: 1, NodeType: models.NodeTypeStart, Status: models.StatusCompleted, Output: models.JSONB{}},
+				{NodeID: "end", StepNumber: 2, NodeType: models.NodeTypeEnd, Status: models.StatusFailed, Output: models.JSONB{}},
+			}},
+			"completed": {ID: "completed", WorkflowID: "wf-1", Status: models.StatusCompleted, Input: storedInput},
+		},
+	}
+	service := workflow.NewWorkflowService(repo)
+	service.SetEngine(execution.NewEngine(registry))
+	h := NewWorkflowHandler(service)
+
+	tests := []struct {
+		name           string
+		executionID    string
+		body           string
+		expectedStatus int
+	}{
+		{"resumed", "failed", `{"email": "test@example.com"}`, http.StatusOK},
+		{"masked email not given again", "failed", "", http.StatusUnprocessableEntity},
+		{"malformed body", "failed", `{"email":`, http.StatusBadRequest},
+		{"not failed", "completed", `{"email": "test@example.com"}`, http.StatusConflict},
+		{"unknown execution", "missing", `{"email": "test@example.com"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/workflows/wf-1/executions/"+tt.executionID+"/resume", strings.NewReader(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"id": "wf-1", "executionId": tt.executionID})
+
+			h.HandleResumeExecution(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				var resumed models.WorkflowExecution
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resumed))
+				assert.Equal(t, models.StatusCompleted, resumed.Status)
+				assert.Equal(t, "failed", resumed.Metadata["resumedFrom"])
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+)
+
+// maxImportBytes caps the size of an imported workflow definition
+const maxImportBytes = 1 << 20
+
+// HandleImportWorkflow stores the workflow definition in the request body as a new
+// workflow. Duplicate node IDs are rejected unless ?fixDuplicates=true asks for them
+// to be renamed.
+func (h *WorkflowHandler) HandleImportWorkflow(w http.ResponseWriter, r *http.Request) {
+	fixDuplicates := r.URL.Query().Get("fixDuplicates") == "true"
+	slog.Debug("Importing workflow", "fixDuplicates", fixDuplicates)
+
+	var definition models.Workflow
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&definition); err != nil {
+		slog.Error("Failed to decode request body", "error", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Service.ImportWorkflow(r.Context(), &definition, fixDuplicates)
+	if err != nil {
+		slog.Error("Failed to import workflow", "error", err)
+		if errors.Is(err, workflow.ErrInvalidTag) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, workflow.ErrInvalidWorkflowStructure) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		http.Error(w, "Failed to import workflow", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
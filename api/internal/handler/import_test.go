@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"workflow-code-test/api/internal/workflow"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleImportWorkflow(t *testing.T) {
+	h := NewWorkflowHandler(workflow.NewWorkflowService(nil))
+
+	t.Run("duplicate node IDs are rejected by default", func(t *testing.T) {
+		body := `{"id": "wf-import", "name": "Import", "nodes": [{"id": "start", "type": "start"}, {"id": "start", "type": "start"},
+			{"id": "end", "type": "end"}], "edges": [{"id": "e1", "source": "start", "target": "end"}]}`
+
+		rec := httptest.NewRecorder()
+		h.HandleImportWorkflow(rec, httptest.NewRequest("POST", "/workflows/import", strings.NewReader(body)))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "duplicate node ID")
+	})
+
+	t.Run("invalid tag key", func(t *testing.T) {
+		body := `{"id": "wf-import", "name": "Import", "nodes": [{"id": "start", "type": "start"}, {"id": "end", "type": "end"}],
+			"edges": [{"id": "e1", "source": "start", "target": "end"}], "tags": {"team:weather": "yes"}}`
+
+		rec := httptest.NewRecorder()
+		h.HandleImportWorkflow(rec, httptest.NewRequest("POST", "/workflows/import", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid tag")
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.HandleImportWorkflow(rec, httptest.NewRequest("POST", "/workflows/import?fixDuplicates=true", strings.NewReader("{")))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"workflow-code-test/api/internal/workflow"
+
+	"github.com/gorilla/mux"
+)
+
+// IntegrationTestRequest names the city to fetch the weather for
+type IntegrationTestRequest struct {
+	City string `json:"city"`
+}
+
+// HandleTestIntegrationNode fetches the weather for a city through one of the
+// workflow's integration nodes. The fetch's outcome, including a failure, is
+// returned with 200; other statuses mean the test couldn't be run.
+func (h *WorkflowHandler) HandleTestIntegrationNode(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, nodeID := vars["id"], vars["nodeId"]
+	slog.Debug("Testing integration node", "id", id, "nodeId", nodeID)
+
+	var request IntegrationTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		slog.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Service.TestIntegrationNode(r.Context(), id, nodeID, request.City)
+	if err != nil {
+		slog.Error("Failed to test integration node", "error", err)
+		if errors.Is(err, workflow.ErrInvalidInput) || errors.Is(err, workflow.ErrNotIntegrationNode) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, workflow.ErrWorkflowNotFound) {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, workflow.ErrNodeNotFound) {
+			http.Error(w, "Node not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to test integration node", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
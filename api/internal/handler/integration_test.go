@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleTestIntegrationNode(t *testing.T) {
+	weatherAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 27.5, "windspeed": 14.0}}`)
+	}))
+	defer weatherAPI.Close()
+
+	integrationNode := func(id, endpoint string) models.Node {
+		return models.Node{ID: id, Type: models.NodeTypeIntegration, Data: models.NodeData{Metadata: map[string]any{
+			"apiEndpoint": endpoint,
+			"options":     []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+		}}}
+	}
+	service := workflow.NewWorkflowService(&storedWorkflowRepository{workflow: &models.Workflow{
+		ID:   "wf-1",
+		Name: "Stored",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			integrationNode("weather-api", weatherAPI.URL+"/forecast?lat={lat}&lon={lon}"),
+			integrationNode("broken-api", weatherAPI.URL+"/broken?lat={lat}&lon={lon}"),
+			{ID: "misconfigured-api", Type: models.NodeTypeIntegration, Data: models.NodeData{Metadata: map[string]any{
+				"apiEndpoint": weatherAPI.URL,
+			}}},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+	}})
+	h := NewWorkflowHandler(service)
+
+	tests := []struct {
+		name           string
+		workflowID     string
+		nodeID         string
+		body           string
+		expectedStatus int
+		expectSuccess  bool
+		expectedError  string
+	}{
+		{name: "resolves the weather", workflowID: "wf-1", nodeID: "weather-api", body: `{"city": "Sydney"}`, expectedStatus: http.StatusOK, expectSuccess: true},
+		{name: "reports an API error", workflowID: "wf-1", nodeID: "broken-api", body: `{"city": "Sydney"}`, expectedStatus: http.StatusOK, expectedError: "status 500"},
+		{name: "reports an unknown city", workflowID: "wf-1", nodeID: "weather-api", body: `{"city": "Atlantis"}`, expectedStatus: http.StatusOK, expectedError: "city not found"},
+		{name: "reports a misconfigured node", workflowID: "wf-1", nodeID: "misconfigured-api", body: `{"city": "Sydney"}`, expectedStatus: http.StatusOK, expectedError: "invalid node configuration"},
+		{name: "malformed body", workflowID: "wf-1", nodeID: "weather-api", body: `{"city":`, expectedStatus: http.StatusBadRequest},
+		{name: "missing city", workflowID: "wf-1", nodeID: "weather-api", body: `{}`, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "not an integration node", workflowID: "wf-1", nodeID: "start", body: `{"city": "Sydney"}`, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "unknown node", workflowID: "wf-1", nodeID: "missing", body: `{"city": "Sydney"}`, expectedStatus: http.StatusNotFound},
+		{name: "unknown workflow", workflowID: "wf-2", nodeID: "weather-api", body: `{"city": "Sydney"}`, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/workflows/"+tt.workflowID+"/nodes/"+tt.nodeID+"/test-integration", strings.NewReader(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"id": tt.workflowID, "nodeId": tt.nodeID})
+
+			h.HandleTestIntegrationNode(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var result workflow.IntegrationTestResult
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, tt.nodeID, result.NodeID)
+			assert.Equal(t, tt.expectSuccess, result.Success)
+			if tt.expectSuccess {
+				if assert.NotNil(t, result.Weather) {
+					assert.Equal(t, 27.5, result.Weather.Temperature)
+					assert.Equal(t, "Sydney", result.Weather.Location)
+				}
+				return
+			}
+			assert.Nil(t, result.Weather)
+			assert.Contains(t, result.Error, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HandleMetrics reports execution concurrency, and execution counts and durations
+// when Metrics is set, in the Prometheus text exposition format
+func (h *WorkflowHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintln(w, "# HELP workflow_executions_in_flight Workflow executions currently running.")
+	fmt.Fprintln(w, "# TYPE workflow_executions_in_flight gauge")
+	fmt.Fprintf(w, "workflow_executions_in_flight %d\n", h.Service.InFlightExecutions())
+	fmt.Fprintln(w, "# HELP workflow_executions_max_concurrent Limit on concurrent executions, 0 when unlimited.")
+	fmt.Fprintln(w, "# TYPE workflow_executions_max_concurrent gauge")
+	fmt.Fprintf(w, "workflow_executions_max_concurrent %d\n", h.Service.MaxConcurrentExecutions())
+	if h.Metrics != nil {
+		h.Metrics.WritePrometheus(w)
+	}
+}
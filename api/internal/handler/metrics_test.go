@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleMetrics(t *testing.T) {
+	service := workflow.NewWorkflowService(nil)
+	service.SetMaxConcurrentExecutions(4)
+	h := NewWorkflowHandler(service)
+
+	w := httptest.NewRecorder()
+	h.HandleMetrics(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, w.Body.String(), "workflow_executions_in_flight 0\n")
+	assert.Contains(t, w.Body.String(), "workflow_executions_max_concurrent 4\n")
+	assert.NotContains(t, w.Body.String(), "workflow_executions_total")
+}
+
+func TestHandleMetricsIncludesExecutionMetrics(t *testing.T) {
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+	engine := execution.NewEngine(registry)
+	_, err := engine.Execute(context.Background(), &models.Workflow{
+		ID:    "wf-1",
+		Nodes: []models.Node{{ID: "start", Type: models.NodeTypeStart}, {ID: "end", Type: models.NodeTypeEnd}},
+		Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+	}, models.WorkflowInput{})
+	assert.NoError(t, err)
+
+	h := NewWorkflowHandler(workflow.NewWorkflowService(nil))
+	h.Metrics = engine.Metrics()
+
+	w := httptest.NewRecorder()
+	h.HandleMetrics(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "workflow_executions_in_flight 0\n")
+	assert.Contains(t, w.Body.String(), `workflow_executions_total{status="completed"} 1`+"\n")
+	assert.Contains(t, w.Body.String(), "workflow_execution_duration_seconds_count 1\n")
+	assert.Contains(t, w.Body.String(), `workflow_node_duration_seconds_count{node_type="end"} 1`+"\n")
+}
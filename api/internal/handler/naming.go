@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// namingSnakeCase selects snake_case response keys for legacy clients
+const namingSnakeCase = "snake_case"
+
+// wantsSnakeCase reports whether the client asked for snake_case keys, either with
+// ?naming=snake_case or an Accept header such as "application/json; profile=snake_case"
+func wantsSnakeCase(r *http.Request) bool {
+	if r.URL.Query().Get("naming") == namingSnakeCase {
+		return true
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && params["profile"] == namingSnakeCase {
+			return true
+		}
+	}
+	return false
+}
+
+// toSnakeCase converts a camelCase key such as "totalDuration" to "total_duration"
+func toSnakeCase(key string) string {
+	var b strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word unless this continues an acronym like "ID"
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// snakeCaseTopLevelKeys rewrites the top-level keys of a JSON object to snake_case.
+// Nested values are left untouched since they carry node outputs as authored.
+func snakeCaseTopLevelKeys(data []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	converted := make(map[string]json.RawMessage, len(fields))
+	for key, value := range fields {
+		converted[toSnakeCase(key)] = value
+	}
+	return json.Marshal(converted)
+}
+
+// writeExecutionJSON writes an execution response, applying the client's naming profile
+func writeExecutionJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if wantsSnakeCase(r) {
+		data, err = snakeCaseTopLevelKeys(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSnakeCase(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"status", "status"},
+		{"startTime", "start_time"},
+		{"totalDuration", "total_duration"},
+		{"executionID", "execution_id"},
+		{"HTTPStatus", "http_status"},
+		{"already_snake", "already_snake"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, toSnakeCase(tc.input))
+		})
+	}
+}
+
+func TestWantsSnakeCase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		url      string
+		accept   string
+		expected bool
+	}{
+		{name: "Default", url: "/", expected: false},
+		{name: "Query param", url: "/?naming=snake_case", expected: true},
+		{name: "Accept profile", url: "/", accept: "application/json; profile=snake_case", expected: true},
+		{name: "Accept profile among others", url: "/", accept: "text/html, application/json; profile=\"snake_case\"", expected: true},
+		{name: "Other profile", url: "/", accept: "application/json; profile=camelCase", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.url, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			assert.Equal(t, tc.expected, wantsSnakeCase(r))
+		})
+	}
+}
+
+func TestWriteExecutionJSON(t *testing.T) {
+	execution := &models.WorkflowExecution{
+		ID:            "exec-1",
+		Status:        models.StatusCompleted,
+		StartTime:     "2024-05-01T10:00:00Z",
+		TotalDuration: 42,
+		Steps: []models.ExecutionStep{
+			{StepNumber: 1, NodeType: models.NodeTypeStart, Output: models.JSONB{"someKey": "value"}},
+		},
+	}
+
+	t.Run("camelCase by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		assert.NoError(t, writeExecutionJSON(w, r, execution))
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Contains(t, body, "startTime")
+		assert.Contains(t, body, "totalDuration")
+	})
+
+	t.Run("snake_case on request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/?naming=snake_case", nil)
+		assert.NoError(t, writeExecutionJSON(w, r, execution))
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "2024-05-01T10:00:00Z", body["start_time"])
+		assert.Equal(t, float64(42), body["total_duration"])
+		assert.NotContains(t, body, "startTime")
+
+		// Only top-level keys are converted
+		steps := body["steps"].([]any)
+		step := steps[0].(map[string]any)
+		assert.Contains(t, step, "stepNumber")
+		assert.Equal(t, "value", step["output"].(map[string]any)["someKey"])
+	})
+
+	// The stored model is not modified by the transform
+	assert.Equal(t, "2024-05-01T10:00:00Z", execution.StartTime)
+}
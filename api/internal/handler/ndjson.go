@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"workflow-code-test/api/pkg/models"
+)
+
+// formatNDJSON selects newline-delimited JSON for execution responses
+const formatNDJSON = "ndjson"
+
+// Line types in an NDJSON execution stream
+const (
+	ndjsonLineStep    = "step"
+	ndjsonLineSummary = "summary"
+)
+
+// ndjsonStepLine is one step of an execution, tagged so clients can tell it from the summary
+type ndjsonStepLine struct {
+	Type string `json:"type"`
+	models.ExecutionStep
+}
+
+// ndjsonSummaryLine closes an execution stream with the overall result
+type ndjsonSummaryLine struct {
+	Type          string        `json:"type"`
+	ID            string        `json:"id"`
+	Status        models.Status `json:"status"`
+	StartTime     string        `json:"startTime"`
+	EndTime       string        `json:"endTime"`
+	TotalDuration int64         `json:"totalDuration,omitempty"`
+	StepCount     int           `json:"stepCount"`
+	Metadata      models.JSONB  `json:"metadata,omitempty"`
+}
+
+// wantsNDJSON reports whether the client asked for ?format=ndjson
+func wantsNDJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == formatNDJSON
+}
+
+// writeExecutionNDJSON streams an execution as one JSON object per line: each step in
+// order, then a summary. Every line is flushed as it is written so clients can process
+// steps as they arrive.
+func writeExecutionNDJSON(w http.ResponseWriter, r *http.Request, execution *models.WorkflowExecution) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	snakeCase := wantsSnakeCase(r)
+	controller := http.NewResponseController(w)
+	writeLine := func(v any) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if snakeCase {
+			if data, err = snakeCaseTopLevelKeys(data); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return err
+		}
+		// Writers that can't flush still receive every line, just buffered
+		if err := controller.Flush(); err != nil && err != http.ErrNotSupported {
+			return err
+		}
+		return nil
+	}
+
+	for _, step := range execution.Steps {
+		if err := writeLine(ndjsonStepLine{Type: ndjsonLineStep, ExecutionStep: step}); err != nil {
+			return err
+		}
+	}
+
+	return writeLine(ndjsonSummaryLine{
+		Type:          ndjsonLineSummary,
+		ID:            execution.ID,
+		Status:        execution.Status,
+		StartTime:     execution.StartTime,
+		EndTime:       execution.EndTime,
+		TotalDuration: execution.TotalDuration,
+		StepCount:     len(execution.Steps),
+		Metadata:      execution.Metadata,
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteExecutionNDJSON(t *testing.T) {
+	execution := &models.WorkflowExecution{
+		ID:            "exec-1",
+		Status:        models.StatusCompleted,
+		StartTime:     "2024-05-01T10:00:00Z",
+		EndTime:       "2024-05-01T10:00:01Z",
+		TotalDuration: 1000,
+		Steps: []models.ExecutionStep{
+			{StepNumber: 1, NodeType: models.NodeTypeStart, Status: models.StatusCompleted},
+			{StepNumber: 2, NodeType: models.NodeTypeEnd, Status: models.StatusCompleted, Output: models.JSONB{"message": "done"}},
+		},
+	}
+
+	readLines := func(t *testing.T, w *httptest.ResponseRecorder) []map[string]any {
+		var lines []map[string]any
+		scanner := bufio.NewScanner(w.Body)
+		for scanner.Scan() {
+			// Each line must parse on its own
+			var line map[string]any
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line), scanner.Text())
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	t.Run("one line per step then a summary", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/?format=ndjson", nil)
+		assert.True(t, wantsNDJSON(r))
+		assert.NoError(t, writeExecutionNDJSON(w, r, execution))
+
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.True(t, w.Flushed)
+
+		lines := readLines(t, w)
+		assert.Len(t, lines, 3)
+		assert.Equal(t, "step", lines[0]["type"])
+		assert.Equal(t, float64(1), lines[0]["stepNumber"])
+		assert.Equal(t, "step", lines[1]["type"])
+		assert.Equal(t, "done", lines[1]["output"].(map[string]any)["message"])
+
+		summary := lines[2]
+		assert.Equal(t, "summary", summary["type"])
+		assert.Equal(t, "exec-1", summary["id"])
+		assert.Equal(t, "completed", summary["status"])
+		assert.Equal(t, float64(2), summary["stepCount"])
+		assert.NotContains(t, summary, "steps")
+	})
+
+	t.Run("snake_case applies to every line", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/?format=ndjson&naming=snake_case", nil)
+		assert.NoError(t, writeExecutionNDJSON(w, r, execution))
+
+		lines := readLines(t, w)
+		assert.Len(t, lines, 3)
+		assert.Contains(t, lines[0], "step_number")
+		assert.Contains(t, lines[2], "step_count")
+		assert.Contains(t, lines[2], "total_duration")
+	})
+
+	t.Run("execution without steps still has a summary", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/?format=ndjson", nil)
+		assert.NoError(t, writeExecutionNDJSON(w, r, &models.WorkflowExecution{ID: "exec-2", Status: models.StatusFailed}))
+
+		lines := readLines(t, w)
+		assert.Len(t, lines, 1)
+		assert.Equal(t, "summary", lines[0]["type"])
+		assert.Equal(t, float64(0), lines[0]["stepCount"])
+	})
+}
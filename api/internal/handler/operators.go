@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"workflow-code-test/api/pkg/models"
+)
+
+// OperatorInfo describes a comparison operator accepted in workflow input
+type OperatorInfo struct {
+	Value       models.Operator `json:"value"`
+	Symbol      string          `json:"symbol"`
+	Description string          `json:"description"`
+	Aliases     []string        `json:"aliases"` // also accepted in input and normalized to Value
+}
+
+// HandleListOperators returns the operators supported by the backend so the
+// frontend doesn't need to hardcode them
+func (h *WorkflowHandler) HandleListOperators(w http.ResponseWriter, r *http.Request) {
+	operators := make([]OperatorInfo, 0, len(models.ValidOperators))
+	for operator := range models.ValidOperators {
+		operators = append(operators, OperatorInfo{
+			Value:       operator,
+			Symbol:      operator.Symbol(),
+			Description: operator.Describe(),
+			Aliases:     operator.Aliases(),
+		})
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		return operators[i].Value < operators[j].Value
+	})
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{"operators": operators})
+}
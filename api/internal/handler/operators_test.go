@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleListOperators(t *testing.T) {
+	h := &WorkflowHandler{}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/v1/operators", nil)
+
+	h.HandleListOperators(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Operators []OperatorInfo `json:"operators"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Len(t, body.Operators, len(models.ValidOperators))
+
+	// Every valid operator appears with its symbol and description
+	returned := make(map[models.Operator]OperatorInfo)
+	for _, info := range body.Operators {
+		returned[info.Value] = info
+	}
+	for operator := range models.ValidOperators {
+		info, ok := returned[operator]
+		assert.True(t, ok, "operator %s should be listed", operator)
+		assert.Equal(t, operator.Symbol(), info.Symbol)
+		assert.NotEmpty(t, info.Symbol)
+		assert.Equal(t, operator.Describe(), info.Description)
+		assert.Equal(t, operator.Aliases(), info.Aliases)
+	}
+}
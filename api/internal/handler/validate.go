@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+)
+
+// maxValidationBatchBytes caps the size of a batch of workflow definitions
+const maxValidationBatchBytes = 10 << 20
+
+// BatchValidationResponse reports each definition's validation result in request order
+type BatchValidationResponse struct {
+	Valid   bool                             `json:"valid"` // true only when every definition is valid
+	Results []workflow.BatchValidationResult `json:"results"`
+}
+
+// HandleValidateWorkflowBatch validates the array of workflow definitions in the
+// request body without storing them, for checking many workflow files at once
+func (h *WorkflowHandler) HandleValidateWorkflowBatch(w http.ResponseWriter, r *http.Request) {
+	evaluateAllBranches := r.URL.Query().Get("evaluateAllBranches") == "true"
+	slog.Debug("Validating workflow batch", "evaluateAllBranches", evaluateAllBranches)
+
+	var definitions []*models.Workflow
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxValidationBatchBytes)).Decode(&definitions); err != nil {
+		slog.Error("Failed to decode request body", "error", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.Service.ValidateWorkflows(r.Context(), definitions, evaluateAllBranches)
+	if err != nil {
+		slog.Error("Failed to validate workflow batch", "error", err)
+		if errors.Is(err, workflow.ErrInvalidValidationBatch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to validate workflows", http.StatusInternalServerError)
+		return
+	}
+
+	response := BatchValidationResponse{Valid: true, Results: results}
+	for _, result := range results {
+		if !result.Valid {
+			response.Valid = false
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"workflow-code-test/api/internal/workflow"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleValidateWorkflowBatch(t *testing.T) {
+	h := NewWorkflowHandler(workflow.NewWorkflowService(nil))
+
+	valid := `{"id": "wf-valid", "name": "Valid", "nodes": [{"id": "start", "type": "start"}, {"id": "end", "type": "end"}],
+		"edges": [{"id": "e1", "source": "start", "target": "end"}]}`
+	broken := `{"id": "wf-broken", "name": "", "nodes": [{"id": "start", "type": "start"}]}`
+	deadEnd := `{"id": "wf-dead-end", "name": "Dead End", "nodes": [{"id": "start", "type": "start"}, {"id": "condition", "type": "condition"},
+		{"id": "email", "type": "email"}, {"id": "end", "type": "end"}],
+		"edges": [{"id": "e1", "source": "start", "target": "condition"},
+			{"id": "e2", "source": "condition", "target": "email", "sourceHandle": "true"},
+			{"id": "e3", "source": "condition", "target": "end", "sourceHandle": "false"}]}`
+
+	t.Run("results are returned in input order", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		body := "[" + valid + "," + broken + "," + valid + "]"
+		h.HandleValidateWorkflowBatch(rec, httptest.NewRequest("POST", "/workflows/validate-batch", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response BatchValidationResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.False(t, response.Valid)
+		if assert.Len(t, response.Results, 3) {
+			for i, result := range response.Results {
+				assert.Equal(t, i, result.Index)
+			}
+			assert.True(t, response.Results[0].Valid)
+			assert.Equal(t, "wf-broken", response.Results[1].ID)
+			assert.False(t, response.Results[1].Valid)
+			assert.Contains(t, response.Results[1].Errors, "workflow requires a name")
+			assert.Greater(t, len(response.Results[1].Errors), 1, "every problem should be reported")
+			assert.True(t, response.Results[2].Valid)
+		}
+	})
+
+	t.Run("branch evaluation on request", func(t *testing.T) {
+		body := "[" + deadEnd + "]"
+
+		rec := httptest.NewRecorder()
+		h.HandleValidateWorkflowBatch(rec, httptest.NewRequest("POST", "/workflows/validate-batch", strings.NewReader(body)))
+		var response BatchValidationResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.True(t, response.Valid)
+
+		rec = httptest.NewRecorder()
+		h.HandleValidateWorkflowBatch(rec, httptest.NewRequest("POST", "/workflows/validate-batch?evaluateAllBranches=true", strings.NewReader(body)))
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.False(t, response.Valid)
+		assert.NotEmpty(t, response.Results[0].Branches)
+	})
+
+	t.Run("large batch keeps order", func(t *testing.T) {
+		definitions := make([]string, workflow.MaxValidationBatchSize)
+		for i := range definitions {
+			definitions[i] = fmt.Sprintf(`{"id": "wf-%d", "name": "Workflow %d"}`, i, i)
+		}
+		rec := httptest.NewRecorder()
+		body := "[" + strings.Join(definitions, ",") + "]"
+		h.HandleValidateWorkflowBatch(rec, httptest.NewRequest("POST", "/workflows/validate-batch", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response BatchValidationResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		for i, result := range response.Results {
+			assert.Equal(t, fmt.Sprintf("wf-%d", i), result.ID)
+		}
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"empty batch", `[]`, http.StatusBadRequest},
+		{"not an array", `{"name": "Single"}`, http.StatusBadRequest},
+		{"too many workflows", "[" + strings.Repeat(valid+",", workflow.MaxValidationBatchSize) + valid + "]", http.StatusBadRequest},
+		{"null entry is reported as invalid", `[null]`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.HandleValidateWorkflowBatch(rec, httptest.NewRequest("POST", "/workflows/validate-batch", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"workflow-code-test/api/pkg/node/integration"
+	"workflow-code-test/api/pkg/node/integration/weather"
+)
+
+// WeatherLookup fetches the current weather for a city outside any workflow
+type WeatherLookup interface {
+	Lookup(ctx context.Context, city string) (*weather.WeatherData, error)
+}
+
+// WeatherResponse is the current weather for a city
+type WeatherResponse struct {
+	City        string   `json:"city"`
+	Temperature float64  `json:"temperature"`
+	Windspeed   *float64 `json:"windspeed,omitempty"`
+}
+
+// HandleGetWeather returns the current weather for ?city= using the default
+// weather integration, for quick checks without running a workflow
+func (h *WorkflowHandler) HandleGetWeather(w http.ResponseWriter, r *http.Request) {
+	city := r.URL.Query().Get("city")
+	if city == "" {
+		http.Error(w, "city is required", http.StatusBadRequest)
+		return
+	}
+	if h.Weather == nil {
+		http.Error(w, "Weather lookup is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	slog.Debug("Looking up weather", "city", city)
+
+	data, err := h.Weather.Lookup(r.Context(), city)
+	if err != nil {
+		if errors.Is(err, integration.ErrCityNotFound) {
+			http.Error(w, "City not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("Failed to look up weather", "city", city, "error", err)
+		http.Error(w, "Weather API request failed", http.StatusBadGateway)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(WeatherResponse{City: data.Location, Temperature: data.Temperature, Windspeed: data.Windspeed})
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workflow-code-test/api/pkg/node/integration"
+	"workflow-code-test/api/pkg/node/integration/weather"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// weatherLookupFunc adapts a function to WeatherLookup
+type weatherLookupFunc func(ctx context.Context, city string) (*weather.WeatherData, error)
+
+func (f weatherLookupFunc) Lookup(ctx context.Context, city string) (*weather.WeatherData, error) {
+	return f(ctx, city)
+}
+
+func TestHandleGetWeather(t *testing.T) {
+	windspeed := 12.0
+	lookup := weatherLookupFunc(func(ctx context.Context, city string) (*weather.WeatherData, error) {
+		switch city {
+		case "Sydney":
+			return &weather.WeatherData{Location: "Sydney", Temperature: 21.5, Windspeed: &windspeed}, nil
+		case "Perth":
+			return nil, errors.New("weather API returned status 500")
+		}
+		return nil, fmt.Errorf("%w: %s", integration.ErrCityNotFound, city)
+	})
+
+	tests := []struct {
+		name           string
+		lookup         WeatherLookup
+		query          string
+		expectedStatus int
+	}{
+		{name: "known city", lookup: lookup, query: "?city=Sydney", expectedStatus: http.StatusOK},
+		{name: "missing city", lookup: lookup, query: "", expectedStatus: http.StatusBadRequest},
+		{name: "unknown city", lookup: lookup, query: "?city=Atlantis", expectedStatus: http.StatusNotFound},
+		{name: "upstream error", lookup: lookup, query: "?city=Perth", expectedStatus: http.StatusBadGateway},
+		{name: "not configured", lookup: nil, query: "?city=Sydney", expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &WorkflowHandler{Weather: tt.lookup}
+			w := httptest.NewRecorder()
+			h.HandleGetWeather(w, httptest.NewRequest("GET", "/api/v1/weather"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var body WeatherResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, WeatherResponse{City: "Sydney", Temperature: 21.5, Windspeed: &windspeed}, body)
+			}
+		})
+	}
+}
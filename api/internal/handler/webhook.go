@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"workflow-code-test/api/internal/workflow"
+
+	"github.com/gorilla/mux"
+)
+
+// maxWebhookPayloadBytes caps the size of a webhook payload
+const maxWebhookPayloadBytes = 1 << 20
+
+// HandleTriggerWebhook executes a workflow for an external system. The raw body
+// must be signed with the workflow's webhook secret; see workflow.WebhookSignatureHeader.
+func (h *WorkflowHandler) HandleTriggerWebhook(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	slog.Debug("Handling webhook trigger for id", "id", id)
+
+	signature := r.Header.Get(workflow.WebhookSignatureHeader)
+	if signature == "" {
+		http.Error(w, "Missing webhook signature", http.StatusUnauthorized)
+		return
+	}
+
+	// The signature covers the exact bytes sent, so the body is read before decoding
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayloadBytes))
+	if err != nil {
+		slog.Error("Failed to read webhook payload", "error", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	execution, err := h.Service.TriggerWebhook(r.Context(), id, payload, signature)
+	if err != nil {
+		slog.Error("Failed to trigger workflow from webhook", "error", err)
+		if errors.Is(err, workflow.ErrInvalidWebhookSignature) {
+			http.Error(w, "Invalid webhook signature", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, workflow.ErrWorkflowNotFound) || errors.Is(err, workflow.ErrWebhookNotConfigured) {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		// As for execute, only a payload that can't be decoded gets 400; a well-formed
+		// one that fails validation gets 422
+		if errors.Is(err, workflow.ErrInvalidWebhookPayload) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, workflow.ErrInvalidInput) || errors.Is(err, workflow.ErrInvalidWorkflowStructure) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, workflow.ErrTooManyExecutions) {
+			w.Header().Set("Retry-After", strconv.Itoa(executionRetryAfterSeconds))
+			http.Error(w, "Too many concurrent executions", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Failed to execute workflow", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeExecutionJSON(w, r, execution); err != nil {
+		slog.Error("Failed to write execution response", "error", err)
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"workflow-code-test/api/internal/workflow"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleTriggerWebhookRequiresSignature(t *testing.T) {
+	h := NewWorkflowHandler(workflow.NewWorkflowService(nil))
+
+	rec := httptest.NewRecorder()
+	h.HandleTriggerWebhook(rec, httptest.NewRequest("POST", "/workflows/wf/trigger/webhook", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// webhookService fails every webhook trigger with err
+type webhookService struct {
+	workflow.WorkflowService
+	err error
+}
+
+func (s webhookService) TriggerWebhook(ctx context.Context, id string, payload []byte, signature string) (*models.WorkflowExecution, error) {
+	return nil, s.err
+}
+
+func TestHandleTriggerWebhookErrorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"payload not decodable", fmt.Errorf("%w: %w: payload is not valid JSON", workflow.ErrInvalidInput, workflow.ErrInvalidWebhookPayload), http.StatusBadRequest},
+		{"mapped input fails validation", fmt.Errorf("%w: threshold must be between 0 and 100", workflow.ErrInvalidInput), http.StatusUnprocessableEntity},
+		{"stored workflow invalid", fmt.Errorf("%w: start not connected to end", workflow.ErrInvalidWorkflowStructure), http.StatusUnprocessableEntity},
+		{"invalid signature", workflow.ErrInvalidWebhookSignature, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewWorkflowHandler(webhookService{err: tt.err})
+			req := httptest.NewRequest("POST", "/workflows/wf/trigger/webhook", strings.NewReader(`{}`))
+			req.Header.Set(workflow.WebhookSignatureHeader, "sha256=00")
+
+			rec := httptest.NewRecorder()
+			h.HandleTriggerWebhook(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
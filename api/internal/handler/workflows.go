@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"workflow-code-test/api/internal/workflow"
+)
+
+// WorkflowSummary describes a workflow in a listing, without its nodes and edges
+type WorkflowSummary struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Version int               `json:"version"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// HandleListWorkflows lists workflows, filtered to those carrying every ?tag=key:value given
+func (h *WorkflowHandler) HandleListWorkflows(w http.ResponseWriter, r *http.Request) {
+	tags, err := parseTagFilters(r.URL.Query()["tag"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	slog.Debug("Listing workflows", "tags", tags)
+
+	workflows, err := h.Service.ListWorkflows(r.Context(), tags)
+	if err != nil {
+		slog.Error("Failed to list workflows", "error", err)
+		if errors.Is(err, workflow.ErrInvalidTag) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to list workflows", http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]WorkflowSummary, 0, len(workflows))
+	for _, wf := range workflows {
+		summaries = append(summaries, WorkflowSummary{
+			ID:      wf.ID,
+			Name:    wf.Name,
+			Version: wf.Version,
+			Tags:    wf.Tags,
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{"workflows": summaries})
+}
+
+// parseTagFilters turns key:value filters into a tag map; repeating a key with
+// different values can never match, so it is rejected
+func parseTagFilters(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("tag filter %q must be key:value", filter)
+		}
+		if existing, exists := tags[key]; exists && existing != value {
+			return nil, fmt.Errorf("tag %q is filtered by more than one value", key)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
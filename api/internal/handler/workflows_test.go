@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   []string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{name: "no filters", filters: nil, expected: nil},
+		{name: "single tag", filters: []string{"team:weather"}, expected: map[string]string{"team": "weather"}},
+		{
+			name:     "multiple tags",
+			filters:  []string{"team:weather", "env:prod"},
+			expected: map[string]string{"team": "weather", "env": "prod"},
+		},
+		{name: "value may contain colons", filters: []string{"owner:ops:oncall"}, expected: map[string]string{"owner": "ops:oncall"}},
+		{name: "empty value", filters: []string{"archived:"}, expected: map[string]string{"archived": ""}},
+		{name: "missing separator", filters: []string{"weather"}, expectErr: true},
+		{name: "missing key", filters: []string{":weather"}, expectErr: true},
+		{name: "conflicting values", filters: []string{"env:prod", "env:staging"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := parseTagFilters(tt.filters)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tags)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"workflow-code-test/api/pkg/mailer"
+)
+
+// queuedAttachment is how a queued email's attachment is stored; the data is base64
+// encoded in the JSON
+type queuedAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+// SaveQueuedEmail stores an email waiting to be re-sent, replacing the one with its ID
+func (r *WorkflowRepositoryImpl) SaveQueuedEmail(ctx context.Context, email mailer.QueuedEmail) error {
+	if err := validateUUID(email.ID); err != nil {
+		return fmt.Errorf("invalid queued email ID: %w", err)
+	}
+
+	attachments := make([]queuedAttachment, 0, len(email.Attachments))
+	for _, attachment := range email.Attachments {
+		attachments = append(attachments, queuedAttachment(attachment))
+	}
+	attachmentsJSON, err := json.Marshal(attachments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queued email attachments: %w", err)
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO email_retry_queue (id, recipient, subject, body, attachments, attempts, next_attempt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id)
+		DO UPDATE SET attempts = EXCLUDED.attempts, next_attempt = EXCLUDED.next_attempt
+	`, email.ID, email.To, email.Subject, email.Body, attachmentsJSON, email.Attempts, email.NextAttempt)
+	if err != nil {
+		return fmt.Errorf("failed to save queued email: %w", err)
+	}
+	return nil
+}
+
+// DeleteQueuedEmail removes a queued email; removing one that isn't stored is not an error
+func (r *WorkflowRepositoryImpl) DeleteQueuedEmail(ctx context.Context, id string) error {
+	if err := validateUUID(id); err != nil {
+		return fmt.Errorf("invalid queued email ID: %w", err)
+	}
+
+	if _, err := r.pool.Exec(ctx, `DELETE FROM email_retry_queue WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete queued email: %w", err)
+	}
+	return nil
+}
+
+// ListQueuedEmails returns the emails waiting to be re-sent, oldest first
+func (r *WorkflowRepositoryImpl) ListQueuedEmails(ctx context.Context) ([]mailer.QueuedEmail, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, recipient, subject, body, attachments, attempts, next_attempt
+		FROM email_retry_queue
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued emails: %w", err)
+	}
+	defer rows.Close()
+
+	var emails []mailer.QueuedEmail
+	for rows.Next() {
+		var email mailer.QueuedEmail
+		var attachmentsJSON []byte
+		if err := rows.Scan(&email.ID, &email.To, &email.Subject, &email.Body, &attachmentsJSON, &email.Attempts, &email.NextAttempt); err != nil {
+			return nil, fmt.Errorf("failed to scan queued email: %w", err)
+		}
+		var attachments []queuedAttachment
+		if err := json.Unmarshal(attachmentsJSON, &attachments); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal queued email attachments: %w", err)
+		}
+		for _, attachment := range attachments {
+			email.Attachments = append(email.Attachments, mailer.Attachment(attachment))
+		}
+		emails = append(emails, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list queued emails: %w", err)
+	}
+	return emails, nil
+}
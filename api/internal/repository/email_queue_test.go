@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workflow-code-test/api/pkg/mailer"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRepositoryImpl_QueuedEmails(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	nextAttempt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	email := mailer.QueuedEmail{
+		ID:          uuid.New().String(),
+		To:          "john@example.com",
+		Subject:     "Weather Alert",
+		Body:        "Hot in Sydney",
+		Attachments: []mailer.Attachment{{Name: "report.csv", ContentType: "text/csv", Data: []byte("city,temperature\nSydney,31.5\n")}},
+		Attempts:    1,
+		NextAttempt: nextAttempt,
+	}
+	defer repo.DeleteQueuedEmail(ctx, email.ID)
+
+	findEmail := func() (mailer.QueuedEmail, bool) {
+		emails, err := repo.ListQueuedEmails(ctx)
+		assert.NoError(t, err)
+		for _, stored := range emails {
+			if stored.ID == email.ID {
+				return stored, true
+			}
+		}
+		return mailer.QueuedEmail{}, false
+	}
+
+	assert.NoError(t, repo.SaveQueuedEmail(ctx, email))
+	stored, found := findEmail()
+	if assert.True(t, found) {
+		assert.Equal(t, email.To, stored.To)
+		assert.Equal(t, email.Subject, stored.Subject)
+		assert.Equal(t, email.Body, stored.Body)
+		assert.Equal(t, email.Attachments, stored.Attachments)
+		assert.True(t, nextAttempt.Equal(stored.NextAttempt))
+	}
+
+	// Saving again records the new attempt
+	email.Attempts = 2
+	email.NextAttempt = nextAttempt.Add(time.Minute)
+	assert.NoError(t, repo.SaveQueuedEmail(ctx, email))
+	stored, _ = findEmail()
+	assert.Equal(t, 2, stored.Attempts)
+
+	assert.NoError(t, repo.DeleteQueuedEmail(ctx, email.ID))
+	_, found = findEmail()
+	assert.False(t, found)
+
+	assert.ErrorContains(t, repo.SaveQueuedEmail(ctx, mailer.QueuedEmail{ID: "not-a-uuid"}), "invalid queued email ID")
+}
@@ -0,0 +1,644 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"workflow-code-test/api/pkg/mailer"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Pagination limits for listing executions
+const (
+	DefaultExecutionPageSize = 20
+	MaxExecutionPageSize     = 100
+)
+
+// ListExecutionsOptions controls pagination when listing executions.
+// Cursor takes precedence over Offset; Offset is kept only for older clients.
+type ListExecutionsOptions struct {
+	Limit       int
+	Cursor      string
+	Offset      int    // Deprecated: use Cursor
+	InputHash   string // only executions triggered by an input with this hash; empty doesn't filter
+	SLABreached bool   // only executions that ran longer than their workflow's maxDurationMs
+}
+
+// SearchExecutionsOptions filters executions across all workflows. Empty fields
+// don't filter; From is inclusive and To is exclusive.
+type SearchExecutionsOptions struct {
+	Status     models.Status
+	WorkflowID string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Cursor     string
+}
+
+// Number of recent executions aggregated into workflow stats
+const (
+	DefaultStatsWindow = 100
+	MaxStatsWindow     = 1000
+)
+
+// ExecutionStats aggregates the KPIs recorded on a workflow's most recent executions.
+// Averages are nil when no execution in the window recorded the value.
+type ExecutionStats struct {
+	Window             int      `json:"window"` // how many recent executions were considered
+	Executions         int      `json:"executions"`
+	Completed          int      `json:"completed"`
+	Partial            int      `json:"partial"`
+	Failed             int      `json:"failed"`
+	AlertsSent         int      `json:"alertsSent"`
+	AverageDurationMs  *float64 `json:"averageDurationMs"` // runs still going are left out
+	AverageTemperature *float64 `json:"averageTemperature"`
+	SlowestNodeID      string   `json:"slowestNodeId,omitempty"` // most often the slowest step
+}
+
+// ExecutionPage is a page of executions, newest first
+type ExecutionPage struct {
+	Executions []models.WorkflowExecution `json:"executions"`
+	NextCursor string                     `json:"nextCursor,omitempty"`
+}
+
+// executionCursor identifies the last execution of a page by its (executed_at, id) key
+type executionCursor struct {
+	ExecutedAt time.Time
+	ID         string
+}
+
+// encodeExecutionCursor builds an opaque cursor from an execution's sort key
+func encodeExecutionCursor(executedAt time.Time, id string) string {
+	raw := executedAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeExecutionCursor parses a cursor produced by encodeExecutionCursor
+func decodeExecutionCursor(cursor string) (*executionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	executedAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if err := validateUUID(parts[1]); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &executionCursor{ExecutedAt: executedAt, ID: parts[1]}, nil
+}
+
+// normalizePageSize clamps a requested page size to the allowed range
+func normalizePageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultExecutionPageSize
+	}
+	if limit > MaxExecutionPageSize {
+		return MaxExecutionPageSize
+	}
+	return limit
+}
+
+// buildExecutionPage trims a result set fetched with limit+1 rows into a page,
+// setting the next cursor when more rows remain
+func buildExecutionPage(executions []models.WorkflowExecution, limit int) *ExecutionPage {
+	page := &ExecutionPage{Executions: executions}
+	if len(executions) > limit {
+		page.Executions = executions[:limit]
+		last := page.Executions[limit-1]
+		page.NextCursor = encodeExecutionCursor(last.ExecutedAt, last.ID)
+	}
+	if page.Executions == nil {
+		page.Executions = make([]models.WorkflowExecution, 0)
+	}
+	return page
+}
+
+// CreateExecution persists a workflow execution and its steps
+func (r *WorkflowRepositoryImpl) CreateExecution(ctx context.Context, execution *models.WorkflowExecution) error {
+	if err := validateUUID(execution.ID); err != nil {
+		return fmt.Errorf("invalid execution ID: %w", err)
+	}
+	if err := validateUUID(execution.WorkflowID); err != nil {
+		return fmt.Errorf("invalid workflow ID: %w", err)
+	}
+
+	metadataJSON, err := json.Marshal(execution.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution metadata: %w", err)
+	}
+	inputJSON, err := marshalExecutionInput(execution.Input)
+	if err != nil {
+		return err
+	}
+	errorSummaryJSON, err := marshalErrorSummary(execution.ErrorSummary)
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginTxFunc(ctx, r.pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO workflow_executions (
+				id, workflow_id, status, start_time, end_time,
+				total_duration, metadata, input_hash, executed_at,
+				enqueued_at, started_at, ended_at, input, error_summary
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, $12, $13, $14)
+		`,
+			execution.ID,
+			execution.WorkflowID,
+			execution.Status,
+			execution.StartTime,
+			execution.EndTime,
+			execution.TotalDuration,
+			metadataJSON,
+			execution.InputHash,
+			execution.ExecutedAt,
+			execution.EnqueuedAt,
+			execution.StartedAt,
+			execution.EndedAt,
+			inputJSON,
+			errorSummaryJSON,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create execution: %w", err)
+		}
+
+		for i := range execution.Steps {
+			execution.Steps[i].ExecutionID = execution.ID
+		}
+		return insertExecutionSteps(ctx, tx, execution.Steps)
+	})
+}
+
+// UpdateExecution stores how a running execution ended: its status, end time, total
+// duration, metadata and error summary. Steps are stored separately as they complete.
+func (r *WorkflowRepositoryImpl) UpdateExecution(ctx context.Context, execution *models.WorkflowExecution) error {
+	if err := validateUUID(execution.ID); err != nil {
+		return ErrExecutionNotFound
+	}
+
+	metadataJSON, err := json.Marshal(execution.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution metadata: %w", err)
+	}
+	errorSummaryJSON, err := marshalErrorSummary(execution.ErrorSummary)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE workflow_executions
+		SET status = $2, end_time = $3, total_duration = $4, metadata = $5,
+			ended_at = $6, error_summary = $7
+		WHERE id = $1
+	`,
+		execution.ID,
+		execution.Status,
+		execution.EndTime,
+		execution.TotalDuration,
+		metadataJSON,
+		execution.EndedAt,
+		errorSummaryJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update execution: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrExecutionNotFound
+	}
+	return nil
+}
+
+// CreateExecutionStep persists a single execution step
+func (r *WorkflowRepositoryImpl) CreateExecutionStep(ctx context.Context, step *models.ExecutionStep) error {
+	return r.CreateExecutionSteps(ctx, []models.ExecutionStep{*step})
+}
+
+// CreateExecutionSteps persists steps in one round-trip and one transaction, so
+// either all of them are stored or none are. Steps keep their own step numbers.
+func (r *WorkflowRepositoryImpl) CreateExecutionSteps(ctx context.Context, steps []models.ExecutionStep) error {
+	for i := range steps {
+		if err := validateUUID(steps[i].ExecutionID); err != nil {
+			return fmt.Errorf("invalid execution ID for step %d: %w", steps[i].StepNumber, err)
+		}
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+
+	return pgx.BeginTxFunc(ctx, r.pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		return insertExecutionSteps(ctx, tx, steps)
+	})
+}
+
+// insertExecutionSteps inserts step rows within a transaction as a single batch,
+// in slice order
+func insertExecutionSteps(ctx context.Context, tx pgx.Tx, steps []models.ExecutionStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for i := range steps {
+		step := &steps[i]
+		outputJSON, err := json.Marshal(step.Output)
+		if err != nil {
+			return fmt.Errorf("failed to marshal step output: %w", err)
+		}
+		batch.Queue(`
+			INSERT INTO execution_steps (
+				execution_id, node_id, step_number, node_type, status,
+				label, description, duration, output, timestamp, error
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`,
+			step.ExecutionID,
+			step.NodeID,
+			step.StepNumber,
+			step.NodeType,
+			step.Status,
+			step.Label,
+			step.Description,
+			step.Duration,
+			outputJSON,
+			step.Timestamp,
+			step.Error,
+		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range steps {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("failed to create execution step: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to create execution steps: %w", err)
+	}
+	return nil
+}
+
+// GetExecution retrieves an execution by its ID, without its steps
+func (r *WorkflowRepositoryImpl) GetExecution(ctx context.Context, id string) (*models.WorkflowExecution, error) {
+	if err := validateUUID(id); err != nil {
+		return nil, ErrExecutionNotFound
+	}
+
+	var row ExecutionRow
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, workflow_id, status, start_time, end_time,
+			total_duration, metadata, COALESCE(input_hash, ''), executed_at,
+			enqueued_at, started_at, ended_at, input, error_summary
+		FROM workflow_executions
+		WHERE id = $1
+	`, id).Scan(
+		&row.ID, &row.WorkflowID, &row.Status, &row.StartTime, &row.EndTime,
+		&row.TotalDuration, &row.Metadata, &row.InputHash, &row.ExecutedAt,
+		&row.EnqueuedAt, &row.StartedAt, &row.EndedAt, &row.Input, &row.ErrorSummary,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExecutionNotFound
+		}
+		return nil, fmt.Errorf("failed to get execution: %w", err)
+	}
+
+	return toModelExecution(row)
+}
+
+// GetLatestExecution retrieves a workflow's most recent execution, without its steps
+func (r *WorkflowRepositoryImpl) GetLatestExecution(ctx context.Context, workflowID string) (*models.WorkflowExecution, error) {
+	if err := validateUUID(workflowID); err != nil {
+		return nil, ErrExecutionNotFound
+	}
+
+	var row ExecutionRow
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, workflow_id, status, start_time, end_time,
+			total_duration, metadata, COALESCE(input_hash, ''), executed_at,
+			enqueued_at, started_at, ended_at, input, error_summary
+		FROM workflow_executions
+		WHERE workflow_id = $1
+		ORDER BY executed_at DESC, id DESC
+		LIMIT 1
+	`, workflowID).Scan(
+		&row.ID, &row.WorkflowID, &row.Status, &row.StartTime, &row.EndTime,
+		&row.TotalDuration, &row.Metadata, &row.InputHash, &row.ExecutedAt,
+		&row.EnqueuedAt, &row.StartedAt, &row.EndedAt, &row.Input, &row.ErrorSummary,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExecutionNotFound
+		}
+		return nil, fmt.Errorf("failed to get latest execution: %w", err)
+	}
+
+	return toModelExecution(row)
+}
+
+// ListExecutions returns a page of a workflow's executions ordered newest first.
+// Keyset pagination on (executed_at, id) keeps pages stable as new executions are added.
+func (r *WorkflowRepositoryImpl) ListExecutions(ctx context.Context, workflowID string, opts ListExecutionsOptions) (*ExecutionPage, error) {
+	if err := validateUUID(workflowID); err != nil {
+		return nil, fmt.Errorf("invalid workflow ID: %w", err)
+	}
+
+	limit := normalizePageSize(opts.Limit)
+
+	var rows pgx.Rows
+	var err error
+	if opts.Cursor != "" {
+		cursor, cursorErr := decodeExecutionCursor(opts.Cursor)
+		if cursorErr != nil {
+			return nil, cursorErr
+		}
+		rows, err = r.pool.Query(ctx, `
+			SELECT id, workflow_id, status, start_time, end_time,
+				total_duration, metadata, COALESCE(input_hash, ''), executed_at,
+				enqueued_at, started_at, ended_at, input, error_summary
+			FROM workflow_executions
+			WHERE workflow_id = $1 AND (executed_at, id) < ($2, $3)
+				AND ($5::text = '' OR input_hash = $5)
+				AND (NOT $6::boolean OR metadata->>'slaBreached' = 'true')
+			ORDER BY executed_at DESC, id DESC
+			LIMIT $4
+		`, workflowID, cursor.ExecutedAt, cursor.ID, limit+1, opts.InputHash, opts.SLABreached)
+	} else {
+		offset := opts.Offset
+		if offset < 0 {
+			offset = 0
+		}
+		rows, err = r.pool.Query(ctx, `
+			SELECT id, workflow_id, status, start_time, end_time,
+				total_duration, metadata, COALESCE(input_hash, ''), executed_at,
+				enqueued_at, started_at, ended_at, input, error_summary
+			FROM workflow_executions
+			WHERE workflow_id = $1 AND ($4::text = '' OR input_hash = $4)
+				AND (NOT $5::boolean OR metadata->>'slaBreached' = 'true')
+			ORDER BY executed_at DESC, id DESC
+			LIMIT $2 OFFSET $3
+		`, workflowID, limit+1, offset, opts.InputHash, opts.SLABreached)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query executions: %w", err)
+	}
+
+	executions, err := scanExecutions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildExecutionPage(executions, limit), nil
+}
+
+// SearchExecutions returns a page of executions across all workflows matching the
+// given filters, newest first, using the same keyset pagination as ListExecutions
+func (r *WorkflowRepositoryImpl) SearchExecutions(ctx context.Context, opts SearchExecutionsOptions) (*ExecutionPage, error) {
+	if opts.WorkflowID != "" {
+		if err := validateUUID(opts.WorkflowID); err != nil {
+			return nil, fmt.Errorf("invalid workflow ID: %w", err)
+		}
+	}
+
+	var cursor *executionCursor
+	if opts.Cursor != "" {
+		var err error
+		if cursor, err = decodeExecutionCursor(opts.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := normalizePageSize(opts.Limit)
+	query, args := buildExecutionSearchQuery(opts, cursor, limit)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search executions: %w", err)
+	}
+
+	executions, err := scanExecutions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildExecutionPage(executions, limit), nil
+}
+
+// buildExecutionSearchQuery assembles the search query and its arguments, adding a
+// WHERE clause for each filter that is set. It fetches limit+1 rows so the caller
+// can tell whether another page follows.
+func buildExecutionSearchQuery(opts SearchExecutionsOptions, cursor *executionCursor, limit int) (string, []any) {
+	var conditions []string
+	var args []any
+	addCondition := func(clause string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(clause, placeholders...))
+	}
+
+	if opts.Status != "" {
+		addCondition("status = %s", string(opts.Status))
+	}
+	if opts.WorkflowID != "" {
+		addCondition("workflow_id = %s", opts.WorkflowID)
+	}
+	if opts.From != nil {
+		addCondition("executed_at >= %s", *opts.From)
+	}
+	if opts.To != nil {
+		addCondition("executed_at < %s", *opts.To)
+	}
+	if cursor != nil {
+		addCondition("(executed_at, id) < (%s, %s)", cursor.ExecutedAt, cursor.ID)
+	}
+
+	query := `
+		SELECT id, workflow_id, status, start_time, end_time,
+			total_duration, metadata, COALESCE(input_hash, ''), executed_at,
+			enqueued_at, started_at, ended_at, input, error_summary
+		FROM workflow_executions`
+	if len(conditions) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf("\n\t\tORDER BY executed_at DESC, id DESC\n\t\tLIMIT $%d", len(args))
+
+	return query, args
+}
+
+// scanExecutions reads and closes a result set of execution rows
+func scanExecutions(rows pgx.Rows) ([]models.WorkflowExecution, error) {
+	defer rows.Close()
+
+	var executions []models.WorkflowExecution
+	for rows.Next() {
+		var row ExecutionRow
+		err := rows.Scan(
+			&row.ID, &row.WorkflowID, &row.Status, &row.StartTime, &row.EndTime,
+			&row.TotalDuration, &row.Metadata, &row.InputHash, &row.ExecutedAt,
+			&row.EnqueuedAt, &row.StartedAt, &row.EndedAt, &row.Input, &row.ErrorSummary,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan execution row: %w", err)
+		}
+		execution, err := toModelExecution(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert execution row: %w", err)
+		}
+		executions = append(executions, *execution)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating execution rows: %w", err)
+	}
+
+	return executions, nil
+}
+
+// GetExecutionSteps retrieves all steps for an execution in step order
+func (r *WorkflowRepositoryImpl) GetExecutionSteps(ctx context.Context, executionID string) ([]models.ExecutionStep, error) {
+	if err := validateUUID(executionID); err != nil {
+		return nil, fmt.Errorf("invalid execution ID: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT execution_id, node_id, step_number, node_type, status,
+			label, description, duration, output, timestamp, error
+		FROM execution_steps
+		WHERE execution_id = $1
+		ORDER BY step_number
+	`, executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution steps: %w", err)
+	}
+	defer rows.Close()
+
+	steps := make([]models.ExecutionStep, 0)
+	for rows.Next() {
+		var row ExecutionStepRow
+		err := rows.Scan(
+			&row.ExecutionID, &row.NodeID, &row.StepNumber, &row.NodeType, &row.Status,
+			&row.Label, &row.Description, &row.Duration, &row.Output, &row.Timestamp, &row.Error,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan execution step row: %w", err)
+		}
+		step, err := toModelExecutionStep(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert execution step row: %w", err)
+		}
+		steps = append(steps, *step)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating execution step rows: %w", err)
+	}
+
+	return steps, nil
+}
+
+// normalizeStatsWindow clamps a requested stats window to the allowed range
+func normalizeStatsWindow(window int) int {
+	if window <= 0 {
+		return DefaultStatsWindow
+	}
+	if window > MaxStatsWindow {
+		return MaxStatsWindow
+	}
+	return window
+}
+
+// GetExecutionStats aggregates the KPIs in the metadata of a workflow's most recent
+// executions. Executions recorded before KPIs existed count towards the totals only.
+func (r *WorkflowRepositoryImpl) GetExecutionStats(ctx context.Context, workflowID string, window int) (*ExecutionStats, error) {
+	if err := validateUUID(workflowID); err != nil {
+		return nil, fmt.Errorf("invalid workflow ID: %w", err)
+	}
+
+	stats := &ExecutionStats{Window: normalizeStatsWindow(window)}
+	var slowestNodeID *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'completed'),
+			COUNT(*) FILTER (WHERE status = 'partial'),
+			COUNT(*) FILTER (WHERE status = 'failed'),
+			COUNT(*) FILTER (WHERE (metadata->'kpis'->>'alertSent')::boolean),
+			AVG(total_duration) FILTER (WHERE status <> 'running')::float8,
+			AVG((metadata->'kpis'->>'temperature')::float8),
+			MODE() WITHIN GROUP (ORDER BY metadata->'kpis'->>'slowestNodeId')
+		FROM (
+			SELECT status, total_duration, metadata
+			FROM workflow_executions
+			WHERE workflow_id = $1
+			ORDER BY executed_at DESC, id DESC
+			LIMIT $2
+		) recent
+	`, workflowID, stats.Window).Scan(
+		&stats.Executions,
+		&stats.Completed,
+		&stats.Partial,
+		&stats.Failed,
+		&stats.AlertsSent,
+		&stats.AverageDurationMs,
+		&stats.AverageTemperature,
+		&slowestNodeID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate execution stats: %w", err)
+	}
+	if slowestNodeID != nil {
+		stats.SlowestNodeID = *slowestNodeID
+	}
+
+	return stats, nil
+}
+
+// LastAlertSent returns when an email step last sent an alert to recipient about
+// city at or after since, across all workflows. Steps are matched on their
+// details.recipient key, as stored addresses are masked; steps recorded before the key
+// fall back to their unmasked emailContent.to. Recipients compare case-insensitively.
+// Alerts recorded before email steps noted their city only match an empty city, and
+// emails sent on a condition's false branch are not alerts. Alerts queued for retry
+// count as sent.
+func (r *WorkflowRepositoryImpl) LastAlertSent(ctx context.Context, recipient, city string, since time.Time) (time.Time, bool, error) {
+	var sentAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT e.executed_at
+		FROM execution_steps s
+		JOIN workflow_executions e ON e.id = s.execution_id
+		WHERE s.node_type = $1
+			AND s.status = ANY($2)
+			AND (s.output->'details'->>'recipient' = $3
+				OR (s.output->'details'->>'recipient' IS NULL AND lower(s.output->'emailContent'->>'to') = lower($6)))
+			AND COALESCE(s.output->'details'->>'city', '') = $4
+			AND COALESCE(s.output->'details'->>'branch', 'true') = 'true'
+			AND e.executed_at >= $5
+		ORDER BY e.executed_at DESC
+		LIMIT 1
+	`, models.NodeTypeEmail, []string{string(models.StatusCompleted), string(models.StatusQueued)},
+		mailer.RecipientKey(recipient), city, since, strings.TrimSpace(recipient)).Scan(&sentAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, fmt.Errorf("failed to look up recent alerts: %w", err)
+	}
+	return sentAt, true, nil
+}
@@ -0,0 +1,719 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"workflow-code-test/api/pkg/mailer"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionCursor(t *testing.T) {
+	executedAt := time.Date(2024, 5, 1, 10, 30, 0, 123456789, time.UTC)
+	id := uuid.New().String()
+
+	cursor, err := decodeExecutionCursor(encodeExecutionCursor(executedAt, id))
+	assert.NoError(t, err)
+	assert.True(t, executedAt.Equal(cursor.ExecutedAt))
+	assert.Equal(t, id, cursor.ID)
+
+	invalid := []string{
+		"not base64!",
+		"bm8tc2VwYXJhdG9y", // "no-separator"
+		encodeExecutionCursor(executedAt, "not-a-uuid"),
+	}
+	for _, value := range invalid {
+		_, err := decodeExecutionCursor(value)
+		assert.ErrorIs(t, err, ErrInvalidCursor, value)
+	}
+}
+
+func TestBuildExecutionPage(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	executions := make([]models.WorkflowExecution, 3)
+	for i := range executions {
+		executions[i] = models.WorkflowExecution{
+			ID:         uuid.New().String(),
+			ExecutedAt: base.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+
+	t.Run("more rows than limit", func(t *testing.T) {
+		page := buildExecutionPage(executions, 2)
+		assert.Len(t, page.Executions, 2)
+		assert.Equal(t, encodeExecutionCursor(executions[1].ExecutedAt, executions[1].ID), page.NextCursor)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		page := buildExecutionPage(executions, 3)
+		assert.Len(t, page.Executions, 3)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		page := buildExecutionPage(nil, 3)
+		assert.NotNil(t, page.Executions)
+		assert.Empty(t, page.Executions)
+	})
+}
+
+func TestNormalizePageSize(t *testing.T) {
+	assert.Equal(t, DefaultExecutionPageSize, normalizePageSize(0))
+	assert.Equal(t, 5, normalizePageSize(5))
+	assert.Equal(t, MaxExecutionPageSize, normalizePageSize(MaxExecutionPageSize+1))
+}
+
+func TestNormalizeStatsWindow(t *testing.T) {
+	assert.Equal(t, DefaultStatsWindow, normalizeStatsWindow(0))
+	assert.Equal(t, 10, normalizeStatsWindow(10))
+	assert.Equal(t, MaxStatsWindow, normalizeStatsWindow(MaxStatsWindow+1))
+}
+
+func TestWorkflowRepositoryImpl_ListExecutions(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Paginated Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	// Two executions share a timestamp so the id tie-breaker is exercised
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	executedAt := []time.Time{base, base, base.Add(-time.Minute), base.Add(-2 * time.Minute), base.Add(-3 * time.Minute)}
+	for _, at := range executedAt {
+		execution := &models.WorkflowExecution{
+			ID:         uuid.New().String(),
+			WorkflowID: workflow.ID,
+			Status:     models.StatusCompleted,
+			ExecutedAt: at,
+			Steps: []models.ExecutionStep{
+				{NodeID: "start", StepNumber: 1, NodeType: models.NodeTypeStart, Status: models.StatusCompleted},
+			},
+		}
+		assert.NoError(t, repo.CreateExecution(ctx, execution))
+	}
+
+	// Walk every page and check each execution appears exactly once, newest first
+	seen := make(map[string]bool)
+	var previous *models.WorkflowExecution
+	cursor := ""
+	for {
+		page, err := repo.ListExecutions(ctx, workflow.ID, ListExecutionsOptions{Limit: 2, Cursor: cursor})
+		assert.NoError(t, err)
+		for i := range page.Executions {
+			execution := page.Executions[i]
+			assert.False(t, seen[execution.ID], "execution returned twice")
+			seen[execution.ID] = true
+			if previous != nil {
+				assert.False(t, execution.ExecutedAt.After(previous.ExecutedAt), "executions should be newest first")
+			}
+			previous = &execution
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Len(t, seen, len(executedAt))
+
+	steps, err := repo.GetExecutionSteps(ctx, previous.ID)
+	assert.NoError(t, err)
+	assert.Len(t, steps, 1)
+}
+
+func TestBuildExecutionSearchQuery(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	workflowID := uuid.New().String()
+
+	t.Run("no filters", func(t *testing.T) {
+		query, args := buildExecutionSearchQuery(SearchExecutionsOptions{}, nil, 20)
+		assert.NotContains(t, query, "WHERE")
+		assert.Contains(t, query, "LIMIT $1")
+		assert.Equal(t, []any{21}, args)
+	})
+
+	t.Run("combined filters", func(t *testing.T) {
+		cursor := &executionCursor{ExecutedAt: to, ID: uuid.New().String()}
+		opts := SearchExecutionsOptions{Status: models.StatusFailed, WorkflowID: workflowID, From: &from, To: &to}
+
+		query, args := buildExecutionSearchQuery(opts, cursor, 5)
+		assert.Contains(t, query, "WHERE status = $1 AND workflow_id = $2 AND executed_at >= $3 AND executed_at < $4 AND (executed_at, id) < ($5, $6)")
+		assert.Contains(t, query, "LIMIT $7")
+		assert.Equal(t, []any{"failed", workflowID, from, to, cursor.ExecutedAt, cursor.ID, 6}, args)
+	})
+}
+
+func TestWorkflowRepositoryImpl_SearchExecutions(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	first := &models.Workflow{ID: uuid.New().String(), Name: "First Workflow"}
+	second := &models.Workflow{ID: uuid.New().String(), Name: "Second Workflow"}
+	for _, workflow := range []*models.Workflow{first, second} {
+		assert.NoError(t, repo.Create(ctx, workflow))
+		defer repo.Delete(ctx, workflow.ID)
+	}
+
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	seeded := []struct {
+		workflowID string
+		status     models.Status
+		executedAt time.Time
+	}{
+		{first.ID, models.StatusFailed, base},
+		{first.ID, models.StatusCompleted, base.Add(-time.Hour)},
+		{first.ID, models.StatusFailed, base.Add(-48 * time.Hour)},
+		{second.ID, models.StatusFailed, base.Add(-2 * time.Hour)},
+		{second.ID, models.StatusCompleted, base.Add(-3 * time.Hour)},
+	}
+	for _, s := range seeded {
+		assert.NoError(t, repo.CreateExecution(ctx, &models.WorkflowExecution{
+			ID:         uuid.New().String(),
+			WorkflowID: s.workflowID,
+			Status:     s.status,
+			ExecutedAt: s.executedAt,
+		}))
+	}
+
+	from := base.Add(-24 * time.Hour)
+	to := base.Add(time.Second)
+	tests := []struct {
+		name     string
+		opts     SearchExecutionsOptions
+		expected int
+	}{
+		{"status across workflows in range", SearchExecutionsOptions{Status: models.StatusFailed, From: &from, To: &to}, 2},
+		{"status and workflow", SearchExecutionsOptions{Status: models.StatusFailed, WorkflowID: first.ID}, 2},
+		{"status, workflow and range", SearchExecutionsOptions{Status: models.StatusFailed, WorkflowID: first.ID, From: &from}, 1},
+		{"workflow and range", SearchExecutionsOptions{WorkflowID: second.ID, From: &from, To: &to}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.SearchExecutions(ctx, tt.opts)
+			assert.NoError(t, err)
+			assert.Len(t, page.Executions, tt.expected)
+			for _, execution := range page.Executions {
+				if tt.opts.Status != "" {
+					assert.Equal(t, tt.opts.Status, execution.Status)
+				}
+				if tt.opts.WorkflowID != "" {
+					assert.Equal(t, tt.opts.WorkflowID, execution.WorkflowID)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowRepositoryImpl_ListExecutionsByInputHash(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Hashed Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	repeated := models.WorkflowInput{Name: "Alice", Email: "alice@example.com", City: "Sydney", Threshold: 25, Operator: models.OperatorGreaterThan}
+	other := models.WorkflowInput{Name: "Bob", Email: "bob@example.com", City: "Melbourne", Threshold: 10, Operator: models.OperatorLessThan}
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	for i, hash := range []string{repeated.Hash(), other.Hash(), repeated.Hash(), ""} {
+		assert.NoError(t, repo.CreateExecution(ctx, &models.WorkflowExecution{
+			ID:         uuid.New().String(),
+			WorkflowID: workflow.ID,
+			Status:     models.StatusCompleted,
+			InputHash:  hash,
+			ExecutedAt: base.Add(-time.Duration(i) * time.Minute),
+		}))
+	}
+
+	page, err := repo.ListExecutions(ctx, workflow.ID, ListExecutionsOptions{InputHash: repeated.Hash()})
+	assert.NoError(t, err)
+	assert.Len(t, page.Executions, 2)
+	for _, execution := range page.Executions {
+		assert.Equal(t, repeated.Hash(), execution.InputHash)
+	}
+
+	// Without a filter every execution is listed, including ones recorded without a hash
+	page, err = repo.ListExecutions(ctx, workflow.ID, ListExecutionsOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Executions, 4)
+	assert.Empty(t, page.Executions[3].InputHash)
+}
+
+func TestWorkflowRepositoryImpl_ListExecutionsBySLABreached(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	maxDurationMs := int64(2000)
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "SLA Workflow", MaxDurationMs: &maxDurationMs}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	stored, err := repo.Get(ctx, workflow.ID)
+	if assert.NoError(t, err) && assert.NotNil(t, stored.MaxDurationMs) {
+		assert.Equal(t, maxDurationMs, *stored.MaxDurationMs)
+	}
+
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	for i, metadata := range []models.JSONB{{"slaBreached": true}, {"slaBreached": false}, {}, {"slaBreached": true}} {
+		assert.NoError(t, repo.CreateExecution(ctx, &models.WorkflowExecution{
+			ID:         uuid.New().String(),
+			WorkflowID: workflow.ID,
+			Status:     models.StatusCompleted,
+			Metadata:   metadata,
+			ExecutedAt: base.Add(-time.Duration(i) * time.Minute),
+		}))
+	}
+
+	page, err := repo.ListExecutions(ctx, workflow.ID, ListExecutionsOptions{SLABreached: true})
+	assert.NoError(t, err)
+	assert.Len(t, page.Executions, 2)
+	for _, execution := range page.Executions {
+		assert.Equal(t, true, execution.Metadata["slaBreached"])
+	}
+
+	page, err = repo.ListExecutions(ctx, workflow.ID, ListExecutionsOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Executions, 4)
+}
+
+func TestWorkflowRepositoryImpl_GetExecutionStats(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Stats Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	executions := []*models.WorkflowExecution{
+		{Status: models.StatusCompleted, TotalDuration: 100, Metadata: models.JSONB{
+			"kpis": map[string]any{"alertSent": true, "temperature": 30.0, "slowestNodeId": "weather-api"},
+		}},
+		{Status: models.StatusCompleted, TotalDuration: 300, Metadata: models.JSONB{
+			"kpis": map[string]any{"alertSent": false, "temperature": 20.0, "slowestNodeId": "weather-api"},
+		}},
+		// Recorded before KPIs existed
+		{Status: models.StatusFailed, TotalDuration: 200},
+	}
+	for i, execution := range executions {
+		execution.ID = uuid.New().String()
+		execution.WorkflowID = workflow.ID
+		execution.ExecutedAt = base.Add(-time.Duration(i) * time.Minute)
+		assert.NoError(t, repo.CreateExecution(ctx, execution))
+	}
+
+	stats, err := repo.GetExecutionStats(ctx, workflow.ID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultStatsWindow, stats.Window)
+	assert.Equal(t, 3, stats.Executions)
+	assert.Equal(t, 2, stats.Completed)
+	assert.Equal(t, 1, stats.Failed)
+	assert.Equal(t, 1, stats.AlertsSent)
+	assert.InDelta(t, 200.0, *stats.AverageDurationMs, 0.001)
+	assert.InDelta(t, 25.0, *stats.AverageTemperature, 0.001)
+	assert.Equal(t, "weather-api", stats.SlowestNodeID)
+
+	// A window of one only considers the newest execution
+	stats, err = repo.GetExecutionStats(ctx, workflow.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Executions)
+	assert.InDelta(t, 30.0, *stats.AverageTemperature, 0.001)
+}
+
+func TestWorkflowRepositoryImpl_LastAlertSent(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Alert Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	recipient := uuid.New().String() + "@example.com"
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	// recordAlert stores an email step: sent steps carry the recipient key and a
+	// masked address, legacy ones only the unmasked address, and suppressed ones neither
+	recordAlert := func(executedAt time.Time, city, kind string) {
+		execution := &models.WorkflowExecution{
+			ID:         uuid.New().String(),
+			WorkflowID: workflow.ID,
+			Status:     models.StatusCompleted,
+			ExecutedAt: executedAt,
+		}
+		assert.NoError(t, repo.CreateExecution(ctx, execution))
+		details := map[string]any{"city": city}
+		output := models.JSONB{"details": details}
+		switch kind {
+		case "sent":
+			details["recipient"] = mailer.RecipientKey(recipient)
+			output["emailContent"] = map[string]any{"to": models.MaskEmail(recipient)}
+		case "legacy":
+			output["emailContent"] = map[string]any{"to": recipient}
+		}
+		assert.NoError(t, repo.CreateExecutionStep(ctx, &models.ExecutionStep{
+			ExecutionID: execution.ID,
+			NodeID:      "email",
+			StepNumber:  1,
+			NodeType:    models.NodeTypeEmail,
+			Status:      models.StatusCompleted,
+			Output:      output,
+		}))
+	}
+	recordAlert(base.Add(-4*time.Hour), "Hobart", "legacy")
+	recordAlert(base.Add(-2*time.Hour), "Sydney", "sent")
+	recordAlert(base.Add(-30*time.Minute), "Sydney", "sent")
+	// Suppressed alerts have no recipient and don't count as sent
+	recordAlert(base.Add(-5*time.Minute), "Sydney", "suppressed")
+
+	sentAt, found, err := repo.LastAlertSent(ctx, strings.ToUpper(recipient), "Sydney", base.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, sentAt.Equal(base.Add(-30*time.Minute)))
+
+	_, found, err = repo.LastAlertSent(ctx, recipient, "Sydney", base.Add(-10*time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// Steps stored before the recipient key match on their unmasked address
+	sentAt, found, err = repo.LastAlertSent(ctx, recipient, "Hobart", base.Add(-5*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, sentAt.Equal(base.Add(-4*time.Hour)))
+
+	_, found, err = repo.LastAlertSent(ctx, recipient, "Melbourne", base.Add(-3*time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestCreateExecutionStepsValidatesExecutionIDs(t *testing.T) {
+	repo := NewWorkflowRepository(nil)
+	err := repo.CreateExecutionSteps(context.Background(), []models.ExecutionStep{
+		{ExecutionID: uuid.New().String(), StepNumber: 1},
+		{ExecutionID: "not-a-uuid", StepNumber: 2},
+	})
+	assert.ErrorContains(t, err, "invalid execution ID for step 2")
+
+	// Nothing to store needs no connection
+	assert.NoError(t, repo.CreateExecutionSteps(context.Background(), nil))
+}
+
+// newStepsExecution stores a step-less execution to attach steps to
+func newStepsExecution(tb testing.TB, repo WorkflowRepository, workflowID string) string {
+	execution := &models.WorkflowExecution{
+		ID:         uuid.New().String(),
+		WorkflowID: workflowID,
+		Status:     models.StatusCompleted,
+		ExecutedAt: time.Now().UTC(),
+	}
+	assert.NoError(tb, repo.CreateExecution(context.Background(), execution))
+	return execution.ID
+}
+
+// stepsFor builds count steps for an execution, numbered from 1
+func stepsFor(executionID string, count int) []models.ExecutionStep {
+	steps := make([]models.ExecutionStep, count)
+	for i := range steps {
+		steps[i] = models.ExecutionStep{
+			ExecutionID: executionID,
+			NodeID:      fmt.Sprintf("node-%d", i+1),
+			StepNumber:  i + 1,
+			NodeType:    models.NodeTypeForm,
+			Status:      models.StatusCompleted,
+			Output:      models.JSONB{"message": fmt.Sprintf("step %d", i+1)},
+		}
+	}
+	return steps
+}
+
+func TestWorkflowRepositoryImpl_CreateExecutionSteps(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Batched Steps Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	t.Run("stores every step in order", func(t *testing.T) {
+		executionID := newStepsExecution(t, repo, workflow.ID)
+		assert.NoError(t, repo.CreateExecutionSteps(ctx, stepsFor(executionID, 5)))
+
+		steps, err := repo.GetExecutionSteps(ctx, executionID)
+		assert.NoError(t, err)
+		assert.Len(t, steps, 5)
+		for i, step := range steps {
+			assert.Equal(t, i+1, step.StepNumber)
+			assert.Equal(t, fmt.Sprintf("node-%d", i+1), step.NodeID)
+			assert.Equal(t, fmt.Sprintf("step %d", i+1), step.Output["message"])
+		}
+	})
+
+	t.Run("stores nothing when one step fails", func(t *testing.T) {
+		executionID := newStepsExecution(t, repo, workflow.ID)
+		steps := stepsFor(executionID, 3)
+		steps[2].StepNumber = 1 // collides with the first step
+		assert.Error(t, repo.CreateExecutionSteps(ctx, steps))
+
+		stored, err := repo.GetExecutionSteps(ctx, executionID)
+		assert.NoError(t, err)
+		assert.Empty(t, stored)
+	})
+}
+
+// BenchmarkCreateExecutionSteps compares storing a run's steps one by one with a single batch
+func BenchmarkCreateExecutionSteps(b *testing.B) {
+	pool := setupTestPgxDB(b)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Step Benchmark Workflow"}
+	assert.NoError(b, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	const stepsPerExecution = 6 // the demo weather workflow's node count
+
+	b.Run("per-step", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			steps := stepsFor(newStepsExecution(b, repo, workflow.ID), stepsPerExecution)
+			b.StartTimer()
+			for j := range steps {
+				if err := repo.CreateExecutionStep(ctx, &steps[j]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			steps := stepsFor(newStepsExecution(b, repo, workflow.ID), stepsPerExecution)
+			b.StartTimer()
+			if err := repo.CreateExecutionSteps(ctx, steps); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestWorkflowRepositoryImpl_UpdateExecution(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Running Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	startedAt := time.Now().UTC()
+	execution := &models.WorkflowExecution{
+		ID:         uuid.New().String(),
+		WorkflowID: workflow.ID,
+		Status:     models.StatusRunning,
+		ExecutedAt: startedAt,
+		StartedAt:  &startedAt,
+		Metadata:   models.JSONB{"triggeredBy": "Alice"},
+	}
+	assert.NoError(t, repo.CreateExecution(ctx, execution))
+
+	// Steps stored so far are readable while the execution is running
+	steps := stepsFor(execution.ID, 2)
+	assert.NoError(t, repo.CreateExecutionStep(ctx, &steps[0]))
+	fetched, err := repo.GetExecution(ctx, execution.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusRunning, fetched.Status)
+	assert.Nil(t, fetched.EndedAt)
+	stored, err := repo.GetExecutionSteps(ctx, execution.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored, 1)
+
+	assert.NoError(t, repo.CreateExecutionStep(ctx, &steps[1]))
+	endedAt := startedAt.Add(time.Second)
+	execution.Status = models.StatusFailed
+	execution.EndTime = endedAt.Format(time.RFC3339)
+	execution.EndedAt = &endedAt
+	execution.TotalDuration = 1000
+	execution.Metadata["kpis"] = map[string]any{"totalDurationMs": 1000}
+	execution.ErrorSummary = &models.ExecutionErrorSummary{NodeID: "node-2", NodeType: models.NodeTypeForm, Message: "boom"}
+	assert.NoError(t, repo.UpdateExecution(ctx, execution))
+
+	fetched, err = repo.GetExecution(ctx, execution.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, fetched.Status)
+	assert.Equal(t, int64(1000), fetched.TotalDuration)
+	assert.Equal(t, execution.ErrorSummary, fetched.ErrorSummary)
+	assert.Contains(t, fetched.Metadata, "kpis")
+	if assert.NotNil(t, fetched.EndedAt) {
+		assert.WithinDuration(t, endedAt, *fetched.EndedAt, time.Millisecond)
+	}
+	stored, err = repo.GetExecutionSteps(ctx, execution.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	// Unknown executions aren't silently ignored
+	assert.ErrorIs(t, repo.UpdateExecution(ctx, &models.WorkflowExecution{ID: uuid.New().String()}), ErrExecutionNotFound)
+	assert.ErrorIs(t, repo.UpdateExecution(ctx, &models.WorkflowExecution{ID: "not-a-uuid"}), ErrExecutionNotFound)
+}
+
+func TestExecutionInputRoundTrip(t *testing.T) {
+	input := &models.WorkflowInput{
+		Name:      "Alice",
+		Email:     "a***@example.com",
+		City:      "Sydney",
+		Threshold: 25,
+		Operator:  models.OperatorGreaterThan,
+		Workflow:  models.JSONB{"id": "embedded", "nodes": []any{}},
+	}
+
+	data, err := marshalExecutionInput(input)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "embedded", "the embedded workflow is not stored")
+	assert.NotNil(t, input.Workflow, "the caller's input is left as it was")
+
+	execution, err := toModelExecution(ExecutionRow{ID: uuid.New().String(), Input: data})
+	assert.NoError(t, err)
+	expected := *input
+	expected.Workflow = nil
+	assert.Equal(t, expected.Name, execution.Input.Name)
+	assert.Equal(t, expected.Email, execution.Input.Email)
+	assert.Equal(t, expected.City, execution.Input.City)
+	assert.Equal(t, expected.Threshold, execution.Input.Threshold)
+	assert.Equal(t, expected.Operator, execution.Input.Operator)
+	assert.Nil(t, execution.Input.Workflow)
+
+	// Executions recorded before inputs were stored have none
+	data, err = marshalExecutionInput(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	execution, err = toModelExecution(ExecutionRow{ID: uuid.New().String()})
+	assert.NoError(t, err)
+	assert.Nil(t, execution.Input)
+}
+
+func TestExecutionErrorSummaryRoundTrip(t *testing.T) {
+	summary := &models.ExecutionErrorSummary{NodeID: "weather-api", NodeType: models.NodeTypeIntegration, Message: "Weather API error: status 503"}
+
+	data, err := marshalErrorSummary(summary)
+	assert.NoError(t, err)
+	execution, err := toModelExecution(ExecutionRow{ID: uuid.New().String(), Status: string(models.StatusFailed), ErrorSummary: data})
+	assert.NoError(t, err)
+	assert.Equal(t, summary, execution.ErrorSummary)
+
+	// Runs that didn't fail, and older executions, have none
+	data, err = marshalErrorSummary(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	execution, err = toModelExecution(ExecutionRow{ID: uuid.New().String()})
+	assert.NoError(t, err)
+	assert.Nil(t, execution.ErrorSummary)
+}
+
+func TestWorkflowRepositoryImpl_ExecutionInput(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Input Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	input := models.WorkflowInput{Name: "Alice", Email: "alice@example.com", City: "Sydney", Threshold: 25, Operator: models.OperatorGreaterThan}
+	stored := input.Redacted()
+	withInput := &models.WorkflowExecution{
+		ID:         uuid.New().String(),
+		WorkflowID: workflow.ID,
+		Status:     models.StatusCompleted,
+		ExecutedAt: time.Now().UTC(),
+		Input:      &stored,
+	}
+	withoutInput := &models.WorkflowExecution{
+		ID:         uuid.New().String(),
+		WorkflowID: workflow.ID,
+		Status:     models.StatusCompleted,
+		ExecutedAt: time.Now().UTC().Add(-time.Minute),
+	}
+	assert.NoError(t, repo.CreateExecution(ctx, withInput))
+	assert.NoError(t, repo.CreateExecution(ctx, withoutInput))
+
+	fetched, err := repo.GetExecution(ctx, withInput.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, fetched.Input) {
+		assert.Equal(t, "a***@example.com", fetched.Input.Email)
+		assert.Equal(t, "Sydney", fetched.Input.City)
+		assert.Equal(t, 25.0, fetched.Input.Threshold)
+		assert.Equal(t, models.OperatorGreaterThan, fetched.Input.Operator)
+	}
+
+	fetched, err = repo.GetExecution(ctx, withoutInput.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, fetched.Input)
+}
+
+func TestWorkflowRepositoryImpl_GetLatestExecution(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Latest Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+
+	_, err := repo.GetLatestExecution(ctx, workflow.ID)
+	assert.ErrorIs(t, err, ErrExecutionNotFound)
+
+	// Stored out of order so the newest isn't simply the last one written
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	var latestID string
+	for _, offset := range []time.Duration{-2 * time.Minute, 0, -time.Hour} {
+		execution := &models.WorkflowExecution{
+			ID:         uuid.New().String(),
+			WorkflowID: workflow.ID,
+			Status:     models.StatusCompleted,
+			ExecutedAt: base.Add(offset),
+		}
+		assert.NoError(t, repo.CreateExecution(ctx, execution))
+		if offset == 0 {
+			latestID = execution.ID
+		}
+	}
+
+	latest, err := repo.GetLatestExecution(ctx, workflow.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, latestID, latest.ID)
+	assert.True(t, latest.ExecutedAt.Equal(base))
+}
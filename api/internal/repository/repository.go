@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
+	"workflow-code-test/api/pkg/mailer"
 	"workflow-code-test/api/pkg/models"
 
 	"github.com/google/uuid"
@@ -18,8 +20,25 @@ type WorkflowRepository interface {
 	Get(ctx context.Context, id string) (*models.Workflow, error)
 	Update(ctx context.Context, workflow *models.Workflow) error
 	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, tags map[string]string) ([]models.Workflow, error)
 	GetNodes(ctx context.Context, workflowID string) ([]models.Node, error)
 	GetEdges(ctx context.Context, workflowID string) ([]models.Edge, error)
+	CreateExecution(ctx context.Context, execution *models.WorkflowExecution) error
+	UpdateExecution(ctx context.Context, execution *models.WorkflowExecution) error
+	GetExecution(ctx context.Context, id string) (*models.WorkflowExecution, error)
+	GetLatestExecution(ctx context.Context, workflowID string) (*models.WorkflowExecution, error)
+	ListExecutions(ctx context.Context, workflowID string, opts ListExecutionsOptions) (*ExecutionPage, error)
+	SearchExecutions(ctx context.Context, opts SearchExecutionsOptions) (*ExecutionPage, error)
+	GetExecutionStats(ctx context.Context, workflowID string, window int) (*ExecutionStats, error)
+	LastAlertSent(ctx context.Context, recipient, city string, since time.Time) (time.Time, bool, error)
+	GetState(ctx context.Context, workflowID, key string) (any, bool, error)
+	SetState(ctx context.Context, workflowID, key string, value any) error
+	SaveQueuedEmail(ctx context.Context, email mailer.QueuedEmail) error
+	DeleteQueuedEmail(ctx context.Context, id string) error
+	ListQueuedEmails(ctx context.Context) ([]mailer.QueuedEmail, error)
+	CreateExecutionStep(ctx context.Context, step *models.ExecutionStep) error
+	CreateExecutionSteps(ctx context.Context, steps []models.ExecutionStep) error
+	GetExecutionSteps(ctx context.Context, executionID string) ([]models.ExecutionStep, error)
 }
 
 // WorkflowRepositoryImpl implements the WorkflowRepository interface
@@ -48,12 +67,29 @@ func (r *WorkflowRepositoryImpl) Create(ctx context.Context, workflow *models.Wo
 			workflow.Version = 1
 		}
 		
+		defaultInputJSON, err := marshalDefaultInput(workflow.DefaultInput)
+		if err != nil {
+			return err
+		}
+		tagsJSON, err := marshalTags(workflow.Tags)
+		if err != nil {
+			return err
+		}
+		webhookJSON, err := marshalWebhook(workflow.Webhook)
+		if err != nil {
+			return err
+		}
+		hooksJSON, err := marshalHooks(workflow.Hooks)
+		if err != nil {
+			return err
+		}
+		
 		// Insert workflow
-		err := tx.QueryRow(ctx, `
-			INSERT INTO workflows (id, name, version)
-			VALUES ($1, $2, $3)
+		err = tx.QueryRow(ctx, `
+			INSERT INTO workflows (id, name, version, default_input, tags, webhook, hooks, max_duration_ms)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			RETURNING created_at, updated_at
-		`, workflow.ID, workflow.Name, workflow.Version).Scan(&workflow.CreatedAt, &workflow.UpdatedAt)
+		`, workflow.ID, workflow.Name, workflow.Version, defaultInputJSON, tagsJSON, webhookJSON, hooksJSON, workflow.MaxDurationMs).Scan(&workflow.CreatedAt, &workflow.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("failed to create workflow: %w", err)
 		}
@@ -72,7 +108,9 @@ func (r *WorkflowRepositoryImpl) Create(ctx context.Context, workflow *models.Wo
 				)
 				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			`, 
-				node.ID,
+				// The row gets its own ID, as Update does; node IDs such as "start"
+				// aren't UUIDs and repeat across workflows
+				uuid.NewString(),
 				workflow.ID,
 				node.ID,
 				node.Type,
@@ -98,9 +136,9 @@ func (r *WorkflowRepositoryImpl) Create(ctx context.Context, workflow *models.Wo
 				INSERT INTO workflow_edges (
 					id, workflow_id, source_node_id, target_node_id,
 					edge_id, type, animated, stroke_color, stroke_width,
-					label, source_handle, label_style
+					label, source_handle, label_style, priority
 				)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 			`,
 				edge.ID,
 				workflow.ID,
@@ -114,6 +152,7 @@ func (r *WorkflowRepositoryImpl) Create(ctx context.Context, workflow *models.Wo
 				edge.Label,
 				edge.SourceHandle,
 				labelStyleJSON,
+				edge.Priority,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to create edge: %w", err)
@@ -132,14 +171,20 @@ func (r *WorkflowRepositoryImpl) Get(ctx context.Context, id string) (*models.Wo
 
 	// Get workflow
 	var workflow models.Workflow
+	var defaultInputJSON, tagsJSON, webhookJSON, hooksJSON []byte
 	err := r.pool.QueryRow(ctx, `
-		SELECT id, name, version, created_at, updated_at
+		SELECT id, name, version, default_input, tags, webhook, hooks, max_duration_ms, created_at, updated_at
 		FROM workflows
 		WHERE id = $1
 	`, id).Scan(
 		&workflow.ID,
 		&workflow.Name,
 		&workflow.Version,
+		&defaultInputJSON,
+		&tagsJSON,
+		&webhookJSON,
+		&hooksJSON,
+		&workflow.MaxDurationMs,
 		&workflow.CreatedAt,
 		&workflow.UpdatedAt,
 	)
@@ -150,6 +195,23 @@ func (r *WorkflowRepositoryImpl) Get(ctx context.Context, id string) (*models.Wo
 		return nil, fmt.Errorf("failed to get workflow: %w", err)
 	}
 
+	workflow.DefaultInput, err = unmarshalDefaultInput(defaultInputJSON)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Tags, err = unmarshalTags(tagsJSON)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Webhook, err = unmarshalWebhook(webhookJSON)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Hooks, err = unmarshalHooks(hooksJSON)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get nodes
 	nodes, err := r.GetNodes(ctx, id)
 	if err != nil {
@@ -224,7 +286,7 @@ func (r *WorkflowRepositoryImpl) GetEdges(ctx context.Context, workflowID string
 	rows, err := r.pool.Query(ctx, `
 		SELECT id, source_node_id, target_node_id,
 			edge_id, type, animated, stroke_color, stroke_width,
-			label, source_handle, label_style
+			label, source_handle, label_style, priority
 		FROM workflow_edges
 		WHERE workflow_id = $1
 	`, workflowID)
@@ -239,7 +301,7 @@ func (r *WorkflowRepositoryImpl) GetEdges(ctx context.Context, workflowID string
 		err := rows.Scan(
 			&edgeRow.ID, &edgeRow.Source, &edgeRow.Target, &edgeRow.EdgeID,
 			&edgeRow.EdgeType, &edgeRow.Animated, &edgeRow.StrokeColor, &edgeRow.StrokeWidth,
-			&edgeRow.Label, &edgeRow.SourceHandle, &edgeRow.LabelStyle,
+			&edgeRow.Label, &edgeRow.SourceHandle, &edgeRow.LabelStyle, &edgeRow.Priority,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan edge row: %w", err)
@@ -285,14 +347,31 @@ func (r *WorkflowRepositoryImpl) Update(ctx context.Context, workflow *models.Wo
 		
 		// Increment the version in our code
 		workflow.Version = currentVersion + 1
+
+		defaultInputJSON, err := marshalDefaultInput(workflow.DefaultInput)
+		if err != nil {
+			return err
+		}
+		tagsJSON, err := marshalTags(workflow.Tags)
+		if err != nil {
+			return err
+		}
+		webhookJSON, err := marshalWebhook(workflow.Webhook)
+		if err != nil {
+			return err
+		}
+		hooksJSON, err := marshalHooks(workflow.Hooks)
+		if err != nil {
+			return err
+		}
 		
 		// Update workflow with new version
 		row := tx.QueryRow(ctx, `
 			UPDATE workflows
-			SET name = $1, version = $2, updated_at = CURRENT_TIMESTAMP
-			WHERE id = $3
+			SET name = $1, version = $2, default_input = $3, tags = $4, webhook = $5, hooks = $6, max_duration_ms = $7, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $8
 			RETURNING created_at, updated_at
-		`, workflow.Name, workflow.Version, workflow.ID)
+		`, workflow.Name, workflow.Version, defaultInputJSON, tagsJSON, webhookJSON, hooksJSON, workflow.MaxDurationMs, workflow.ID)
 
 		err = row.Scan(&workflow.CreatedAt, &workflow.UpdatedAt)
 		if err != nil {
@@ -353,9 +432,9 @@ func (r *WorkflowRepositoryImpl) Update(ctx context.Context, workflow *models.Wo
 				INSERT INTO workflow_edges (
 					id, workflow_id, source_node_id, target_node_id,
 					edge_id, type, animated, stroke_color, stroke_width,
-					label, source_handle, label_style
+					label, source_handle, label_style, priority
 				)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 			`,
 				edge.ID,
 				workflow.ID,
@@ -369,6 +448,7 @@ func (r *WorkflowRepositoryImpl) Update(ctx context.Context, workflow *models.Wo
 				edge.Label,
 				edge.SourceHandle,
 				labelStyleJSON,
+				edge.Priority,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to create edge: %w", err)
@@ -395,4 +475,47 @@ func (r *WorkflowRepositoryImpl) Delete(ctx context.Context, id string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// List returns workflow headers (without nodes and edges) ordered by name. Only
+// workflows carrying every given tag are returned; no tags returns all workflows.
+func (r *WorkflowRepositoryImpl) List(ctx context.Context, tags map[string]string) ([]models.Workflow, error) {
+	tagsJSON, err := marshalTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, name, version, tags, created_at, updated_at
+		FROM workflows
+		WHERE tags @> $1::jsonb
+		ORDER BY name, id
+	`, tagsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflows: %w", err)
+	}
+	defer rows.Close()
+
+	workflows := make([]models.Workflow, 0)
+	for rows.Next() {
+		var workflow models.Workflow
+		var rowTagsJSON []byte
+		err := rows.Scan(
+			&workflow.ID, &workflow.Name, &workflow.Version, &rowTagsJSON,
+			&workflow.CreatedAt, &workflow.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workflow row: %w", err)
+		}
+		if workflow.Tags, err = unmarshalTags(rowTagsJSON); err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, workflow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workflow rows: %w", err)
+	}
+
+	return workflows, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetState returns the value stored under key for a workflow, reporting false when
+// nothing is stored
+func (r *WorkflowRepositoryImpl) GetState(ctx context.Context, workflowID, key string) (any, bool, error) {
+	if err := validateUUID(workflowID); err != nil {
+		return nil, false, fmt.Errorf("invalid workflow ID: %w", err)
+	}
+
+	var raw []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT value
+		FROM workflow_state
+		WHERE workflow_id = $1 AND key = $2
+	`, workflowID, key).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get state: %w", err)
+	}
+
+	var value any
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal state value: %w", err)
+		}
+	}
+	return value, true, nil
+}
+
+// SetState stores value under key for a workflow, replacing any earlier value
+func (r *WorkflowRepositoryImpl) SetState(ctx context.Context, workflowID, key string, value any) error {
+	if err := validateUUID(workflowID); err != nil {
+		return fmt.Errorf("invalid workflow ID: %w", err)
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state value: %w", err)
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO workflow_state (workflow_id, key, value, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (workflow_id, key)
+		DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+	`, workflowID, key, valueJSON)
+	if err != nil {
+		return fmt.Errorf("failed to set state: %w", err)
+	}
+	return nil
+}
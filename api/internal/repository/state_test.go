@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRepositoryImpl_State(t *testing.T) {
+	pool := setupTestPgxDB(t)
+	defer pool.Close()
+
+	repo := NewWorkflowRepository(pool)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{ID: uuid.New().String(), Name: "Stateful Workflow"}
+	assert.NoError(t, repo.Create(ctx, workflow))
+	defer repo.Delete(ctx, workflow.ID)
+	other := &models.Workflow{ID: uuid.New().String(), Name: "Other Workflow"}
+	assert.NoError(t, repo.Create(ctx, other))
+	defer repo.Delete(ctx, other.ID)
+
+	t.Run("missing key", func(t *testing.T) {
+		value, found, err := repo.GetState(ctx, workflow.ID, "lastAlert:Sydney")
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, value)
+	})
+
+	t.Run("read after write", func(t *testing.T) {
+		assert.NoError(t, repo.SetState(ctx, workflow.ID, "lastAlert:Sydney", "2024-05-01T12:00:00Z"))
+		value, found, err := repo.GetState(ctx, workflow.ID, "lastAlert:Sydney")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "2024-05-01T12:00:00Z", value)
+
+		// Writing again replaces the value
+		assert.NoError(t, repo.SetState(ctx, workflow.ID, "lastAlert:Sydney", map[string]any{"temperature": 31.5}))
+		value, _, err = repo.GetState(ctx, workflow.ID, "lastAlert:Sydney")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"temperature": 31.5}, value)
+	})
+
+	t.Run("keys are scoped by workflow", func(t *testing.T) {
+		_, found, err := repo.GetState(ctx, other.ID, "lastAlert:Sydney")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("invalid workflow ID", func(t *testing.T) {
+		_, _, err := repo.GetState(ctx, "not-a-uuid", "key")
+		assert.ErrorContains(t, err, "invalid workflow ID")
+		assert.ErrorContains(t, repo.SetState(ctx, "not-a-uuid", "key", 1), "invalid workflow ID")
+	})
+}
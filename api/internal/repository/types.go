@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 	"workflow-code-test/api/pkg/models"
 )
 
@@ -11,6 +12,7 @@ var (
     ErrWorkflowNotFound  = errors.New("workflow not found")
     ErrInvalidUUID       = errors.New("invalid UUID format")
     ErrExecutionNotFound = errors.New("execution not found")
+    ErrInvalidCursor     = errors.New("invalid pagination cursor")
 )
 // NodeRow represents a node row from the database.
 type NodeRow struct {
@@ -37,6 +39,194 @@ type EdgeRow struct {
     Label        string  `db:"label"`
     SourceHandle string  `db:"source_handle"`
     LabelStyle   []byte  `db:"label_style"`
+    Priority     int     `db:"priority"`
+}
+
+// marshalDefaultInput converts a workflow's default input to JSON for storage.
+// The embedded workflow definition is never stored as part of the defaults.
+func marshalDefaultInput(input *models.WorkflowInput) ([]byte, error) {
+    if input == nil {
+        return nil, nil
+    }
+    defaults := *input
+    defaults.Workflow = nil
+    data, err := json.Marshal(defaults)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal default input: %w", err)
+    }
+    return data, nil
+}
+
+// unmarshalDefaultInput converts stored default input JSON to a *models.WorkflowInput.
+func unmarshalDefaultInput(data []byte) (*models.WorkflowInput, error) {
+    if len(data) == 0 {
+        return nil, nil
+    }
+    var input models.WorkflowInput
+    if err := json.Unmarshal(data, &input); err != nil {
+        return nil, fmt.Errorf("failed to unmarshal default input: %w", err)
+    }
+    return &input, nil
+}
+
+// marshalExecutionInput converts an execution's input to JSON for storage. The
+// embedded workflow definition is never stored with it.
+func marshalExecutionInput(input *models.WorkflowInput) ([]byte, error) {
+    if input == nil {
+        return nil, nil
+    }
+    stored := *input
+    stored.Workflow = nil
+    data, err := json.Marshal(stored)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal execution input: %w", err)
+    }
+    return data, nil
+}
+
+// unmarshalExecutionInput converts stored execution input JSON to a *models.WorkflowInput.
+func unmarshalExecutionInput(data []byte) (*models.WorkflowInput, error) {
+    if len(data) == 0 {
+        return nil, nil
+    }
+    var input models.WorkflowInput
+    if err := json.Unmarshal(data, &input); err != nil {
+        return nil, fmt.Errorf("failed to unmarshal execution input: %w", err)
+    }
+    return &input, nil
+}
+
+// marshalErrorSummary converts a failed execution's error summary to JSON for storage
+func marshalErrorSummary(summary *models.ExecutionErrorSummary) ([]byte, error) {
+    if summary == nil {
+        return nil, nil
+    }
+    data, err := json.Marshal(summary)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal execution error summary: %w", err)
+    }
+    return data, nil
+}
+
+// unmarshalErrorSummary converts stored error summary JSON to a *models.ExecutionErrorSummary.
+func unmarshalErrorSummary(data []byte) (*models.ExecutionErrorSummary, error) {
+    if len(data) == 0 {
+        return nil, nil
+    }
+    var summary models.ExecutionErrorSummary
+    if err := json.Unmarshal(data, &summary); err != nil {
+        return nil, fmt.Errorf("failed to unmarshal execution error summary: %w", err)
+    }
+    return &summary, nil
+}
+
+// marshalWebhook converts a workflow's webhook configuration to JSON for storage
+func marshalWebhook(webhook *models.WebhookConfig) ([]byte, error) {
+    if webhook == nil {
+        return nil, nil
+    }
+    data, err := json.Marshal(webhook)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal webhook: %w", err)
+    }
+    return data, nil
+}
+
+// unmarshalWebhook converts stored webhook JSON to a *models.WebhookConfig
+func unmarshalWebhook(data []byte) (*models.WebhookConfig, error) {
+    if len(data) == 0 {
+        return nil, nil
+    }
+    var webhook models.WebhookConfig
+    if err := json.Unmarshal(data, &webhook); err != nil {
+        return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+    }
+    return &webhook, nil
+}
+
+// marshalTags converts workflow tags to JSON for storage, storing no tags as an empty object
+func marshalTags(tags map[string]string) ([]byte, error) {
+    if tags == nil {
+        tags = map[string]string{}
+    }
+    data, err := json.Marshal(tags)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal tags: %w", err)
+    }
+    return data, nil
+}
+
+// unmarshalTags converts stored tags JSON to a map, returning nil when there are none
+func unmarshalTags(data []byte) (map[string]string, error) {
+    var tags map[string]string
+    if len(data) > 0 {
+        if err := json.Unmarshal(data, &tags); err != nil {
+            return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+        }
+    }
+    if len(tags) == 0 {
+        return nil, nil
+    }
+    return tags, nil
+}
+
+// marshalHooks converts workflow execution hooks to JSON for storage, storing no hooks as an empty array
+func marshalHooks(hooks []models.ExecutionHook) ([]byte, error) {
+    if hooks == nil {
+        hooks = []models.ExecutionHook{}
+    }
+    data, err := json.Marshal(hooks)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal hooks: %w", err)
+    }
+    return data, nil
+}
+
+// unmarshalHooks converts stored hooks JSON to a slice, returning nil when there are none
+func unmarshalHooks(data []byte) ([]models.ExecutionHook, error) {
+    var hooks []models.ExecutionHook
+    if len(data) > 0 {
+        if err := json.Unmarshal(data, &hooks); err != nil {
+            return nil, fmt.Errorf("failed to unmarshal hooks: %w", err)
+        }
+    }
+    if len(hooks) == 0 {
+        return nil, nil
+    }
+    return hooks, nil
+}
+
+// ExecutionRow represents a workflow execution row from the database.
+type ExecutionRow struct {
+    ID            string     `db:"id"`
+    WorkflowID    string     `db:"workflow_id"`
+    Status        string     `db:"status"`
+    StartTime     string     `db:"start_time"`
+    EndTime       string     `db:"end_time"`
+    TotalDuration int64      `db:"total_duration"`
+    Metadata      []byte     `db:"metadata"`
+    InputHash     string     `db:"input_hash"`
+    ExecutedAt    time.Time  `db:"executed_at"`
+    EnqueuedAt    *time.Time `db:"enqueued_at"`
+    StartedAt     *time.Time `db:"started_at"`
+    EndedAt       *time.Time `db:"ended_at"`
+    Input         []byte     `db:"input"`
+    ErrorSummary  []byte     `db:"error_summary"`
+}
+
+// ExecutionStepRow represents an execution step row from the database.
+type ExecutionStepRow struct {
+    ExecutionID string `db:"execution_id"`
+    NodeID      string `db:"node_id"`
+    StepNumber  int    `db:"step_number"`
+    NodeType    string `db:"node_type"`
+    Status      string `db:"status"`
+    Label       string `db:"label"`
+    Description string `db:"description"`
+    Duration    int64  `db:"duration"`
+    Output      []byte `db:"output"`
+    Timestamp   string `db:"timestamp"`
+    Error       string `db:"error"`
 }
 
 // toModelNode converts a NodeRow to a *models.Node.
@@ -82,9 +272,68 @@ func toModelEdge(row EdgeRow) (*models.Edge, error) {
         },
         Label:        row.Label,
         SourceHandle: row.SourceHandle,
+        Priority:     row.Priority,
     }
     if labelStyle != nil && (labelStyle.Fill != "" || labelStyle.FontWeight != "") {
         edge.LabelStyle = labelStyle
     }
     return edge, nil
 }
+
+// toModelExecution converts an ExecutionRow to a *models.WorkflowExecution.
+func toModelExecution(row ExecutionRow) (*models.WorkflowExecution, error) {
+    var metadata models.JSONB
+    if len(row.Metadata) > 0 {
+        if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+            return nil, fmt.Errorf("failed to unmarshal execution metadata: %w", err)
+        }
+    }
+    input, err := unmarshalExecutionInput(row.Input)
+    if err != nil {
+        return nil, err
+    }
+    errorSummary, err := unmarshalErrorSummary(row.ErrorSummary)
+    if err != nil {
+        return nil, err
+    }
+    return &models.WorkflowExecution{
+        ID:            row.ID,
+        WorkflowID:    row.WorkflowID,
+        Status:        models.Status(row.Status),
+        StartTime:     row.StartTime,
+        EndTime:       row.EndTime,
+        TotalDuration: row.TotalDuration,
+        Steps:         make([]models.ExecutionStep, 0),
+        Metadata:      metadata,
+        InputHash:     row.InputHash,
+        ExecutedAt:    row.ExecutedAt,
+        EnqueuedAt:    row.EnqueuedAt,
+        StartedAt:     row.StartedAt,
+        EndedAt:       row.EndedAt,
+        Input:         input,
+        ErrorSummary:  errorSummary,
+    }, nil
+}
+
+// toModelExecutionStep converts an ExecutionStepRow to a *models.ExecutionStep.
+func toModelExecutionStep(row ExecutionStepRow) (*models.ExecutionStep, error) {
+    var output models.JSONB
+    if len(row.Output) > 0 {
+        if err := json.Unmarshal(row.Output, &output); err != nil {
+            return nil, fmt.Errorf("failed to unmarshal step output: %w", err)
+        }
+    }
+    return &models.ExecutionStep{
+        ExecutionID: row.ExecutionID,
+        NodeID:      row.NodeID,
+        StepNumber:  row.StepNumber,
+        NodeType:    models.NodeType(row.NodeType),
+        Status:      models.Status(row.Status),
+        Label:       row.Label,
+        Description: row.Description,
+        Duration:    row.Duration,
+        Output:      output,
+        Timestamp:   row.Timestamp,
+        Error:       row.Error,
+    }, nil
+}
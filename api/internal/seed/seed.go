@@ -0,0 +1,176 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// DefaultWorkflowID is the ID of the canonical weather alert workflow, matching scripts/init_db.sql
+const DefaultWorkflowID = "550e8400-e29b-41d4-a716-446655440000"
+
+// DefaultWorkflowName is the name of the canonical weather alert workflow
+const DefaultWorkflowName = "Weather Alert Workflow"
+
+// weatherAPIEndpoint is the Open-Meteo endpoint used by the weather node
+const weatherAPIEndpoint = "https://api.open-meteo.com/v1/forecast?latitude={lat}&longitude={lon}&current_weather=true&current=relative_humidity_2m"
+
+// workflowStore is the subset of the repository needed for seeding
+type workflowStore interface {
+	Get(ctx context.Context, id string) (*models.Workflow, error)
+	Create(ctx context.Context, workflow *models.Workflow) error
+}
+
+// DefaultWorkflow builds the start → form → integration → condition → email → end workflow
+func DefaultWorkflow() *models.Workflow {
+	nodes := []models.Node{
+		{
+			ID:       string(models.NodeIDStart),
+			Type:     models.NodeTypeStart,
+			Position: models.Position{X: -160, Y: 300},
+			Data: models.NodeData{
+				Label:       "Start",
+				Description: "Begin weather check workflow",
+				Metadata: map[string]any{
+					"hasHandles": map[string]any{"source": true, "target": false},
+				},
+			},
+		},
+		{
+			ID:       string(models.NodeIDForm),
+			Type:     models.NodeTypeForm,
+			Position: models.Position{X: 152, Y: 304},
+			Data: models.NodeData{
+				Label:       "User Input",
+				Description: "Process collected data - name, email, location",
+				Metadata: map[string]any{
+					"hasHandles":      map[string]any{"source": true, "target": true},
+					"inputFields":     []any{"name", "email", "city"},
+					"outputVariables": []any{"name", "email", "city"},
+				},
+			},
+		},
+		{
+			ID:       string(models.NodeIDWeatherAPI),
+			Type:     models.NodeTypeIntegration,
+			Position: models.Position{X: 460, Y: 304},
+			Data: models.NodeData{
+				Label:       "Weather API",
+				Description: "Fetch current temperature for {{city}}",
+				Metadata: map[string]any{
+					"hasHandles":     map[string]any{"source": true, "target": true},
+					"inputVariables": []any{"city"},
+					"apiEndpoint":    weatherAPIEndpoint,
+					"options": []any{
+						map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093},
+						map[string]any{"city": "Melbourne", "lat": -37.8136, "lon": 144.9631},
+						map[string]any{"city": "Brisbane", "lat": -27.4698, "lon": 153.0251},
+						map[string]any{"city": "Perth", "lat": -31.9505, "lon": 115.8605},
+						map[string]any{"city": "Adelaide", "lat": -34.9285, "lon": 138.6007},
+					},
+					"outputVariables": []any{"temperature"},
+				},
+			},
+		},
+		{
+			ID:       string(models.NodeIDCondition),
+			Type:     models.NodeTypeCondition,
+			Position: models.Position{X: 794, Y: 304},
+			Data: models.NodeData{
+				Label:       "Check Condition",
+				Description: "Evaluate temperature threshold",
+				Metadata: map[string]any{
+					"hasHandles":          map[string]any{"source": []any{"true", "false"}, "target": true},
+					"conditionExpression": "temperature {{operator}} {{threshold}}",
+					"outputVariables":     []any{"conditionMet"},
+				},
+			},
+		},
+		{
+			ID:       string(models.NodeIDEmail),
+			Type:     models.NodeTypeEmail,
+			Position: models.Position{X: 1096, Y: 88},
+			Data: models.NodeData{
+				Label:       "Send Alert",
+				Description: "Email weather alert notification",
+				Metadata: map[string]any{
+					"hasHandles":     map[string]any{"source": true, "target": true},
+					"inputVariables": []any{"name", "city", "temperature"},
+					"emailTemplate": map[string]any{
+						"subject": "Weather Alert",
+						"body":    "Weather alert for {{city}}! Temperature is {{temperature}}°C!",
+					},
+					"outputVariables": []any{"emailSent"},
+				},
+			},
+		},
+		{
+			ID:       string(models.NodeIDEnd),
+			Type:     models.NodeTypeEnd,
+			Position: models.Position{X: 1360, Y: 302},
+			Data: models.NodeData{
+				Label:       "Complete",
+				Description: "Workflow execution finished",
+				Metadata: map[string]any{
+					"hasHandles": map[string]any{"source": false, "target": true},
+				},
+			},
+		},
+	}
+
+	edges := []models.Edge{
+		newEdge("e1", models.NodeIDStart, models.NodeIDForm, "#10b981", 3, "Initialize", "", nil),
+		newEdge("e2", models.NodeIDForm, models.NodeIDWeatherAPI, "#3b82f6", 3, "Submit Data", "", nil),
+		newEdge("e3", models.NodeIDWeatherAPI, models.NodeIDCondition, "#f97316", 3, "Temperature Data", "", nil),
+		newEdge("e4", models.NodeIDCondition, models.NodeIDEmail, "#10b981", 3, "✓ Condition Met", "true",
+			&models.LabelStyle{Fill: "#10b981", FontWeight: "bold"}),
+		newEdge("e5", models.NodeIDCondition, models.NodeIDEnd, "#6b7280", 3, "✗ No Alert Needed", "false",
+			&models.LabelStyle{Fill: "#6b7280", FontWeight: "bold"}),
+		newEdge("e6", models.NodeIDEmail, models.NodeIDEnd, "#ef4444", 2, "Alert Sent", "",
+			&models.LabelStyle{Fill: "#ef4444", FontWeight: "bold"}),
+	}
+
+	return &models.Workflow{
+		ID:    DefaultWorkflowID,
+		Name:  DefaultWorkflowName,
+		Nodes: nodes,
+		Edges: edges,
+	}
+}
+
+// newEdge builds an animated smoothstep edge between two nodes
+func newEdge(edgeID string, source, target models.NodeID, stroke string, width int, label, sourceHandle string, labelStyle *models.LabelStyle) models.Edge {
+	return models.Edge{
+		ID:           uuid.NewString(),
+		Source:       string(source),
+		Target:       string(target),
+		EdgeID:       edgeID,
+		EdgeType:     "smoothstep",
+		Animated:     true,
+		Style:        models.EdgeStyle{Stroke: stroke, StrokeWidth: width},
+		Label:        label,
+		SourceHandle: sourceHandle,
+		LabelStyle:   labelStyle,
+	}
+}
+
+// DefaultWorkflowIfMissing stores the default workflow unless a workflow with its ID
+// already exists. It returns the workflow ID and whether it was created.
+func DefaultWorkflowIfMissing(ctx context.Context, store workflowStore) (string, bool, error) {
+	_, err := store.Get(ctx, DefaultWorkflowID)
+	if err == nil {
+		return DefaultWorkflowID, false, nil
+	}
+	if !errors.Is(err, repository.ErrWorkflowNotFound) {
+		return "", false, fmt.Errorf("failed to check for default workflow: %w", err)
+	}
+
+	if err := store.Create(ctx, DefaultWorkflow()); err != nil {
+		return "", false, fmt.Errorf("failed to seed default workflow: %w", err)
+	}
+	return DefaultWorkflowID, true, nil
+}
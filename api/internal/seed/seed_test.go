@@ -0,0 +1,79 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStore is an in-memory workflowStore
+type fakeStore struct {
+	workflows map[string]*models.Workflow
+	getErr    error
+	creates   int
+}
+
+func (f *fakeStore) Get(ctx context.Context, id string) (*models.Workflow, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	if wf, ok := f.workflows[id]; ok {
+		return wf, nil
+	}
+	return nil, repository.ErrWorkflowNotFound
+}
+
+func (f *fakeStore) Create(ctx context.Context, workflow *models.Workflow) error {
+	f.creates++
+	f.workflows[workflow.ID] = workflow
+	return nil
+}
+
+func TestDefaultWorkflow(t *testing.T) {
+	wf := DefaultWorkflow()
+
+	assert.Equal(t, DefaultWorkflowID, wf.ID)
+	assert.Len(t, wf.Nodes, 6)
+	assert.Equal(t, models.NodeTypeStart, wf.Nodes[0].Type)
+	assert.Equal(t, models.NodeTypeEnd, wf.Nodes[len(wf.Nodes)-1].Type)
+
+	// Every edge connects nodes that exist in the workflow
+	nodeIDs := make(map[string]bool)
+	for _, n := range wf.Nodes {
+		nodeIDs[n.ID] = true
+	}
+	for _, e := range wf.Edges {
+		assert.True(t, nodeIDs[e.Source], "unknown edge source %s", e.Source)
+		assert.True(t, nodeIDs[e.Target], "unknown edge target %s", e.Target)
+	}
+}
+
+func TestDefaultWorkflowIfMissing(t *testing.T) {
+	t.Run("creates when missing", func(t *testing.T) {
+		store := &fakeStore{workflows: map[string]*models.Workflow{}}
+
+		id, created, err := DefaultWorkflowIfMissing(context.Background(), store)
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, DefaultWorkflowID, id)
+		assert.Equal(t, 1, store.creates)
+
+		// A second call is a no-op
+		_, created, err = DefaultWorkflowIfMissing(context.Background(), store)
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, 1, store.creates)
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		store := &fakeStore{workflows: map[string]*models.Workflow{}, getErr: errors.New("connection refused")}
+
+		_, _, err := DefaultWorkflowIfMissing(context.Background(), store)
+		assert.Error(t, err)
+		assert.Equal(t, 0, store.creates)
+	})
+}
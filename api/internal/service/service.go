@@ -1,6 +1,8 @@
 package service
 
 import (
+	"net/http"
+	"time"
 	"workflow-code-test/api/internal/api/middleware"
 	"workflow-code-test/api/internal/execution"
 	"workflow-code-test/api/internal/handler"
@@ -16,8 +18,32 @@ import (
 type Service struct {
 	DB      *pgxpool.Pool
 	Handler *handler.WorkflowHandler
+	// ExecuteLimiter throttles executions per workflow when set
+	ExecuteLimiter *middleware.RateLimiter
+	// EnableDebugExecution exposes the forced-order execution endpoint
+	EnableDebugExecution bool
+	// RequestTimeout bounds every API request; zero or less disables it
+	RequestTimeout time.Duration
+	// ExecuteTimeout replaces RequestTimeout for the execute endpoints, which run
+	// whole workflows; zero or less exempts them
+	ExecuteTimeout time.Duration
+	// AdminToken is the bearer token the admin endpoints require; they aren't
+	// mounted when it is empty
+	AdminToken string
 }
 
+// DefaultExecuteTimeout bounds workflow execution requests unless configured otherwise
+const DefaultExecuteTimeout = 5 * time.Minute
+
+// Names of routes that use ExecuteTimeout instead of RequestTimeout
+const (
+	routeExecuteWorkflow      = "execute-workflow"
+	routeExecuteAdhocWorkflow = "execute-adhoc-workflow"
+	routeDebugExecuteWorkflow = "debug-execute-workflow"
+	routeTriggerWebhook       = "trigger-webhook"
+	routeResumeExecution      = "resume-execution"
+)
+
 func NewService(dbPool *pgxpool.Pool, engine *execution.Engine) (*Service, error) {
 	repo := repository.NewWorkflowRepository(dbPool)
 	
@@ -28,15 +54,81 @@ func NewService(dbPool *pgxpool.Pool, engine *execution.Engine) (*Service, error
 	return &Service{
 		DB: dbPool,
 		Handler: handler,
+		RequestTimeout: middleware.DefaultRequestTimeout,
+		ExecuteTimeout: DefaultExecuteTimeout,
 	}, nil
 }
 
 
 func (s *Service) LoadRoutes(parentRouter *mux.Router, isProduction bool) {
+	parentRouter.Use(middleware.Timeout(s.RequestTimeout, map[string]time.Duration{
+		routeExecuteWorkflow:      s.ExecuteTimeout,
+		routeExecuteAdhocWorkflow: s.ExecuteTimeout,
+		routeDebugExecuteWorkflow: s.ExecuteTimeout,
+		routeTriggerWebhook:       s.ExecuteTimeout,
+		routeResumeExecution:      s.ExecuteTimeout,
+	}))
+
+	operatorsRouter := parentRouter.PathPrefix("/operators").Subrouter()
+	operatorsRouter.Use(middleware.JsonMiddleware)
+	operatorsRouter.HandleFunc("", s.Handler.HandleListOperators).Methods("GET")
+
+	conditionRouter := parentRouter.PathPrefix("/condition").Subrouter()
+	conditionRouter.Use(middleware.JsonMiddleware)
+	conditionRouter.HandleFunc("/evaluate", s.Handler.HandleEvaluateCondition).Methods("POST")
+
+	weatherRouter := parentRouter.PathPrefix("/weather").Subrouter()
+	weatherRouter.Use(middleware.JsonMiddleware)
+	weatherRouter.HandleFunc("", s.Handler.HandleGetWeather).Methods("GET")
+
+	if s.AdminToken != "" {
+		adminRouter := parentRouter.PathPrefix("/admin").Subrouter()
+		adminRouter.Use(middleware.JsonMiddleware, middleware.RequireBearerToken(s.AdminToken))
+		adminRouter.HandleFunc("/node-types/reload", s.Handler.HandleReloadNodeTypes).Methods("POST")
+	}
+
+	executionsRouter := parentRouter.PathPrefix("/executions").Subrouter()
+	executionsRouter.Use(middleware.JsonMiddleware)
+	executionsRouter.HandleFunc("", s.Handler.HandleSearchExecutions).Methods("GET")
+
 	router := parentRouter.PathPrefix("/workflows").Subrouter()
 	router.StrictSlash(false)
 	router.Use(middleware.JsonMiddleware)
 	
+	router.HandleFunc("", s.Handler.HandleListWorkflows).Methods("GET")
+	router.HandleFunc("/validate-batch", s.Handler.HandleValidateWorkflowBatch).Methods("POST")
+	router.HandleFunc("/import", s.Handler.HandleImportWorkflow).Methods("POST")
+	router.HandleFunc("/execute-adhoc", s.Handler.HandleExecuteAdhocWorkflow).Methods("POST").Name(routeExecuteAdhocWorkflow)
 	router.HandleFunc("/{id}", s.Handler.HandleGetWorkflow).Methods("GET")
-	router.HandleFunc("/{id}/execute", s.Handler.HandleExecuteWorkflow).Methods("POST")
+	var executeHandler http.Handler = http.HandlerFunc(s.Handler.HandleExecuteWorkflow)
+	if s.ExecuteLimiter != nil {
+		executeHandler = middleware.RateLimit(s.ExecuteLimiter, "id")(executeHandler)
+	}
+	router.Handle("/{id}/execute", executeHandler).Methods("POST").Name(routeExecuteWorkflow)
+	var webhookHandler http.Handler = http.HandlerFunc(s.Handler.HandleTriggerWebhook)
+	if s.ExecuteLimiter != nil {
+		webhookHandler = middleware.RateLimit(s.ExecuteLimiter, "id")(webhookHandler)
+	}
+	router.Handle("/{id}/trigger/webhook", webhookHandler).Methods("POST").Name(routeTriggerWebhook)
+	router.HandleFunc("/{id}/validate", s.Handler.HandleValidateWorkflow).Methods("GET")
+	router.HandleFunc("/{id}/nodes/{nodeId}/test-integration", s.Handler.HandleTestIntegrationNode).Methods("POST")
+	if s.EnableDebugExecution {
+		router.HandleFunc("/{id}/debug/execute", s.Handler.HandleDebugExecuteWorkflow).Methods("POST").Name(routeDebugExecuteWorkflow)
+	}
+	router.HandleFunc("/{id}/executions", s.Handler.HandleListExecutions).Methods("GET")
+	router.HandleFunc("/{id}/stats", s.Handler.HandleGetWorkflowStats).Methods("GET")
+	// Registered before the single execution route so "diff" and "latest" aren't taken as execution IDs
+	router.HandleFunc("/{id}/executions/diff", s.Handler.HandleDiffExecutions).Methods("GET")
+	router.HandleFunc("/{id}/executions/latest", s.Handler.HandleGetLatestExecution).Methods("GET")
+	router.HandleFunc("/{id}/executions/{executionId}", s.Handler.HandleGetExecution).Methods("GET")
+	var resumeHandler http.Handler = http.HandlerFunc(s.Handler.HandleResumeExecution)
+	if s.ExecuteLimiter != nil {
+		resumeHandler = middleware.RateLimit(s.ExecuteLimiter, "id")(resumeHandler)
+	}
+	router.Handle("/{id}/executions/{executionId}/resume", resumeHandler).Methods("POST").Name(routeResumeExecution)
+}
+
+// LoadMetricsRoutes mounts the metrics endpoint, outside the versioned API prefix
+func (s *Service) LoadMetricsRoutes(router *mux.Router) {
+	router.HandleFunc("/metrics", s.Handler.HandleMetrics).Methods("GET")
 }
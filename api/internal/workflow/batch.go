@@ -0,0 +1,96 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"workflow-code-test/api/pkg/models"
+)
+
+// MaxValidationBatchSize bounds how many workflow definitions one batch may validate
+const MaxValidationBatchSize = 100
+
+// BatchValidationResult is the validation outcome of one definition in a batch,
+// identified by its position in the request
+type BatchValidationResult struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	ValidationResult
+}
+
+// ValidateWorkflows validates unsaved workflow definitions concurrently, applying
+// the checks used when creating a workflow and, when requested, the branch
+// simulation. Results are returned in input order.
+func (s *WorkflowServiceImpl) ValidateWorkflows(ctx context.Context, workflows []*models.Workflow, checkBranches bool) ([]BatchValidationResult, error) {
+	if len(workflows) == 0 {
+		return nil, fmt.Errorf("%w: at least one workflow is required", ErrInvalidValidationBatch)
+	}
+	if len(workflows) > MaxValidationBatchSize {
+		return nil, fmt.Errorf("%w: %d workflows exceeds the limit of %d", ErrInvalidValidationBatch, len(workflows), MaxValidationBatchSize)
+	}
+
+	results := make([]BatchValidationResult, len(workflows))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(workflows)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker writes only the slots for the indexes it receives
+			for i := range indexes {
+				results[i] = s.validateDefinition(i, workflows[i], checkBranches)
+			}
+		}()
+	}
+
+	for i := range workflows {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			close(indexes)
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+// validateDefinition reports every problem with an unsaved workflow definition
+func (s *WorkflowServiceImpl) validateDefinition(index int, wf *models.Workflow, checkBranches bool) BatchValidationResult {
+	result := BatchValidationResult{
+		Index: index,
+		ValidationResult: ValidationResult{
+			Valid:  true,
+			Errors: make([]string, 0),
+		},
+	}
+	if wf == nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, "workflow definition is required")
+		return result
+	}
+	result.ID = wf.ID
+	result.Name = wf.Name
+
+	for _, err := range s.definitionErrors(wf) {
+		result.Valid = false
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	if checkBranches {
+		result.Branches = evaluateAllBranches(wf.Nodes, wf.Edges)
+		for _, branch := range result.Branches {
+			if !branch.ReachesEnd {
+				result.Valid = false
+				result.Errors = append(result.Errors, branch.Error)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"workflow-code-test/api/pkg/models"
+)
+
+// Condition branch handles as used on edge source handles
+const (
+	branchTrue  = "true"
+	branchFalse = "false"
+)
+
+// BranchResult reports whether one branch of a condition node terminates at an end node
+type BranchResult struct {
+	ConditionNodeID string   `json:"conditionNodeId"`
+	Branch          string   `json:"branch"`
+	TargetNodeID    string   `json:"targetNodeId,omitempty"`
+	ReachesEnd      bool     `json:"reachesEnd"`
+	Path            []string `json:"path,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// ValidationResult is the outcome of validating a workflow definition
+type ValidationResult struct {
+	Valid    bool           `json:"valid"`
+	Errors   []string       `json:"errors"`
+	Branches []BranchResult `json:"branches,omitempty"`
+}
+
+// branchWalker symbolically follows workflow routing the same way the engine does
+type branchWalker struct {
+	nodes  map[string]models.Node
+	routes map[string]map[string]string
+	memo   map[string]bool
+}
+
+// evaluateAllBranches simulates both branches of every condition node and reports
+// whether each one terminates at an end node
+func evaluateAllBranches(nodes []models.Node, edges []models.Edge) []BranchResult {
+	walker := &branchWalker{
+		nodes:  make(map[string]models.Node),
+		routes: make(map[string]map[string]string),
+		memo:   make(map[string]bool),
+	}
+	for _, n := range nodes {
+		walker.nodes[n.ID] = n
+	}
+	// Like the engine, a node's default route is its highest-priority unlabelled
+	// edge to a known node
+	ordered := slices.Clone(edges)
+	models.SortEdgesByPriority(ordered)
+	for _, edge := range ordered {
+		if walker.routes[edge.Source] == nil {
+			walker.routes[edge.Source] = make(map[string]string)
+		}
+		if edge.SourceHandle == "" {
+			if _, known := walker.nodes[edge.Target]; !known {
+				continue
+			}
+			if _, routed := walker.routes[edge.Source][""]; routed {
+				continue
+			}
+		}
+		walker.routes[edge.Source][edge.SourceHandle] = edge.Target
+	}
+
+	results := make([]BranchResult, 0)
+	for _, n := range nodes {
+		if n.Type != models.NodeTypeCondition {
+			continue
+		}
+		for _, branch := range []string{branchTrue, branchFalse} {
+			result := BranchResult{
+				ConditionNodeID: n.ID,
+				Branch:          branch,
+			}
+			target, ok := walker.routes[n.ID][branch]
+			if !ok {
+				result.Error = fmt.Sprintf("condition node %s has no %s branch", n.ID, branch)
+				results = append(results, result)
+				continue
+			}
+			result.TargetNodeID = target
+			path, reachesEnd, reason := walker.walk(target)
+			result.Path = path
+			result.ReachesEnd = reachesEnd
+			if !reachesEnd {
+				result.Error = reason
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// walk follows default routes from nodeID until it reaches an end node, a nested
+// condition node, or a dead end. Nested conditions reach the end only when both
+// of their own branches do.
+func (w *branchWalker) walk(nodeID string) ([]string, bool, string) {
+	path := make([]string, 0)
+	visited := make(map[string]bool)
+	current := nodeID
+
+	for {
+		n, ok := w.nodes[current]
+		if !ok {
+			return path, false, fmt.Sprintf("branch references undefined node %s", current)
+		}
+		if visited[current] {
+			return path, false, fmt.Sprintf("branch loops back to node %s", current)
+		}
+		visited[current] = true
+		path = append(path, current)
+
+		switch n.Type {
+		case models.NodeTypeEnd:
+			return path, true, ""
+		case models.NodeTypeCondition:
+			if w.conditionReachesEnd(current, map[string]bool{}) {
+				return path, true, ""
+			}
+			return path, false, fmt.Sprintf("nested condition node %s has a branch that does not reach an end node", current)
+		}
+
+		next, ok := w.routes[current][""]
+		if !ok {
+			return path, false, fmt.Sprintf("node %s has no outgoing edge", current)
+		}
+		current = next
+	}
+}
+
+// conditionReachesEnd reports whether both branches of a condition node reach an end node
+func (w *branchWalker) conditionReachesEnd(nodeID string, inProgress map[string]bool) bool {
+	if result, ok := w.memo[nodeID]; ok {
+		return result
+	}
+	if inProgress[nodeID] {
+		return false
+	}
+	inProgress[nodeID] = true
+
+	reaches := true
+	for _, branch := range []string{branchTrue, branchFalse} {
+		target, ok := w.routes[nodeID][branch]
+		if !ok || !w.reachesEnd(target, inProgress) {
+			reaches = false
+			break
+		}
+	}
+
+	w.memo[nodeID] = reaches
+	return reaches
+}
+
+// reachesEnd follows default routes from nodeID, recursing into nested conditions
+func (w *branchWalker) reachesEnd(nodeID string, inProgress map[string]bool) bool {
+	visited := make(map[string]bool)
+	current := nodeID
+	for {
+		n, ok := w.nodes[current]
+		if !ok || visited[current] {
+			return false
+		}
+		visited[current] = true
+
+		switch n.Type {
+		case models.NodeTypeEnd:
+			return true
+		case models.NodeTypeCondition:
+			return w.conditionReachesEnd(current, inProgress)
+		}
+
+		next, ok := w.routes[current][""]
+		if !ok {
+			return false
+		}
+		current = next
+	}
+}
@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateAllBranches(t *testing.T) {
+	baseNodes := []models.Node{
+		{ID: "start", Type: models.NodeTypeStart},
+		{ID: "condition", Type: models.NodeTypeCondition},
+		{ID: "email", Type: models.NodeTypeEmail},
+		{ID: "end", Type: models.NodeTypeEnd},
+	}
+
+	tests := []struct {
+		name     string
+		nodes    []models.Node
+		edges    []models.Edge
+		expected map[string]bool // key: conditionNodeID/branch, value: reachesEnd
+		errors   map[string]string
+	}{
+		{
+			name:  "both branches reach end",
+			nodes: baseNodes,
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "condition"},
+				{ID: "e2", Source: "condition", Target: "email", SourceHandle: "true"},
+				{ID: "e3", Source: "condition", Target: "end", SourceHandle: "false"},
+				{ID: "e4", Source: "email", Target: "end"},
+			},
+			expected: map[string]bool{
+				"condition/true":  true,
+				"condition/false": true,
+			},
+		},
+		{
+			name:  "true branch dead ends",
+			nodes: baseNodes,
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "condition"},
+				{ID: "e2", Source: "condition", Target: "email", SourceHandle: "true"},
+				{ID: "e3", Source: "condition", Target: "end", SourceHandle: "false"},
+			},
+			expected: map[string]bool{
+				"condition/true":  false,
+				"condition/false": true,
+			},
+			errors: map[string]string{
+				"condition/true": "node email has no outgoing edge",
+			},
+		},
+		{
+			name:  "missing false branch",
+			nodes: baseNodes,
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "condition"},
+				{ID: "e2", Source: "condition", Target: "email", SourceHandle: "true"},
+				{ID: "e4", Source: "email", Target: "end"},
+			},
+			expected: map[string]bool{
+				"condition/true":  true,
+				"condition/false": false,
+			},
+			errors: map[string]string{
+				"condition/false": "has no false branch",
+			},
+		},
+		{
+			name: "nested condition with orphaned branch",
+			nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "temp-check", Type: models.NodeTypeCondition},
+				{ID: "wind-check", Type: models.NodeTypeCondition},
+				{ID: "email", Type: models.NodeTypeEmail},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "temp-check"},
+				{ID: "e2", Source: "temp-check", Target: "wind-check", SourceHandle: "true"},
+				{ID: "e3", Source: "temp-check", Target: "end", SourceHandle: "false"},
+				{ID: "e4", Source: "wind-check", Target: "email", SourceHandle: "true"},
+				{ID: "e5", Source: "wind-check", Target: "email", SourceHandle: "false"},
+			},
+			expected: map[string]bool{
+				"temp-check/true":  false,
+				"temp-check/false": true,
+				"wind-check/true":  false,
+				"wind-check/false": false,
+			},
+			errors: map[string]string{
+				"temp-check/true": "nested condition node wind-check",
+			},
+		},
+		{
+			name:  "branch loops",
+			nodes: baseNodes,
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "condition"},
+				{ID: "e2", Source: "condition", Target: "email", SourceHandle: "true"},
+				{ID: "e3", Source: "condition", Target: "end", SourceHandle: "false"},
+				{ID: "e4", Source: "email", Target: "start"},
+				{ID: "e5", Source: "start", Target: "email"},
+			},
+			expected: map[string]bool{
+				"condition/true":  false,
+				"condition/false": true,
+			},
+			errors: map[string]string{
+				"condition/true": "loops back",
+			},
+		},
+		{
+			name:  "highest-priority edge is followed",
+			nodes: baseNodes,
+			edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "condition"},
+				{ID: "e2", Source: "condition", Target: "email", SourceHandle: "true"},
+				{ID: "e3", Source: "condition", Target: "end", SourceHandle: "false"},
+				{ID: "e4", Source: "email", Target: "end", Priority: 2},
+				{ID: "e5", Source: "email", Target: "start", Priority: 1},
+			},
+			expected: map[string]bool{
+				"condition/true":  true,
+				"condition/false": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := evaluateAllBranches(tt.nodes, tt.edges)
+			assert.Len(t, results, len(tt.expected))
+
+			for _, result := range results {
+				key := result.ConditionNodeID + "/" + result.Branch
+				expected, ok := tt.expected[key]
+				assert.True(t, ok, "unexpected branch result %s", key)
+				assert.Equal(t, expected, result.ReachesEnd, key)
+				if expectedErr, ok := tt.errors[key]; ok {
+					assert.Contains(t, result.Error, expectedErr)
+				}
+				if result.ReachesEnd {
+					assert.Empty(t, result.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateAllBranchesPath(t *testing.T) {
+	nodes := []models.Node{
+		{ID: "start", Type: models.NodeTypeStart},
+		{ID: "condition", Type: models.NodeTypeCondition},
+		{ID: "email", Type: models.NodeTypeEmail},
+		{ID: "end", Type: models.NodeTypeEnd},
+	}
+	edges := []models.Edge{
+		{ID: "e1", Source: "start", Target: "condition"},
+		{ID: "e2", Source: "condition", Target: "email", SourceHandle: "true"},
+		{ID: "e3", Source: "condition", Target: "end", SourceHandle: "false"},
+		{ID: "e4", Source: "email", Target: "end"},
+	}
+
+	results := evaluateAllBranches(nodes, edges)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "true", results[0].Branch)
+	assert.Equal(t, "email", results[0].TargetNodeID)
+	assert.Equal(t, []string{"email", "end"}, results[0].Path)
+	assert.Equal(t, "false", results[1].Branch)
+	assert.Equal(t, []string{"end"}, results[1].Path)
+}
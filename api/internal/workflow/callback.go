@@ -0,0 +1,136 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+	"workflow-code-test/api/pkg/models"
+)
+
+// CallbackSignatureHeader carries the hex HMAC-SHA256 of the callback body, keyed
+// with the configured callback secret and prefixed with "sha256=". Receivers can
+// check it with VerifyWebhookSignature.
+const CallbackSignatureHeader = "X-Callback-Signature"
+
+// Defaults for delivering execution callbacks
+const (
+	DefaultCallbackAttempts  = 3
+	DefaultCallbackBaseDelay = time.Second
+	callbackTimeout          = 10 * time.Second
+)
+
+// CallbackConfig controls how execution results are POSTed to a run's callbackUrl
+type CallbackConfig struct {
+	// Secret signs each callback; callbackUrl is rejected while it is empty
+	Secret string
+	// RequireHTTPS rejects plain http callback URLs, as production deployments should
+	RequireHTTPS bool
+	// Attempts and BaseDelay bound the retries; the wait doubles after each failed attempt
+	Attempts  int
+	BaseDelay time.Duration
+	Client    *http.Client
+}
+
+// SetCallbackConfig configures execution callbacks. Non-positive retry settings fall
+// back to the defaults. It should be called before the service handles requests.
+func (s *WorkflowServiceImpl) SetCallbackConfig(config CallbackConfig) {
+	if config.Attempts <= 0 {
+		config.Attempts = DefaultCallbackAttempts
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = DefaultCallbackBaseDelay
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: callbackTimeout}
+	}
+	s.callbacks = config
+}
+
+// validateCallbackURL checks a requested callback URL before the run starts, so a
+// bad one is reported to the caller rather than failing after the fact
+func (s *WorkflowServiceImpl) validateCallbackURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if s.callbacks.Secret == "" {
+		return fmt.Errorf("%w: %w: callbacks are not enabled", ErrInvalidInput, ErrInvalidCallbackURL)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("%w: %w: callbackUrl must be an absolute http or https URL", ErrInvalidInput, ErrInvalidCallbackURL)
+	}
+	if s.callbacks.RequireHTTPS && parsed.Scheme != "https" {
+		return fmt.Errorf("%w: %w: callbackUrl must use https", ErrInvalidInput, ErrInvalidCallbackURL)
+	}
+	return nil
+}
+
+// sendCallback POSTs the finished execution to callbackURL in the background, so
+// the caller's response isn't held up by a slow or failing receiver. The run's email
+// address is masked in the steps, as it is in storage.
+func (s *WorkflowServiceImpl) sendCallback(callbackURL, email string, execution *models.WorkflowExecution) {
+	// Encoded now, before the caller goes on to use the execution
+	body, err := json.Marshal(newAddressMasker(email).execution(execution))
+	if err != nil {
+		slog.Error("Failed to encode execution callback", "executionId", execution.ID, "error", err)
+		return
+	}
+	go func() {
+		if err := s.deliverCallback(context.Background(), callbackURL, body); err != nil {
+			slog.Error("Execution callback failed", "executionId", execution.ID, "error", err)
+		}
+	}()
+}
+
+// deliverCallback sends a signed callback, retrying with exponential backoff after
+// network errors, 429s and 5xx responses. Other responses are final.
+func (s *WorkflowServiceImpl) deliverCallback(ctx context.Context, callbackURL string, body []byte) error {
+	signature := SignWebhookPayload(s.callbacks.Secret, body)
+	var lastErr error
+	for attempt := 1; attempt <= s.callbacks.Attempts; attempt++ {
+		retry, err := s.postCallback(ctx, callbackURL, body, signature)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == s.callbacks.Attempts {
+			break
+		}
+
+		delay := s.callbacks.BaseDelay << (attempt - 1)
+		slog.Warn("Execution callback failed, retrying", "attempt", attempt, "retryIn", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return lastErr
+}
+
+// postCallback makes one delivery attempt and reports whether a failure is worth retrying
+func (s *WorkflowServiceImpl) postCallback(ctx context.Context, callbackURL string, body []byte, signature string) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CallbackSignatureHeader, signature)
+	resp, err := s.callbacks.Client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("callback request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
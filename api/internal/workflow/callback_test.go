@@ -0,0 +1,170 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCallbackWorkflow() *models.Workflow {
+	return &models.Workflow{
+		ID:   "callback-workflow",
+		Name: "Callback Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end"},
+		},
+	}
+}
+
+func newCallbackInput(callbackURL string) models.WorkflowInput {
+	return models.WorkflowInput{
+		Name:        "Alice",
+		Email:       "alice@example.com",
+		City:        "Sydney",
+		Threshold:   25,
+		Operator:    models.OperatorGreaterThan,
+		CallbackURL: callbackURL,
+	}
+}
+
+func TestExecuteWorkflowCallback(t *testing.T) {
+	const secret = "callback-s3cret"
+	received := make(chan models.WorkflowExecution, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := VerifyWebhookSignature(secret, body, r.Header.Get(CallbackSignatureHeader)); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var execution models.WorkflowExecution
+		if err := json.Unmarshal(body, &execution); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received <- execution
+	}))
+	defer server.Close()
+
+	workflow := newCallbackWorkflow()
+	mockRepo := new(MockWorkflowRepository)
+	mockStoredWorkflow(mockRepo, workflow)
+	service := newTestService(mockRepo)
+	service.SetCallbackConfig(CallbackConfig{Secret: secret, BaseDelay: time.Millisecond, Client: server.Client()})
+
+	execution, err := service.ExecuteWorkflow(context.Background(), workflow.ID, newCallbackInput(server.URL+"/done"))
+	assert.NoError(t, err)
+
+	select {
+	case callback := <-received:
+		assert.Equal(t, execution.ID, callback.ID)
+		assert.Equal(t, models.StatusCompleted, callback.Status)
+		if assert.NotNil(t, callback.Input) {
+			assert.Equal(t, "a***@example.com", callback.Input.Email)
+			assert.Empty(t, callback.Input.CallbackURL, "the callback URL should not be echoed back")
+		}
+		if assert.Len(t, callback.Steps, 3) {
+			assert.Equal(t, "a***@example.com", callback.Steps[1].Output["email"], "step outputs are masked too")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+}
+
+func TestDeliverCallbackRetries(t *testing.T) {
+	newService := func(server *httptest.Server) *WorkflowServiceImpl {
+		service := NewWorkflowService(new(MockWorkflowRepository)).(*WorkflowServiceImpl)
+		service.SetCallbackConfig(CallbackConfig{Secret: "s3cret", Attempts: 3, BaseDelay: time.Millisecond, Client: server.Client()})
+		return service
+	}
+
+	t.Run("server errors are retried", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		err := newService(server).deliverCallback(context.Background(), server.URL, []byte(`{}`))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("gives up after the configured attempts", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		err := newService(server).deliverCallback(context.Background(), server.URL, []byte(`{}`))
+		assert.ErrorContains(t, err, "status 429")
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		err := newService(server).deliverCallback(context.Background(), server.URL, []byte(`{}`))
+		assert.ErrorContains(t, err, "status 401")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestExecuteWorkflowRejectsInvalidCallbackURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *CallbackConfig
+		callbackURL string
+		message     string
+	}{
+		{"callbacks not enabled", nil, "https://hooks.example.com/done", "callbacks are not enabled"},
+		{"relative url", &CallbackConfig{Secret: "s3cret"}, "/done", "absolute http or https URL"},
+		{"unsupported scheme", &CallbackConfig{Secret: "s3cret"}, "ftp://hooks.example.com/done", "absolute http or https URL"},
+		{"http in production", &CallbackConfig{Secret: "s3cret", RequireHTTPS: true}, "http://hooks.example.com/done", "must use https"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The request embeds a new workflow, which must not be stored when the
+			// callback URL is rejected
+			encoded, err := json.Marshal(newCallbackWorkflow())
+			assert.NoError(t, err)
+			input := newCallbackInput(tt.callbackURL)
+			assert.NoError(t, json.Unmarshal(encoded, &input.Workflow))
+
+			mockRepo := new(MockWorkflowRepository)
+			service := newTestService(mockRepo)
+			if tt.config != nil {
+				service.SetCallbackConfig(*tt.config)
+			}
+
+			_, err = service.ExecuteWorkflow(context.Background(), "callback-workflow", input)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+			assert.ErrorIs(t, err, ErrInvalidCallbackURL)
+			assert.ErrorContains(t, err, tt.message)
+			assert.Empty(t, mockRepo.Calls, "nothing is read or stored")
+		})
+	}
+}
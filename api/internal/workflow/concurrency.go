@@ -0,0 +1,41 @@
+package workflow
+
+// SetMaxConcurrentExecutions caps how many executions may run at once. A limit of
+// zero or less removes the cap. It should be called before the service handles requests.
+func (s *WorkflowServiceImpl) SetMaxConcurrentExecutions(limit int) {
+	if limit <= 0 {
+		s.slots = nil
+		return
+	}
+	s.slots = make(chan struct{}, limit)
+}
+
+// MaxConcurrentExecutions returns the concurrency cap, or zero when executions are unlimited
+func (s *WorkflowServiceImpl) MaxConcurrentExecutions() int {
+	return cap(s.slots)
+}
+
+// InFlightExecutions returns the number of executions currently running
+func (s *WorkflowServiceImpl) InFlightExecutions() int {
+	return int(s.inFlight.Load())
+}
+
+// acquireExecutionSlot reserves room for one execution without waiting. It returns
+// false when the service is already at its limit; otherwise the caller must call release.
+func (s *WorkflowServiceImpl) acquireExecutionSlot() (release func(), ok bool) {
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+		default:
+			return nil, false
+		}
+	}
+
+	s.inFlight.Add(1)
+	return func() {
+		s.inFlight.Add(-1)
+		if s.slots != nil {
+			<-s.slots
+		}
+	}, true
+}
@@ -0,0 +1,116 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingNode holds its execution open until unblock is closed
+type blockingNode struct {
+	node.BaseNode
+	unblock <-chan struct{}
+}
+
+func (n *blockingNode) Type() models.NodeType { return models.NodeTypeForm }
+
+func (n *blockingNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *blockingNode) Validate() error { return nil }
+
+func (n *blockingNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	<-n.unblock
+	return node.NodeOutputs{Data: map[string]any{}, Status: models.StatusCompleted}, nil
+}
+
+func TestMaxConcurrentExecutions(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "busy-workflow",
+		Name: "Busy Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	unblock := make(chan struct{})
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeForm, func(model models.Node) (node.Node, error) {
+		return &blockingNode{BaseNode: node.BaseNode{ID: model.ID}, unblock: unblock}, nil
+	})
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+
+	mockRepo := new(MockWorkflowRepository)
+	mockStoredWorkflow(mockRepo, workflow)
+	mockExecutionStorage(mockRepo)
+
+	service := NewWorkflowService(mockRepo)
+	service.SetEngine(execution.NewEngine(registry))
+	service.SetMaxConcurrentExecutions(2)
+	assert.Equal(t, 2, service.MaxConcurrentExecutions())
+
+	// Fill every slot with a run that can't finish yet
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+			assert.NoError(t, err)
+		}()
+	}
+	assert.Eventually(t, func() bool { return service.InFlightExecutions() == 2 }, time.Second, time.Millisecond)
+
+	// A third run is turned away rather than queued
+	_, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+	assert.ErrorIs(t, err, ErrTooManyExecutions)
+	assert.Equal(t, 2, service.InFlightExecutions())
+
+	// Once the running executions finish, slots are free again
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, 0, service.InFlightExecutions())
+
+	_, err = service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+	assert.NoError(t, err)
+}
+
+func TestUnlimitedConcurrentExecutions(t *testing.T) {
+	service := NewWorkflowService(new(MockWorkflowRepository)).(*WorkflowServiceImpl)
+	service.SetMaxConcurrentExecutions(0)
+	assert.Equal(t, 0, service.MaxConcurrentExecutions())
+
+	// Without a limit slots are always granted but still counted
+	var releases []func()
+	for i := 0; i < 5; i++ {
+		release, ok := service.acquireExecutionSlot()
+		assert.True(t, ok)
+		releases = append(releases, release)
+	}
+	assert.Equal(t, 5, service.InFlightExecutions())
+	for _, release := range releases {
+		release()
+	}
+	assert.Equal(t, 0, service.InFlightExecutions())
+}
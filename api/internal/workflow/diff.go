@@ -0,0 +1,187 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"workflow-code-test/api/pkg/models"
+)
+
+// ExecutionDiff describes how two executions of the same workflow differ
+type ExecutionDiff struct {
+	A             string       `json:"a"`
+	B             string       `json:"b"`
+	Status        *ValueChange `json:"status,omitempty"`
+	DurationDelta int64        `json:"durationDelta"` // B minus A, in milliseconds
+	StepCount     [2]int       `json:"stepCount"`
+	Steps         []StepDiff   `json:"steps"`
+	Identical     bool         `json:"identical"`
+}
+
+// StepDiff compares the steps two executions recorded for one node. Steps are
+// matched by node ID, so a node visited by only one run has Presence "a" or "b".
+type StepDiff struct {
+	NodeID        string          `json:"nodeId"`
+	NodeType      models.NodeType `json:"nodeType"`
+	Presence      string          `json:"presence"` // "both", "a" or "b"
+	StepNumber    *ValueChange    `json:"stepNumber,omitempty"`
+	Status        *ValueChange    `json:"status,omitempty"`
+	Error         *ValueChange    `json:"error,omitempty"`
+	DurationDelta int64           `json:"durationDelta"`
+	Output        []FieldChange   `json:"output,omitempty"`
+}
+
+// ValueChange holds a value that differs between the two executions
+type ValueChange struct {
+	A any `json:"a"`
+	B any `json:"b"`
+}
+
+// FieldChange is a differing output field, addressed by a dotted path. A field
+// missing from one side has a nil value there.
+type FieldChange struct {
+	Path string `json:"path"`
+	A    any    `json:"a"`
+	B    any    `json:"b"`
+}
+
+// Presence values for StepDiff
+const (
+	StepInBoth = "both"
+	StepOnlyA  = "a"
+	StepOnlyB  = "b"
+)
+
+// DiffExecutions loads two executions of a workflow and compares them step by step
+func (s *WorkflowServiceImpl) DiffExecutions(ctx context.Context, workflowID, executionA, executionB string) (*ExecutionDiff, error) {
+	if executionA == "" || executionB == "" {
+		return nil, fmt.Errorf("%w: both a and b execution IDs are required", ErrInvalidExecutionFilter)
+	}
+	a, err := s.GetExecution(ctx, workflowID, executionA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.GetExecution(ctx, workflowID, executionB)
+	if err != nil {
+		return nil, err
+	}
+	return diffExecutions(a, b), nil
+}
+
+// diffExecutions compares a and b. Steps are listed in a's order, followed by
+// steps only b recorded in b's order.
+func diffExecutions(a, b *models.WorkflowExecution) *ExecutionDiff {
+	diff := &ExecutionDiff{
+		A:             a.ID,
+		B:             b.ID,
+		DurationDelta: b.TotalDuration - a.TotalDuration,
+		StepCount:     [2]int{len(a.Steps), len(b.Steps)},
+		Steps:         make([]StepDiff, 0),
+	}
+	if a.Status != b.Status {
+		diff.Status = &ValueChange{A: a.Status, B: b.Status}
+	}
+
+	stepsB := make(map[string]models.ExecutionStep, len(b.Steps))
+	for _, step := range b.Steps {
+		if _, seen := stepsB[step.NodeID]; !seen {
+			stepsB[step.NodeID] = step
+		}
+	}
+	matched := make(map[string]bool, len(a.Steps))
+
+	differs := diff.Status != nil || len(a.Steps) != len(b.Steps)
+	for _, stepA := range a.Steps {
+		if matched[stepA.NodeID] {
+			continue
+		}
+		matched[stepA.NodeID] = true
+
+		stepB, ok := stepsB[stepA.NodeID]
+		if !ok {
+			diff.Steps = append(diff.Steps, StepDiff{NodeID: stepA.NodeID, NodeType: stepA.NodeType, Presence: StepOnlyA})
+			differs = true
+			continue
+		}
+		stepDiff := diffSteps(stepA, stepB)
+		if stepDiff.StepNumber != nil || stepDiff.Status != nil || stepDiff.Error != nil || len(stepDiff.Output) > 0 {
+			differs = true
+		}
+		diff.Steps = append(diff.Steps, stepDiff)
+	}
+	for _, stepB := range b.Steps {
+		if matched[stepB.NodeID] {
+			continue
+		}
+		matched[stepB.NodeID] = true
+		diff.Steps = append(diff.Steps, StepDiff{NodeID: stepB.NodeID, NodeType: stepB.NodeType, Presence: StepOnlyB})
+		differs = true
+	}
+
+	// Durations always vary a little, so they don't make runs differ
+	diff.Identical = !differs
+	return diff
+}
+
+// diffSteps compares two steps recorded for the same node. Outputs stored under an
+// older schema are upgraded first, so a run from before a change isn't reported as
+// differing in shape or schema version alone.
+func diffSteps(a, b models.ExecutionStep) StepDiff {
+	diff := StepDiff{
+		NodeID:        a.NodeID,
+		NodeType:      a.NodeType,
+		Presence:      StepInBoth,
+		DurationDelta: b.Duration - a.Duration,
+	}
+	for _, change := range diffFields("", models.UpgradeOutput(a.NodeType, a.Output), models.UpgradeOutput(b.NodeType, b.Output), nil) {
+		if change.Path != models.OutputSchemaVersionKey {
+			diff.Output = append(diff.Output, change)
+		}
+	}
+	if a.StepNumber != b.StepNumber {
+		diff.StepNumber = &ValueChange{A: a.StepNumber, B: b.StepNumber}
+	}
+	if a.Status != b.Status {
+		diff.Status = &ValueChange{A: a.Status, B: b.Status}
+	}
+	if a.Error != b.Error {
+		diff.Error = &ValueChange{A: a.Error, B: b.Error}
+	}
+	return diff
+}
+
+// diffFields appends the differences between two decoded JSON values to changes,
+// descending into objects so each change names the innermost differing field
+func diffFields(path string, a, b any, changes []FieldChange) []FieldChange {
+	mapA, okA := a.(map[string]any)
+	mapB, okB := b.(map[string]any)
+	if !okA || !okB {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, FieldChange{Path: path, A: a, B: b})
+		}
+		return changes
+	}
+
+	keys := make(map[string]struct{}, len(mapA)+len(mapB))
+	for key := range mapA {
+		keys[key] = struct{}{}
+	}
+	for key := range mapB {
+		keys[key] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		changes = diffFields(childPath, mapA[key], mapB[key], changes)
+	}
+	return changes
+}
@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDiffExecutions(t *testing.T) {
+	// Both runs check the temperature; only the hot run sends an email
+	cold := &models.WorkflowExecution{
+		ID:            "exec-cold",
+		Status:        models.StatusCompleted,
+		TotalDuration: 100,
+		Steps: []models.ExecutionStep{
+			{NodeID: "start", StepNumber: 1, NodeType: models.NodeTypeStart, Status: models.StatusCompleted, Duration: 1},
+			{NodeID: "condition", StepNumber: 2, NodeType: models.NodeTypeCondition, Status: models.StatusCompleted, Duration: 5,
+				Output: models.JSONB{"conditionResult": map[string]any{"result": false, "temperature": 12.0}, "message": "same"}},
+			{NodeID: "end", StepNumber: 3, NodeType: models.NodeTypeEnd, Status: models.StatusCompleted, Duration: 1},
+		},
+	}
+	hot := &models.WorkflowExecution{
+		ID:            "exec-hot",
+		Status:        models.StatusCompleted,
+		TotalDuration: 140,
+		Steps: []models.ExecutionStep{
+			{NodeID: "start", StepNumber: 1, NodeType: models.NodeTypeStart, Status: models.StatusCompleted, Duration: 1},
+			{NodeID: "condition", StepNumber: 2, NodeType: models.NodeTypeCondition, Status: models.StatusCompleted, Duration: 8,
+				Output: models.JSONB{"conditionResult": map[string]any{"result": true, "temperature": 31.0}, "message": "same"}},
+			{NodeID: "email", StepNumber: 3, NodeType: models.NodeTypeEmail, Status: models.StatusCompleted, Duration: 20},
+			{NodeID: "end", StepNumber: 4, NodeType: models.NodeTypeEnd, Status: models.StatusCompleted, Duration: 1},
+		},
+	}
+
+	t.Run("different paths", func(t *testing.T) {
+		diff := diffExecutions(cold, hot)
+
+		assert.False(t, diff.Identical)
+		assert.Nil(t, diff.Status)
+		assert.Equal(t, int64(40), diff.DurationDelta)
+		assert.Equal(t, [2]int{3, 4}, diff.StepCount)
+		assert.Len(t, diff.Steps, 4)
+
+		start := diff.Steps[0]
+		assert.Equal(t, StepInBoth, start.Presence)
+		assert.Empty(t, start.Output)
+
+		condition := diff.Steps[1]
+		assert.Equal(t, int64(3), condition.DurationDelta)
+		assert.Equal(t, []FieldChange{
+			{Path: "conditionResult.result", A: false, B: true},
+			{Path: "conditionResult.temperature", A: 12.0, B: 31.0},
+		}, condition.Output)
+
+		end := diff.Steps[2]
+		assert.Equal(t, "end", end.NodeID)
+		assert.Equal(t, &ValueChange{A: 3, B: 4}, end.StepNumber)
+
+		email := diff.Steps[3]
+		assert.Equal(t, "email", email.NodeID)
+		assert.Equal(t, StepOnlyB, email.Presence)
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		failed := &models.WorkflowExecution{
+			ID:     "exec-failed",
+			Status: models.StatusFailed,
+			Steps: []models.ExecutionStep{
+				{NodeID: "start", StepNumber: 1, NodeType: models.NodeTypeStart, Status: models.StatusCompleted, Duration: 1},
+				{NodeID: "condition", StepNumber: 2, NodeType: models.NodeTypeCondition, Status: models.StatusFailed, Error: "missing temperature"},
+			},
+		}
+
+		diff := diffExecutions(cold, failed)
+		assert.Equal(t, &ValueChange{A: models.StatusCompleted, B: models.StatusFailed}, diff.Status)
+		assert.Equal(t, &ValueChange{A: models.StatusCompleted, B: models.StatusFailed}, diff.Steps[1].Status)
+		assert.Equal(t, &ValueChange{A: "", B: "missing temperature"}, diff.Steps[1].Error)
+		assert.Equal(t, StepOnlyA, diff.Steps[2].Presence)
+	})
+
+	t.Run("same outcome with different timings is identical", func(t *testing.T) {
+		rerun := *cold
+		rerun.ID = "exec-rerun"
+		rerun.TotalDuration = 90
+
+		diff := diffExecutions(cold, &rerun)
+		assert.True(t, diff.Identical)
+		assert.Equal(t, int64(-10), diff.DurationDelta)
+	})
+}
+
+func TestDiffExecutionsAcrossOutputSchemas(t *testing.T) {
+	// The same outcome, stored before and after the condition output moved
+	before := &models.WorkflowExecution{Status: models.StatusCompleted, Steps: []models.ExecutionStep{
+		{NodeID: "condition", StepNumber: 1, NodeType: models.NodeTypeCondition, Status: models.StatusCompleted,
+			Output: models.JSONB{"conditionMet": true}},
+	}}
+	after := &models.WorkflowExecution{Status: models.StatusCompleted, Steps: []models.ExecutionStep{
+		{NodeID: "condition", StepNumber: 1, NodeType: models.NodeTypeCondition, Status: models.StatusCompleted,
+			Output: models.JSONB{"conditionResult": map[string]any{"result": true}, models.OutputSchemaVersionKey: float64(models.OutputSchemaVersion)}},
+	}}
+
+	diff := diffExecutions(before, after)
+	assert.True(t, diff.Identical)
+	assert.Empty(t, diff.Steps[0].Output)
+}
+
+func TestServiceDiffExecutions(t *testing.T) {
+	stored := func(id string) *models.WorkflowExecution {
+		return &models.WorkflowExecution{ID: id, WorkflowID: "workflow-1", Status: models.StatusCompleted}
+	}
+
+	t.Run("loads both executions", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		for _, id := range []string{"exec-a", "exec-b"} {
+			mockRepo.On("GetExecution", mock.Anything, id).Return(stored(id), nil)
+			mockRepo.On("GetExecutionSteps", mock.Anything, id).Return([]models.ExecutionStep{{NodeID: "start", StepNumber: 1}}, nil)
+		}
+
+		diff, err := NewWorkflowService(mockRepo).DiffExecutions(context.Background(), "workflow-1", "exec-a", "exec-b")
+		assert.NoError(t, err)
+		assert.Equal(t, "exec-a", diff.A)
+		assert.True(t, diff.Identical)
+	})
+
+	t.Run("missing execution", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("GetExecution", mock.Anything, "exec-a").Return(stored("exec-a"), nil)
+		mockRepo.On("GetExecutionSteps", mock.Anything, "exec-a").Return([]models.ExecutionStep{}, nil)
+		mockRepo.On("GetExecution", mock.Anything, "exec-gone").Return(nil, repository.ErrExecutionNotFound)
+
+		_, err := NewWorkflowService(mockRepo).DiffExecutions(context.Background(), "workflow-1", "exec-a", "exec-gone")
+		assert.ErrorIs(t, err, ErrExecutionNotFound)
+	})
+
+	t.Run("both IDs required", func(t *testing.T) {
+		_, err := NewWorkflowService(new(MockWorkflowRepository)).DiffExecutions(context.Background(), "workflow-1", "exec-a", "")
+		assert.ErrorIs(t, err, ErrInvalidExecutionFilter)
+	})
+}
@@ -0,0 +1,113 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// NodeIDRemap records a duplicate node that was given a new ID during import
+type NodeIDRemap struct {
+	Index int    `json:"index"` // position of the node in the imported definition
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ImportResult is a stored workflow along with any IDs changed to store it
+type ImportResult struct {
+	Workflow        *models.Workflow `json:"workflow"`
+	SourceID        string           `json:"sourceId,omitempty"` // ID given in the imported definition
+	RemappedNodeIDs []NodeIDRemap    `json:"remappedNodeIds"`
+}
+
+// ImportWorkflow stores a workflow definition as a new workflow. It always gets a
+// fresh ID, so an import can never replace an existing workflow or its webhook secret
+// and hooks. Duplicate node IDs are rejected unless fixDuplicates is set, in which
+// case they are renamed and their edges rewired before validation.
+func (s *WorkflowServiceImpl) ImportWorkflow(ctx context.Context, workflow *models.Workflow, fixDuplicates bool) (*ImportResult, error) {
+	if workflow == nil {
+		return nil, fmt.Errorf("%w: workflow definition is required", ErrInvalidWorkflowStructure)
+	}
+
+	result := &ImportResult{SourceID: workflow.ID, RemappedNodeIDs: []NodeIDRemap{}}
+	if fixDuplicates {
+		result.RemappedNodeIDs = remapDuplicateNodeIDs(workflow)
+		if len(result.RemappedNodeIDs) > 0 {
+			slog.Info("Remapped duplicate node IDs during import", "id", workflow.ID, "remapped", len(result.RemappedNodeIDs))
+		}
+	}
+
+	workflow.ID = uuid.New().String()
+	// CreateWorkflow validates the definition
+	if err := s.CreateWorkflow(ctx, workflow); err != nil {
+		return nil, err
+	}
+
+	result.Workflow = workflow
+	return result, nil
+}
+
+// remapDuplicateNodeIDs renames every repeated occurrence of a node ID to a unique
+// "<id>-<n>" and returns the renames in node order. Edges naming a duplicated ID are
+// shared out between its occurrences in order: the n-th edge leaving the ID through a
+// handle starts at the n-th occurrence, and the n-th edge entering it ends there.
+// Edges beyond the number of occurrences stay on the last one. For a linear run of
+// pasted nodes this reconnects them into a chain.
+func remapDuplicateNodeIDs(workflow *models.Workflow) []NodeIDRemap {
+	taken := make(map[string]bool, len(workflow.Nodes))
+	for _, node := range workflow.Nodes {
+		taken[node.ID] = true
+	}
+
+	remaps := []NodeIDRemap{}
+	occurrences := make(map[string][]string) // original ID -> ID of each occurrence in node order
+	for i := range workflow.Nodes {
+		id := workflow.Nodes[i].ID
+		if id == "" {
+			continue
+		}
+		if len(occurrences[id]) > 0 {
+			newID := uniqueNodeID(id, taken)
+			taken[newID] = true
+			workflow.Nodes[i].ID = newID
+			remaps = append(remaps, NodeIDRemap{Index: i, From: id, To: newID})
+		}
+		occurrences[id] = append(occurrences[id], workflow.Nodes[i].ID)
+	}
+	if len(remaps) == 0 {
+		return remaps
+	}
+
+	// nth picks the occurrence for the next edge counted under key
+	sourceCounts := make(map[string]int)
+	targetCounts := make(map[string]int)
+	nth := func(ids []string, counts map[string]int, key string) string {
+		n := min(counts[key], len(ids)-1)
+		counts[key]++
+		return ids[n]
+	}
+	for i := range workflow.Edges {
+		edge := &workflow.Edges[i]
+		if ids := occurrences[edge.Source]; len(ids) > 1 {
+			edge.Source = nth(ids, sourceCounts, edge.Source+"\x00"+edge.SourceHandle)
+		}
+		if ids := occurrences[edge.Target]; len(ids) > 1 {
+			edge.Target = nth(ids, targetCounts, edge.Target)
+		}
+	}
+
+	return remaps
+}
+
+// uniqueNodeID returns the first "<id>-<n>", counting from 2, not already taken
+func uniqueNodeID(id string, taken map[string]bool) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", id, n)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
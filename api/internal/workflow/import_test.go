@@ -0,0 +1,141 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pastedWorkflow has a form node pasted twice without a new ID
+func pastedWorkflow() *models.Workflow {
+	return &models.Workflow{
+		ID:   "import-workflow",
+		Name: "Imported Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "form"},
+			{ID: "e3", Source: "form", Target: "end"},
+		},
+	}
+}
+
+func TestRemapDuplicateNodeIDs(t *testing.T) {
+	t.Run("rewires edges into a chain", func(t *testing.T) {
+		workflow := pastedWorkflow()
+
+		remaps := remapDuplicateNodeIDs(workflow)
+		assert.Equal(t, []NodeIDRemap{{Index: 2, From: "form", To: "form-2"}}, remaps)
+		assert.Equal(t, "form-2", workflow.Nodes[2].ID)
+		assert.Equal(t, "start", workflow.Edges[0].Source)
+		assert.Equal(t, "form", workflow.Edges[0].Target)
+		assert.Equal(t, "form", workflow.Edges[1].Source)
+		assert.Equal(t, "form-2", workflow.Edges[1].Target)
+		assert.Equal(t, "form-2", workflow.Edges[2].Source)
+		assert.Equal(t, "end", workflow.Edges[2].Target)
+		assert.NoError(t, validateWorkflowStructure(workflow.Nodes, workflow.Edges))
+	})
+
+	t.Run("skips IDs already in use", func(t *testing.T) {
+		workflow := &models.Workflow{Nodes: []models.Node{{ID: "a"}, {ID: "a-2"}, {ID: "a"}}}
+
+		remaps := remapDuplicateNodeIDs(workflow)
+		assert.Equal(t, []NodeIDRemap{{Index: 2, From: "a", To: "a-3"}}, remaps)
+	})
+
+	t.Run("counts edges per source handle", func(t *testing.T) {
+		workflow := &models.Workflow{
+			Nodes: []models.Node{{ID: "check"}, {ID: "check"}, {ID: "yes"}, {ID: "no"}},
+			Edges: []models.Edge{
+				{ID: "t1", Source: "check", SourceHandle: "true", Target: "yes"},
+				{ID: "f1", Source: "check", SourceHandle: "false", Target: "no"},
+				{ID: "t2", Source: "check", SourceHandle: "true", Target: "yes"},
+				{ID: "f2", Source: "check", SourceHandle: "false", Target: "no"},
+			},
+		}
+
+		remapDuplicateNodeIDs(workflow)
+		assert.Equal(t, "check", workflow.Edges[0].Source)
+		assert.Equal(t, "check", workflow.Edges[1].Source)
+		assert.Equal(t, "check-2", workflow.Edges[2].Source)
+		assert.Equal(t, "check-2", workflow.Edges[3].Source)
+	})
+
+	t.Run("no duplicates", func(t *testing.T) {
+		workflow := &models.Workflow{
+			Nodes: []models.Node{{ID: "start"}, {ID: "end"}},
+			Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+		}
+
+		assert.Empty(t, remapDuplicateNodeIDs(workflow))
+		assert.Equal(t, "start", workflow.Edges[0].Source)
+	})
+}
+
+func TestImportWorkflow(t *testing.T) {
+	t.Run("rejects duplicates by default", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		service := NewWorkflowService(mockRepo)
+
+		_, err := service.ImportWorkflow(context.Background(), pastedWorkflow(), false)
+		assert.True(t, errors.Is(err, ErrInvalidWorkflowStructure))
+		assert.True(t, errors.Is(err, ErrDuplicateNodeID))
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a definition without a name", func(t *testing.T) {
+		workflow := pastedWorkflow()
+		workflow.Name = ""
+		mockRepo := new(MockWorkflowRepository)
+		service := NewWorkflowService(mockRepo)
+
+		_, err := service.ImportWorkflow(context.Background(), workflow, true)
+		assert.True(t, errors.Is(err, ErrInvalidWorkflowStructure))
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("creates workflow with remapped IDs", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		service := NewWorkflowService(mockRepo)
+
+		result, err := service.ImportWorkflow(context.Background(), pastedWorkflow(), true)
+		assert.NoError(t, err)
+		assert.Equal(t, []NodeIDRemap{{Index: 2, From: "form", To: "form-2"}}, result.RemappedNodeIDs)
+
+		stored := mockRepo.Calls[0].Arguments.Get(1).(*models.Workflow)
+		assert.Equal(t, "form-2", stored.Nodes[2].ID)
+		assert.Equal(t, "form-2", stored.Edges[1].Target)
+		assert.Equal(t, "form-2", stored.Edges[2].Source)
+	})
+
+	t.Run("never replaces a workflow with the same ID", func(t *testing.T) {
+		workflow := pastedWorkflow()
+		workflow.Nodes = append(workflow.Nodes[:2], workflow.Nodes[3])
+		workflow.Edges = []models.Edge{{ID: "e1", Source: "start", Target: "form"}, {ID: "e2", Source: "form", Target: "end"}}
+
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("Create", mock.Anything, workflow).Return(nil)
+		service := NewWorkflowService(mockRepo)
+
+		result, err := service.ImportWorkflow(context.Background(), workflow, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "import-workflow", result.SourceID)
+		assert.NotEqual(t, "import-workflow", result.Workflow.ID)
+		assert.NoError(t, uuid.Validate(result.Workflow.ID))
+		assert.Empty(t, result.RemappedNodeIDs)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node/integration"
+	"workflow-code-test/api/pkg/node/integration/weather"
+)
+
+// IntegrationTestResult reports whether an integration node could fetch the weather.
+// A misconfigured node or failed request is a result, not an error, so authors see
+// what went wrong.
+type IntegrationTestResult struct {
+	NodeID   string               `json:"nodeId"`
+	City     string               `json:"city"`
+	Success  bool                 `json:"success"`
+	Weather  *weather.WeatherData `json:"weather,omitempty"`
+	Error    string               `json:"error,omitempty"`
+	Duration int64                `json:"duration"` // milliseconds
+}
+
+// TestIntegrationNode fetches the weather for city through one of a workflow's
+// integration nodes, using its endpoint, options, field paths and units but
+// bypassing the response cache. Nothing is stored.
+func (s *WorkflowServiceImpl) TestIntegrationNode(ctx context.Context, workflowID, nodeID, city string) (*IntegrationTestResult, error) {
+	city = strings.TrimSpace(city)
+	if city == "" {
+		return nil, fmt.Errorf("%w: city is required", ErrInvalidInput)
+	}
+	workflow, err := s.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	var model *models.Node
+	for i := range workflow.Nodes {
+		if workflow.Nodes[i].ID == nodeID {
+			model = &workflow.Nodes[i]
+			break
+		}
+	}
+	if model == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+	if model.Type != models.NodeTypeIntegration {
+		return nil, fmt.Errorf("%w: node %s has type %q", ErrNotIntegrationNode, nodeID, model.Type)
+	}
+
+	result := &IntegrationTestResult{NodeID: nodeID, City: city}
+	started := time.Now()
+	defer func() { result.Duration = time.Since(started).Milliseconds() }()
+
+	n, err := integration.NewNode(*model)
+	if err == nil {
+		err = n.Validate()
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid node configuration: %v", err)
+		return result, nil
+	}
+	data, err := n.(*integration.Node).Probe(ctx, city)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Success = true
+	result.Weather = data
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"workflow-code-test/api/pkg/models"
+)
+
+// addressMasker replaces a run's email address, in any letter case, with its
+// models.MaskEmail form wherever it appears in what the run stores or sends out:
+// step outputs such as formData.email, emailContent.to and details.to, explanations
+// and error messages. A nil masker masks nothing.
+type addressMasker struct {
+	pattern *regexp.Regexp
+	masked  []byte
+}
+
+// newAddressMasker returns a masker for email, or nil when there is no address
+func newAddressMasker(email string) *addressMasker {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+	return &addressMasker{
+		pattern: regexp.MustCompile("(?i)" + regexp.QuoteMeta(email)),
+		masked:  []byte(models.MaskEmail(email)),
+	}
+}
+
+// execution returns a copy of execution with the address masked in its steps and
+// error summary, leaving execution untouched. It returns execution itself when the
+// address appears nowhere.
+func (m *addressMasker) execution(execution *models.WorkflowExecution) *models.WorkflowExecution {
+	if m == nil {
+		return execution
+	}
+
+	var steps []models.ExecutionStep
+	for i, step := range execution.Steps {
+		masked, ok := m.step(step)
+		if !ok {
+			continue
+		}
+		if steps == nil {
+			steps = append([]models.ExecutionStep(nil), execution.Steps...)
+		}
+		steps[i] = masked
+	}
+	var summary *models.ExecutionErrorSummary
+	if execution.ErrorSummary != nil {
+		if message, ok := m.text(execution.ErrorSummary.Message); ok {
+			masked := *execution.ErrorSummary
+			masked.Message = message
+			summary = &masked
+		}
+	}
+	if steps == nil && summary == nil {
+		return execution
+	}
+
+	stored := *execution
+	if steps != nil {
+		stored.Steps = steps
+	}
+	if summary != nil {
+		stored.ErrorSummary = summary
+	}
+	return &stored
+}
+
+// step masks the address in the step's output and error, reporting whether it appeared
+func (m *addressMasker) step(step models.ExecutionStep) (models.ExecutionStep, bool) {
+	if m == nil {
+		return step, false
+	}
+	output, outputMasked := m.output(step.Output)
+	stepError, errorMasked := m.text(step.Error)
+	if !outputMasked && !errorMasked {
+		return step, false
+	}
+	step.Output = output
+	step.Error = stepError
+	return step, true
+}
+
+// output masks the address in every string of the output, whatever its nesting
+func (m *addressMasker) output(output models.JSONB) (models.JSONB, bool) {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false) // so addresses with & still match
+	if err := encoder.Encode(output); err != nil || !m.pattern.Match(encoded.Bytes()) {
+		return output, false
+	}
+
+	var masked models.JSONB
+	if err := json.Unmarshal(m.pattern.ReplaceAllLiteral(encoded.Bytes(), m.masked), &masked); err != nil {
+		// The address can't be masked in place, so none of the output is kept
+		return models.JSONB{"redacted": true}, true
+	}
+	return masked, true
+}
+
+// text masks the address in a message
+func (m *addressMasker) text(s string) (string, bool) {
+	if !m.pattern.MatchString(s) {
+		return s, false
+	}
+	return m.pattern.ReplaceAllLiteralString(s, string(m.masked)), true
+}
@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddressMasker(t *testing.T) {
+	mask := newAddressMasker("Alice@Example.com")
+	execution := &models.WorkflowExecution{
+		Steps: []models.ExecutionStep{
+			{StepNumber: 1, Output: models.JSONB{"message": "Workflow started"}},
+			{StepNumber: 2, Output: models.JSONB{
+				"formData":     map[string]any{"email": "alice@example.com", "city": "Sydney"},
+				"emailContent": map[string]any{"to": "ALICE@example.com"},
+				"explanation":  "Email sent to alice@example.com because the condition was met",
+				"attempts":     2,
+			}, Error: "Failed to send email to alice@example.com"},
+		},
+		ErrorSummary: &models.ExecutionErrorSummary{NodeID: "email", Message: "Failed to send email to alice@example.com"},
+	}
+
+	masked := mask.execution(execution)
+	assert.Equal(t, execution.Steps[0], masked.Steps[0], "steps without the address are kept as they are")
+	output := masked.Steps[1].Output
+	assert.Equal(t, map[string]any{"email": "A***@Example.com", "city": "Sydney"}, output["formData"])
+	assert.Equal(t, map[string]any{"to": "A***@Example.com"}, output["emailContent"])
+	assert.Equal(t, "Email sent to A***@Example.com because the condition was met", output["explanation"])
+	assert.Equal(t, 2.0, output["attempts"])
+	assert.Equal(t, "Failed to send email to A***@Example.com", masked.Steps[1].Error)
+	assert.Equal(t, "Failed to send email to A***@Example.com", masked.ErrorSummary.Message)
+
+	// The execution itself is left for the caller
+	assert.Equal(t, "ALICE@example.com", execution.Steps[1].Output["emailContent"].(map[string]any)["to"])
+	assert.Equal(t, "Failed to send email to alice@example.com", execution.ErrorSummary.Message)
+
+	t.Run("nothing to mask", func(t *testing.T) {
+		clean := &models.WorkflowExecution{Steps: []models.ExecutionStep{{Output: models.JSONB{"city": "Sydney"}}}}
+		assert.Same(t, clean, mask.execution(clean))
+		assert.Same(t, clean, newAddressMasker("").execution(clean))
+	})
+}
+
+func TestExecuteWorkflowMasksStoredEmailAddress(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "masked-workflow",
+		Name: "Masked Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	mockRepo := new(MockWorkflowRepository)
+	mockStoredWorkflow(mockRepo, workflow)
+	var stored []models.ExecutionStep
+	mockRepo.On("CreateExecutionStep", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = append(stored, *args.Get(1).(*models.ExecutionStep))
+	}).Return(nil)
+	service := newTestService(mockRepo)
+
+	result, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", result.Steps[1].Output["email"], "the caller gets the address")
+
+	if assert.Len(t, stored, 3) {
+		encoded, err := json.Marshal(stored)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(encoded), "test@example.com")
+		assert.Equal(t, "t***@example.com", stored[1].Output["email"])
+		assert.Equal(t, "t***@example.com", stored[1].Output["formData"].(map[string]any)["email"])
+	}
+}
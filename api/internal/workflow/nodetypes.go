@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"workflow-code-test/api/pkg/models"
+)
+
+// SetAllowedNodeTypes restricts the node types a workflow may contain when it is
+// created, updated or run ad hoc, for deployments that must not run some nodes. An
+// empty list allows every registered type.
+func (s *WorkflowServiceImpl) SetAllowedNodeTypes(types []models.NodeType) {
+	if len(types) == 0 {
+		s.allowedNodeTypes = nil
+		return
+	}
+	s.allowedNodeTypes = make(map[models.NodeType]bool, len(types))
+	for _, nodeType := range types {
+		s.allowedNodeTypes[nodeType] = true
+	}
+}
+
+// AllowedNodeTypes returns the allowed node types in name order, or nil when every
+// registered type is allowed
+func (s *WorkflowServiceImpl) AllowedNodeTypes() []models.NodeType {
+	if s.allowedNodeTypes == nil {
+		return nil
+	}
+	types := make([]models.NodeType, 0, len(s.allowedNodeTypes))
+	for nodeType := range s.allowedNodeTypes {
+		types = append(types, nodeType)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// checkNodeTypesAllowed rejects the first node whose type isn't on the allowlist
+func (s *WorkflowServiceImpl) checkNodeTypesAllowed(nodes []models.Node) error {
+	if s.allowedNodeTypes == nil {
+		return nil
+	}
+	for _, node := range nodes {
+		if !s.allowedNodeTypes[node.Type] {
+			return fmt.Errorf("%w: %w: node %s has type %q", ErrInvalidWorkflowStructure, ErrNodeTypeNotAllowed, node.ID, node.Type)
+		}
+	}
+	return nil
+}
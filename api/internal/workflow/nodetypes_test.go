@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAllowedNodeTypes(t *testing.T) {
+	newWorkflow := func() *models.Workflow {
+		return &models.Workflow{
+			ID:   "restricted-workflow",
+			Name: "Restricted Workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "weather-api", Type: models.NodeTypeIntegration},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "weather-api"},
+				{ID: "e2", Source: "weather-api", Target: "end"},
+			},
+		}
+	}
+	restricted := []models.NodeType{models.NodeTypeStart, models.NodeTypeForm, models.NodeTypeEnd}
+
+	t.Run("every type is allowed by default", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+		service := NewWorkflowService(mockRepo)
+
+		assert.Nil(t, service.AllowedNodeTypes())
+		assert.NoError(t, service.CreateWorkflow(context.Background(), newWorkflow()))
+	})
+
+	t.Run("create rejects a disallowed type and names the node", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		service := NewWorkflowService(mockRepo)
+		service.SetAllowedNodeTypes(restricted)
+
+		err := service.CreateWorkflow(context.Background(), newWorkflow())
+		assert.ErrorIs(t, err, ErrNodeTypeNotAllowed)
+		assert.ErrorIs(t, err, ErrInvalidWorkflowStructure)
+		assert.ErrorContains(t, err, `node weather-api has type "integration"`)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update rejects a disallowed type", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		service := NewWorkflowService(mockRepo)
+		service.SetAllowedNodeTypes(restricted)
+
+		err := service.UpdateWorkflow(context.Background(), newWorkflow())
+		assert.ErrorIs(t, err, ErrNodeTypeNotAllowed)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("batch validation reports a disallowed type", func(t *testing.T) {
+		service := NewWorkflowService(new(MockWorkflowRepository))
+		service.SetAllowedNodeTypes(restricted)
+
+		results, err := service.ValidateWorkflows(context.Background(), []*models.Workflow{newWorkflow()}, false)
+		assert.NoError(t, err)
+		assert.False(t, results[0].Valid)
+		assert.Equal(t, []string{`invalid workflow structure: node type not allowed: node weather-api has type "integration"`}, results[0].Errors)
+	})
+
+	t.Run("ad-hoc runs are restricted too", func(t *testing.T) {
+		service := newTestService(new(MockWorkflowRepository))
+		service.SetAllowedNodeTypes(restricted)
+
+		_, err := service.ExecuteAdhocWorkflow(context.Background(), newWorkflow(), models.WorkflowInput{})
+		assert.ErrorIs(t, err, ErrNodeTypeNotAllowed)
+	})
+
+	t.Run("allowed types pass and an empty list lifts the restriction", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Twice()
+		service := NewWorkflowService(mockRepo)
+		service.SetAllowedNodeTypes(append(restricted, models.NodeTypeIntegration))
+
+		assert.Equal(t, []models.NodeType{"end", "form", "integration", "start"}, service.AllowedNodeTypes())
+		assert.NoError(t, service.CreateWorkflow(context.Background(), newWorkflow()))
+
+		service.SetAllowedNodeTypes(restricted)
+		service.SetAllowedNodeTypes(nil)
+		assert.Nil(t, service.AllowedNodeTypes())
+		assert.NoError(t, service.CreateWorkflow(context.Background(), newWorkflow()))
+	})
+}
@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"workflow-code-test/api/pkg/models"
+)
+
+// DefaultMaxStepOutputBytes caps the stored size of each step's output unless
+// SetMaxStepOutputBytes is called
+const DefaultMaxStepOutputBytes = 64 << 10
+
+// SetMaxStepOutputBytes caps the JSON size of each step output written to the
+// execution history. Larger outputs are replaced by a truncation marker in storage
+// only; the execution returned to the caller keeps the full output. A limit of zero
+// or less removes the cap.
+func (s *WorkflowServiceImpl) SetMaxStepOutputBytes(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	s.maxStepOutputBytes = limit
+}
+
+// MaxStepOutputBytes returns the stored step output cap, or zero when outputs are stored in full
+func (s *WorkflowServiceImpl) MaxStepOutputBytes() int {
+	return s.maxStepOutputBytes
+}
+
+// capStepOutputs returns the execution to persist. When any step output exceeds
+// limit bytes it returns a copy with those outputs truncated, leaving execution untouched.
+func capStepOutputs(execution *models.WorkflowExecution, limit int) *models.WorkflowExecution {
+	if limit <= 0 {
+		return execution
+	}
+
+	var steps []models.ExecutionStep
+	for i, step := range execution.Steps {
+		truncated, ok := truncateOutput(step.Output, limit)
+		if !ok {
+			continue
+		}
+		if steps == nil {
+			steps = append([]models.ExecutionStep(nil), execution.Steps...)
+		}
+		steps[i].Output = truncated
+	}
+	if steps == nil {
+		return execution
+	}
+
+	stored := *execution
+	stored.Steps = steps
+	return &stored
+}
+
+// truncateOutput replaces output with a size note when its JSON encoding exceeds limit
+// bytes. The step's message, details and email recipient are kept when they fit: later
+// runs look up sent alerts by them for deduplication, so dropping them from a large
+// email step would let the same alert go out again.
+func truncateOutput(output models.JSONB, limit int) (models.JSONB, bool) {
+	encoded, err := json.Marshal(output)
+	if err != nil || len(encoded) <= limit {
+		return nil, false
+	}
+
+	truncated := models.JSONB{
+		"truncated":     true,
+		"originalBytes": len(encoded),
+		"note":          fmt.Sprintf("output of %d bytes exceeded the %d byte limit and was not stored", len(encoded), limit),
+	}
+	// Keep the step's summary message when it fits, so the history stays readable
+	if message, ok := output["message"].(string); ok && len(message) <= limit/2 {
+		truncated["message"] = message
+	}
+	if details, ok := output["details"]; ok {
+		if encoded, err := json.Marshal(details); err == nil && len(encoded) <= limit/4 {
+			truncated["details"] = details
+		}
+	}
+	if content, ok := output["emailContent"].(map[string]any); ok {
+		if to, ok := content["to"].(string); ok && len(to) <= limit/8 {
+			truncated["emailContent"] = map[string]any{"to": to}
+		}
+	}
+	return truncated, true
+}
@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// bulkyNode produces an output with a large raw payload
+type bulkyNode struct {
+	node.BaseNode
+	size int
+}
+
+func (n *bulkyNode) Type() models.NodeType { return models.NodeTypeForm }
+
+func (n *bulkyNode) GetBaseInfo() node.BaseNode { return n.BaseNode }
+
+func (n *bulkyNode) Validate() error { return nil }
+
+func (n *bulkyNode) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	return node.NodeOutputs{
+		Data:   map[string]any{"message": "Fetched payload", "raw": strings.Repeat("x", n.size)},
+		Status: models.StatusCompleted,
+	}, nil
+}
+
+func TestExecuteWorkflowCapsStoredStepOutput(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "bulky-workflow",
+		Name: "Bulky Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeForm, func(model models.Node) (node.Node, error) {
+		return &bulkyNode{BaseNode: node.BaseNode{ID: model.ID}, size: 4096}, nil
+	})
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+
+	t.Run("oversized output is truncated in storage only", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		var stored []models.ExecutionStep
+		mockRepo.On("CreateExecutionStep", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = append(stored, *args.Get(1).(*models.ExecutionStep))
+		}).Return(nil)
+		mockExecutionStorage(mockRepo)
+
+		service := NewWorkflowService(mockRepo)
+		service.SetEngine(execution.NewEngine(registry))
+		service.SetMaxStepOutputBytes(1024)
+
+		result, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+
+		// The caller sees the full output
+		assert.Len(t, result.Steps[1].Output["raw"], 4096)
+		assert.Nil(t, result.Steps[1].Output["truncated"])
+
+		// Storage gets a marker with a size note instead
+		if assert.Len(t, stored, 3) {
+			output := stored[1].Output
+			assert.Equal(t, true, output["truncated"])
+			assert.Greater(t, output["originalBytes"], 4096)
+			assert.Contains(t, output["note"], "exceeded the 1024 byte limit")
+			assert.Equal(t, "Fetched payload", output["message"])
+			assert.NotContains(t, output, "raw")
+
+			// Steps within the limit are stored unchanged
+			assert.Equal(t, result.Steps[0].Output, stored[0].Output)
+		}
+	})
+
+	t.Run("zero limit stores outputs in full", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		var stored []models.ExecutionStep
+		mockRepo.On("CreateExecutionStep", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = append(stored, *args.Get(1).(*models.ExecutionStep))
+		}).Return(nil)
+		mockExecutionStorage(mockRepo)
+
+		service := NewWorkflowService(mockRepo)
+		service.SetEngine(execution.NewEngine(registry))
+		service.SetMaxStepOutputBytes(0)
+		assert.Equal(t, 0, service.MaxStepOutputBytes())
+
+		result, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		if assert.Len(t, stored, 3) {
+			assert.Equal(t, result.Steps[1].Output, stored[1].Output)
+		}
+	})
+}
+
+func TestTruncateOutputKeepsAlertFields(t *testing.T) {
+	output := models.JSONB{
+		"message": "Email sent successfully",
+		"details": map[string]any{"city": "Sydney", "branch": "true"},
+		"emailContent": map[string]any{
+			"to":      "test@example.com",
+			"subject": "Weather alert",
+			"body":    strings.Repeat("x", 4096),
+		},
+	}
+
+	truncated, ok := truncateOutput(output, 1024)
+	assert.True(t, ok)
+	assert.Equal(t, true, truncated["truncated"])
+	assert.Equal(t, "Email sent successfully", truncated["message"])
+	assert.Equal(t, map[string]any{"city": "Sydney", "branch": "true"}, truncated["details"])
+	assert.Equal(t, map[string]any{"to": "test@example.com"}, truncated["emailContent"])
+
+	// Fields that would crowd out the marker are dropped
+	output["details"] = map[string]any{"raw": strings.Repeat("y", 512)}
+	truncated, ok = truncateOutput(output, 1024)
+	assert.True(t, ok)
+	assert.NotContains(t, truncated, "details")
+}
+
+func TestNewWorkflowServiceDefaultStepOutputCap(t *testing.T) {
+	assert.Equal(t, DefaultMaxStepOutputBytes, NewWorkflowService(nil).MaxStepOutputBytes())
+}
@@ -0,0 +1,109 @@
+package workflow
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/pkg/models"
+)
+
+// executionRecorder stores a run while it happens: the execution is created as
+// running when the run starts, each step is stored as it completes and the final
+// status is written when it finishes. Reads of the execution in the meantime return
+// the steps so far. Storage failures are logged rather than failing the run, and
+// whatever couldn't be stored along the way is stored when the run finishes. The
+// triggering email address is masked in everything stored.
+type executionRecorder struct {
+	repo               repository.WorkflowRepository
+	input              models.WorkflowInput
+	enqueuedAt         time.Time
+	maxStepOutputBytes int
+	mask               *addressMasker
+
+	created     bool // the running execution was stored
+	storedSteps int  // how many leading steps were stored
+}
+
+func (s *WorkflowServiceImpl) newExecutionRecorder(input models.WorkflowInput, enqueuedAt time.Time) *executionRecorder {
+	return &executionRecorder{
+		repo:               s.repo,
+		input:              input,
+		enqueuedAt:         enqueuedAt,
+		maxStepOutputBytes: s.maxStepOutputBytes,
+		mask:               newAddressMasker(input.Email),
+	}
+}
+
+// stored returns the execution as it is persisted: the address masked and oversized
+// step outputs truncated, leaving execution untouched
+func (r *executionRecorder) stored(execution *models.WorkflowExecution) *models.WorkflowExecution {
+	return capStepOutputs(r.mask.execution(execution), r.maxStepOutputBytes)
+}
+
+// Started records the triggering input on the run and stores it as running, along
+// with any steps it starts with, such as those a resumed run carries over
+func (r *executionRecorder) Started(ctx context.Context, execution *models.WorkflowExecution) {
+	execution.InputHash = r.input.Hash()
+	execution.EnqueuedAt = &r.enqueuedAt
+	storedInput := r.input.Redacted()
+	execution.Input = &storedInput
+
+	if err := r.repo.CreateExecution(ctx, r.stored(execution)); err != nil {
+		slog.Warn("Failed to store running execution, storing it when it finishes", "workflowId", execution.WorkflowID, "executionId", execution.ID, "error", err)
+		return
+	}
+	r.created = true
+	r.storedSteps = len(execution.Steps)
+}
+
+// StepCompleted stores the step. Steps are stored in order, so once one fails the
+// rest wait for the run to finish.
+func (r *executionRecorder) StepCompleted(ctx context.Context, execution *models.WorkflowExecution, step models.ExecutionStep) {
+	if !r.created || r.storedSteps != len(execution.Steps)-1 {
+		return
+	}
+	step.ExecutionID = execution.ID
+	step, _ = r.mask.step(step)
+	if r.maxStepOutputBytes > 0 {
+		if truncated, ok := truncateOutput(step.Output, r.maxStepOutputBytes); ok {
+			step.Output = truncated
+		}
+	}
+	if err := r.repo.CreateExecutionStep(ctx, &step); err != nil {
+		slog.Warn("Failed to store execution step, storing it when the run finishes", "workflowId", execution.WorkflowID, "executionId", execution.ID, "stepNumber", step.StepNumber, "error", err)
+		return
+	}
+	r.storedSteps++
+}
+
+// Finished stores the steps not stored yet and the final status. A run whose
+// start couldn't be stored is stored whole instead.
+func (r *executionRecorder) Finished(ctx context.Context, execution *models.WorkflowExecution) {
+	// A run cut short by the request's deadline must still not be left as running
+	ctx = context.WithoutCancel(ctx)
+
+	// Oversized step outputs are truncated and the address masked in storage only; the
+	// caller still gets them in full
+	stored := r.stored(execution)
+	if !r.created {
+		if err := r.repo.CreateExecution(ctx, stored); err != nil {
+			slog.Error("Failed to persist workflow execution", "workflowId", execution.WorkflowID, "executionId", execution.ID, "error", err)
+		}
+		return
+	}
+
+	if r.storedSteps < len(stored.Steps) {
+		remaining := make([]models.ExecutionStep, 0, len(stored.Steps)-r.storedSteps)
+		for _, step := range stored.Steps[r.storedSteps:] {
+			step.ExecutionID = execution.ID
+			remaining = append(remaining, step)
+		}
+		if err := r.repo.CreateExecutionSteps(ctx, remaining); err != nil {
+			slog.Error("Failed to persist execution steps", "workflowId", execution.WorkflowID, "executionId", execution.ID, "error", err)
+		}
+	}
+	if err := r.repo.UpdateExecution(ctx, stored); err != nil {
+		slog.Error("Failed to persist workflow execution", "workflowId", execution.WorkflowID, "executionId", execution.ID, "error", err)
+	}
+}
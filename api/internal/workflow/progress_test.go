@@ -0,0 +1,184 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryExecutionStore keeps executions and their steps in memory, so a run can be
+// read back while it is still going
+type memoryExecutionStore struct {
+	*MockWorkflowRepository
+	mu         sync.Mutex
+	executions map[string]models.WorkflowExecution
+	steps      map[string][]models.ExecutionStep
+	created    []string
+}
+
+func newMemoryExecutionStore() *memoryExecutionStore {
+	return &memoryExecutionStore{
+		MockWorkflowRepository: new(MockWorkflowRepository),
+		executions:             make(map[string]models.WorkflowExecution),
+		steps:                  make(map[string][]models.ExecutionStep),
+	}
+}
+
+func (s *memoryExecutionStore) CreateExecution(ctx context.Context, execution *models.WorkflowExecution) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *execution
+	stored.Steps = nil
+	s.executions[execution.ID] = stored
+	s.steps[execution.ID] = append([]models.ExecutionStep(nil), execution.Steps...)
+	s.created = append(s.created, execution.ID)
+	return nil
+}
+
+func (s *memoryExecutionStore) UpdateExecution(ctx context.Context, execution *models.WorkflowExecution) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.executions[execution.ID]
+	if !ok {
+		return repository.ErrExecutionNotFound
+	}
+	stored.Status = execution.Status
+	stored.EndTime = execution.EndTime
+	stored.EndedAt = execution.EndedAt
+	stored.TotalDuration = execution.TotalDuration
+	stored.ErrorSummary = execution.ErrorSummary
+	stored.Metadata = execution.Metadata
+	s.executions[execution.ID] = stored
+	return nil
+}
+
+func (s *memoryExecutionStore) CreateExecutionStep(ctx context.Context, step *models.ExecutionStep) error {
+	return s.CreateExecutionSteps(ctx, []models.ExecutionStep{*step})
+}
+
+func (s *memoryExecutionStore) CreateExecutionSteps(ctx context.Context, steps []models.ExecutionStep) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, step := range steps {
+		s.steps[step.ExecutionID] = append(s.steps[step.ExecutionID], step)
+	}
+	return nil
+}
+
+func (s *memoryExecutionStore) GetExecution(ctx context.Context, id string) (*models.WorkflowExecution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.executions[id]
+	if !ok {
+		return nil, repository.ErrExecutionNotFound
+	}
+	return &stored, nil
+}
+
+func (s *memoryExecutionStore) GetExecutionSteps(ctx context.Context, executionID string) ([]models.ExecutionStep, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.ExecutionStep{}, s.steps[executionID]...), nil
+}
+
+// createdID returns the ID of the first execution stored, or "" before there is one
+func (s *memoryExecutionStore) createdID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.created) == 0 {
+		return ""
+	}
+	return s.created[0]
+}
+
+func TestExecutionStepsReadableWhileRunning(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "slow-workflow",
+		Name: "Slow Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	unblock := make(chan struct{})
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeForm, func(model models.Node) (node.Node, error) {
+		return &blockingNode{BaseNode: node.BaseNode{ID: model.ID}, unblock: unblock}, nil
+	})
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+
+	store := newMemoryExecutionStore()
+	mockStoredWorkflow(store.MockWorkflowRepository, workflow)
+	service := NewWorkflowService(store)
+	service.SetEngine(execution.NewEngine(registry))
+
+	finished := make(chan *models.WorkflowExecution, 1)
+	go func() {
+		execution, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		finished <- execution
+	}()
+
+	// The form node is holding the run open, so only the start step has completed
+	var running *models.WorkflowExecution
+	polled := assert.Eventually(t, func() bool {
+		id := store.createdID()
+		if id == "" {
+			return false
+		}
+		execution, err := service.GetExecution(context.Background(), workflow.ID, id)
+		if err != nil || len(execution.Steps) == 0 {
+			return false
+		}
+		running = execution
+		return true
+	}, time.Second, time.Millisecond)
+	if !polled {
+		close(unblock)
+		return
+	}
+	assert.Equal(t, models.StatusRunning, running.Status)
+	assert.Len(t, running.Steps, 1)
+	assert.Equal(t, models.NodeTypeStart, running.Steps[0].NodeType)
+	assert.Nil(t, running.EndedAt)
+
+	close(unblock)
+	result := <-finished
+
+	done, err := service.GetExecution(context.Background(), workflow.ID, running.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, result.ID, done.ID)
+	assert.Equal(t, models.StatusCompleted, done.Status)
+	assert.NotNil(t, done.EndedAt)
+	if assert.Len(t, done.Steps, 3) {
+		for i, step := range done.Steps {
+			assert.Equal(t, i+1, step.StepNumber)
+		}
+		assert.Equal(t, models.NodeTypeEnd, done.Steps[2].NodeType)
+	}
+}
@@ -0,0 +1,67 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/pkg/models"
+)
+
+// ResumeExecution runs a failed execution again from the node it failed at, as a
+// new execution stored like any other run. The completed steps before that node are
+// carried over rather than run again. The failed run's stored input is reused, but
+// as its email is masked, a run that had an email needs it given again. The engine
+// puts the given email in the carried form output too.
+func (s *WorkflowServiceImpl) ResumeExecution(ctx context.Context, workflowID, executionID, email string) (*models.WorkflowExecution, error) {
+	enqueuedAt := time.Now()
+	if s.engine == nil {
+		return nil, ErrEngineNotInitialized
+	}
+
+	previous, err := s.GetExecution(ctx, workflowID, executionID)
+	if err != nil {
+		return nil, err
+	}
+	if previous.Status != models.StatusFailed {
+		return nil, fmt.Errorf("%w: execution is %s, not failed", ErrExecutionNotResumable, previous.Status)
+	}
+	if previous.Input == nil {
+		return nil, fmt.Errorf("%w: execution has no stored input", ErrExecutionNotResumable)
+	}
+	// Later nodes would read a truncation note in place of the output they expect
+	for _, step := range previous.Steps {
+		if truncated, _ := step.Output["truncated"].(bool); truncated {
+			return nil, fmt.Errorf("%w: output of step %d was not stored in full", ErrExecutionNotResumable, step.StepNumber)
+		}
+	}
+
+	workflow, err := s.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	// Carried steps must come from the definition that is about to run
+	if version, ok := previous.Metadata["workflowVersion"]; ok && fmt.Sprint(version) != strconv.Itoa(workflow.Version) {
+		return nil, fmt.Errorf("%w: workflow has changed since version %v", ErrExecutionNotResumable, version)
+	}
+
+	input := *previous.Input
+	if email != "" {
+		input.Email = email
+	} else if input.Email != "" {
+		return nil, fmt.Errorf("%w: email is required, the stored one is masked", ErrInvalidInput)
+	}
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	release, ok := s.acquireExecutionSlot()
+	if !ok {
+		return nil, ErrTooManyExecutions
+	}
+	defer release()
+
+	runCtx := execution.WithProgress(ctx, s.newExecutionRecorder(input, enqueuedAt))
+	return s.engine.Resume(runCtx, workflow, input, previous)
+}
@@ -0,0 +1,263 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/email"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/form"
+	"workflow-code-test/api/pkg/node/integration"
+	"workflow-code-test/api/pkg/node/start"
+
+	"github.com/stretchr/testify/assert"
+	mail "gopkg.in/gomail.v2"
+)
+
+func TestResumeExecutionPastFailedIntegrationNode(t *testing.T) {
+	// The weather service is down for the first run and back for the resumed one
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 31.0}}`)
+	}))
+	defer server.Close()
+
+	workflow := &models.Workflow{
+		ID:   "resume-workflow",
+		Name: "Resume Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "weather-api", Type: models.NodeTypeIntegration, Data: models.NodeData{Metadata: map[string]any{
+				"apiEndpoint": server.URL + "?latitude={lat}&longitude={lon}",
+				"options":     []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+				"cacheTtlMs":  0.0,
+			}}},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "weather-api"},
+			{ID: "e3", Source: "weather-api", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 25,
+	}
+
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeForm, form.NewNode)
+	registry.Register(models.NodeTypeIntegration, integration.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+
+	store := newMemoryExecutionStore()
+	mockStoredWorkflow(store.MockWorkflowRepository, workflow)
+	service := NewWorkflowService(store)
+	service.SetEngine(execution.NewEngine(registry))
+
+	failed, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+	assert.Equal(t, "weather-api", failed.ErrorSummary.NodeID)
+
+	// The stored email is masked, so it has to be given again
+	_, err = service.ResumeExecution(context.Background(), workflow.ID, failed.ID, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	resumed, err := service.ResumeExecution(context.Background(), workflow.ID, failed.ID, input.Email)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, resumed) {
+		return
+	}
+	assert.Equal(t, models.StatusCompleted, resumed.Status)
+	assert.Equal(t, failed.ID, resumed.Metadata["resumedFrom"])
+	assert.Equal(t, int32(2), calls.Load())
+
+	// The resumed run is stored with the carried steps once each, followed by its own
+	stored, err := service.GetExecution(context.Background(), workflow.ID, resumed.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, models.StatusCompleted, stored.Status)
+	if assert.Len(t, stored.Steps, 4) {
+		for i, step := range stored.Steps {
+			assert.Equal(t, i+1, step.StepNumber)
+		}
+		assert.Equal(t, "weather-api", stored.Steps[2].NodeID)
+		assert.Equal(t, models.StatusCompleted, stored.Steps[2].Status)
+		assert.Equal(t, 31.0, stored.Steps[2].Output["temperature"])
+	}
+
+	// The failed run is left as it was, and the completed one can't be resumed
+	original, err := service.GetExecution(context.Background(), workflow.ID, failed.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, models.StatusFailed, original.Status)
+	}
+	_, err = service.ResumeExecution(context.Background(), workflow.ID, resumed.ID, input.Email)
+	assert.ErrorIs(t, err, ErrExecutionNotResumable)
+}
+
+// recordingSender records the emails it is given
+type recordingSender struct {
+	messages []*mail.Message
+}
+
+func (s *recordingSender) Send(_ context.Context, messages ...*mail.Message) error {
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func TestResumeExecutionSendsToSuppliedEmail(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 31.0}}`)
+	}))
+	defer server.Close()
+
+	workflow := &models.Workflow{
+		ID:   "resume-email-workflow",
+		Name: "Resume Email Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "weather-api", Type: models.NodeTypeIntegration, Data: models.NodeData{Metadata: map[string]any{
+				"apiEndpoint": server.URL + "?latitude={lat}&longitude={lon}",
+				"options":     []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+				"cacheTtlMs":  0.0,
+			}}},
+			{ID: "email", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: map[string]any{
+				"inputVariables": []any{"city", "temperature"},
+				"emailTemplate":  map[string]any{"subject": "Weather in {{city}}", "body": "It is {{temperature}} degrees"},
+			}}},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "weather-api"},
+			{ID: "e3", Source: "weather-api", Target: "email"},
+			{ID: "e4", Source: "email", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 25,
+	}
+
+	sender := &recordingSender{}
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeForm, form.NewNode)
+	registry.Register(models.NodeTypeIntegration, integration.NewNode)
+	registry.Register(models.NodeTypeEmail, email.NewNodeFactory(email.FactoryConfig{Sender: sender}))
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+
+	store := newMemoryExecutionStore()
+	mockStoredWorkflow(store.MockWorkflowRepository, workflow)
+	service := NewWorkflowService(store)
+	service.SetEngine(execution.NewEngine(registry))
+
+	failed, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+	assert.NoError(t, err)
+	assert.Equal(t, "weather-api", failed.ErrorSummary.NodeID)
+	assert.Empty(t, sender.messages)
+
+	// The carried form step holds the masked address; the resumed run sends to the given one
+	resumed, err := service.ResumeExecution(context.Background(), workflow.ID, failed.ID, "new@example.com")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, resumed) {
+		return
+	}
+	assert.Equal(t, models.StatusCompleted, resumed.Status)
+	if assert.Len(t, sender.messages, 1) {
+		assert.Equal(t, []string{"new@example.com"}, sender.messages[0].GetHeader("To"))
+	}
+
+	// It is masked again when stored
+	stored, err := service.GetExecution(context.Background(), workflow.ID, resumed.ID)
+	if assert.NoError(t, err) && assert.Len(t, stored.Steps, 5) {
+		assert.Equal(t, "n***@example.com", stored.Steps[1].Output["email"])
+		assert.Equal(t, "n***@example.com", stored.Steps[1].Output["formData"].(map[string]any)["email"])
+	}
+}
+
+func TestResumeExecutionRejections(t *testing.T) {
+	workflow := &models.Workflow{ID: "resume-workflow", Version: 2}
+	storedInput := models.WorkflowInput{Name: "Test User", City: "Sydney", Operator: models.OperatorGreaterThan, Threshold: 25}
+
+	tests := []struct {
+		name     string
+		previous models.WorkflowExecution
+		steps    []models.ExecutionStep
+		expected error
+	}{
+		{
+			name:     "other workflow's execution",
+			previous: models.WorkflowExecution{WorkflowID: "other-workflow", Status: models.StatusFailed, Input: &storedInput},
+			expected: ErrExecutionNotFound,
+		},
+		{
+			name:     "no stored input",
+			previous: models.WorkflowExecution{WorkflowID: workflow.ID, Status: models.StatusFailed},
+			expected: ErrExecutionNotResumable,
+		},
+		{
+			name:     "truncated step output",
+			previous: models.WorkflowExecution{WorkflowID: workflow.ID, Status: models.StatusFailed, Input: &storedInput},
+			steps: []models.ExecutionStep{
+				{NodeID: "start", StepNumber: 1, Status: models.StatusCompleted, Output: models.JSONB{"truncated": true}},
+				{NodeID: "weather-api", StepNumber: 2, Status: models.StatusFailed},
+			},
+			expected: ErrExecutionNotResumable,
+		},
+		{
+			name: "workflow changed since",
+			previous: models.WorkflowExecution{
+				WorkflowID: workflow.ID,
+				Status:     models.StatusFailed,
+				Input:      &storedInput,
+				Metadata:   models.JSONB{"workflowVersion": 1.0},
+			},
+			expected: ErrExecutionNotResumable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryExecutionStore()
+			mockStoredWorkflow(store.MockWorkflowRepository, workflow)
+			service := NewWorkflowService(store)
+			service.SetEngine(execution.NewEngine(node.NewRegistry()))
+
+			previous := tt.previous
+			previous.ID = "failed-execution"
+			previous.Steps = tt.steps
+			assert.NoError(t, store.CreateExecution(context.Background(), &previous))
+
+			resumed, err := service.ResumeExecution(context.Background(), workflow.ID, previous.ID, "test@example.com")
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Nil(t, resumed)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package workflow
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"workflow-code-test/api/internal/execution"
 	"workflow-code-test/api/internal/repository"
 	"workflow-code-test/api/pkg/models"
@@ -26,27 +27,75 @@ var (
 	ErrDuplicateEdgeID       = errors.New("duplicate edge ID found")
 	ErrInvalidEdgeConnection = errors.New("edge has invalid source or target")
 	ErrEdgeToUnknownNode     = errors.New("edge references undefined node")
+	ErrDuplicateEdgePriority = errors.New("duplicate edge priority")
+	ErrStartNotConnectedToEnd = errors.New("start not connected to end")
+	ErrExecutionNotFound     = errors.New("execution not found")
+	ErrExecutionNotResumable = execution.ErrNotResumable // shared so the engine's reasons pass through
+	ErrInvalidCursor         = errors.New("invalid pagination cursor")
+	ErrInvalidExecutionFilter = errors.New("invalid execution filter")
+	ErrTooManyExecutions     = errors.New("too many concurrent executions")
+	ErrInvalidTag            = errors.New("invalid tag")
+	ErrInvalidHook           = errors.New("invalid execution hook")
+	ErrInvalidEdgeStyle      = errors.New("invalid edge style")
+	ErrInvalidMaxDuration    = errors.New("invalid max duration")
+	ErrNodeTypeNotAllowed    = errors.New("node type not allowed")
+	ErrInvalidExecutionOrder = errors.New("invalid execution order")
+	ErrInvalidValidationBatch = errors.New("invalid validation batch")
+	ErrWebhookNotConfigured  = errors.New("webhook not configured")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+	ErrInvalidCallbackURL    = errors.New("invalid callback URL")
+	ErrNodeNotFound          = errors.New("node not found")
+	ErrNotIntegrationNode    = errors.New("node is not an integration node")
 )
 
 // WorkflowServiceImpl implements the workflow.WorkflowService interface
 type WorkflowServiceImpl struct {
 	repo repository.WorkflowRepository
 	engine *execution.Engine
+	slots    chan struct{} // one entry per running execution when a limit is set
+	inFlight atomic.Int64
+	maxStepOutputBytes int // stored step output cap; zero stores outputs in full
+	allowedNodeTypes map[models.NodeType]bool // nil allows every registered type
+	callbacks CallbackConfig
 }
 
 // WorkflowService defines the interface for workflow operations
 type WorkflowService interface {
 	GetWorkflow(ctx context.Context, id string) (*models.Workflow, error)
+	ListWorkflows(ctx context.Context, tags map[string]string) ([]models.Workflow, error)
 	ExecuteWorkflow(ctx context.Context, id string, input models.WorkflowInput) (*models.WorkflowExecution, error)
+	ExecuteWorkflowInOrder(ctx context.Context, id string, input models.WorkflowInput, order []string) (*models.WorkflowExecution, error)
+	ExecuteAdhocWorkflow(ctx context.Context, workflow *models.Workflow, input models.WorkflowInput) (*models.WorkflowExecution, error)
 	CreateWorkflow(ctx context.Context, workflow *models.Workflow) error
 	UpdateWorkflow(ctx context.Context, workflow *models.Workflow) error
 	ProcessWorkflowInput(ctx context.Context, id string, input models.WorkflowInput) (*models.Workflow, error)
+	ImportWorkflow(ctx context.Context, workflow *models.Workflow, fixDuplicates bool) (*ImportResult, error)
+	TriggerWebhook(ctx context.Context, id string, payload []byte, signature string) (*models.WorkflowExecution, error)
+	TestIntegrationNode(ctx context.Context, workflowID, nodeID, city string) (*IntegrationTestResult, error)
+	ValidateWorkflow(ctx context.Context, id string, checkBranches bool) (*ValidationResult, error)
+	ValidateWorkflows(ctx context.Context, workflows []*models.Workflow, checkBranches bool) ([]BatchValidationResult, error)
+	ListExecutions(ctx context.Context, workflowID string, opts repository.ListExecutionsOptions) (*repository.ExecutionPage, error)
+	GetExecution(ctx context.Context, workflowID, executionID string) (*models.WorkflowExecution, error)
+	ResumeExecution(ctx context.Context, workflowID, executionID, email string) (*models.WorkflowExecution, error)
+	GetLatestExecution(ctx context.Context, workflowID string) (*models.WorkflowExecution, error)
+	DiffExecutions(ctx context.Context, workflowID, executionA, executionB string) (*ExecutionDiff, error)
+	SearchExecutions(ctx context.Context, opts repository.SearchExecutionsOptions) (*repository.ExecutionPage, error)
+	GetExecutionStats(ctx context.Context, workflowID string, window int) (*repository.ExecutionStats, error)
 	SetEngine(engine *execution.Engine)
+	SetMaxConcurrentExecutions(limit int)
+	MaxConcurrentExecutions() int
+	InFlightExecutions() int
+	SetMaxStepOutputBytes(limit int)
+	MaxStepOutputBytes() int
+	SetAllowedNodeTypes(types []models.NodeType)
+	AllowedNodeTypes() []models.NodeType
+	SetCallbackConfig(config CallbackConfig)
 }
 
 // NewWorkflowService creates a new workflow service
 func NewWorkflowService(repo repository.WorkflowRepository) WorkflowService {
-	return &WorkflowServiceImpl{repo: repo}
+	return &WorkflowServiceImpl{repo: repo, maxStepOutputBytes: DefaultMaxStepOutputBytes}
 }
 
 // SetEngine sets the execution engine for the service
@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"workflow-code-test/api/pkg/models"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body, keyed
+// with the workflow's webhook secret and prefixed with "sha256="
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+const webhookSignaturePrefix = "sha256="
+
+// SignWebhookPayload returns the signature header value for body, as callers compute it
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a signature header value against body in constant time
+func VerifyWebhookSignature(secret string, body []byte, signature string) error {
+	if signature == "" {
+		return fmt.Errorf("%w: missing %s header", ErrInvalidWebhookSignature, WebhookSignatureHeader)
+	}
+	digest, found := strings.CutPrefix(signature, webhookSignaturePrefix)
+	if !found {
+		return fmt.Errorf("%w: signature must start with %s", ErrInvalidWebhookSignature, webhookSignaturePrefix)
+	}
+	provided, err := hex.DecodeString(digest)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not hex", ErrInvalidWebhookSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(provided, mac.Sum(nil)) {
+		return fmt.Errorf("%w: signature does not match", ErrInvalidWebhookSignature)
+	}
+	return nil
+}
+
+// MapWebhookPayload builds the workflow input from a webhook payload. Each mapping
+// entry copies the value at a dotted payload path to an input field; paths missing
+// from the payload leave the field unset so workflow defaults still apply. Without
+// a mapping the payload is decoded as the input directly. A payload that can't be
+// decoded or mapped is reported as ErrInvalidWebhookPayload.
+func MapWebhookPayload(payload []byte, mapping map[string]string) (models.WorkflowInput, error) {
+	var input models.WorkflowInput
+	if len(mapping) == 0 {
+		if err := json.Unmarshal(payload, &input); err != nil {
+			return input, fmt.Errorf("%w: %w: payload is not a workflow input: %v", ErrInvalidInput, ErrInvalidWebhookPayload, err)
+		}
+		return input, nil
+	}
+
+	var document any
+	if err := json.Unmarshal(payload, &document); err != nil {
+		return input, fmt.Errorf("%w: %w: payload is not valid JSON: %v", ErrInvalidInput, ErrInvalidWebhookPayload, err)
+	}
+	fields := make(map[string]any, len(mapping))
+	for field, path := range mapping {
+		if value, ok := lookupPath(document, path); ok {
+			fields[field] = value
+		}
+	}
+	// Round-trip through JSON so the input records which fields were provided
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return input, fmt.Errorf("%w: %w: %v", ErrInvalidInput, ErrInvalidWebhookPayload, err)
+	}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return input, fmt.Errorf("%w: %w: mapped payload is not a workflow input: %v", ErrInvalidInput, ErrInvalidWebhookPayload, err)
+	}
+	return input, nil
+}
+
+// lookupPath walks a decoded JSON document along a dotted path of object keys
+func lookupPath(document any, path string) (any, bool) {
+	current := document
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// TriggerWebhook verifies a signed webhook request for a workflow, maps its
+// payload to the workflow input and executes the workflow
+func (s *WorkflowServiceImpl) TriggerWebhook(ctx context.Context, id string, payload []byte, signature string) (*models.WorkflowExecution, error) {
+	workflow, err := s.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if workflow.Webhook == nil || workflow.Webhook.Secret == "" {
+		return nil, ErrWebhookNotConfigured
+	}
+	if err := VerifyWebhookSignature(workflow.Webhook.Secret, payload, signature); err != nil {
+		return nil, err
+	}
+
+	input, err := MapWebhookPayload(payload, workflow.Webhook.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	// A webhook can't replace the workflow definition it is triggering
+	input.Workflow = nil
+
+	return s.ExecuteWorkflow(ctx, id, input)
+}
+
+// webhooksEqual compares two webhook configurations
+func webhooksEqual(a, b *models.WebhookConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Secret == b.Secret && maps.Equal(a.Mapping, b.Mapping)
+}
@@ -0,0 +1,137 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"city":"Sydney"}`)
+	valid := SignWebhookPayload("s3cret", body)
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", valid)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		valid     bool
+	}{
+		{"valid", "s3cret", body, valid, true},
+		{"missing", "s3cret", body, "", false},
+		{"wrong secret", "other", body, valid, false},
+		{"tampered body", "s3cret", []byte(`{"city":"Perth"}`), valid, false},
+		{"missing prefix", "s3cret", body, valid[len("sha256="):], false},
+		{"not hex", "s3cret", body, "sha256=zz", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookSignature(tt.secret, tt.body, tt.signature)
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ErrInvalidWebhookSignature))
+		})
+	}
+}
+
+func TestMapWebhookPayload(t *testing.T) {
+	payload := []byte(`{"location": {"name": "Melbourne"}, "limits": {"max": 35}, "contact": "ops@example.com"}`)
+
+	input, err := MapWebhookPayload(payload, map[string]string{
+		"city":      "location.name",
+		"threshold": "limits.max",
+		"email":     "contact",
+		"name":      "missing.path",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Melbourne", input.City)
+	assert.Equal(t, 35.0, input.Threshold)
+	assert.Equal(t, "ops@example.com", input.Email)
+
+	// Unmapped fields are left for the workflow defaults
+	input.ApplyDefaults(models.WorkflowInput{Name: "Webhook", City: "Sydney"})
+	assert.Equal(t, "Webhook", input.Name)
+	assert.Equal(t, "Melbourne", input.City)
+
+	t.Run("payload used directly without a mapping", func(t *testing.T) {
+		input, err := MapWebhookPayload([]byte(`{"city": "Perth", "threshold": 20}`), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Perth", input.City)
+		assert.Equal(t, 20.0, input.Threshold)
+	})
+
+	t.Run("mapped value of the wrong type", func(t *testing.T) {
+		_, err := MapWebhookPayload(payload, map[string]string{"threshold": "location.name"})
+		assert.True(t, errors.Is(err, ErrInvalidInput))
+		assert.True(t, errors.Is(err, ErrInvalidWebhookPayload))
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := MapWebhookPayload([]byte(`{`), map[string]string{"city": "city"})
+		assert.True(t, errors.Is(err, ErrInvalidInput))
+		assert.True(t, errors.Is(err, ErrInvalidWebhookPayload))
+	})
+}
+
+func TestTriggerWebhook(t *testing.T) {
+	newWorkflow := func(webhook *models.WebhookConfig) *models.Workflow {
+		return &models.Workflow{
+			ID:   "webhook-workflow",
+			Name: "Webhook Workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "form", Type: models.NodeTypeForm},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "form"},
+				{ID: "e2", Source: "form", Target: "end"},
+			},
+			DefaultInput: &models.WorkflowInput{
+				Name: "Webhook", Email: "alerts@example.com", City: "Sydney",
+				Threshold: 30, Operator: models.OperatorGreaterThan,
+			},
+			Webhook: webhook,
+		}
+	}
+	webhook := &models.WebhookConfig{Secret: "s3cret", Mapping: map[string]string{"city": "location.name"}}
+	payload := []byte(`{"location": {"name": "Perth"}}`)
+
+	t.Run("valid signature executes with mapped input", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, newWorkflow(webhook))
+		service := newTestService(mockRepo)
+
+		execution, err := service.TriggerWebhook(context.Background(), "webhook-workflow", payload, SignWebhookPayload("s3cret", payload))
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		assert.Equal(t, "Perth", execution.Steps[1].Output["city"])
+	})
+
+	t.Run("invalid signature is rejected before executing", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, newWorkflow(webhook))
+		service := newTestService(mockRepo)
+
+		_, err := service.TriggerWebhook(context.Background(), "webhook-workflow", payload, SignWebhookPayload("guess", payload))
+		assert.True(t, errors.Is(err, ErrInvalidWebhookSignature))
+		mockRepo.AssertNotCalled(t, "CreateExecution", mock.Anything, mock.Anything)
+	})
+
+	t.Run("workflow without a webhook", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, newWorkflow(nil))
+		service := newTestService(mockRepo)
+
+		_, err := service.TriggerWebhook(context.Background(), "webhook-workflow", payload, SignWebhookPayload("", payload))
+		assert.True(t, errors.Is(err, ErrWebhookNotConfigured))
+	})
+}
@@ -6,8 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"workflow-code-test/api/internal/execution"
 	"workflow-code-test/api/internal/repository"
 	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/google/uuid"
 )
 
 // GetWorkflow retrieves a workflow by its ID
@@ -36,46 +44,320 @@ func (s *WorkflowServiceImpl) GetWorkflow(ctx context.Context, id string) (*mode
 	return workflow, nil
 }
 
+// ListWorkflows returns workflows carrying every given tag, without their nodes and edges
+func (s *WorkflowServiceImpl) ListWorkflows(ctx context.Context, tags map[string]string) ([]models.Workflow, error) {
+	if err := validateTags(tags); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, tags)
+}
+
 // ExecuteWorkflow runs a workflow with the given input
 func (s *WorkflowServiceImpl) ExecuteWorkflow(ctx context.Context, id string, input models.WorkflowInput) (*models.WorkflowExecution, error) {
+	// Time spent loading the workflow and waiting for a slot counts as queueing
+	enqueuedAt := time.Now()
+	// Checked first, as preparing the run may store a workflow embedded in the input
+	if err := s.validateCallbackURL(input.CallbackURL); err != nil {
+		return nil, err
+	}
+	workflow, input, err := s.prepareExecution(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Validate workflow structure before execution, so a stored definition that can't
+	// run, such as one whose start node isn't connected to its end, is rejected clearly
+	if err := validateWorkflowStructure(workflow.Nodes, workflow.Edges); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWorkflowStructure, err)
+	}
+	
+	// Hold a slot for the whole run, including persistence
+	release, ok := s.acquireExecutionSlot()
+	if !ok {
+		return nil, ErrTooManyExecutions
+	}
+	defer release()
+	
+	// Execute the workflow, storing it as it runs so its progress can be read
+	runCtx := execution.WithProgress(ctx, s.newExecutionRecorder(input, enqueuedAt))
+	execution, err := s.engine.Execute(runCtx, workflow, input)
+	if err != nil {
+		return nil, err
+	}
+	if input.CallbackURL != "" {
+		s.sendCallback(input.CallbackURL, input.Email, execution)
+	}
+
+	return execution, nil
+}
+
+// ExecuteAdhocWorkflow validates and runs a workflow definition that is not stored,
+// for trying out definitions before saving them. Neither the workflow nor the run is
+// persisted, and nodes run with node.WithEphemeral so they don't write to stores such
+// as workflow state. The definition must pass the same checks as a stored workflow.
+func (s *WorkflowServiceImpl) ExecuteAdhocWorkflow(ctx context.Context, workflow *models.Workflow, input models.WorkflowInput) (*models.WorkflowExecution, error) {
 	if s.engine == nil {
 		return nil, ErrEngineNotInitialized
 	}
+	if workflow == nil {
+		return nil, fmt.Errorf("%w: workflow definition is required", ErrInvalidWorkflowStructure)
+	}
+	if err := validateWorkflow(workflow); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkflowStructure, err)
+	}
+	if err := validateTags(workflow.Tags); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkflowStructure, err)
+	}
+	if err := s.checkNodeTypesAllowed(workflow.Nodes); err != nil {
+		return nil, err
+	}
+	if workflow.ID == "" {
+		workflow.ID = uuid.New().String()
+	}
+	
+	if workflow.DefaultInput != nil {
+		input.ApplyDefaults(*workflow.DefaultInput)
+	}
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if err := s.validateCallbackURL(input.CallbackURL); err != nil {
+		return nil, err
+	}
+	
+	release, ok := s.acquireExecutionSlot()
+	if !ok {
+		return nil, ErrTooManyExecutions
+	}
+	defer release()
+	
+	execution, err := s.engine.Execute(node.WithEphemeral(ctx), workflow, input)
+	if err != nil {
+		return nil, err
+	}
+	if input.CallbackURL != "" {
+		s.sendCallback(input.CallbackURL, input.Email, execution)
+	}
+	return execution, nil
+}
+
+// ExecuteWorkflowInOrder runs the given nodes in the given order, bypassing edges and
+// conditional routing, to help isolate a misbehaving node. The run is returned but not
+// stored in the execution history.
+func (s *WorkflowServiceImpl) ExecuteWorkflowInOrder(ctx context.Context, id string, input models.WorkflowInput, order []string) (*models.WorkflowExecution, error) {
+	workflow, input, err := s.prepareExecution(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	
+	if len(order) == 0 {
+		return nil, fmt.Errorf("%w: at least one node ID is required", ErrInvalidExecutionOrder)
+	}
+	nodeIDs := make(map[string]struct{}, len(workflow.Nodes))
+	for _, node := range workflow.Nodes {
+		nodeIDs[node.ID] = struct{}{}
+	}
+	for _, nodeID := range order {
+		if _, exists := nodeIDs[nodeID]; !exists {
+			return nil, fmt.Errorf("%w: unknown node %s", ErrInvalidExecutionOrder, nodeID)
+		}
+	}
+	
+	release, ok := s.acquireExecutionSlot()
+	if !ok {
+		return nil, ErrTooManyExecutions
+	}
+	defer release()
+	
+	return s.engine.ExecuteSequence(ctx, workflow, input, order)
+}
+
+// prepareExecution loads the workflow and merges and validates the execution input
+func (s *WorkflowServiceImpl) prepareExecution(ctx context.Context, id string, input models.WorkflowInput) (*models.Workflow, models.WorkflowInput, error) {
+	if s.engine == nil {
+		return nil, input, ErrEngineNotInitialized
+	}
 
 	// Process any workflow data in the input and get the workflow in one step
 	workflow, err := s.ProcessWorkflowInput(ctx, id, input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to process workflow input: %w", err)
+		return nil, input, fmt.Errorf("failed to process workflow input: %w", err)
 	}
 
 	// If no workflow was returned (no JSONB processing occurred), get it directly
 	if workflow == nil {
 		workflow, err = s.GetWorkflow(ctx, id)
 		if err != nil {
-			return nil, err
+			return nil, input, err
 		}
 	}
 	
-	// Validate workflow structure before execution
-	if err := validateWorkflowStructure(workflow.Nodes, workflow.Edges); err != nil {
-		return nil, fmt.Errorf("invalid workflow structure: %w", err)
+	// Fill fields missing from the request with the workflow's defaults
+	if workflow.DefaultInput != nil {
+		input.ApplyDefaults(*workflow.DefaultInput)
 	}
-	
-	// Execute the workflow
-	execution, err := s.engine.Execute(ctx, workflow, input)
+
+	// Validate the merged input
+	if err := input.Validate(); err != nil {
+		return nil, input, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	return workflow, input, nil
+}
+
+// ListExecutions returns a page of a workflow's past executions, newest first
+func (s *WorkflowServiceImpl) ListExecutions(ctx context.Context, workflowID string, opts repository.ListExecutionsOptions) (*repository.ExecutionPage, error) {
+	if _, err := s.repo.Get(ctx, workflowID); err != nil {
+		if errors.Is(err, repository.ErrWorkflowNotFound) {
+			return nil, ErrWorkflowNotFound
+		}
+		return nil, err
+	}
+	if opts.InputHash != "" && !isInputHash(opts.InputHash) {
+		return nil, fmt.Errorf("%w: inputHash must be 64 lowercase hex characters", ErrInvalidExecutionFilter)
+	}
+
+	page, err := s.repo.ListExecutions(ctx, workflowID, opts)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, ErrInvalidCursor
+		}
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetExecutionStats aggregates the KPIs of a workflow's most recent executions
+func (s *WorkflowServiceImpl) GetExecutionStats(ctx context.Context, workflowID string, window int) (*repository.ExecutionStats, error) {
+	if _, err := s.repo.Get(ctx, workflowID); err != nil {
+		if errors.Is(err, repository.ErrWorkflowNotFound) {
+			return nil, ErrWorkflowNotFound
+		}
+		return nil, err
+	}
+
+	return s.repo.GetExecutionStats(ctx, workflowID, window)
+}
+
+// isInputHash reports whether hash has the form produced by models.WorkflowInput.Hash
+func isInputHash(hash string) bool {
+	if len(hash) != 64 {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchExecutions returns a page of executions across all workflows matching the filters, newest first
+func (s *WorkflowServiceImpl) SearchExecutions(ctx context.Context, opts repository.SearchExecutionsOptions) (*repository.ExecutionPage, error) {
+	if opts.Status != "" && !models.ValidStatuses[opts.Status] {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidExecutionFilter, opts.Status)
+	}
+	if opts.WorkflowID != "" {
+		if _, err := uuid.Parse(opts.WorkflowID); err != nil {
+			return nil, fmt.Errorf("%w: workflowId must be a UUID", ErrInvalidExecutionFilter)
+		}
+	}
+	if opts.From != nil && opts.To != nil && !opts.From.Before(*opts.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidExecutionFilter)
+	}
+
+	page, err := s.repo.SearchExecutions(ctx, opts)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, ErrInvalidCursor
+		}
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetExecution retrieves a single execution of a workflow along with its steps
+func (s *WorkflowServiceImpl) GetExecution(ctx context.Context, workflowID, executionID string) (*models.WorkflowExecution, error) {
+	execution, err := s.repo.GetExecution(ctx, executionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrExecutionNotFound) {
+			return nil, ErrExecutionNotFound
+		}
+		return nil, err
+	}
+
+	// Don't expose executions through another workflow's URL
+	if execution.WorkflowID != workflowID {
+		return nil, ErrExecutionNotFound
+	}
+
+	steps, err := s.repo.GetExecutionSteps(ctx, executionID)
 	if err != nil {
 		return nil, err
 	}
+	execution.Steps = steps
 
 	return execution, nil
 }
 
-// CreateWorkflow creates a new workflow
+// GetLatestExecution retrieves a workflow's most recent execution along with its steps
+func (s *WorkflowServiceImpl) GetLatestExecution(ctx context.Context, workflowID string) (*models.WorkflowExecution, error) {
+	execution, err := s.repo.GetLatestExecution(ctx, workflowID)
+	if err != nil {
+		if errors.Is(err, repository.ErrExecutionNotFound) {
+			return nil, ErrExecutionNotFound
+		}
+		return nil, err
+	}
+
+	steps, err := s.repo.GetExecutionSteps(ctx, execution.ID)
+	if err != nil {
+		return nil, err
+	}
+	execution.Steps = steps
+
+	return execution, nil
+}
+
+// ValidateWorkflow validates a stored workflow's structure and, when requested,
+// simulates both branches of every condition node to confirm they reach an end node
+func (s *WorkflowServiceImpl) ValidateWorkflow(ctx context.Context, id string, checkBranches bool) (*ValidationResult, error) {
+	workflow, err := s.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: make([]string, 0),
+	}
+	for _, err := range checkWorkflowStructure(workflow.Nodes, workflow.Edges, false) {
+		result.Valid = false
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	if checkBranches {
+		result.Branches = evaluateAllBranches(workflow.Nodes, workflow.Edges)
+		for _, branch := range result.Branches {
+			if !branch.ReachesEnd {
+				result.Valid = false
+				result.Errors = append(result.Errors, branch.Error)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// CreateWorkflow creates a new workflow. A definition that fails validation is
+// rejected with ErrInvalidWorkflowStructure.
 func (s *WorkflowServiceImpl) CreateWorkflow(ctx context.Context, workflow *models.Workflow) error {
-	// Validate workflow structure
-	if err := validateWorkflowStructure(workflow.Nodes, workflow.Edges); err != nil {
-		return fmt.Errorf("cannot create workflow with ID %s: %w", workflow.ID, err)
+	if errs := s.definitionErrors(workflow); len(errs) > 0 {
+		return fmt.Errorf("%w: cannot create workflow with ID %s: %w", ErrInvalidWorkflowStructure, workflow.ID, errors.Join(errs...))
 	}
+	applyEdgeStyleDefaults(workflow.Edges)
 
 	err := s.repo.Create(ctx, workflow)
 	if err != nil {
@@ -86,10 +368,10 @@ func (s *WorkflowServiceImpl) CreateWorkflow(ctx context.Context, workflow *mode
 
 // UpdateWorkflow updates an existing workflow
 func (s *WorkflowServiceImpl) UpdateWorkflow(ctx context.Context, workflow *models.Workflow) error {
-	// Validate workflow structure
-	if err := validateWorkflowStructure(workflow.Nodes, workflow.Edges); err != nil {
-		return fmt.Errorf("cannot update workflow with ID %s: %w", workflow.ID, err)
+	if errs := s.definitionErrors(workflow); len(errs) > 0 {
+		return fmt.Errorf("cannot update workflow with ID %s: %w", workflow.ID, errors.Join(errs...))
 	}
+	applyEdgeStyleDefaults(workflow.Edges)
 
 	err := s.repo.Update(ctx, workflow)
 	if err != nil {
@@ -114,12 +396,20 @@ func (s *WorkflowServiceImpl) ProcessWorkflowInput(ctx context.Context, id strin
 	// Convert input.Workflow to workflow model in one step without intermediate marshal/unmarshal
 	var wf models.Workflow
 	if err := convertJSONBToWorkflow(input.Workflow, &wf); err != nil {
-		return nil, fmt.Errorf("failed to convert workflow JSONB data for ID %s: %w", id, err)
+		return nil, fmt.Errorf("%w: failed to convert workflow JSONB data for ID %s: %v", ErrInvalidWorkflowStructure, id, err)
 	}
 
 	// Basic validation of workflow structure
 	if err := validateWorkflow(&wf); err != nil {
-		return nil, fmt.Errorf("workflow validation error for ID %s: %w", id, err)
+		return nil, fmt.Errorf("%w: workflow validation error for ID %s: %v", ErrInvalidWorkflowStructure, id, err)
+	}
+
+	// Every node must be buildable from its metadata, so a malformed definition is
+	// rejected here instead of being stored and failing mid-run
+	if s.engine != nil {
+		if err := s.engine.ValidateNodes(wf.Nodes); err != nil {
+			return nil, fmt.Errorf("%w: invalid node configuration for ID %s: %v", ErrInvalidWorkflowStructure, id, err)
+		}
 	}
 
 	// Check if the ID matches an existing workflow
@@ -156,6 +446,28 @@ func (s *WorkflowServiceImpl) ProcessWorkflowInput(ctx context.Context, id strin
 	return s.GetWorkflow(ctx, id)
 }
 
+// definitionErrors returns every problem found by the checks a stored workflow
+// definition must pass. Creating, updating and batch validation share them, so a
+// definition that validates in a batch can also be stored.
+func (s *WorkflowServiceImpl) definitionErrors(wf *models.Workflow) []error {
+	var errs []error
+	if wf.Name == "" {
+		errs = append(errs, errors.New("workflow requires a name"))
+	}
+	for _, err := range []error{
+		validateTags(wf.Tags),
+		validateHooks(wf.Hooks),
+		validateMaxDuration(wf.MaxDurationMs),
+		validateEdgeStyles(wf.Edges),
+		s.checkNodeTypesAllowed(wf.Nodes),
+	} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return append(errs, checkWorkflowStructure(wf.Nodes, wf.Edges, false)...)
+}
+
 // validateWorkflow performs validation on workflow structure
 func validateWorkflow(wf *models.Workflow) error {
 	if wf.Name == "" {
@@ -163,13 +475,70 @@ func validateWorkflow(wf *models.Workflow) error {
 	}
 
 	// Use the comprehensive workflow structure validation
-	if err := validateWorkflowStructure(wf.Nodes, wf.Edges); err != nil {
+	if err := validateWorkflowStructureAll(wf.Nodes, wf.Edges); err != nil {
+		return err
+	}
+	if err := validateHooks(wf.Hooks); err != nil {
+		return err
+	}
+	if err := validateMaxDuration(wf.MaxDurationMs); err != nil {
+		return err
+	}
+	if err := validateEdgeStyles(wf.Edges); err != nil {
 		return err
 	}
 	
 	return nil
 }
 
+// validateTags checks tag keys are usable in key:value filters
+func validateTags(tags map[string]string) error {
+	for key := range tags {
+		if key == "" {
+			return fmt.Errorf("%w: key cannot be empty", ErrInvalidTag)
+		}
+		if strings.Contains(key, ":") {
+			return fmt.Errorf("%w: key %q cannot contain ':'", ErrInvalidTag, key)
+		}
+	}
+	return nil
+}
+
+// validateHooks checks each execution hook has a known stage, type and target
+func validateHooks(hooks []models.ExecutionHook) error {
+	for i, hook := range hooks {
+		if err := hook.Validate(); err != nil {
+			return fmt.Errorf("%w: hook %d: %v", ErrInvalidHook, i, err)
+		}
+	}
+	return nil
+}
+
+// validateMaxDuration checks a declared maximum run duration is positive
+func validateMaxDuration(maxDurationMs *int64) error {
+	if maxDurationMs != nil && *maxDurationMs <= 0 {
+		return fmt.Errorf("%w: maxDurationMs must be positive, got %d", ErrInvalidMaxDuration, *maxDurationMs)
+	}
+	return nil
+}
+
+// validateEdgeStyles checks each edge's stroke width and color
+func validateEdgeStyles(edges []models.Edge) error {
+	for _, edge := range edges {
+		if err := edge.Style.Validate(); err != nil {
+			return fmt.Errorf("%w: edge %s: %v", ErrInvalidEdgeStyle, edge.ID, err)
+		}
+	}
+	return nil
+}
+
+// applyEdgeStyleDefaults fills in the stroke color and width of edges that leave them out
+func applyEdgeStyleDefaults(edges []models.Edge) {
+	for i := range edges {
+		edges[i].Style.ApplyDefaults()
+	}
+}
+
 // convertJSONBToWorkflow converts JSONB map to workflow struct without intermediate marshaling
 func convertJSONBToWorkflow(jsonbData models.JSONB, wf *models.Workflow) error {
 	// Use a more efficient approach than marshal/unmarshal
@@ -191,6 +560,22 @@ func workflowsEqual(wf1, wf2 *models.Workflow) bool {
 	if wf1.Name != wf2.Name {
 		return false
 	}
+	if !maps.Equal(wf1.Tags, wf2.Tags) {
+		return false
+	}
+	if !defaultInputsEqual(wf1.DefaultInput, wf2.DefaultInput) {
+		return false
+	}
+	if !webhooksEqual(wf1.Webhook, wf2.Webhook) {
+		return false
+	}
+	if !slices.Equal(wf1.Hooks, wf2.Hooks) {
+		return false
+	}
+	if (wf1.MaxDurationMs == nil) != (wf2.MaxDurationMs == nil) ||
+		(wf1.MaxDurationMs != nil && *wf1.MaxDurationMs != *wf2.MaxDurationMs) {
+		return false
+	}
 	
 	// Quick check for number of nodes and edges
 	if len(wf1.Nodes) != len(wf2.Nodes) || len(wf1.Edges) != len(wf2.Edges) {
@@ -246,14 +631,19 @@ func workflowsEqual(wf1, wf2 *models.Workflow) bool {
 				return
 			}
 			
-			// Compare edge properties
+			// Compare edge properties. Styles are compared with their defaults filled
+			// in, as they are stored, so a definition leaving them out still matches.
+			edge1.Style.ApplyDefaults()
+			edge2.Style.ApplyDefaults()
 			if edge1.Source != edge2.Source ||
 			   edge1.Target != edge2.Target ||
 			   edge1.EdgeID != edge2.EdgeID ||
 			   edge1.EdgeType != edge2.EdgeType ||
 			   edge1.Animated != edge2.Animated ||
 			   edge1.SourceHandle != edge2.SourceHandle ||
-			   edge1.Label != edge2.Label {
+			   edge1.Label != edge2.Label ||
+			   edge1.Priority != edge2.Priority ||
+			   edge1.Style != edge2.Style {
 				edgesChan <- false
 				return
 			}
@@ -269,10 +659,45 @@ func workflowsEqual(wf1, wf2 *models.Workflow) bool {
 	return nodesEqual && edgesEqual
 }
 
-// validateWorkflowStructure validates the structure of a workflow
+// defaultInputsEqual compares the user-facing fields of two default inputs
+func defaultInputsEqual(a, b *models.WorkflowInput) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name &&
+		a.Email == b.Email &&
+		a.City == b.City &&
+		a.Threshold == b.Threshold &&
+		a.Operator == b.Operator
+}
+
+// validateWorkflowStructure returns the first structural problem found. It is used on
+// the execution path where stopping early is cheapest.
 func validateWorkflowStructure(nodes []models.Node, edges []models.Edge) error {
+	if errs := checkWorkflowStructure(nodes, edges, true); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// validateWorkflowStructureAll reports every structural problem at once, joined into a
+// single error; errors.Is still matches each individual problem
+func validateWorkflowStructureAll(nodes []models.Node, edges []models.Edge) error {
+	return errors.Join(checkWorkflowStructure(nodes, edges, false)...)
+}
+
+// checkWorkflowStructure validates nodes and edges, stopping at the first problem when failFast is set
+func checkWorkflowStructure(nodes []models.Node, edges []models.Edge, failFast bool) []error {
+	var errs []error
+	// report records a problem and returns true when validation should stop
+	report := func(err error) bool {
+		errs = append(errs, err)
+		return failFast
+	}
+
 	if len(nodes) == 0 {
-		return fmt.Errorf("%w: workflow must have at least one node", ErrInvalidWorkflowStructure)
+		report(fmt.Errorf("%w: workflow must have at least one node", ErrInvalidWorkflowStructure))
+		return errs
 	}
 
 	// Validate required node types and their positions
@@ -280,6 +705,7 @@ func validateWorkflowStructure(nodes []models.Node, edges []models.Edge) error {
 	hasEnd := false
 	startNodeIndex := -1
 	endNodeIndex := -1
+	endNodeIDs := make(map[string]bool)
 
 	// Ensure all nodes have unique IDs and required fields
 	nodeIDs := make(map[string]struct{})
@@ -292,60 +718,128 @@ func validateWorkflowStructure(nodes []models.Node, edges []models.Edge) error {
 		if node.Type == models.NodeTypeEnd {
 			hasEnd = true
 			endNodeIndex = i
+			endNodeIDs[node.ID] = true
 		}
 		
 		// Basic node validation
 		if node.ID == "" {
-			return fmt.Errorf("%w: node ID cannot be empty", ErrEmptyNodeID)
-		}
-		if _, exists := nodeIDs[node.ID]; exists {
-			return fmt.Errorf("%w: %s", ErrDuplicateNodeID, node.ID)
+			if report(fmt.Errorf("%w: node ID cannot be empty", ErrEmptyNodeID)) {
+				return errs
+			}
+		} else if _, exists := nodeIDs[node.ID]; exists {
+			if report(fmt.Errorf("%w: %s", ErrDuplicateNodeID, node.ID)) {
+				return errs
+			}
 		}
 		nodeIDs[node.ID] = struct{}{}
 		
 		// Validate node-specific fields
 		if node.Type == "" {
-			return fmt.Errorf("%w: node %s requires a type", ErrInvalidNodeType, node.ID)
+			if report(fmt.Errorf("%w: node %s requires a type", ErrInvalidNodeType, node.ID)) {
+				return errs
+			}
 		}
 	}
 
 	// Check if workflow has required start and end nodes
-	if !hasStart {
-		return ErrMissingStartNode
+	if !hasStart && report(ErrMissingStartNode) {
+		return errs
 	}
-	if !hasEnd {
-		return ErrMissingEndNode
+	if !hasEnd && report(ErrMissingEndNode) {
+		return errs
 	}
-	if startNodeIndex != 0 {
-		return ErrStartNodePosition
+	if hasStart && startNodeIndex != 0 && report(ErrStartNodePosition) {
+		return errs
 	}
-	if endNodeIndex != len(nodes)-1 {
-		return ErrEndNodePosition
+	if hasEnd && endNodeIndex != len(nodes)-1 && report(ErrEndNodePosition) {
+		return errs
 	}
 
-	// Ensure all edges have unique IDs and correct source/target nodes
+	// Ensure all edges have unique IDs and correct source/target nodes, and that a
+	// node's unlabelled edges have distinct priorities so the engine's pick is clear
 	edgeIDs := make(map[string]struct{})
+	type sourcePriority struct {
+		source   string
+		priority int
+	}
+	priorityEdges := make(map[sourcePriority]string)
 	for _, edge := range edges {
 		if edge.ID == "" {
-			return ErrEmptyEdgeID
-		}
-		if _, exists := edgeIDs[edge.ID]; exists {
-			return fmt.Errorf("%w: %s", ErrDuplicateEdgeID, edge.ID)
+			if report(ErrEmptyEdgeID) {
+				return errs
+			}
+		} else if _, exists := edgeIDs[edge.ID]; exists {
+			if report(fmt.Errorf("%w: %s", ErrDuplicateEdgeID, edge.ID)) {
+				return errs
+			}
 		}
 		edgeIDs[edge.ID] = struct{}{}
 		
 		// Validate edge-specific fields
 		if edge.Source == "" || edge.Target == "" {
-			return fmt.Errorf("%w: edge %s must have non-empty source and target", ErrInvalidEdgeConnection, edge.ID)
+			if report(fmt.Errorf("%w: edge %s must have non-empty source and target", ErrInvalidEdgeConnection, edge.ID)) {
+				return errs
+			}
+			continue
 		}
 		if _, exists := nodeIDs[edge.Source]; !exists {
-			return fmt.Errorf("%w: edge %s references undefined source node %s", ErrEdgeToUnknownNode, edge.ID, edge.Source)
+			if report(fmt.Errorf("%w: edge %s references undefined source node %s", ErrEdgeToUnknownNode, edge.ID, edge.Source)) {
+				return errs
+			}
 		}
 		if _, exists := nodeIDs[edge.Target]; !exists {
-			return fmt.Errorf("%w: edge %s references undefined target node %s", ErrEdgeToUnknownNode, edge.ID, edge.Target)
+			if report(fmt.Errorf("%w: edge %s references undefined target node %s", ErrEdgeToUnknownNode, edge.ID, edge.Target)) {
+				return errs
+			}
+		}
+		if edge.SourceHandle == "" {
+			key := sourcePriority{edge.Source, edge.Priority}
+			if other, exists := priorityEdges[key]; exists {
+				if report(fmt.Errorf("%w: edges %s and %s from node %s both have priority %d", ErrDuplicateEdgePriority, other, edge.ID, edge.Source, edge.Priority)) {
+					return errs
+				}
+			} else {
+				priorityEdges[key] = edge.ID
+			}
 		}
 	}
 
-	return nil
+	// A run can only finish if some path of edges leads from the start node to an end
+	// node; the smallest such workflow is a start node with one edge to an end node.
+	// Problems found above already explain a broken path, so it is only checked without them.
+	if hasStart && hasEnd && len(errs) == 0 {
+		startNodeID := nodes[startNodeIndex].ID
+		if !reachesEnd(startNodeID, edges, endNodeIDs) {
+			report(fmt.Errorf("%w: no edges lead from start node %s to an end node", ErrStartNotConnectedToEnd, startNodeID))
+		}
+	}
+
+	return errs
+}
+
+// reachesEnd reports whether any path of edges, whatever their handles, leads from
+// startNodeID to one of endNodeIDs
+func reachesEnd(startNodeID string, edges []models.Edge, endNodeIDs map[string]bool) bool {
+	targets := make(map[string][]string)
+	for _, edge := range edges {
+		targets[edge.Source] = append(targets[edge.Source], edge.Target)
+	}
+
+	visited := map[string]bool{startNodeID: true}
+	queue := []string{startNodeID}
+	for len(queue) > 0 {
+		nodeID := queue[0]
+		queue = queue[1:]
+		if endNodeIDs[nodeID] {
+			return true
+		}
+		for _, target := range targets[nodeID] {
+			if !visited[target] {
+				visited[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	return false
 }
 
@@ -2,10 +2,21 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
+	"workflow-code-test/api/internal/execution"
+	"workflow-code-test/api/internal/repository"
+	"workflow-code-test/api/pkg/mailer"
 	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/form"
+	"workflow-code-test/api/pkg/node/integration"
+	"workflow-code-test/api/pkg/node/start"
+	"workflow-code-test/api/pkg/node/state"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -92,6 +103,14 @@ func (m *MockWorkflowRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *MockWorkflowRepository) List(ctx context.Context, tags map[string]string) ([]models.Workflow, error) {
+	args := m.Called(ctx, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Workflow), args.Error(1)
+}
+
 func (m *MockWorkflowRepository) GetNodes(ctx context.Context, workflowID string) ([]models.Node, error) {
 	args := m.Called(ctx, workflowID)
 	return args.Get(0).([]models.Node), args.Error(1)
@@ -107,6 +126,11 @@ func (m *MockWorkflowRepository) CreateExecution(ctx context.Context, execution
 	return args.Error(0)
 }
 
+func (m *MockWorkflowRepository) UpdateExecution(ctx context.Context, execution *models.WorkflowExecution) error {
+	args := m.Called(ctx, execution)
+	return args.Error(0)
+}
+
 func (m *MockWorkflowRepository) GetExecution(ctx context.Context, id string) (*models.WorkflowExecution, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -115,11 +139,79 @@ func (m *MockWorkflowRepository) GetExecution(ctx context.Context, id string) (*
 	return args.Get(0).(*models.WorkflowExecution), args.Error(1)
 }
 
+func (m *MockWorkflowRepository) GetLatestExecution(ctx context.Context, workflowID string) (*models.WorkflowExecution, error) {
+	args := m.Called(ctx, workflowID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WorkflowExecution), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) ListExecutions(ctx context.Context, workflowID string, opts repository.ListExecutionsOptions) (*repository.ExecutionPage, error) {
+	args := m.Called(ctx, workflowID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ExecutionPage), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) SearchExecutions(ctx context.Context, opts repository.SearchExecutionsOptions) (*repository.ExecutionPage, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ExecutionPage), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) GetExecutionStats(ctx context.Context, workflowID string, window int) (*repository.ExecutionStats, error) {
+	args := m.Called(ctx, workflowID, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ExecutionStats), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) LastAlertSent(ctx context.Context, recipient, city string, since time.Time) (time.Time, bool, error) {
+	args := m.Called(ctx, recipient, city, since)
+	return args.Get(0).(time.Time), args.Bool(1), args.Error(2)
+}
+
+func (m *MockWorkflowRepository) GetState(ctx context.Context, workflowID, key string) (any, bool, error) {
+	args := m.Called(ctx, workflowID, key)
+	return args.Get(0), args.Bool(1), args.Error(2)
+}
+
+func (m *MockWorkflowRepository) SetState(ctx context.Context, workflowID, key string, value any) error {
+	args := m.Called(ctx, workflowID, key, value)
+	return args.Error(0)
+}
+
+func (m *MockWorkflowRepository) SaveQueuedEmail(ctx context.Context, email mailer.QueuedEmail) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockWorkflowRepository) DeleteQueuedEmail(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWorkflowRepository) ListQueuedEmails(ctx context.Context) ([]mailer.QueuedEmail, error) {
+	args := m.Called(ctx)
+	emails, _ := args.Get(0).([]mailer.QueuedEmail)
+	return emails, args.Error(1)
+}
+
 func (m *MockWorkflowRepository) CreateExecutionStep(ctx context.Context, step *models.ExecutionStep) error {
 	args := m.Called(ctx, step)
 	return args.Error(0)
 }
 
+func (m *MockWorkflowRepository) CreateExecutionSteps(ctx context.Context, steps []models.ExecutionStep) error {
+	args := m.Called(ctx, steps)
+	return args.Error(0)
+}
+
 func (m *MockWorkflowRepository) GetExecutionSteps(ctx context.Context, executionID string) ([]models.ExecutionStep, error) {
 	args := m.Called(ctx, executionID)
 	return args.Get(0).([]models.ExecutionStep), args.Error(1)
@@ -334,6 +426,66 @@ func TestValidateWorkflowStructure(t *testing.T) {
 			},
 			expectedError: "end node must be the last node in the workflow",
 		},
+		{
+			name: "prioritised default edges",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "form", Type: models.NodeTypeForm},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "edge1", Source: "start", Target: "form", Priority: 2},
+				{ID: "edge2", Source: "start", Target: "end", Priority: 1},
+				{ID: "edge3", Source: "form", Target: "end"},
+			},
+			expectedError: "",
+		},
+		{
+			name: "minimal start to end",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "edge1", Source: "start", Target: "end"},
+			},
+			expectedError: "",
+		},
+		{
+			name: "start and end without an edge",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			expectedError: "start not connected to end: no edges lead from start node start to an end node",
+		},
+		{
+			name: "edges stopping short of the end",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "form", Type: models.NodeTypeForm},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "edge1", Source: "start", Target: "form"},
+				{ID: "edge2", Source: "end", Target: "form"},
+			},
+			expectedError: "start not connected to end",
+		},
+		{
+			name: "default edges sharing a priority",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "form", Type: models.NodeTypeForm},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "edge1", Source: "start", Target: "form", Priority: 1},
+				{ID: "edge2", Source: "start", Target: "end", Priority: 1},
+				{ID: "edge3", Source: "form", Target: "end"},
+			},
+			expectedError: "edges edge1 and edge2 from node start both have priority 1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -347,4 +499,1007 @@ func TestValidateWorkflowStructure(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestValidateWorkflowStructureAll(t *testing.T) {
+	nodes := []models.Node{
+		{ID: "start", Type: models.NodeTypeStart},
+		{ID: "form", Type: models.NodeTypeForm},
+		{ID: "form", Type: models.NodeTypeForm},
+		{ID: "end", Type: models.NodeTypeEnd},
+	}
+	edges := []models.Edge{
+		{ID: "e1", Source: "start", Target: "form"},
+		{ID: "e1", Source: "form", Target: "end"},
+		{ID: "", Source: "form", Target: "end"},
+		{ID: "e3", Source: "ghost", Target: "phantom"},
+		{ID: "e4", Source: "form", Target: ""},
+	}
+
+	// The fail-fast variant stops at the first problem
+	err := validateWorkflowStructure(nodes, edges)
+	assert.ErrorIs(t, err, ErrDuplicateNodeID)
+	assert.NotErrorIs(t, err, ErrDuplicateEdgeID)
+
+	errs := checkWorkflowStructure(nodes, edges, false)
+	assert.Len(t, errs, 7)
+
+	err = validateWorkflowStructureAll(nodes, edges)
+	assert.ErrorIs(t, err, ErrDuplicateNodeID)
+	assert.ErrorIs(t, err, ErrDuplicateEdgeID)
+	assert.ErrorIs(t, err, ErrEmptyEdgeID)
+	assert.ErrorIs(t, err, ErrEdgeToUnknownNode)
+	assert.ErrorIs(t, err, ErrInvalidEdgeConnection)
+	assert.ErrorIs(t, err, ErrDuplicateEdgePriority)
+	assert.Contains(t, err.Error(), "undefined source node ghost")
+	assert.Contains(t, err.Error(), "undefined target node phantom")
+
+	// A valid workflow produces no aggregated error
+	assert.NoError(t, validateWorkflowStructureAll(
+		[]models.Node{nodes[0], nodes[1], nodes[3]},
+		[]models.Edge{edges[0], {ID: "e2", Source: "form", Target: "end"}},
+	))
+}
+
+func TestExecuteMinimalWorkflow(t *testing.T) {
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 25,
+	}
+
+	t.Run("start connected to end", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		workflow := &models.Workflow{
+			ID:    "minimal-workflow",
+			Name:  "Minimal Workflow",
+			Nodes: []models.Node{{ID: "start", Type: models.NodeTypeStart}, {ID: "end", Type: models.NodeTypeEnd}},
+			Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+		}
+		mockStoredWorkflow(mockRepo, workflow)
+
+		result, err := newTestService(mockRepo).ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		if assert.NotNil(t, result) {
+			assert.Equal(t, models.StatusCompleted, result.Status)
+			if assert.Len(t, result.Steps, 2) {
+				assert.Equal(t, "start", result.Steps[0].NodeID)
+				assert.Equal(t, "end", result.Steps[1].NodeID)
+			}
+		}
+	})
+
+	t.Run("start not connected to end", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		workflow := &models.Workflow{
+			ID:    "unconnected-workflow",
+			Name:  "Unconnected Workflow",
+			Nodes: []models.Node{{ID: "start", Type: models.NodeTypeStart}, {ID: "end", Type: models.NodeTypeEnd}},
+		}
+		mockStoredWorkflow(mockRepo, workflow)
+
+		result, err := newTestService(mockRepo).ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrInvalidWorkflowStructure)
+		assert.ErrorIs(t, err, ErrStartNotConnectedToEnd)
+		mockRepo.AssertNotCalled(t, "CreateExecution", mock.Anything, mock.Anything)
+	})
+}
+
+func TestValidateWorkflowReportsAllStructureErrors(t *testing.T) {
+	mockRepo := new(MockWorkflowRepository)
+	workflow := &models.Workflow{
+		ID:   "broken-workflow",
+		Name: "Broken Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "missing"},
+			{ID: "e1", Source: "nowhere", Target: "end"},
+		},
+	}
+	mockStoredWorkflow(mockRepo, workflow)
+
+	result, err := newTestService(mockRepo).ValidateWorkflow(context.Background(), workflow.ID, false)
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Len(t, result.Errors, 3)
+}
+
+func TestCreateWorkflowReportsAllStructureErrors(t *testing.T) {
+	mockRepo := new(MockWorkflowRepository)
+	workflow := &models.Workflow{
+		ID:   "broken-workflow",
+		Name: "Broken Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "", Source: "start", Target: "end"},
+			{ID: "e2", Source: "start", Target: "missing"},
+		},
+	}
+
+	err := newTestService(mockRepo).CreateWorkflow(context.Background(), workflow)
+	assert.ErrorIs(t, err, ErrEmptyEdgeID)
+	assert.ErrorIs(t, err, ErrEdgeToUnknownNode)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// newTestService creates a real service backed by the mock repository and an engine
+// that knows the start, form, and end node types
+func newTestService(mockRepo *MockWorkflowRepository) WorkflowService {
+	registry := node.NewRegistry()
+	registry.Register(models.NodeTypeStart, start.NewNode)
+	registry.Register(models.NodeTypeForm, form.NewNode)
+	registry.Register(models.NodeTypeEnd, end.NewNode)
+
+	mockExecutionStorage(mockRepo)
+
+	service := NewWorkflowService(mockRepo)
+	service.SetEngine(execution.NewEngine(registry))
+	return service
+}
+
+// mockExecutionStorage accepts whatever the service stores while running a workflow.
+// Expectations registered before it take precedence.
+func mockExecutionStorage(mockRepo *MockWorkflowRepository) {
+	mockRepo.On("CreateExecution", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.On("CreateExecutionStep", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.On("CreateExecutionSteps", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.On("UpdateExecution", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+// mockStoredWorkflow registers the mock calls needed for GetWorkflow to return wf
+func mockStoredWorkflow(mockRepo *MockWorkflowRepository, wf *models.Workflow) {
+	mockRepo.On("Get", mock.Anything, wf.ID).Return(wf, nil)
+	mockRepo.On("GetNodes", mock.Anything, wf.ID).Return(wf.Nodes, nil)
+	mockRepo.On("GetEdges", mock.Anything, wf.ID).Return(wf.Edges, nil)
+}
+
+func TestExecuteWorkflowDefaultInput(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "default-input-workflow",
+		Name: "Default Input Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end"},
+		},
+		DefaultInput: &models.WorkflowInput{
+			Name:      "Cron",
+			Email:     "cron@example.com",
+			City:      "Sydney",
+			Threshold: 10,
+			Operator:  models.OperatorLessThan,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCity string
+		expectedErr  error
+	}{
+		{
+			name:         "empty input uses defaults",
+			body:         `{}`,
+			expectedCity: "Sydney",
+		},
+		{
+			name:         "request input takes precedence",
+			body:         `{"city":"Perth"}`,
+			expectedCity: "Perth",
+		},
+		{
+			name:        "merged input is still validated",
+			body:        `{"email":"not-an-email"}`,
+			expectedErr: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWorkflowRepository)
+			mockStoredWorkflow(mockRepo, workflow)
+			service := newTestService(mockRepo)
+
+			var input models.WorkflowInput
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &input))
+
+			execution, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, execution.Status)
+			assert.Equal(t, "Cron", execution.Metadata["triggeredBy"])
+
+			// The form step should reflect the merged input
+			formStep := execution.Steps[1]
+			assert.Equal(t, models.NodeTypeForm, formStep.NodeType)
+			assert.Equal(t, tt.expectedCity, formStep.Output["city"])
+		})
+	}
+}
+
+func TestExecuteWorkflowWithoutDefaultInput(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "no-default-workflow",
+		Name: "No Default Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "end"},
+		},
+	}
+
+	mockRepo := new(MockWorkflowRepository)
+	mockStoredWorkflow(mockRepo, workflow)
+	service := newTestService(mockRepo)
+
+	_, err := service.ExecuteWorkflow(context.Background(), workflow.ID, models.WorkflowInput{})
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+	assert.Contains(t, err.Error(), "name is required")
+}
+
+func TestExecuteWorkflowPersistsExecution(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "persisted-workflow",
+		Name: "Persisted Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	t.Run("execution is saved as it runs", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		mockRepo.On("CreateExecution", mock.Anything, mock.MatchedBy(func(e *models.WorkflowExecution) bool {
+			return e.WorkflowID == workflow.ID && e.Status == models.StatusRunning && len(e.Steps) == 0 && e.InputHash == input.Hash()
+		})).Return(nil).Once()
+		var stored []models.ExecutionStep
+		mockRepo.On("CreateExecutionStep", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = append(stored, *args.Get(1).(*models.ExecutionStep))
+		}).Return(nil)
+		mockRepo.On("UpdateExecution", mock.Anything, mock.MatchedBy(func(e *models.WorkflowExecution) bool {
+			return e.Status == models.StatusCompleted && e.EndedAt != nil
+		})).Return(nil).Once()
+		service := newTestService(mockRepo)
+
+		execution, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		assert.NotNil(t, execution)
+		mockRepo.AssertNumberOfCalls(t, "CreateExecution", 1)
+		mockRepo.AssertNumberOfCalls(t, "UpdateExecution", 1)
+		mockRepo.AssertNotCalled(t, "CreateExecutionSteps", mock.Anything, mock.Anything)
+		if assert.Len(t, stored, 2) {
+			assert.Equal(t, "start", stored[0].NodeID)
+			assert.Equal(t, "end", stored[1].NodeID)
+			assert.Equal(t, execution.ID, stored[1].ExecutionID)
+		}
+	})
+
+	t.Run("steps that couldn't be stored are stored when the run finishes", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		mockRepo.On("CreateExecutionStep", mock.Anything, mock.Anything).Return(fmt.Errorf("database unavailable")).Once()
+		mockRepo.On("CreateExecutionSteps", mock.Anything, mock.MatchedBy(func(steps []models.ExecutionStep) bool {
+			return len(steps) == 2 && steps[0].NodeID == "start" && steps[1].NodeID == "end" && steps[0].ExecutionID != ""
+		})).Return(nil).Once()
+		service := newTestService(mockRepo)
+
+		_, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		// Steps stay in order, so nothing is stored after the first failure until the end
+		mockRepo.AssertNumberOfCalls(t, "CreateExecutionStep", 1)
+		mockRepo.AssertNumberOfCalls(t, "CreateExecutionSteps", 1)
+		mockRepo.AssertNumberOfCalls(t, "UpdateExecution", 1)
+	})
+
+	t.Run("queueing and running timestamps are ordered", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		mockRepo.On("CreateExecution", mock.Anything, mock.Anything).Return(nil).Once()
+		service := newTestService(mockRepo)
+
+		before := time.Now()
+		execution, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		assert.NotNil(t, execution.EnqueuedAt)
+		assert.NotNil(t, execution.StartedAt)
+		assert.NotNil(t, execution.EndedAt)
+		assert.False(t, execution.EnqueuedAt.Before(before))
+		assert.False(t, execution.StartedAt.Before(*execution.EnqueuedAt), "enqueuedAt must not be after startedAt")
+		assert.False(t, execution.EndedAt.Before(*execution.StartedAt), "startedAt must not be after endedAt")
+	})
+
+	t.Run("input is stored with the email masked", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		mockRepo.On("CreateExecution", mock.Anything, mock.Anything).Return(nil).Once()
+		service := newTestService(mockRepo)
+
+		execution, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.WorkflowInput{
+			Name:      "Test User",
+			Email:     "t***@example.com",
+			City:      "Sydney",
+			Operator:  models.OperatorGreaterThan,
+			Threshold: 20,
+		}, execution.Input)
+		mockRepo.AssertCalled(t, "CreateExecution", mock.Anything, execution)
+	})
+
+	t.Run("storage failure does not fail the run", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		mockRepo.On("CreateExecution", mock.Anything, mock.Anything).Return(fmt.Errorf("database unavailable")).Twice()
+		service := newTestService(mockRepo)
+
+		execution, err := service.ExecuteWorkflow(context.Background(), workflow.ID, input)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		// The running execution couldn't be stored, so the whole run is stored at the end
+		mockRepo.AssertNumberOfCalls(t, "CreateExecution", 2)
+		mockRepo.AssertNotCalled(t, "CreateExecutionStep", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "UpdateExecution", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListExecutions(t *testing.T) {
+	workflow := &models.Workflow{ID: "list-workflow"}
+	page := &repository.ExecutionPage{
+		Executions: []models.WorkflowExecution{{ID: "exec-2"}, {ID: "exec-1"}},
+		NextCursor: "next",
+	}
+
+	tests := []struct {
+		name        string
+		opts        repository.ListExecutionsOptions
+		setup       func(mockRepo *MockWorkflowRepository)
+		expectedErr error
+	}{
+		{
+			name: "returns repository page",
+			setup: func(mockRepo *MockWorkflowRepository) {
+				mockRepo.On("Get", mock.Anything, workflow.ID).Return(workflow, nil)
+				mockRepo.On("ListExecutions", mock.Anything, workflow.ID, mock.Anything).Return(page, nil)
+			},
+		},
+		{
+			name: "unknown workflow",
+			setup: func(mockRepo *MockWorkflowRepository) {
+				mockRepo.On("Get", mock.Anything, workflow.ID).Return(nil, repository.ErrWorkflowNotFound)
+			},
+			expectedErr: ErrWorkflowNotFound,
+		},
+		{
+			name: "invalid cursor",
+			setup: func(mockRepo *MockWorkflowRepository) {
+				mockRepo.On("Get", mock.Anything, workflow.ID).Return(workflow, nil)
+				mockRepo.On("ListExecutions", mock.Anything, workflow.ID, mock.Anything).Return(nil, repository.ErrInvalidCursor)
+			},
+			expectedErr: ErrInvalidCursor,
+		},		{
+			name: "malformed input hash",
+			opts: repository.ListExecutionsOptions{InputHash: "not-a-hash"},
+			setup: func(mockRepo *MockWorkflowRepository) {
+				mockRepo.On("Get", mock.Anything, workflow.ID).Return(workflow, nil)
+			},
+			expectedErr: ErrInvalidExecutionFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWorkflowRepository)
+			tt.setup(mockRepo)
+			service := NewWorkflowService(mockRepo)
+
+			result, err := service.ListExecutions(context.Background(), workflow.ID, tt.opts)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, page, result)
+		})
+	}
+}
+
+func TestGetExecutionStats(t *testing.T) {
+	workflow := &models.Workflow{ID: "stats-workflow"}
+	stats := &repository.ExecutionStats{Window: 10, Executions: 3, Completed: 2, Failed: 1, AlertsSent: 1}
+
+	t.Run("returns repository stats", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("Get", mock.Anything, workflow.ID).Return(workflow, nil)
+		mockRepo.On("GetExecutionStats", mock.Anything, workflow.ID, 10).Return(stats, nil)
+		service := NewWorkflowService(mockRepo)
+
+		result, err := service.GetExecutionStats(context.Background(), workflow.ID, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, stats, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown workflow", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("Get", mock.Anything, workflow.ID).Return(nil, repository.ErrWorkflowNotFound)
+		service := NewWorkflowService(mockRepo)
+
+		_, err := service.GetExecutionStats(context.Background(), workflow.ID, 10)
+		assert.True(t, errors.Is(err, ErrWorkflowNotFound))
+		mockRepo.AssertNotCalled(t, "GetExecutionStats", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetExecution(t *testing.T) {
+	stored := &models.WorkflowExecution{ID: "exec-1", WorkflowID: "workflow-1", Status: models.StatusCompleted}
+	steps := []models.ExecutionStep{{ExecutionID: "exec-1", StepNumber: 1, NodeType: models.NodeTypeStart}}
+
+	t.Run("includes steps", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("GetExecution", mock.Anything, "exec-1").Return(stored, nil)
+		mockRepo.On("GetExecutionSteps", mock.Anything, "exec-1").Return(steps, nil)
+		service := NewWorkflowService(mockRepo)
+
+		execution, err := service.GetExecution(context.Background(), "workflow-1", "exec-1")
+		assert.NoError(t, err)
+		assert.Equal(t, steps, execution.Steps)
+	})
+
+	t.Run("belongs to another workflow", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("GetExecution", mock.Anything, "exec-1").Return(stored, nil)
+		service := NewWorkflowService(mockRepo)
+
+		_, err := service.GetExecution(context.Background(), "workflow-2", "exec-1")
+		assert.True(t, errors.Is(err, ErrExecutionNotFound))
+	})
+}
+
+func TestGetLatestExecution(t *testing.T) {
+	latest := &models.WorkflowExecution{ID: "exec-2", WorkflowID: "workflow-1", Status: models.StatusCompleted}
+	steps := []models.ExecutionStep{{ExecutionID: "exec-2", StepNumber: 1, NodeType: models.NodeTypeStart}}
+
+	t.Run("includes steps", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("GetLatestExecution", mock.Anything, "workflow-1").Return(latest, nil)
+		mockRepo.On("GetExecutionSteps", mock.Anything, "exec-2").Return(steps, nil)
+		service := NewWorkflowService(mockRepo)
+
+		execution, err := service.GetLatestExecution(context.Background(), "workflow-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "exec-2", execution.ID)
+		assert.Equal(t, steps, execution.Steps)
+	})
+
+	t.Run("never run", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockRepo.On("GetLatestExecution", mock.Anything, "workflow-1").Return(nil, repository.ErrExecutionNotFound)
+		service := NewWorkflowService(mockRepo)
+
+		_, err := service.GetLatestExecution(context.Background(), "workflow-1")
+		assert.True(t, errors.Is(err, ErrExecutionNotFound))
+		mockRepo.AssertNotCalled(t, "GetExecutionSteps", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSearchExecutions(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	page := &repository.ExecutionPage{
+		Executions: []models.WorkflowExecution{{ID: "exec-1", Status: models.StatusFailed}},
+	}
+
+	tests := []struct {
+		name        string
+		opts        repository.SearchExecutionsOptions
+		setup       func(mockRepo *MockWorkflowRepository)
+		expectedErr error
+	}{
+		{
+			name: "combined filters are passed to the repository",
+			opts: repository.SearchExecutionsOptions{
+				Status:     models.StatusFailed,
+				WorkflowID: "550e8400-e29b-41d4-a716-446655440000",
+				From:       &from,
+				To:         &to,
+				Limit:      10,
+			},
+			setup: func(mockRepo *MockWorkflowRepository) {
+				mockRepo.On("SearchExecutions", mock.Anything, repository.SearchExecutionsOptions{
+					Status:     models.StatusFailed,
+					WorkflowID: "550e8400-e29b-41d4-a716-446655440000",
+					From:       &from,
+					To:         &to,
+					Limit:      10,
+				}).Return(page, nil)
+			},
+		},
+		{
+			name: "partial status",
+			opts: repository.SearchExecutionsOptions{Status: models.StatusPartial},
+			setup: func(mockRepo *MockWorkflowRepository) {
+				mockRepo.On("SearchExecutions", mock.Anything, repository.SearchExecutionsOptions{Status: models.StatusPartial}).Return(page, nil)
+			},
+		},
+		{
+			name:        "unknown status",
+			opts:        repository.SearchExecutionsOptions{Status: "exploded"},
+			expectedErr: ErrInvalidExecutionFilter,
+		},
+		{
+			name:        "workflow ID is not a UUID",
+			opts:        repository.SearchExecutionsOptions{WorkflowID: "weather"},
+			expectedErr: ErrInvalidExecutionFilter,
+		},
+		{
+			name:        "empty date range",
+			opts:        repository.SearchExecutionsOptions{From: &to, To: &from},
+			expectedErr: ErrInvalidExecutionFilter,
+		},
+		{
+			name: "invalid cursor",
+			opts: repository.SearchExecutionsOptions{Cursor: "bad"},
+			setup: func(mockRepo *MockWorkflowRepository) {
+				mockRepo.On("SearchExecutions", mock.Anything, mock.Anything).Return(nil, repository.ErrInvalidCursor)
+			},
+			expectedErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWorkflowRepository)
+			if tt.setup != nil {
+				tt.setup(mockRepo)
+			}
+			service := NewWorkflowService(mockRepo)
+
+			result, err := service.SearchExecutions(context.Background(), tt.opts)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, page, result)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListWorkflows(t *testing.T) {
+	tagged := []models.Workflow{{ID: "wf-1", Name: "Sydney Alerts", Tags: map[string]string{"team": "weather", "env": "prod"}}}
+
+	t.Run("tags are passed to the repository", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		tags := map[string]string{"team": "weather", "env": "prod"}
+		mockRepo.On("List", mock.Anything, tags).Return(tagged, nil)
+
+		workflows, err := NewWorkflowService(mockRepo).ListWorkflows(context.Background(), tags)
+		assert.NoError(t, err)
+		assert.Equal(t, tagged, workflows)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid tag key", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		_, err := NewWorkflowService(mockRepo).ListWorkflows(context.Background(), map[string]string{"": "weather"})
+		assert.ErrorIs(t, err, ErrInvalidTag)
+		mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateWorkflowRejectsInvalidTags(t *testing.T) {
+	mockRepo := new(MockWorkflowRepository)
+	workflow := &models.Workflow{
+		ID:   "tagged-workflow",
+		Name: "Tagged Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+		Tags:  map[string]string{"team:weather": "yes"},
+	}
+
+	err := NewWorkflowService(mockRepo).CreateWorkflow(context.Background(), workflow)
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateWorkflowRejectsInvalidHooks(t *testing.T) {
+	mockRepo := new(MockWorkflowRepository)
+	workflow := &models.Workflow{
+		ID:   "hooked-workflow",
+		Name: "Hooked Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+		Hooks: []models.ExecutionHook{{Stage: models.HookStagePost, Type: models.HookTypeWebhook}},
+	}
+
+	err := NewWorkflowService(mockRepo).CreateWorkflow(context.Background(), workflow)
+	assert.ErrorIs(t, err, ErrInvalidHook)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateWorkflowRejectsNonPositiveMaxDuration(t *testing.T) {
+	for _, maxDurationMs := range []int64{0, -500} {
+		mockRepo := new(MockWorkflowRepository)
+		workflow := &models.Workflow{
+			ID:   "sla-workflow",
+			Name: "SLA Workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges:         []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+			MaxDurationMs: &maxDurationMs,
+		}
+
+		err := NewWorkflowService(mockRepo).CreateWorkflow(context.Background(), workflow)
+		assert.ErrorIs(t, err, ErrInvalidMaxDuration)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	}
+}
+
+func TestCreateWorkflowRejectsInvalidEdgeStyles(t *testing.T) {
+	tests := []struct {
+		name  string
+		style models.EdgeStyle
+	}{
+		{"negative width", models.EdgeStyle{Stroke: "#10b981", StrokeWidth: -2}},
+		{"invalid color", models.EdgeStyle{Stroke: "not-a-color", StrokeWidth: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWorkflowRepository)
+			workflow := &models.Workflow{
+				ID:   "styled-workflow",
+				Name: "Styled Workflow",
+				Nodes: []models.Node{
+					{ID: "start", Type: models.NodeTypeStart},
+					{ID: "end", Type: models.NodeTypeEnd},
+				},
+				Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end", Style: tt.style}},
+			}
+
+			err := NewWorkflowService(mockRepo).CreateWorkflow(context.Background(), workflow)
+			assert.ErrorIs(t, err, ErrInvalidEdgeStyle)
+			assert.Contains(t, err.Error(), "edge e1")
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+			err = NewWorkflowService(mockRepo).UpdateWorkflow(context.Background(), workflow)
+			assert.ErrorIs(t, err, ErrInvalidEdgeStyle)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+			// Imports go through the same checks and are reported as structure problems
+			_, err = NewWorkflowService(mockRepo).ImportWorkflow(context.Background(), workflow, false)
+			assert.ErrorIs(t, err, ErrInvalidEdgeStyle)
+			assert.ErrorIs(t, err, ErrInvalidWorkflowStructure)
+
+			// So does batch validation
+			results, err := NewWorkflowService(mockRepo).ValidateWorkflows(context.Background(), []*models.Workflow{workflow}, false)
+			assert.NoError(t, err)
+			assert.False(t, results[0].Valid)
+			assert.Len(t, results[0].Errors, 1)
+			assert.Contains(t, results[0].Errors[0], "edge e1")
+		})
+	}
+}
+
+func TestCreateWorkflowDefaultsEdgeStyles(t *testing.T) {
+	mockRepo := new(MockWorkflowRepository)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	workflow := &models.Workflow{
+		ID:   "styled-workflow",
+		Name: "Styled Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end", Style: models.EdgeStyle{Stroke: "#ef4444", StrokeWidth: 3}},
+		},
+	}
+
+	err := NewWorkflowService(mockRepo).CreateWorkflow(context.Background(), workflow)
+	assert.NoError(t, err)
+	assert.Equal(t, models.EdgeStyle{Stroke: models.DefaultEdgeStroke, StrokeWidth: models.DefaultEdgeStrokeWidth}, workflow.Edges[0].Style)
+	assert.Equal(t, models.EdgeStyle{Stroke: "#ef4444", StrokeWidth: 3}, workflow.Edges[1].Style)
+	mockRepo.AssertCalled(t, "Create", mock.Anything, workflow)
+}
+
+func TestExecuteWorkflowInOrder(t *testing.T) {
+	workflow := &models.Workflow{
+		ID:   "ordered-workflow",
+		Name: "Ordered Workflow",
+		Nodes: []models.Node{
+			{ID: "start", Type: models.NodeTypeStart},
+			{ID: "form", Type: models.NodeTypeForm},
+			{ID: "end", Type: models.NodeTypeEnd},
+		},
+		Edges: []models.Edge{
+			{ID: "e1", Source: "start", Target: "form"},
+			{ID: "e2", Source: "form", Target: "end"},
+		},
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	t.Run("runs the requested nodes without storing the run", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+		service := newTestService(mockRepo)
+
+		execution, err := service.ExecuteWorkflowInOrder(context.Background(), workflow.ID, input, []string{"start", "end"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		assert.Len(t, execution.Steps, 2)
+		mockRepo.AssertNotCalled(t, "CreateExecution", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		for _, order := range [][]string{nil, {"start", "ghost"}} {
+			mockRepo := new(MockWorkflowRepository)
+			mockStoredWorkflow(mockRepo, workflow)
+
+			_, err := newTestService(mockRepo).ExecuteWorkflowInOrder(context.Background(), workflow.ID, input, order)
+			assert.ErrorIs(t, err, ErrInvalidExecutionOrder)
+		}
+	})
+
+	t.Run("input is still validated", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		mockStoredWorkflow(mockRepo, workflow)
+
+		_, err := newTestService(mockRepo).ExecuteWorkflowInOrder(context.Background(), workflow.ID, models.WorkflowInput{}, []string{"start"})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestExecuteAdhocWorkflow(t *testing.T) {
+	newDefinition := func() *models.Workflow {
+		return &models.Workflow{
+			Name: "Ad-hoc Workflow",
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "form", Type: models.NodeTypeForm},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{
+				{ID: "e1", Source: "start", Target: "form"},
+				{ID: "e2", Source: "form", Target: "end"},
+			},
+		}
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	t.Run("runs in memory without touching the repository", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		service := newTestService(mockRepo)
+
+		execution, err := service.ExecuteAdhocWorkflow(context.Background(), newDefinition(), input)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		assert.Len(t, execution.Steps, 3)
+		mockRepo.AssertNotCalled(t, "CreateExecution", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("state nodes don't write to the store", func(t *testing.T) {
+		mockRepo := new(MockWorkflowRepository)
+		registry := node.NewRegistry()
+		registry.Register(models.NodeTypeStart, start.NewNode)
+		registry.Register(models.NodeTypeState, state.NewNodeFactory(mockRepo))
+		registry.Register(models.NodeTypeEnd, end.NewNode)
+		service := NewWorkflowService(mockRepo)
+		service.SetEngine(execution.NewEngine(registry))
+
+		definition := newDefinition()
+		definition.ID = "stored-workflow"
+		definition.Nodes[1] = models.Node{ID: "remember", Type: models.NodeTypeState, Data: models.NodeData{
+			Metadata: map[string]any{"operation": "set", "key": "lastCity", "value": "Sydney"},
+		}}
+		definition.Edges[0].Target = "remember"
+		definition.Edges[1].Source = "remember"
+
+		execution, err := service.ExecuteAdhocWorkflow(context.Background(), definition, input)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		mockRepo.AssertNotCalled(t, "SetState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("definition must pass stored workflow validation", func(t *testing.T) {
+		service := newTestService(new(MockWorkflowRepository))
+
+		unnamed := newDefinition()
+		unnamed.Name = ""
+		_, err := service.ExecuteAdhocWorkflow(context.Background(), unnamed, input)
+		assert.ErrorIs(t, err, ErrInvalidWorkflowStructure)
+
+		noEnd := newDefinition()
+		noEnd.Nodes = noEnd.Nodes[:2]
+		noEnd.Edges = noEnd.Edges[:1]
+		_, err = service.ExecuteAdhocWorkflow(context.Background(), noEnd, input)
+		assert.ErrorIs(t, err, ErrInvalidWorkflowStructure)
+
+		_, err = service.ExecuteAdhocWorkflow(context.Background(), nil, input)
+		assert.ErrorIs(t, err, ErrInvalidWorkflowStructure)
+	})
+
+	t.Run("default input fills missing fields", func(t *testing.T) {
+		service := newTestService(new(MockWorkflowRepository))
+
+		definition := newDefinition()
+		definition.DefaultInput = &input
+		execution, err := service.ExecuteAdhocWorkflow(context.Background(), definition, models.WorkflowInput{})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+
+		_, err = service.ExecuteAdhocWorkflow(context.Background(), newDefinition(), models.WorkflowInput{})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestExecuteWorkflowRejectsMalformedEmbeddedNodes(t *testing.T) {
+	embedded := func(integrationNode map[string]any) models.JSONB {
+		return models.JSONB{
+			"id":   "embedded-workflow",
+			"name": "Embedded Workflow",
+			"nodes": []any{
+				map[string]any{"id": "start", "type": "start"},
+				integrationNode,
+				map[string]any{"id": "end", "type": "end"},
+			},
+			"edges": []any{
+				map[string]any{"id": "e1", "source": "start", "target": "weather-api"},
+				map[string]any{"id": "e2", "source": "weather-api", "target": "end"},
+			},
+		}
+	}
+	input := models.WorkflowInput{
+		Name:      "Test User",
+		Email:     "test@example.com",
+		City:      "Sydney",
+		Operator:  models.OperatorGreaterThan,
+		Threshold: 20,
+	}
+
+	tests := []struct {
+		name          string
+		node          map[string]any
+		expectedError string
+	}{
+		{
+			name:          "missing metadata",
+			node:          map[string]any{"id": "weather-api", "type": "integration"},
+			expectedError: "node weather-api",
+		},
+		{
+			name: "missing API endpoint",
+			node: map[string]any{"id": "weather-api", "type": "integration", "data": map[string]any{
+				"metadata": map[string]any{"options": []any{}},
+			}},
+			expectedError: "missing API endpoint",
+		},
+		{
+			name: "no location options",
+			node: map[string]any{"id": "weather-api", "type": "integration", "data": map[string]any{
+				"metadata": map[string]any{"apiEndpoint": "https://api.open-meteo.com/v1/forecast"},
+			}},
+			expectedError: "no location options configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := node.NewRegistry()
+			registry.Register(models.NodeTypeStart, start.NewNode)
+			registry.Register(models.NodeTypeIntegration, integration.NewNode)
+			registry.Register(models.NodeTypeEnd, end.NewNode)
+
+			mockRepo := new(MockWorkflowRepository)
+			service := NewWorkflowService(mockRepo)
+			service.SetEngine(execution.NewEngine(registry))
+
+			input := input
+			input.Workflow = embedded(tt.node)
+			_, err := service.ExecuteWorkflow(context.Background(), "embedded-workflow", input)
+
+			assert.ErrorIs(t, err, ErrInvalidWorkflowStructure)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWorkflowsEqual(t *testing.T) {
+	newWorkflow := func() *models.Workflow {
+		return &models.Workflow{
+			ID:   "equal-workflow",
+			Name: "Equal Workflow",
+			Tags: map[string]string{"team": "ops"},
+			Nodes: []models.Node{
+				{ID: "start", Type: models.NodeTypeStart},
+				{ID: "end", Type: models.NodeTypeEnd},
+			},
+			Edges: []models.Edge{{ID: "e1", Source: "start", Target: "end"}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(wf *models.Workflow)
+		equal  bool
+	}{
+		{"identical", func(wf *models.Workflow) {}, true},
+		{"tag value changed", func(wf *models.Workflow) { wf.Tags["team"] = "dev" }, false},
+		{"tag added", func(wf *models.Workflow) { wf.Tags["env"] = "prod" }, false},
+		{"tags removed", func(wf *models.Workflow) { wf.Tags = nil }, false},
+		{"edge priority changed", func(wf *models.Workflow) { wf.Edges[0].Priority = 1 }, false},
+		{"edge stroke changed", func(wf *models.Workflow) { wf.Edges[0].Style.Stroke = "#ff0000" }, false},
+		{"edge stroke width changed", func(wf *models.Workflow) { wf.Edges[0].Style.StrokeWidth = 4 }, false},
+		{"edge style defaults given", func(wf *models.Workflow) {
+			wf.Edges[0].Style = models.EdgeStyle{Stroke: models.DefaultEdgeStroke, StrokeWidth: models.DefaultEdgeStrokeWidth}
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := newWorkflow()
+			tt.change(changed)
+			assert.Equal(t, tt.equal, workflowsEqual(newWorkflow(), changed))
+		})
+	}
+}
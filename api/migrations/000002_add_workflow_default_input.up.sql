@@ -0,0 +1,4 @@
+SET search_path TO public;
+
+-- Default execution input used to fill fields missing from execute requests
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS default_input JSONB;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_execution_steps_execution_id;
+DROP INDEX IF EXISTS idx_workflow_executions_workflow_id_executed_at;
+
+DROP TABLE IF EXISTS execution_steps;
+DROP TABLE IF EXISTS workflow_executions;
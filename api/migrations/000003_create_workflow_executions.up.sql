@@ -0,0 +1,35 @@
+SET search_path TO public;
+
+-- Create workflow_executions table
+CREATE TABLE IF NOT EXISTS workflow_executions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    start_time VARCHAR(64) NOT NULL DEFAULT '',
+    end_time VARCHAR(64) NOT NULL DEFAULT '',
+    total_duration BIGINT NOT NULL DEFAULT 0,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    executed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create execution_steps table
+CREATE TABLE IF NOT EXISTS execution_steps (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    execution_id UUID NOT NULL REFERENCES workflow_executions(id) ON DELETE CASCADE,
+    node_id VARCHAR(50) NOT NULL,
+    step_number INTEGER NOT NULL,
+    node_type VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    duration BIGINT NOT NULL DEFAULT 0,
+    output JSONB NOT NULL DEFAULT '{}',
+    timestamp VARCHAR(64) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    UNIQUE(execution_id, step_number)
+);
+
+-- Keyset pagination walks executions newest first using (executed_at, id)
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_workflow_id_executed_at
+    ON workflow_executions(workflow_id, executed_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_execution_steps_execution_id ON execution_steps(execution_id);
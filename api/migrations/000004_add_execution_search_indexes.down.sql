@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_workflow_executions_status_executed_at;
+DROP INDEX IF EXISTS idx_workflow_executions_executed_at;
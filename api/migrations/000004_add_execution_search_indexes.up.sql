@@ -0,0 +1,7 @@
+SET search_path TO public;
+
+-- Global execution search filters by status and/or date range, newest first
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_executed_at
+    ON workflow_executions(executed_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_status_executed_at
+    ON workflow_executions(status, executed_at DESC, id DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_workflows_tags;
+
+ALTER TABLE workflows DROP COLUMN IF EXISTS tags;
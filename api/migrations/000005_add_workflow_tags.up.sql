@@ -0,0 +1,7 @@
+SET search_path TO public;
+
+-- Key-value tags for organizing workflows, e.g. {"team": "weather"}
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '{}';
+
+-- Tag filters use JSONB containment (tags @> '{"team": "weather"}')
+CREATE INDEX IF NOT EXISTS idx_workflows_tags ON workflows USING GIN (tags jsonb_path_ops);
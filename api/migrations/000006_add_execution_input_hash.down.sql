@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_workflow_executions_input_hash;
+
+ALTER TABLE workflow_executions DROP COLUMN IF EXISTS input_hash;
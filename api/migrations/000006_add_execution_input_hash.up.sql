@@ -0,0 +1,9 @@
+SET search_path TO public;
+
+-- SHA-256 of the normalized triggering input, for spotting duplicate triggers.
+-- Executions recorded before this column existed have no hash.
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS input_hash CHAR(64);
+
+-- Supports listing a workflow's executions for one input, newest first
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_input_hash
+    ON workflow_executions (workflow_id, input_hash, executed_at DESC, id DESC);
@@ -0,0 +1,5 @@
+SET search_path TO public;
+
+-- Webhook trigger settings: the HMAC secret and the payload to input mapping.
+-- Workflows without a webhook can't be triggered by one.
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS webhook JSONB;
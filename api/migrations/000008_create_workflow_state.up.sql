@@ -0,0 +1,10 @@
+SET search_path TO public;
+
+-- Values state nodes keep between executions, scoped to their workflow
+CREATE TABLE IF NOT EXISTS workflow_state (
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,
+    value JSONB,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workflow_id, key)
+);
@@ -0,0 +1,4 @@
+SET search_path TO public;
+
+-- Pre- and post-execution notifications, e.g. [{"stage": "post", "type": "webhook", "url": "..."}]
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS hooks JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+ALTER TABLE workflow_executions DROP COLUMN IF EXISTS enqueued_at;
+ALTER TABLE workflow_executions DROP COLUMN IF EXISTS started_at;
+ALTER TABLE workflow_executions DROP COLUMN IF EXISTS ended_at;
@@ -0,0 +1,7 @@
+SET search_path TO public;
+
+-- When the request was accepted, the run started and the run finished, so queue
+-- latency can be measured apart from execution time. Older executions have none.
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS enqueued_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS started_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS ended_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1 @@
+ALTER TABLE workflow_edges DROP COLUMN IF EXISTS priority;
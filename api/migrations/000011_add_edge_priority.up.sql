@@ -0,0 +1,5 @@
+SET search_path TO public;
+
+-- Orders a node's unlabelled edges: the engine follows the highest-priority one
+-- whose target exists. Existing edges keep priority 0.
+ALTER TABLE workflow_edges ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
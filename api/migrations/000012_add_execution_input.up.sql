@@ -0,0 +1,6 @@
+SET search_path TO public;
+
+-- The input that triggered each run, with defaults applied and the email masked,
+-- for display and replay. Embedded workflow definitions are not kept. Older
+-- executions have none.
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS input JSONB;
@@ -0,0 +1,5 @@
+SET search_path TO public;
+
+-- Why a failed run failed: the node it failed at and the error, so triage doesn't
+-- need the steps. Null for runs that didn't fail and for older executions.
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS error_summary JSONB;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_workflow_executions_sla_breached;
+
+ALTER TABLE workflows DROP COLUMN IF EXISTS max_duration_ms;
@@ -0,0 +1,10 @@
+SET search_path TO public;
+
+-- Expected longest run in milliseconds; runs taking longer are flagged slaBreached
+-- in their metadata. Null when the workflow declares no limit.
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS max_duration_ms BIGINT;
+
+-- Supports listing a workflow's slow runs, newest first
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_sla_breached
+    ON workflow_executions (workflow_id, executed_at DESC, id DESC)
+    WHERE metadata->>'slaBreached' = 'true';
@@ -0,0 +1 @@
+ALTER TABLE workflow_nodes ADD CONSTRAINT workflow_nodes_workflow_id_node_type_key UNIQUE (workflow_id, node_type);
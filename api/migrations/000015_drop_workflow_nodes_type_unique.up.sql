@@ -0,0 +1,5 @@
+SET search_path TO public;
+
+-- A workflow may hold several nodes of one type, such as chained conditions or an
+-- email node per fan-out branch. Node IDs are kept unique by workflow validation.
+ALTER TABLE workflow_nodes DROP CONSTRAINT IF EXISTS workflow_nodes_workflow_id_node_type_key;
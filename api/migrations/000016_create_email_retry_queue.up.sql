@@ -0,0 +1,14 @@
+SET search_path TO public;
+
+-- Emails whose delivery failed transiently, kept so the retry queue picks them up
+-- again after a restart. Rows are removed once sent or dropped.
+CREATE TABLE IF NOT EXISTS email_retry_queue (
+    id UUID PRIMARY KEY,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    attachments JSONB NOT NULL DEFAULT '[]',
+    attempts INTEGER NOT NULL,
+    next_attempt TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"unicode"
+
+	mail "gopkg.in/gomail.v2"
+)
+
+// FromAddress is the address emails are sent from
+const FromAddress = "weather-alerts@checkbox.com"
+
+// ErrInvalidFromName is returned for a display name that can't be sent with FromAddress
+var ErrInvalidFromName = errors.New("invalid from name")
+
+// fromName is the display name shown with FromAddress; empty sends the bare address
+var fromName string
+
+// SetFromName sets the display name emails are sent from, e.g. "Weather Alerts" for
+// "Weather Alerts" <weather-alerts@checkbox.com>. Surrounding whitespace is trimmed,
+// and an empty name sends the bare address. A name that doesn't form a valid address
+// with FromAddress is rejected, leaving the current one in place. Set it before any
+// email is sent.
+func SetFromName(name string) error {
+	name = strings.TrimSpace(name)
+	if name != "" {
+		if err := checkFromName(name); err != nil {
+			return err
+		}
+	}
+	fromName = name
+	return nil
+}
+
+// From returns the From header emails are sent with: FromAddress, with the display
+// name when one is set, formatted by gomail
+func From() string {
+	return mail.NewMessage().FormatAddress(FromAddress, fromName)
+}
+
+// SetFrom sets m's From header to FromAddress and the display name, if any
+func SetFrom(m *mail.Message) {
+	m.SetAddressHeader("From", FromAddress, fromName)
+}
+
+// checkFromName makes sure name and FromAddress form an address that parses back to
+// the same name and address. Control characters, which could be used to forge
+// headers, are rejected outright.
+func checkFromName(name string) error {
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return fmt.Errorf("%w: %q contains control characters", ErrInvalidFromName, name)
+	}
+	formatted := mail.NewMessage().FormatAddress(FromAddress, name)
+	parsed, err := netmail.ParseAddress(formatted)
+	if err != nil {
+		return fmt.Errorf("%w: %q does not form a valid address: %v", ErrInvalidFromName, formatted, err)
+	}
+	if parsed.Name != name || parsed.Address != FromAddress {
+		return fmt.Errorf("%w: %q does not form a valid address", ErrInvalidFromName, formatted)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "gopkg.in/gomail.v2"
+)
+
+func TestFromHeader(t *testing.T) {
+	defer func(name string) { fromName = name }(fromName)
+	template := EmailTemplate{Subject: "Weather Alert", Body: "Weather alert for {{city}}"}
+
+	tests := []struct {
+		name     string
+		fromName string
+		expected string
+	}{
+		{"no name", "", "weather-alerts@checkbox.com"},
+		{"plain name", "Weather Alerts", `"Weather Alerts" <weather-alerts@checkbox.com>`},
+		{"surrounding whitespace", "  Weather Alerts ", `"Weather Alerts" <weather-alerts@checkbox.com>`},
+		{"quotes escaped", `Weather "Live" Alerts`, `"Weather \"Live\" Alerts" <weather-alerts@checkbox.com>`},
+		{"non-ASCII name encoded", "Météo Alerts", "=?UTF-8?q?M=C3=A9t=C3=A9o_Alerts?= <weather-alerts@checkbox.com>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, SetFromName(tt.fromName))
+			assert.Equal(t, tt.expected, From())
+
+			m := mail.NewMessage()
+			SetFrom(m)
+			assert.Equal(t, []string{tt.expected}, m.GetHeader("From"))
+			m.SetHeader("To", "john@example.com")
+			m.SetBody("text/plain", "body")
+			var written bytes.Buffer
+			_, err := m.WriteTo(&written)
+			require.NoError(t, err)
+			assert.Contains(t, written.String(), "From: "+tt.expected+"\r\n")
+
+			result, err := PrepareAndStubSendEmail("john@example.com", map[string]any{"city": "Sydney"}, template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result["from"])
+			if tt.fromName == "" {
+				assert.NotContains(t, result, "fromName")
+			} else {
+				assert.Equal(t, fromName, result["fromName"])
+			}
+		})
+	}
+}
+
+func TestSetFromNameRejectsInvalidNames(t *testing.T) {
+	defer func(name string) { fromName = name }(fromName)
+	require.NoError(t, SetFromName("Weather Alerts"))
+
+	for _, name := range []string{"Weather\r\nBcc: victim@example.com", "Weather\x00Alerts", "Weather\tAlerts"} {
+		err := SetFromName(name)
+		assert.ErrorIs(t, err, ErrInvalidFromName, name)
+	}
+	// A rejected name leaves the current one in place
+	assert.Equal(t, `"Weather Alerts" <weather-alerts@checkbox.com>`, From())
+}
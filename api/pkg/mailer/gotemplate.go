@@ -0,0 +1,203 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Template engines an EmailTemplate can be rendered with
+const (
+	TemplateEngineSimple = "simple"     // {{variable}} substitution only
+	TemplateEngineGo     = "gotemplate" // Go text/template with conditionals and loops
+)
+
+// Limits applied when rendering templates so a template can't stall or bloat a workflow
+const (
+	TemplateRenderTimeout  = 100 * time.Millisecond // Go templates only
+	DefaultMaxRenderedSize = 64 << 10               // 64 KiB per rendered subject or body
+)
+
+// MaxRenderedSize is the most bytes a rendered subject or body may hold, with either engine
+var MaxRenderedSize = DefaultMaxRenderedSize
+
+// ErrTemplateTooLarge is returned when rendered output exceeds MaxRenderedSize
+var ErrTemplateTooLarge = errors.New("rendered template exceeds maximum size")
+
+// ValidTemplateEngine reports whether engine names a supported engine; empty means simple
+func ValidTemplateEngine(engine string) bool {
+	return engine == "" || engine == TemplateEngineSimple || engine == TemplateEngineGo
+}
+
+// ValidateTemplate checks the template engine and, for Go templates, that the
+// subject and body parse
+func ValidateTemplate(template EmailTemplate) error {
+	if !ValidTemplateEngine(template.Engine) {
+		return fmt.Errorf("unsupported template engine: %s", template.Engine)
+	}
+	if template.Engine != TemplateEngineGo {
+		return nil
+	}
+	if _, err := parseGoTemplate("subject", template.Subject); err != nil {
+		return err
+	}
+	if _, err := parseGoTemplate("body", template.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderGoTemplate executes text as a Go template against variables. Missing
+// variables are errors rather than "<no value>", and execution is bounded by
+// TemplateRenderTimeout and MaxRenderedSize.
+func renderGoTemplate(name, text string, variables map[string]any) (string, error) {
+	tmpl, err := parseGoTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		out := &limitedBuffer{limit: MaxRenderedSize}
+		err := tmpl.Execute(out, variables)
+		done <- result{out.String(), err}
+	}()
+
+	// text/template can't be cancelled, so a runaway template is abandoned
+	// rather than stopped; its goroutine exits when execution finishes
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return "", fmt.Errorf("failed to render %s template: %w", name, r.err)
+		}
+		return r.output, nil
+	case <-time.After(TemplateRenderTimeout):
+		return "", fmt.Errorf("rendering %s template exceeded %s", name, TemplateRenderTimeout)
+	}
+}
+
+// parseGoTemplate parses text with comparison functions that accept mixed numeric types
+func parseGoTemplate(name, text string) (*texttemplate.Template, error) {
+	tmpl, err := texttemplate.New(name).
+		Option("missingkey=error").
+		Funcs(comparisonFuncs).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// singleLine collapses line breaks so a rendered subject stays a single header line
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(strings.NewReplacer("\r", " ", "\n", " ").Replace(s)), " ")
+}
+
+// limitedBuffer is a bytes.Buffer that fails writes past limit, which aborts template execution
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, ErrTemplateTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+// WriteString shadows bytes.Buffer's, which io.WriteString would otherwise use to skip the limit
+func (b *limitedBuffer) WriteString(s string) (int, error) {
+	return b.Write([]byte(s))
+}
+
+// comparisonFuncs replace the built-in comparisons, which reject comparing a
+// float variable such as .temperature with an integer literal such as 30
+var comparisonFuncs = texttemplate.FuncMap{
+	"eq": func(a any, others ...any) (bool, error) {
+		for _, b := range others {
+			c, err := compare(a, b)
+			if err != nil {
+				return false, err
+			}
+			if c == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+	"ne": func(a, b any) (bool, error) {
+		c, err := compare(a, b)
+		return c != 0, err
+	},
+	"lt": func(a, b any) (bool, error) {
+		c, err := compare(a, b)
+		return c < 0, err
+	},
+	"le": func(a, b any) (bool, error) {
+		c, err := compare(a, b)
+		return c <= 0, err
+	},
+	"gt": func(a, b any) (bool, error) {
+		c, err := compare(a, b)
+		return c > 0, err
+	},
+	"ge": func(a, b any) (bool, error) {
+		c, err := compare(a, b)
+		return c >= 0, err
+	},
+}
+
+// compare orders two numbers of any numeric type, two strings or two bools
+// (equality only), returning -1, 0 or 1
+func compare(a, b any) (int, error) {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1, nil
+			case x > y:
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		}
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			if x == y {
+				return 0, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, fmt.Errorf("incompatible types for comparison: %T and %T", a, b)
+}
+
+// toFloat converts any integer or float value to float64
+func toFloat(v any) (float64, bool) {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
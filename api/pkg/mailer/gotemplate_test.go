@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderGoTemplate(t *testing.T) {
+	variables := map[string]any{
+		"temperature": 32.5,
+		"city":        "Sydney",
+		"alerts":      []any{"heat", "uv"},
+	}
+
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"conditional with integer literal", "{{if gt .temperature 30}}Hot!{{end}}", "Hot!"},
+		{"false conditional", "{{if lt .temperature 30}}Cold!{{else}}Warm{{end}}", "Warm"},
+		{"equality across types", "{{if eq .temperature 32.5 40}}match{{end}}", "match"},
+		{"string comparison", "{{if eq .city \"Sydney\"}}home{{end}}", "home"},
+		{"loop", "{{range .alerts}}[{{.}}]{{end}}", "[heat][uv]"},
+		{"printf", "{{printf \"%.0f\" .temperature}}°C in {{.city}}", "32°C in Sydney"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := renderGoTemplate("body", tc.template, variables)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rendered)
+		})
+	}
+
+	t.Run("missing variable is an error", func(t *testing.T) {
+		_, err := renderGoTemplate("body", "Hi {{.name}}", variables)
+		assert.ErrorContains(t, err, "failed to render body template")
+	})
+
+	t.Run("incompatible comparison", func(t *testing.T) {
+		_, err := renderGoTemplate("body", "{{if gt .city 30}}x{{end}}", variables)
+		assert.ErrorContains(t, err, "incompatible types for comparison")
+	})
+
+	t.Run("output is bounded", func(t *testing.T) {
+		_, err := renderGoTemplate("body", "{{range 100000}}0123456789{{end}}", variables)
+		assert.ErrorIs(t, err, ErrTemplateTooLarge)
+	})
+
+	t.Run("execution time is bounded", func(t *testing.T) {
+		_, err := renderGoTemplate("body", "{{range 100000000}}{{end}}", variables)
+		assert.ErrorContains(t, err, "exceeded")
+	})
+}
+
+func TestPrepareAndStubSendEmailWithGoTemplate(t *testing.T) {
+	template := EmailTemplate{
+		Subject: "Alert for {{.city}}\r\nBcc: someone@example.com",
+		Body:    "{{if gt .temperature 30}}Hot in {{.city}}!{{end}}",
+		Engine:  TemplateEngineGo,
+	}
+
+	payload, err := PrepareAndStubSendEmail("test@example.com", map[string]any{"city": "Sydney", "temperature": 35.0}, template)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hot in Sydney!", payload["body"])
+	// Line breaks rendered into the subject can't start new headers
+	assert.False(t, strings.ContainsAny(payload["subject"].(string), "\r\n"))
+	assert.NotContains(t, payload, "unresolvedPlaceholders")
+
+	_, err = PrepareAndStubSendEmail("test@example.com", map[string]any{"city": "Sydney"}, template)
+	assert.Error(t, err)
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert.NoError(t, ValidateTemplate(EmailTemplate{Subject: "{{city}}", Body: "{{temperature}}"}))
+	assert.NoError(t, ValidateTemplate(EmailTemplate{Subject: "{{.city}}", Body: "{{if .hot}}x{{end}}", Engine: TemplateEngineGo}))
+	assert.Error(t, ValidateTemplate(EmailTemplate{Subject: "{{.city", Body: "x", Engine: TemplateEngineGo}))
+	assert.Error(t, ValidateTemplate(EmailTemplate{Subject: "x", Body: "x", Engine: "mustache"}))
+}
@@ -1,8 +1,14 @@
 package mailer
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log/slog"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,56 +19,284 @@ import (
 type EmailTemplate struct {
 	Subject string `json:"subject"`
 	Body    string `json:"body"`
+	// Engine selects how placeholders are rendered; empty means TemplateEngineSimple
+	Engine string `json:"engine,omitempty"`
+}
+
+// Attachment size limits in bytes
+const (
+	MaxAttachmentSize      = 1 << 20 // 1 MiB per attachment
+	MaxTotalAttachmentSize = 5 << 20 // 5 MiB across all attachments
+)
+
+// Attachment represents a file attached to an email
+type Attachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"-"`
+}
+
+// Sender delivers prepared messages, such as SMTPSender
+type Sender interface {
+	Send(ctx context.Context, messages ...*mail.Message) error
 }
 
 // PrepareAndStubSendEmail prepares an email using gomail and logs the payload (does not send).
-func PrepareAndStubSendEmail(to string, variables map[string]any, template EmailTemplate) (map[string]any, error) {
+func PrepareAndStubSendEmail(to string, variables map[string]any, template EmailTemplate, attachments ...Attachment) (map[string]any, error) {
+	_, payload, err := prepareEmail(to, variables, template, attachments)
+	if err != nil {
+		return nil, err
+	}
+	subject, _ := payload["subject"].(string)
+	slog.Debug(fmt.Sprintf("[STUB EMAIL] Would send: To=%s, Subject=%s, Attachments=%d", to, subject, len(attachments)))
+	return payload, nil
+}
+
+// PrepareAndSendEmail prepares an email like PrepareAndStubSendEmail and delivers it
+// through sender, returning the same payload. A failed delivery returns the payload
+// with the sender's error wrapped, so the email can be queued when IsTransient says
+// it is worth retrying.
+func PrepareAndSendEmail(ctx context.Context, sender Sender, to string, variables map[string]any, template EmailTemplate, attachments ...Attachment) (map[string]any, error) {
+	m, payload, err := prepareEmail(to, variables, template, attachments)
+	if err != nil {
+		return nil, err
+	}
+	if err := sender.Send(ctx, m); err != nil {
+		return payload, fmt.Errorf("failed to deliver email to %s: %w", to, err)
+	}
+	slog.Debug("Email sent", "to", to, "attachments", len(attachments))
+	return payload, nil
+}
+
+// prepareEmail renders the email into a gomail message and the payload describing it
+func prepareEmail(to string, variables map[string]any, template EmailTemplate, attachments []Attachment) (*mail.Message, map[string]any, error) {
+	if err := validateAttachments(attachments); err != nil {
+		return nil, nil, err
+	}
+
 	m := mail.NewMessage()
-	m.SetHeader("From", "weather-alerts@checkbox.com")
+	SetFrom(m)
 	m.SetHeader("To", to)
 
 	// Process subject and body using provided variables
-	subject := processTemplate(template.Subject, variables)
-	body := processTemplate(template.Body, variables)
+	subject, body, err := renderEmail(template, variables)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	m.SetHeader("Subject", subject)
 	m.SetBody("text/plain", body)
 
-	slog.Debug(fmt.Sprintf("[STUB EMAIL] Would send: To=%s, Subject=%s", to, subject))
+	// Attach files and collect their metadata for the returned payload
+	attach(m, attachments)
+	attachmentMeta := make([]map[string]any, 0, len(attachments))
+	for _, attachment := range attachments {
+		attachmentMeta = append(attachmentMeta, map[string]any{
+			"name":        attachment.Name,
+			"contentType": attachment.ContentType,
+			"size":        len(attachment.Data),
+		})
+	}
 
-	return map[string]any{
+	payload := map[string]any{
 		"to":        to,
-		"from":      "weather-alerts@checkbox.com",
+		"from":      From(),
 		"subject":   subject,
 		"body":      body,
 		"variables": variables,
 		"timestamp": time.Now().Format(time.RFC3339),
-	}, nil
+	}
+	if fromName != "" {
+		payload["fromName"] = fromName
+	}
+	if len(attachmentMeta) > 0 {
+		payload["attachments"] = attachmentMeta
+	}
+	if unresolved := UnresolvedPlaceholders(template, variables); len(unresolved) > 0 {
+		payload["unresolvedPlaceholders"] = unresolved
+	}
+
+	return m, payload, nil
+}
+
+// attach adds the attachments to m
+func attach(m *mail.Message, attachments []Attachment) {
+	for _, attachment := range attachments {
+		data := attachment.Data
+		m.Attach(attachment.Name,
+			mail.SetHeader(map[string][]string{"Content-Type": {attachment.ContentType}}),
+			mail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}),
+		)
+	}
+}
+
+// renderEmail renders the subject and body with the template's engine
+func renderEmail(template EmailTemplate, variables map[string]any) (string, string, error) {
+	switch template.Engine {
+	case "", TemplateEngineSimple:
+		subject, err := renderSimpleTemplate("subject", template.Subject, variables)
+		if err != nil {
+			return "", "", err
+		}
+		body, err := renderSimpleTemplate("body", template.Body, variables)
+		if err != nil {
+			return "", "", err
+		}
+		return subject, body, nil
+	case TemplateEngineGo:
+		subject, err := renderGoTemplate("subject", template.Subject, variables)
+		if err != nil {
+			return "", "", err
+		}
+		body, err := renderGoTemplate("body", template.Body, variables)
+		if err != nil {
+			return "", "", err
+		}
+		return singleLine(subject), body, nil
+	default:
+		return "", "", fmt.Errorf("unsupported template engine: %s", template.Engine)
+	}
+}
+
+// validateAttachments checks attachment names and enforces size limits
+func validateAttachments(attachments []Attachment) error {
+	total := 0
+	for _, attachment := range attachments {
+		if attachment.Name == "" {
+			return fmt.Errorf("attachment requires a name")
+		}
+		if len(attachment.Data) > MaxAttachmentSize {
+			return fmt.Errorf("attachment %s exceeds maximum size of %d bytes", attachment.Name, MaxAttachmentSize)
+		}
+		total += len(attachment.Data)
+	}
+	if total > MaxTotalAttachmentSize {
+		return fmt.Errorf("attachments exceed maximum total size of %d bytes", MaxTotalAttachmentSize)
+	}
+	return nil
+}
+
+// RecipientKey identifies a recipient without storing their address: the hex SHA-256
+// of the address, trimmed and lower-cased so differently written forms match
+func RecipientKey(address string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(address))))
+	return hex.EncodeToString(sum[:])
+}
+
+// AllowedVariables returns the variables named in allowed, so templates can't
+// render anything else, along with the sorted names of those left out. A nil
+// allowed list keeps every variable.
+func AllowedVariables(variables map[string]any, allowed []string) (map[string]any, []string) {
+	if allowed == nil {
+		return variables, nil
+	}
+	kept := make(map[string]any, len(variables))
+	var dropped []string
+	for name, value := range variables {
+		if slices.Contains(allowed, name) {
+			kept[name] = value
+		} else {
+			dropped = append(dropped, name)
+		}
+	}
+	slices.Sort(dropped)
+	return kept, dropped
+}
+
+// placeholderPattern matches {{variable}} placeholders in a template
+var placeholderPattern = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// RenderTemplate replaces placeholders with values from variables and returns the
+// rendered text along with the names of any placeholders that had no value
+func RenderTemplate(template string, variables map[string]any) (string, []string) {
+	return processTemplate(template, variables), unresolved(template, variables, nil)
+}
+
+// UnresolvedPlaceholders returns the placeholders in the subject and body that
+// have no matching variable, in order of first appearance. Go templates report
+// missing variables as render errors instead, so they never have unresolved placeholders.
+func UnresolvedPlaceholders(template EmailTemplate, variables map[string]any) []string {
+	if template.Engine == TemplateEngineGo {
+		return nil
+	}
+	missing := unresolved(template.Subject, variables, nil)
+	return unresolved(template.Body, variables, missing)
+}
+
+// unresolved appends placeholder names in template that are missing from variables to found, skipping duplicates
+func unresolved(template string, variables map[string]any, found []string) []string {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		name := match[1]
+		if _, ok := variables[name]; ok {
+			continue
+		}
+		duplicate := false
+		for _, existing := range found {
+			if existing == name {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			found = append(found, name)
+		}
+	}
+	return found
 }
 
 // processTemplate replaces template placeholders {{variable}} with actual values
 func processTemplate(template string, variables map[string]any) string {
-	result := template
-
-	// Replace each variable in the template
-	for key, value := range variables {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-
-		// Convert value to string based on type
-		var stringValue string
-		switch v := value.(type) {
-		case float64:
-			stringValue = fmt.Sprintf("%.1f", v)
-		case int:
-			stringValue = fmt.Sprintf("%d", v)
-		case string:
-			stringValue = v
-		default:
-			stringValue = fmt.Sprintf("%v", v)
-		}
+	var result strings.Builder
+	substitute(&result, template, variables)
+	return result.String()
+}
 
-		result = strings.Replace(result, placeholder, stringValue, -1)
+// renderSimpleTemplate substitutes variables into an email subject or body, failing
+// once the output grows past MaxRenderedSize
+func renderSimpleTemplate(name, template string, variables map[string]any) (string, error) {
+	out := &limitedBuffer{limit: MaxRenderedSize}
+	if err := substitute(out, template, variables); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
 	}
+	return out.String(), nil
+}
+
+// substitute writes template to w with each {{variable}} placeholder replaced by its
+// value. It makes a single pass over the template, so placeholder-like text inside a
+// value, such as a name of "{{city}}", is written as is rather than substituted in
+// turn. Placeholders without a value are left in place.
+func substitute(w io.Writer, template string, variables map[string]any) error {
+	last := 0
+	for _, match := range placeholderPattern.FindAllStringSubmatchIndex(template, -1) {
+		value, ok := variables[template[match[2]:match[3]]]
+		if !ok {
+			continue
+		}
+		if _, err := io.WriteString(w, template[last:match[0]]); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, formatVariable(value)); err != nil {
+			return err
+		}
+		last = match[1]
+	}
+	_, err := io.WriteString(w, template[last:])
+	return err
+}
 
-	return result
+// formatVariable converts a variable to the text substituted for its placeholder
+func formatVariable(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return fmt.Sprintf("%.1f", v)
+	case int:
+		return fmt.Sprintf("%d", v)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
 }
@@ -1,10 +1,14 @@
 package mailer
 
 import (
+	"context"
 	"fmt"
+	"net/textproto"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	mail "gopkg.in/gomail.v2"
 )
 
 func TestPrepareAndStubSendEmail(t *testing.T) {
@@ -106,6 +110,87 @@ func TestPrepareAndStubSendEmail(t *testing.T) {
 	}
 }
 
+func TestPrepareAndStubSendEmailWithAttachments(t *testing.T) {
+	template := EmailTemplate{
+		Subject: "Weather Alert",
+		Body:    "Weather alert for {{city}}!",
+	}
+	variables := map[string]any{"city": "Sydney"}
+
+	testCases := []struct {
+		name          string
+		attachments   []Attachment
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Single attachment",
+			attachments: []Attachment{
+				{Name: "report.csv", ContentType: "text/csv", Data: []byte("field,value\ncity,Sydney\n")},
+			},
+			expectError: false,
+		},
+		{
+			name: "Attachment without name",
+			attachments: []Attachment{
+				{ContentType: "text/csv", Data: []byte("data")},
+			},
+			expectError:   true,
+			errorContains: "requires a name",
+		},
+		{
+			name: "Attachment too large",
+			attachments: []Attachment{
+				{Name: "big.csv", ContentType: "text/csv", Data: make([]byte, MaxAttachmentSize+1)},
+			},
+			expectError:   true,
+			errorContains: "exceeds maximum size",
+		},
+		{
+			name: "Attachments too large in total",
+			attachments: []Attachment{
+				{Name: "a.csv", ContentType: "text/csv", Data: make([]byte, MaxAttachmentSize)},
+				{Name: "b.csv", ContentType: "text/csv", Data: make([]byte, MaxAttachmentSize)},
+				{Name: "c.csv", ContentType: "text/csv", Data: make([]byte, MaxAttachmentSize)},
+				{Name: "d.csv", ContentType: "text/csv", Data: make([]byte, MaxAttachmentSize)},
+				{Name: "e.csv", ContentType: "text/csv", Data: make([]byte, MaxAttachmentSize)},
+				{Name: "f.csv", ContentType: "text/csv", Data: make([]byte, 1)},
+			},
+			expectError:   true,
+			errorContains: "maximum total size",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := PrepareAndStubSendEmail("test@example.com", variables, template, tc.attachments...)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			attachments, ok := result["attachments"].([]map[string]any)
+			assert.True(t, ok, "Should include attachment metadata")
+			assert.Len(t, attachments, len(tc.attachments))
+			for i, attachment := range tc.attachments {
+				assert.Equal(t, attachment.Name, attachments[i]["name"])
+				assert.Equal(t, attachment.ContentType, attachments[i]["contentType"])
+				assert.Equal(t, len(attachment.Data), attachments[i]["size"])
+			}
+		})
+	}
+
+	t.Run("No attachments", func(t *testing.T) {
+		result, err := PrepareAndStubSendEmail("test@example.com", variables, template)
+		assert.NoError(t, err)
+		assert.NotContains(t, result, "attachments")
+	})
+}
+
 func TestProcessTemplate(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -166,6 +251,118 @@ func TestProcessTemplate(t *testing.T) {
 	}
 }
 
+func TestProcessTemplateSinglePass(t *testing.T) {
+	testCases := []struct {
+		name      string
+		template  string
+		variables map[string]any
+		expected  string
+	}{
+		{
+			name:      "Value naming another variable is not substituted",
+			template:  "Hello {{name}}, it is hot in {{city}}",
+			variables: map[string]any{"name": "{{city}}", "city": "Sydney"},
+			expected:  "Hello {{city}}, it is hot in Sydney",
+		},
+		{
+			name:      "Value naming itself is not expanded",
+			template:  "Hello {{name}}",
+			variables: map[string]any{"name": "{{name}}{{name}}"},
+			expected:  "Hello {{name}}{{name}}",
+		},
+		{
+			name:      "Value naming a missing variable",
+			template:  "Alert: {{message}}",
+			variables: map[string]any{"message": "{{unknown}} degrees"},
+			expected:  "Alert: {{unknown}} degrees",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Substitution must not depend on the order variables are visited in
+			for range 20 {
+				assert.Equal(t, tc.expected, processTemplate(tc.template, tc.variables))
+			}
+		})
+	}
+}
+
+func TestRenderEmailSizeLimit(t *testing.T) {
+	defer func(limit int) { MaxRenderedSize = limit }(MaxRenderedSize)
+	MaxRenderedSize = 64
+
+	template := EmailTemplate{Subject: "Alert for {{city}}", Body: "{{message}} {{message}}"}
+
+	_, _, err := renderEmail(template, map[string]any{"city": "Sydney", "message": "Hot"})
+	assert.NoError(t, err)
+
+	_, _, err = renderEmail(template, map[string]any{"city": "Sydney", "message": strings.Repeat("x", 40)})
+	assert.ErrorIs(t, err, ErrTemplateTooLarge)
+	assert.ErrorContains(t, err, "failed to render body template")
+
+	_, err = PrepareAndStubSendEmail("test@example.com", map[string]any{"city": strings.Repeat("x", 100)}, template)
+	assert.ErrorIs(t, err, ErrTemplateTooLarge)
+}
+
+func TestRenderTemplateUnresolved(t *testing.T) {
+	testCases := []struct {
+		name               string
+		template           string
+		variables          map[string]any
+		expected           string
+		expectedUnresolved []string
+	}{
+		{
+			name:               "All resolved",
+			template:           "Hello {{name}}!",
+			variables:          map[string]any{"name": "Ann"},
+			expected:           "Hello Ann!",
+			expectedUnresolved: nil,
+		},
+		{
+			name:               "Multiple missing variables",
+			template:           "{{greeting}} {{name}}! It is {{temperature}}°C in {{city}}.",
+			variables:          map[string]any{"name": "Ann"},
+			expected:           "{{greeting}} Ann! It is {{temperature}}°C in {{city}}.",
+			expectedUnresolved: []string{"greeting", "temperature", "city"},
+		},
+		{
+			name:               "Repeated missing variable reported once",
+			template:           "{{city}} alert: check {{city}} now",
+			variables:          map[string]any{},
+			expected:           "{{city}} alert: check {{city}} now",
+			expectedUnresolved: []string{"city"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, unresolved := RenderTemplate(tc.template, tc.variables)
+			assert.Equal(t, tc.expected, rendered)
+			assert.Equal(t, tc.expectedUnresolved, unresolved)
+		})
+	}
+}
+
+func TestUnresolvedPlaceholders(t *testing.T) {
+	template := EmailTemplate{
+		Subject: "Alert for {{city}}",
+		Body:    "{{name}}, {{city}} is {{temperature}}°C",
+	}
+
+	unresolved := UnresolvedPlaceholders(template, map[string]any{"name": "Ann"})
+	assert.Equal(t, []string{"city", "temperature"}, unresolved)
+
+	result, err := PrepareAndStubSendEmail("test@example.com", map[string]any{"name": "Ann"}, template)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"city", "temperature"}, result["unresolvedPlaceholders"])
+
+	result, err = PrepareAndStubSendEmail("test@example.com", map[string]any{"name": "Ann", "city": "Perth", "temperature": 30.0}, template)
+	assert.NoError(t, err)
+	assert.NotContains(t, result, "unresolvedPlaceholders")
+}
+
 // Helper function to format temperature same way as in the main function
 func getFormattedTemperature(temp float64) string {
 	return getString(temp)
@@ -175,3 +372,53 @@ func getFormattedTemperature(temp float64) string {
 func getString(f float64) string {
 	return fmt.Sprintf("%.1f", f)
 }
+
+func TestAllowedVariables(t *testing.T) {
+	variables := map[string]any{"city": "Sydney", "temperature": 25.0, "token": "secret", "apiKey": "key"}
+
+	kept, dropped := AllowedVariables(variables, []string{"city", "temperature", "name"})
+	assert.Equal(t, map[string]any{"city": "Sydney", "temperature": 25.0}, kept)
+	assert.Equal(t, []string{"apiKey", "token"}, dropped)
+
+	kept, dropped = AllowedVariables(variables, []string{})
+	assert.Empty(t, kept)
+	assert.Len(t, dropped, 4)
+
+	kept, dropped = AllowedVariables(variables, nil)
+	assert.Equal(t, variables, kept)
+	assert.Nil(t, dropped)
+}
+
+// recordingSender records the messages it is given and fails with err when set
+type recordingSender struct {
+	messages []*mail.Message
+	err      error
+}
+
+func (s *recordingSender) Send(_ context.Context, messages ...*mail.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func TestPrepareAndSendEmail(t *testing.T) {
+	template := EmailTemplate{Subject: "Weather Alert", Body: "Weather alert for {{city}}"}
+	variables := map[string]any{"city": "Sydney"}
+
+	sender := &recordingSender{}
+	payload, err := PrepareAndSendEmail(context.Background(), sender, "john@example.com", variables, template)
+	assert.NoError(t, err)
+	assert.Equal(t, "Weather alert for Sydney", payload["body"])
+	if assert.Len(t, sender.messages, 1) {
+		assert.Equal(t, []string{"john@example.com"}, sender.messages[0].GetHeader("To"))
+		assert.Equal(t, []string{"Weather Alert"}, sender.messages[0].GetHeader("Subject"))
+	}
+
+	// A failed delivery keeps the payload, so the email can be queued
+	sender = &recordingSender{err: &textproto.Error{Code: 451, Msg: "try again later"}}
+	payload, err = PrepareAndSendEmail(context.Background(), sender, "john@example.com", variables, template)
+	assert.True(t, IsTransient(err))
+	assert.Equal(t, "Weather alert for Sydney", payload["body"])
+}
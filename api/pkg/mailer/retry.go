@@ -0,0 +1,277 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	mail "gopkg.in/gomail.v2"
+)
+
+// Defaults for the email retry queue
+const (
+	DefaultRetryQueueCapacity = 100
+	DefaultRetryMaxAttempts   = 5
+	DefaultRetryBaseDelay     = 30 * time.Second
+	retryPollInterval         = time.Second
+)
+
+// ErrRetryQueueFull is returned when an email can't be queued because the queue is at capacity
+var ErrRetryQueueFull = errors.New("email retry queue is full")
+
+// ErrTransient marks a send failure that is worth retrying
+var ErrTransient = errors.New("transient email failure")
+
+// IsTransient reports whether a send error is likely to succeed on retry: SMTP 4xx
+// replies, network timeouts, failed dials other than a 5xx reply (an unreachable or
+// refusing server), and errors wrapping ErrTransient
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrTransient) {
+		return true
+	}
+	if errors.Is(err, ErrDialFailed) {
+		return !isPermanentSMTPError(err)
+	}
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code >= 400 && smtpErr.Code < 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// QueuedEmail is a rendered email waiting to be re-sent
+type QueuedEmail struct {
+	// ID identifies the email in a RetryStore; Enqueue assigns one when it is empty
+	ID          string
+	To          string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+	Attempts    int
+	NextAttempt time.Time
+}
+
+// SendFunc delivers a queued email
+type SendFunc func(ctx context.Context, email QueuedEmail) error
+
+// RetryStore persists queued emails so they survive a restart
+type RetryStore interface {
+	// SaveQueuedEmail stores the email, replacing the one with its ID
+	SaveQueuedEmail(ctx context.Context, email QueuedEmail) error
+	DeleteQueuedEmail(ctx context.Context, id string) error
+	// ListQueuedEmails returns the stored emails, oldest first
+	ListQueuedEmails(ctx context.Context) ([]QueuedEmail, error)
+}
+
+// RetryQueue holds emails whose send failed transiently and re-sends them in the
+// background with exponential backoff. It is bounded and in-process; with a store
+// from Restore, queued emails are also persisted and picked up again after a
+// restart.
+type RetryQueue struct {
+	send        SendFunc
+	capacity    int
+	maxAttempts int
+	baseDelay   time.Duration
+	now         func() time.Time // overridable clock for tests
+
+	mu    sync.Mutex
+	items []QueuedEmail
+	store RetryStore
+}
+
+// NewRetryQueue creates a queue that re-sends through send. Non-positive
+// settings fall back to the defaults.
+func NewRetryQueue(send SendFunc, capacity, maxAttempts int, baseDelay time.Duration) *RetryQueue {
+	if capacity <= 0 {
+		capacity = DefaultRetryQueueCapacity
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultRetryMaxAttempts
+	}
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+	return &RetryQueue{
+		send:        send,
+		capacity:    capacity,
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+		now:         time.Now,
+	}
+}
+
+// SendVia returns a SendFunc that delivers queued emails through sender
+func SendVia(sender Sender) SendFunc {
+	return func(ctx context.Context, email QueuedEmail) error {
+		m := mail.NewMessage()
+		SetFrom(m)
+		m.SetHeader("To", email.To)
+		m.SetHeader("Subject", email.Subject)
+		m.SetBody("text/plain", email.Body)
+		attach(m, email.Attachments)
+		return sender.Send(ctx, m)
+	}
+}
+
+// Restore persists the queue in store from now on and loads the emails it holds,
+// returning how many were loaded. Stored emails beyond the queue's capacity are
+// dropped. Call it before Run.
+func (q *RetryQueue) Restore(ctx context.Context, store RetryStore) (int, error) {
+	emails, err := store.ListQueuedEmails(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mu.Lock()
+	q.store = store
+	var dropped []QueuedEmail
+	for _, email := range emails {
+		if len(q.items) >= q.capacity {
+			dropped = append(dropped, email)
+			continue
+		}
+		q.items = append(q.items, email)
+	}
+	loaded := len(q.items)
+	q.mu.Unlock()
+
+	for _, email := range dropped {
+		slog.Error("Dropping stored email, retry queue is full", "to", email.To, "attempts", email.Attempts)
+		q.forget(ctx, email)
+	}
+	return loaded, nil
+}
+
+// Enqueue adds an email that has already failed once and returns its 1-based
+// position in the queue. A failure to persist it is logged; the email is still
+// retried until a restart.
+func (q *RetryQueue) Enqueue(ctx context.Context, email QueuedEmail) (int, error) {
+	q.mu.Lock()
+	if len(q.items) >= q.capacity {
+		q.mu.Unlock()
+		return 0, ErrRetryQueueFull
+	}
+	if email.ID == "" {
+		email.ID = uuid.NewString()
+	}
+	if email.Attempts == 0 {
+		email.Attempts = 1
+	}
+	email.NextAttempt = q.now().Add(q.backoff(email.Attempts))
+	q.items = append(q.items, email)
+	position := len(q.items)
+	q.mu.Unlock()
+
+	q.persist(ctx, email)
+	return position, nil
+}
+
+// Len returns the number of queued emails
+func (q *RetryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Run drains the queue until ctx is cancelled
+func (q *RetryQueue) Run(ctx context.Context) {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.drainDue(ctx)
+		}
+	}
+}
+
+// drainDue attempts every email whose retry time has passed. Transient failures are
+// rescheduled until maxAttempts; other failures and exhausted emails are dropped.
+func (q *RetryQueue) drainDue(ctx context.Context) {
+	q.mu.Lock()
+	now := q.now()
+	var due, waiting []QueuedEmail
+	for _, email := range q.items {
+		if email.NextAttempt.After(now) {
+			waiting = append(waiting, email)
+		} else {
+			due = append(due, email)
+		}
+	}
+	q.items = waiting
+	q.mu.Unlock()
+
+	// Send outside the lock so Enqueue isn't blocked by slow deliveries
+	for _, email := range due {
+		err := q.send(ctx, email)
+		if err == nil {
+			slog.Info("Queued email sent", "to", email.To, "attempts", email.Attempts+1)
+			q.forget(ctx, email)
+			continue
+		}
+
+		email.Attempts++
+		if !IsTransient(err) || email.Attempts >= q.maxAttempts {
+			slog.Error("Dropping queued email", "to", email.To, "attempts", email.Attempts, "error", err)
+			q.forget(ctx, email)
+			continue
+		}
+
+		// Emails enqueued while this one was being sent may have filled the queue
+		q.mu.Lock()
+		full := len(q.items) >= q.capacity
+		if !full {
+			email.NextAttempt = q.now().Add(q.backoff(email.Attempts))
+			q.items = append(q.items, email)
+		}
+		q.mu.Unlock()
+		if full {
+			slog.Error("Dropping queued email, retry queue is full", "to", email.To, "attempts", email.Attempts, "error", err)
+			q.forget(ctx, email)
+			continue
+		}
+		q.persist(ctx, email)
+	}
+}
+
+// persist saves email to the queue's store, if it has one
+func (q *RetryQueue) persist(ctx context.Context, email QueuedEmail) {
+	if store := q.currentStore(); store != nil {
+		if err := store.SaveQueuedEmail(ctx, email); err != nil {
+			slog.Warn("Failed to persist queued email", "to", email.To, "error", err)
+		}
+	}
+}
+
+// forget removes email from the queue's store, if it has one
+func (q *RetryQueue) forget(ctx context.Context, email QueuedEmail) {
+	if store := q.currentStore(); store != nil {
+		if err := store.DeleteQueuedEmail(ctx, email.ID); err != nil {
+			slog.Warn("Failed to remove queued email from store", "to", email.To, "error", err)
+		}
+	}
+}
+
+func (q *RetryQueue) currentStore() RetryStore {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.store
+}
+
+// backoff returns the delay before the next attempt, doubling with each attempt made
+// up to MaxBackoffDelay
+func (q *RetryQueue) backoff(attempts int) time.Duration {
+	return backoffDelay(q.baseDelay, attempts)
+}
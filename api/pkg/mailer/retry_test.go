@@ -0,0 +1,221 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, IsTransient(&textproto.Error{Code: 421, Msg: "service not available"}))
+	assert.True(t, IsTransient(fmt.Errorf("send: %w", ErrTransient)))
+	assert.False(t, IsTransient(&textproto.Error{Code: 550, Msg: "mailbox unavailable"}))
+	assert.False(t, IsTransient(errors.New("bad address")))
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	assert.False(t, IsTransient(refused))
+	assert.True(t, IsTransient(fmt.Errorf("%w: smtp.example.com:587 after 3 attempts: %w", ErrDialFailed, refused)))
+	assert.False(t, IsTransient(fmt.Errorf("%w: smtp.example.com:587 after 1 attempts: %w", ErrDialFailed,
+		&textproto.Error{Code: 535, Msg: "authentication failed"})))
+	assert.False(t, IsTransient(nil))
+}
+
+func TestRetryQueue(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newQueue := func(send SendFunc) *RetryQueue {
+		q := NewRetryQueue(send, 2, 3, time.Minute)
+		q.now = func() time.Time { return clock }
+		return q
+	}
+
+	t.Run("bounded with positions", func(t *testing.T) {
+		q := newQueue(func(ctx context.Context, email QueuedEmail) error { return nil })
+
+		position, err := q.Enqueue(context.Background(), QueuedEmail{To: "a@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, position)
+		position, err = q.Enqueue(context.Background(), QueuedEmail{To: "b@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, position)
+
+		_, err = q.Enqueue(context.Background(), QueuedEmail{To: "c@example.com"})
+		assert.ErrorIs(t, err, ErrRetryQueueFull)
+	})
+
+	t.Run("retries with backoff until sent", func(t *testing.T) {
+		failures := 1
+		var sent []QueuedEmail
+		q := newQueue(func(ctx context.Context, email QueuedEmail) error {
+			if failures > 0 {
+				failures--
+				return &textproto.Error{Code: 451, Msg: "try again later"}
+			}
+			sent = append(sent, email)
+			return nil
+		})
+		_, err := q.Enqueue(context.Background(), QueuedEmail{To: "a@example.com"})
+		assert.NoError(t, err)
+
+		// Not due until the first backoff has passed
+		q.drainDue(context.Background())
+		assert.Equal(t, 1, q.Len())
+
+		clock = clock.Add(time.Minute)
+		q.drainDue(context.Background())
+		assert.Equal(t, 1, q.Len(), "transient failure is rescheduled")
+		assert.Empty(t, sent)
+
+		// The second retry waits twice as long
+		clock = clock.Add(time.Minute)
+		q.drainDue(context.Background())
+		assert.Equal(t, 1, q.Len())
+		clock = clock.Add(time.Minute)
+		q.drainDue(context.Background())
+		assert.Equal(t, 0, q.Len())
+		assert.Len(t, sent, 1)
+	})
+
+	t.Run("permanent failures and exhausted retries are dropped", func(t *testing.T) {
+		q := newQueue(func(ctx context.Context, email QueuedEmail) error {
+			if email.To == "bad@example.com" {
+				return &textproto.Error{Code: 550, Msg: "mailbox unavailable"}
+			}
+			return fmt.Errorf("timeout: %w", ErrTransient)
+		})
+		_, _ = q.Enqueue(context.Background(), QueuedEmail{To: "bad@example.com"})
+		_, _ = q.Enqueue(context.Background(), QueuedEmail{To: "slow@example.com", Attempts: 2})
+
+		clock = clock.Add(time.Hour)
+		q.drainDue(context.Background())
+		assert.Equal(t, 0, q.Len())
+	})
+}
+
+// fakeRetryStore keeps queued emails in memory, in the order first saved
+type fakeRetryStore struct {
+	emails map[string]QueuedEmail
+	order  []string
+}
+
+func newFakeRetryStore(emails ...QueuedEmail) *fakeRetryStore {
+	store := &fakeRetryStore{emails: make(map[string]QueuedEmail)}
+	for _, email := range emails {
+		_ = store.SaveQueuedEmail(context.Background(), email)
+	}
+	return store
+}
+
+func (s *fakeRetryStore) SaveQueuedEmail(_ context.Context, email QueuedEmail) error {
+	if _, ok := s.emails[email.ID]; !ok {
+		s.order = append(s.order, email.ID)
+	}
+	s.emails[email.ID] = email
+	return nil
+}
+
+func (s *fakeRetryStore) DeleteQueuedEmail(_ context.Context, id string) error {
+	delete(s.emails, id)
+	return nil
+}
+
+func (s *fakeRetryStore) ListQueuedEmails(context.Context) ([]QueuedEmail, error) {
+	var emails []QueuedEmail
+	for _, id := range s.order {
+		if email, ok := s.emails[id]; ok {
+			emails = append(emails, email)
+		}
+	}
+	return emails, nil
+}
+
+func TestRetryQueuePersistence(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	transient := fmt.Errorf("timeout: %w", ErrTransient)
+	failing := map[string]bool{}
+	var sent []string
+	q := NewRetryQueue(func(ctx context.Context, email QueuedEmail) error {
+		if failing[email.To] {
+			return transient
+		}
+		sent = append(sent, email.To)
+		return nil
+	}, 2, 5, time.Minute)
+	q.now = func() time.Time { return clock }
+
+	// Stored emails are loaded up to capacity; the rest are dropped from the store
+	store := newFakeRetryStore(
+		QueuedEmail{ID: "stored-1", To: "a@example.com", Attempts: 1, NextAttempt: clock},
+		QueuedEmail{ID: "stored-2", To: "b@example.com", Attempts: 1, NextAttempt: clock},
+		QueuedEmail{ID: "stored-3", To: "c@example.com", Attempts: 1, NextAttempt: clock},
+	)
+	loaded, err := q.Restore(context.Background(), store)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loaded)
+	assert.Len(t, store.emails, 2)
+
+	// A rescheduled email is saved with its new attempt count, a sent one removed
+	failing["b@example.com"] = true
+	q.drainDue(context.Background())
+	assert.Equal(t, []string{"a@example.com"}, sent)
+	assert.NotContains(t, store.emails, "stored-1")
+	assert.Equal(t, 2, store.emails["stored-2"].Attempts)
+
+	// New emails are stored under a generated ID
+	position, err := q.Enqueue(context.Background(), QueuedEmail{To: "d@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, position)
+	assert.Len(t, store.emails, 2)
+}
+
+func TestRetryQueueRespectsCapacityWhenRescheduling(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var q *RetryQueue
+	q = NewRetryQueue(func(ctx context.Context, email QueuedEmail) error {
+		// The queue fills up while the first email is being sent
+		_, _ = q.Enqueue(ctx, QueuedEmail{To: "new@example.com"})
+		return fmt.Errorf("timeout: %w", ErrTransient)
+	}, 1, 5, time.Minute)
+	q.now = func() time.Time { return clock }
+	store := newFakeRetryStore()
+	_, err := q.Restore(context.Background(), store)
+	assert.NoError(t, err)
+
+	_, err = q.Enqueue(context.Background(), QueuedEmail{ID: "first", To: "first@example.com"})
+	assert.NoError(t, err)
+	clock = clock.Add(time.Minute)
+	q.drainDue(context.Background())
+
+	assert.Equal(t, 1, q.Len(), "rescheduling must not grow the queue past its capacity")
+	assert.NotContains(t, store.emails, "first")
+}
+
+func TestSendVia(t *testing.T) {
+	defer func(name string) { fromName = name }(fromName)
+	require.NoError(t, SetFromName("Weather Alerts"))
+	sender := &recordingSender{}
+
+	err := SendVia(sender)(context.Background(), QueuedEmail{
+		To:          "john@example.com",
+		Subject:     "Weather Alert",
+		Body:        "Hot in Sydney",
+		Attachments: []Attachment{{Name: "report.csv", ContentType: "text/csv", Data: []byte("a,b")}},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, sender.messages, 1) {
+		m := sender.messages[0]
+		assert.Equal(t, []string{`"Weather Alerts" <weather-alerts@checkbox.com>`}, m.GetHeader("From"))
+		assert.Equal(t, []string{"john@example.com"}, m.GetHeader("To"))
+		assert.Equal(t, []string{"Weather Alert"}, m.GetHeader("Subject"))
+		var written bytes.Buffer
+		_, err := m.WriteTo(&written)
+		assert.NoError(t, err)
+		assert.Contains(t, written.String(), "report.csv")
+	}
+}
@@ -0,0 +1,134 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/textproto"
+	"sync"
+	"time"
+
+	mail "gopkg.in/gomail.v2"
+)
+
+// Defaults for dialing the SMTP server
+const (
+	DefaultDialAttempts  = 3
+	DefaultDialBaseDelay = 500 * time.Millisecond
+)
+
+// ErrDialFailed is returned when no connection to the SMTP server could be opened
+var ErrDialFailed = errors.New("could not connect to SMTP server")
+
+// SMTPSender sends messages over one pooled SMTP connection, dialing it on first
+// use and again after a failed send. A failed dial is retried with exponential
+// backoff before giving up; this is separate from RetryQueue, which re-sends
+// messages whose delivery failed. It is safe for concurrent use.
+type SMTPSender struct {
+	dialer    *mail.Dialer
+	attempts  int
+	baseDelay time.Duration
+
+	mu   sync.Mutex
+	conn mail.SendCloser
+}
+
+// NewSMTPSender creates a sender for dialer that makes up to attempts dials,
+// waiting baseDelay after the first failure and doubling the wait after each
+// further one. Non-positive settings fall back to the defaults.
+func NewSMTPSender(dialer *mail.Dialer, attempts int, baseDelay time.Duration) *SMTPSender {
+	if attempts <= 0 {
+		attempts = DefaultDialAttempts
+	}
+	if baseDelay <= 0 {
+		baseDelay = DefaultDialBaseDelay
+	}
+	return &SMTPSender{
+		dialer:    dialer,
+		attempts:  attempts,
+		baseDelay: baseDelay,
+	}
+}
+
+// Send delivers messages over the pooled connection, dialing first if there is
+// none. After a failed send the connection is dropped so the next send redials.
+func (s *SMTPSender) Send(ctx context.Context, messages ...*mail.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dialWithRetry(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	if err := mail.Send(s.conn, messages...); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// Close closes the pooled connection, if any
+func (s *SMTPSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// dialWithRetry dials until it connects, the attempts run out, the server gives a
+// permanent (5xx) reply such as a rejected login, or ctx is done
+func (s *SMTPSender) dialWithRetry(ctx context.Context) (mail.SendCloser, error) {
+	var lastErr error
+	attempt := 1
+	for ; attempt <= s.attempts; attempt++ {
+		conn, err := s.dialer.Dial()
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		if isPermanentSMTPError(err) || attempt == s.attempts {
+			break
+		}
+
+		delay := backoffDelay(s.baseDelay, attempt)
+		slog.Warn("SMTP dial failed, retrying", "host", s.dialer.Host, "attempt", attempt, "retryIn", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w: %s:%d: %w", ErrDialFailed, s.dialer.Host, s.dialer.Port, ctx.Err())
+		case <-timer.C:
+		}
+	}
+	slog.Error("SMTP dial failed", "host", s.dialer.Host, "attempts", attempt, "error", lastErr)
+	return nil, fmt.Errorf("%w: %s:%d after %d attempts: %w", ErrDialFailed, s.dialer.Host, s.dialer.Port, attempt, lastErr)
+}
+
+// MaxBackoffDelay caps the wait between dial attempts and between email retries
+const MaxBackoffDelay = time.Hour
+
+// backoffDelay returns the wait after the given 1-based attempt: base, doubled after
+// each further attempt, and never more than MaxBackoffDelay
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < MaxBackoffDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxBackoffDelay)
+}
+
+// isPermanentSMTPError reports whether err is an SMTP 5xx reply, which retrying won't fix
+func isPermanentSMTPError(err error) bool {
+	var smtpErr *textproto.Error
+	return errors.As(err, &smtpErr) && smtpErr.Code >= 500
+}
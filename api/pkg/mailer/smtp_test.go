@@ -0,0 +1,169 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mail "gopkg.in/gomail.v2"
+)
+
+// fakeSMTPServer answers the first rejectFirst connections with greeting and then
+// accepts mail on later ones, recording each message's recipients
+type fakeSMTPServer struct {
+	listener    net.Listener
+	rejectFirst int
+	greeting    string // sent to rejected connections, e.g. "421 busy"
+	connections atomic.Int32
+
+	mu         sync.Mutex
+	recipients []string
+}
+
+func newFakeSMTPServer(t *testing.T, rejectFirst int, greeting string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTPServer{listener: listener, rejectFirst: rejectFirst, greeting: greeting}
+	t.Cleanup(func() { listener.Close() })
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) dialer() *mail.Dialer {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return mail.NewDialer(addr.IP.String(), addr.Port, "", "")
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		if int(s.connections.Add(1)) <= s.rejectFirst {
+			fmt.Fprintf(conn, "%s\r\n", s.greeting)
+			conn.Close()
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle speaks just enough SMTP for gomail to send a message
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 fake.smtp ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fake.smtp")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.mu.Lock()
+			s.recipients = append(s.recipients, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end with .")
+			for {
+				data, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if data == ".\r\n" {
+					break
+				}
+			}
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func newTestMessage(to string) *mail.Message {
+	m := mail.NewMessage()
+	m.SetHeader("From", "weather-alerts@checkbox.com")
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", "Weather alert")
+	m.SetBody("text/plain", "Hot in Sydney")
+	return m
+}
+
+func TestSMTPSenderRetriesDial(t *testing.T) {
+	server := newFakeSMTPServer(t, 1, "421 fake.smtp busy, try again")
+	sender := NewSMTPSender(server.dialer(), 3, time.Millisecond)
+	defer sender.Close()
+
+	err := sender.Send(context.Background(), newTestMessage("a@example.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), server.connections.Load(), "the rejected dial should be retried")
+
+	// The pooled connection is reused for later messages
+	err = sender.Send(context.Background(), newTestMessage("b@example.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), server.connections.Load())
+	server.mu.Lock()
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, server.recipients)
+	server.mu.Unlock()
+}
+
+func TestSMTPSenderDialFailures(t *testing.T) {
+	t.Run("gives up after the configured attempts", func(t *testing.T) {
+		server := newFakeSMTPServer(t, 10, "421 fake.smtp busy, try again")
+		sender := NewSMTPSender(server.dialer(), 3, time.Millisecond)
+
+		err := sender.Send(context.Background(), newTestMessage("a@example.com"))
+		assert.ErrorIs(t, err, ErrDialFailed)
+		assert.ErrorContains(t, err, "after 3 attempts")
+		assert.ErrorContains(t, err, "421")
+		assert.Equal(t, int32(3), server.connections.Load())
+	})
+
+	t.Run("permanent replies are not retried", func(t *testing.T) {
+		server := newFakeSMTPServer(t, 10, "554 fake.smtp no service")
+		sender := NewSMTPSender(server.dialer(), 3, time.Millisecond)
+
+		err := sender.Send(context.Background(), newTestMessage("a@example.com"))
+		assert.ErrorIs(t, err, ErrDialFailed)
+		assert.Equal(t, int32(1), server.connections.Load())
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		server := newFakeSMTPServer(t, 10, "421 fake.smtp busy, try again")
+		sender := NewSMTPSender(server.dialer(), 3, time.Hour)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := sender.Send(ctx, newTestMessage("a@example.com"))
+		assert.ErrorIs(t, err, ErrDialFailed)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), server.connections.Load())
+	})
+}
+
+func TestBackoffDelay(t *testing.T) {
+	assert.Equal(t, time.Second, backoffDelay(time.Second, 1))
+	assert.Equal(t, 4*time.Second, backoffDelay(time.Second, 3))
+	// Large attempt counts stop at the cap instead of overflowing
+	assert.Equal(t, MaxBackoffDelay, backoffDelay(30*time.Second, 100))
+	assert.Equal(t, MaxBackoffDelay, backoffDelay(2*time.Hour, 1))
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	hexColor        = regexp.MustCompile(`^#([0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	functionalColor = regexp.MustCompile(`^(?i)(rgb|rgba|hsl|hsla)\(\s*[-+0-9.%a-z]+((\s*[,/]\s*|\s+)[-+0-9.%a-z]+){2,3}\s*\)$`)
+)
+
+// namedColors are the CSS named colors, plus transparent and currentcolor
+var namedColors = map[string]bool{
+	"aliceblue": true, "antiquewhite": true, "aqua": true, "aquamarine": true,
+	"azure": true, "beige": true, "bisque": true, "black": true, "blanchedalmond": true,
+	"blue": true, "blueviolet": true, "brown": true, "burlywood": true, "cadetblue": true,
+	"chartreuse": true, "chocolate": true, "coral": true, "cornflowerblue": true,
+	"cornsilk": true, "crimson": true, "cyan": true, "darkblue": true, "darkcyan": true,
+	"darkgoldenrod": true, "darkgray": true, "darkgreen": true, "darkgrey": true,
+	"darkkhaki": true, "darkmagenta": true, "darkolivegreen": true, "darkorange": true,
+	"darkorchid": true, "darkred": true, "darksalmon": true, "darkseagreen": true,
+	"darkslateblue": true, "darkslategray": true, "darkslategrey": true,
+	"darkturquoise": true, "darkviolet": true, "deeppink": true, "deepskyblue": true,
+	"dimgray": true, "dimgrey": true, "dodgerblue": true, "firebrick": true,
+	"floralwhite": true, "forestgreen": true, "fuchsia": true, "gainsboro": true,
+	"ghostwhite": true, "gold": true, "goldenrod": true, "gray": true, "green": true,
+	"greenyellow": true, "grey": true, "honeydew": true, "hotpink": true, "indianred": true,
+	"indigo": true, "ivory": true, "khaki": true, "lavender": true, "lavenderblush": true,
+	"lawngreen": true, "lemonchiffon": true, "lightblue": true, "lightcoral": true,
+	"lightcyan": true, "lightgoldenrodyellow": true, "lightgray": true, "lightgreen": true,
+	"lightgrey": true, "lightpink": true, "lightsalmon": true, "lightseagreen": true,
+	"lightskyblue": true, "lightslategray": true, "lightslategrey": true,
+	"lightsteelblue": true, "lightyellow": true, "lime": true, "limegreen": true,
+	"linen": true, "magenta": true, "maroon": true, "mediumaquamarine": true,
+	"mediumblue": true, "mediumorchid": true, "mediumpurple": true, "mediumseagreen": true,
+	"mediumslateblue": true, "mediumspringgreen": true, "mediumturquoise": true,
+	"mediumvioletred": true, "midnightblue": true, "mintcream": true, "mistyrose": true,
+	"moccasin": true, "navajowhite": true, "navy": true, "oldlace": true, "olive": true,
+	"olivedrab": true, "orange": true, "orangered": true, "orchid": true,
+	"palegoldenrod": true, "palegreen": true, "paleturquoise": true, "palevioletred": true,
+	"papayawhip": true, "peachpuff": true, "peru": true, "pink": true, "plum": true,
+	"powderblue": true, "purple": true, "rebeccapurple": true, "red": true,
+	"rosybrown": true, "royalblue": true, "saddlebrown": true, "salmon": true,
+	"sandybrown": true, "seagreen": true, "seashell": true, "sienna": true, "silver": true,
+	"skyblue": true, "slateblue": true, "slategray": true, "slategrey": true, "snow": true,
+	"springgreen": true, "steelblue": true, "tan": true, "teal": true, "thistle": true,
+	"tomato": true, "turquoise": true, "violet": true, "wheat": true, "white": true,
+	"whitesmoke": true, "yellow": true, "yellowgreen": true, "transparent": true,
+	"currentcolor": true,
+}
+
+// IsCSSColor reports whether value is a color the frontend can render: a hex color
+// (#rgb, #rgba, #rrggbb or #rrggbbaa), an rgb()/rgba()/hsl()/hsla() color or a CSS
+// named color. Names are case-insensitive, as in CSS.
+func IsCSSColor(value string) bool {
+	return hexColor.MatchString(value) ||
+		functionalColor.MatchString(value) ||
+		namedColors[strings.ToLower(value)]
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
 // Edge represents a connection between nodes
 type Edge struct {
 	ID           string      `json:"id" db:"id"`
@@ -13,6 +19,9 @@ type Edge struct {
 	Label        string      `json:"label,omitempty" db:"label"`
 	SourceHandle string      `json:"sourceHandle,omitempty" db:"source_handle"`
 	LabelStyle   *LabelStyle `json:"labelStyle,omitempty" db:"label_style"`
+	// Priority orders a node's unlabelled edges; the highest one with a valid target is followed,
+	// unless the node runs its branches in parallel, when it orders their steps
+	Priority     int         `json:"priority,omitempty" db:"priority"`
 }
 
 // EdgeStyle represents the visual style of an edge
@@ -21,8 +30,45 @@ type EdgeStyle struct {
 	StrokeWidth int    `json:"strokeWidth"`
 }
 
+// Defaults for an edge style that leaves them out
+const (
+	DefaultEdgeStroke      = "#6b7280"
+	DefaultEdgeStrokeWidth = 2
+)
+
+// ApplyDefaults fills in a missing stroke color and width
+func (s *EdgeStyle) ApplyDefaults() {
+	if s.Stroke == "" {
+		s.Stroke = DefaultEdgeStroke
+	}
+	if s.StrokeWidth == 0 {
+		s.StrokeWidth = DefaultEdgeStrokeWidth
+	}
+}
+
+// Validate checks the stroke width is at least 1 and the stroke is a CSS color.
+// Missing values are valid; ApplyDefaults fills them in.
+func (s EdgeStyle) Validate() error {
+	if s.StrokeWidth < 0 {
+		return fmt.Errorf("stroke width must be at least 1, got %d", s.StrokeWidth)
+	}
+	if s.Stroke != "" && !IsCSSColor(s.Stroke) {
+		return fmt.Errorf("stroke %q is not a hex or CSS color", s.Stroke)
+	}
+	return nil
+}
+
 // LabelStyle represents the visual style of an edge label
 type LabelStyle struct {
 	Fill       string `json:"fill,omitempty"`
 	FontWeight string `json:"fontWeight,omitempty"`
-}
\ No newline at end of file
+}
+// SortEdgesByPriority orders edges highest priority first. Among edges of equal
+// priority the later one comes first, so workflows saved before priorities existed
+// keep following their last unlabelled edge.
+func SortEdgesByPriority(edges []Edge) {
+	slices.Reverse(edges)
+	slices.SortStableFunc(edges, func(a, b Edge) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+}
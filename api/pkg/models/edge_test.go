@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestEdgeStyle_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   EdgeStyle
+		wantErr bool
+	}{
+		{"hex color", EdgeStyle{Stroke: "#10b981", StrokeWidth: 3}, false},
+		{"short hex color", EdgeStyle{Stroke: "#fff", StrokeWidth: 1}, false},
+		{"hex color with alpha", EdgeStyle{Stroke: "#10b98180", StrokeWidth: 1}, false},
+		{"named color", EdgeStyle{Stroke: "Crimson", StrokeWidth: 2}, false},
+		{"rgb color", EdgeStyle{Stroke: "rgb(16, 185, 129)", StrokeWidth: 2}, false},
+		{"space separated rgb with alpha", EdgeStyle{Stroke: "rgb(16 185 129 / 50%)", StrokeWidth: 2}, false},
+		{"hsla color", EdgeStyle{Stroke: "hsla(160, 84%, 39%, 0.5)", StrokeWidth: 2}, false},
+		{"missing values", EdgeStyle{}, false},
+		{"negative width", EdgeStyle{Stroke: "#10b981", StrokeWidth: -1}, true},
+		{"unknown color name", EdgeStyle{Stroke: "blurple", StrokeWidth: 2}, true},
+		{"hex without hash", EdgeStyle{Stroke: "10b981", StrokeWidth: 2}, true},
+		{"hex of the wrong length", EdgeStyle{Stroke: "#10b98", StrokeWidth: 2}, true},
+		{"non-hex digits", EdgeStyle{Stroke: "#zzzzzz", StrokeWidth: 2}, true},
+		{"rgb with too few channels", EdgeStyle{Stroke: "rgb(16, 185)", StrokeWidth: 2}, true},
+		{"markup", EdgeStyle{Stroke: "red;background:url(x)", StrokeWidth: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.style.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEdgeStyle_ApplyDefaults(t *testing.T) {
+	tests := []struct {
+		name  string
+		style EdgeStyle
+		want  EdgeStyle
+	}{
+		{"missing values", EdgeStyle{}, EdgeStyle{Stroke: DefaultEdgeStroke, StrokeWidth: DefaultEdgeStrokeWidth}},
+		{"missing width", EdgeStyle{Stroke: "#ef4444"}, EdgeStyle{Stroke: "#ef4444", StrokeWidth: DefaultEdgeStrokeWidth}},
+		{"missing stroke", EdgeStyle{StrokeWidth: 5}, EdgeStyle{Stroke: DefaultEdgeStroke, StrokeWidth: 5}},
+		{"complete style is kept", EdgeStyle{Stroke: "blue", StrokeWidth: 1}, EdgeStyle{Stroke: "blue", StrokeWidth: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style := tt.style
+			style.ApplyDefaults()
+			if style != tt.want {
+				t.Errorf("ApplyDefaults() = %+v, want %+v", style, tt.want)
+			}
+			if err := style.Validate(); err != nil {
+				t.Errorf("defaulted style is invalid: %v", err)
+			}
+		})
+	}
+}
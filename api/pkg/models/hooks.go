@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// HookStage is when an execution hook fires
+type HookStage string
+
+// Hook stages
+const (
+	HookStagePre  HookStage = "pre"  // before the start node runs
+	HookStagePost HookStage = "post" // once the run has finished, whatever its status
+)
+
+// HookType is how an execution hook delivers its notification
+type HookType string
+
+// Hook types
+const (
+	HookTypeWebhook HookType = "webhook" // POSTs a JSON event to URL
+	HookTypeEmail   HookType = "email"   // emails the event to To
+)
+
+// ExecutionHook notifies an outside system that a run started or finished,
+// regardless of which branch the condition takes
+type ExecutionHook struct {
+	Stage HookStage `json:"stage"`
+	Type  HookType  `json:"type"`
+	URL   string    `json:"url,omitempty"` // webhook target
+	To    string    `json:"to,omitempty"`  // email recipient
+	// FailExecution fails the run when the hook fails; otherwise failures are only logged
+	FailExecution bool `json:"failExecution,omitempty"`
+}
+
+// Validate checks the hook has a known stage and type and a target for its type
+func (h ExecutionHook) Validate() error {
+	if h.Stage != HookStagePre && h.Stage != HookStagePost {
+		return fmt.Errorf("unsupported hook stage: %q", h.Stage)
+	}
+	switch h.Type {
+	case HookTypeWebhook:
+		parsed, err := url.Parse(h.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("webhook hook requires an http or https url")
+		}
+	case HookTypeEmail:
+		if !strings.Contains(h.To, "@") {
+			return fmt.Errorf("email hook requires a recipient")
+		}
+	default:
+		return fmt.Errorf("unsupported hook type: %q", h.Type)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestExecutionHook_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    ExecutionHook
+		wantErr bool
+	}{
+		{"webhook", ExecutionHook{Stage: HookStagePre, Type: HookTypeWebhook, URL: "https://hooks.example.com/run"}, false},
+		{"email", ExecutionHook{Stage: HookStagePost, Type: HookTypeEmail, To: "ops@example.com"}, false},
+		{"unknown stage", ExecutionHook{Stage: "during", Type: HookTypeEmail, To: "ops@example.com"}, true},
+		{"unknown type", ExecutionHook{Stage: HookStagePre, Type: "sms"}, true},
+		{"webhook without url", ExecutionHook{Stage: HookStagePre, Type: HookTypeWebhook}, true},
+		{"webhook with non-http url", ExecutionHook{Stage: HookStagePre, Type: HookTypeWebhook, URL: "ftp://hooks.example.com"}, true},
+		{"email without recipient", ExecutionHook{Stage: HookStagePost, Type: HookTypeEmail}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.hook.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "maps"
+
+// OutputSchemaVersionKey is the step output key recording which shape of output the
+// node wrote
+const OutputSchemaVersionKey = "outputSchemaVersion"
+
+// OutputSchemaVersion is the current shape of node outputs. Version 2 moved the
+// condition outcome from a top-level conditionMet to conditionResult.result.
+// Increase it, and teach UpgradeOutput the change, whenever an output field that
+// readers rely on moves or changes meaning.
+const OutputSchemaVersion = 2
+
+// OutputVersion returns the schema version recorded in a step output. Outputs
+// stored before versioning have none and are treated as version 1.
+func OutputVersion(output map[string]any) int {
+	switch version := output[OutputSchemaVersionKey].(type) {
+	case int:
+		return version
+	case float64:
+		// Decoded from stored JSON
+		return int(version)
+	}
+	return 1
+}
+
+// UpgradeOutput returns a node's output in the current schema, so readers of stored
+// executions handle older shapes in one place. Upgrades go by the fields present, as
+// unversioned outputs may already have the newer shape. output isn't modified; it is
+// returned as is when it is already current.
+func UpgradeOutput(nodeType NodeType, output map[string]any) map[string]any {
+	if output == nil || OutputVersion(output) >= OutputSchemaVersion {
+		return output
+	}
+
+	upgraded := maps.Clone(output)
+	if nodeType == NodeTypeCondition {
+		if _, current := upgraded["conditionResult"]; !current {
+			if met, ok := upgraded[string(OutputKeyConditionMet)].(bool); ok {
+				upgraded["conditionResult"] = map[string]any{"result": met}
+				delete(upgraded, string(OutputKeyConditionMet))
+			}
+		}
+	}
+	upgraded[OutputSchemaVersionKey] = OutputSchemaVersion
+	return upgraded
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUpgradeOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodeType NodeType
+		output   map[string]any
+		expected map[string]any
+	}{
+		{
+			name:     "v1 condition output",
+			nodeType: NodeTypeCondition,
+			output:   map[string]any{"message": "Temperature 31.0°C is greater than 25.0°C - condition met", "conditionMet": true},
+			expected: map[string]any{
+				"message":              "Temperature 31.0°C is greater than 25.0°C - condition met",
+				"conditionResult":      map[string]any{"result": true},
+				OutputSchemaVersionKey: OutputSchemaVersion,
+			},
+		},
+		{
+			name:     "unversioned output already in the current shape",
+			nodeType: NodeTypeCondition,
+			output:   map[string]any{"conditionResult": map[string]any{"result": false, "temperature": 12.0}},
+			expected: map[string]any{
+				"conditionResult":      map[string]any{"result": false, "temperature": 12.0},
+				OutputSchemaVersionKey: OutputSchemaVersion,
+			},
+		},
+		{
+			name:     "conditionMet on another node type is left alone",
+			nodeType: NodeTypeForm,
+			output:   map[string]any{"conditionMet": true},
+			expected: map[string]any{"conditionMet": true, OutputSchemaVersionKey: OutputSchemaVersion},
+		},
+		{
+			name:     "current output",
+			nodeType: NodeTypeCondition,
+			output:   map[string]any{"conditionResult": map[string]any{"result": true}, OutputSchemaVersionKey: OutputSchemaVersion},
+			expected: map[string]any{"conditionResult": map[string]any{"result": true}, OutputSchemaVersionKey: OutputSchemaVersion},
+		},
+		{
+			name:     "no output",
+			nodeType: NodeTypeStart,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upgraded := UpgradeOutput(tt.nodeType, tt.output)
+			if !reflect.DeepEqual(tt.expected, upgraded) {
+				t.Errorf("expected %v, got %v", tt.expected, upgraded)
+			}
+		})
+	}
+}
+
+func TestUpgradeOutputLeavesStoredOutputAlone(t *testing.T) {
+	stored := map[string]any{"conditionMet": false}
+	UpgradeOutput(NodeTypeCondition, stored)
+
+	if !reflect.DeepEqual(map[string]any{"conditionMet": false}, stored) {
+		t.Errorf("stored output was modified: %v", stored)
+	}
+}
+
+func TestOutputVersion(t *testing.T) {
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(`{"outputSchemaVersion": 2}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		output   map[string]any
+		expected int
+	}{
+		{"unversioned", map[string]any{"message": "ok"}, 1},
+		{"recorded by the engine", map[string]any{OutputSchemaVersionKey: 2}, 2},
+		{"decoded from storage", decoded, 2},
+		{"nil", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if version := OutputVersion(tt.output); version != tt.expected {
+				t.Errorf("expected version %d, got %d", tt.expected, version)
+			}
+		})
+	}
+}
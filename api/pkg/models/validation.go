@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// EmailCheck is how strictly an input email address is checked
+type EmailCheck string
+
+const (
+	// EmailCheckBasic only requires an "@" and a "."
+	EmailCheckBasic EmailCheck = "basic"
+	// EmailCheckStrict requires a bare RFC 5322 address whose domain has a dot
+	EmailCheckStrict EmailCheck = "strict"
+)
+
+// InputValidationProfile sets how strictly WorkflowInput.Validate checks execution
+// input, so deployments can relax or tighten the rules, such as allowing negative
+// thresholds for specialized sensors. The operator and condition field are always
+// checked.
+type InputValidationProfile struct {
+	// MinThreshold and MaxThreshold bound the threshold, inclusive
+	MinThreshold float64 `json:"minThreshold"`
+	MaxThreshold float64 `json:"maxThreshold"`
+	// RequireName, RequireEmail and RequireCity reject input missing the field. A
+	// field that is given is still checked.
+	RequireName  bool       `json:"requireName"`
+	RequireEmail bool       `json:"requireEmail"`
+	RequireCity  bool       `json:"requireCity"`
+	EmailCheck   EmailCheck `json:"emailCheck"`
+	// MaxNameLength is the longest name accepted, in characters. The name is
+	// recorded as the execution's triggeredBy and rendered into email bodies.
+	MaxNameLength int `json:"maxNameLength"`
+}
+
+// DefaultInputValidationProfile returns the rules Validate applies unless
+// InputValidation is changed: every field is required, the threshold must be
+// between 0 and 100, emails get the basic check and names are at most
+// DefaultMaxNameLength characters
+func DefaultInputValidationProfile() InputValidationProfile {
+	return InputValidationProfile{
+		MinThreshold:  0,
+		MaxThreshold:  100,
+		RequireName:   true,
+		RequireEmail:  true,
+		RequireCity:   true,
+		EmailCheck:    EmailCheckBasic,
+		MaxNameLength: DefaultMaxNameLength,
+	}
+}
+
+// InputValidation is the profile WorkflowInput.Validate applies. Set it before any
+// workflow runs.
+var InputValidation = DefaultInputValidationProfile()
+
+// Validate checks that the profile itself is usable
+func (p InputValidationProfile) Validate() error {
+	if p.MinThreshold > p.MaxThreshold {
+		return fmt.Errorf("minThreshold %g is above maxThreshold %g", p.MinThreshold, p.MaxThreshold)
+	}
+	switch p.EmailCheck {
+	case EmailCheckBasic, EmailCheckStrict:
+	default:
+		return fmt.Errorf("unknown emailCheck %q (valid: basic, strict)", p.EmailCheck)
+	}
+	if p.MaxNameLength <= 0 {
+		return fmt.Errorf("maxNameLength must be positive, got %d", p.MaxNameLength)
+	}
+	return nil
+}
+
+// DefaultMaxNameLength is the default profile's MaxNameLength
+const DefaultMaxNameLength = 100
+
+// checkEmail reports whether email passes the profile's email check
+func (p InputValidationProfile) checkEmail(email string) bool {
+	if p.EmailCheck == EmailCheckStrict {
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Address != email || address.Name != "" {
+			return false
+		}
+		_, domain, _ := strings.Cut(email, "@")
+		return strings.Contains(strings.Trim(domain, "."), ".")
+	}
+	return strings.Contains(email, "@") && strings.Contains(email, ".")
+}
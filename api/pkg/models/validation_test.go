@@ -0,0 +1,102 @@
+package models
+
+import "testing"
+
+func TestWorkflowInput_ValidateWithProfiles(t *testing.T) {
+	valid := WorkflowInput{
+		Name:      "John Doe",
+		Email:     "john@example.com",
+		City:      "Sydney",
+		Operator:  OperatorGreaterThan,
+		Threshold: 20,
+	}
+	withInput := func(change func(*WorkflowInput)) WorkflowInput {
+		input := valid
+		change(&input)
+		return input
+	}
+	sensors := DefaultInputValidationProfile()
+	sensors.MinThreshold = -50
+	sensors.MaxThreshold = 150
+	optionalEmail := DefaultInputValidationProfile()
+	optionalEmail.RequireEmail = false
+	strictEmail := DefaultInputValidationProfile()
+	strictEmail.EmailCheck = EmailCheckStrict
+	shortNames := DefaultInputValidationProfile()
+	shortNames.MaxNameLength = 4
+
+	tests := []struct {
+		name    string
+		input   WorkflowInput
+		profile InputValidationProfile
+		wantErr bool
+	}{
+		{"default accepts valid input", valid, DefaultInputValidationProfile(), false},
+		{"default rejects negative threshold", withInput(func(i *WorkflowInput) { i.Threshold = -10 }), DefaultInputValidationProfile(), true},
+		{"default accepts the bounds", withInput(func(i *WorkflowInput) { i.Threshold = 100 }), DefaultInputValidationProfile(), false},
+		{"default rejects threshold above 100", withInput(func(i *WorkflowInput) { i.Threshold = 100.5 }), DefaultInputValidationProfile(), true},
+		{"sensor profile accepts negative threshold", withInput(func(i *WorkflowInput) { i.Threshold = -10 }), sensors, false},
+		{"sensor profile accepts threshold above 100", withInput(func(i *WorkflowInput) { i.Threshold = 120 }), sensors, false},
+		{"sensor profile still has a floor", withInput(func(i *WorkflowInput) { i.Threshold = -60 }), sensors, true},
+		{"default requires email", withInput(func(i *WorkflowInput) { i.Email = "" }), DefaultInputValidationProfile(), true},
+		{"email can be optional", withInput(func(i *WorkflowInput) { i.Email = "" }), optionalEmail, false},
+		{"optional email is still checked", withInput(func(i *WorkflowInput) { i.Email = "invalid-email" }), optionalEmail, true},
+		{"basic check accepts loose address", withInput(func(i *WorkflowInput) { i.Email = "John <john@example.com>" }), DefaultInputValidationProfile(), false},
+		{"strict check rejects display name", withInput(func(i *WorkflowInput) { i.Email = "John <john@example.com>" }), strictEmail, true},
+		{"strict check rejects undotted domain", withInput(func(i *WorkflowInput) { i.Email = "john.doe@localhost" }), strictEmail, true},
+		{"strict check accepts plain address", valid, strictEmail, false},
+		{"short name limit rejects longer names", valid, shortNames, true},
+		{"short name limit accepts names within it", withInput(func(i *WorkflowInput) { i.Name = "John" }), shortNames, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			err := input.ValidateWith(tt.profile)
+			if tt.wantErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWorkflowInput_ValidateUsesInputValidation(t *testing.T) {
+	defer func(profile InputValidationProfile) { InputValidation = profile }(InputValidation)
+	input := WorkflowInput{Name: "John Doe", Email: "john@example.com", City: "Sydney", Operator: OperatorLessThan, Threshold: -10}
+
+	if err := input.Validate(); err == nil {
+		t.Error("expected the default profile to reject a negative threshold")
+	}
+	InputValidation.MinThreshold = -40
+	if err := input.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInputValidationProfile_Validate(t *testing.T) {
+	if err := DefaultInputValidationProfile().Validate(); err != nil {
+		t.Errorf("default profile is invalid: %v", err)
+	}
+
+	inverted := DefaultInputValidationProfile()
+	inverted.MinThreshold = 50
+	inverted.MaxThreshold = 10
+	if err := inverted.Validate(); err == nil {
+		t.Error("expected error for minThreshold above maxThreshold")
+	}
+
+	unknownCheck := DefaultInputValidationProfile()
+	unknownCheck.EmailCheck = "paranoid"
+	if err := unknownCheck.Validate(); err == nil {
+		t.Error("expected error for unknown emailCheck")
+	}
+
+	noNames := DefaultInputValidationProfile()
+	noNames.MaxNameLength = 0
+	if err := noNames.Validate(); err == nil {
+		t.Error("expected error for a non-positive maxNameLength")
+	}
+}
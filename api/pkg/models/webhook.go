@@ -0,0 +1,24 @@
+package models
+
+// WebhookConfig lets external systems trigger a workflow with a signed request
+type WebhookConfig struct {
+	// Secret is the HMAC-SHA256 key callers sign request bodies with
+	Secret string `json:"secret,omitempty"`
+	// Mapping maps WorkflowInput JSON fields to dotted paths in the webhook
+	// payload, e.g. {"city": "location.name"}. Without a mapping the payload
+	// is read as a WorkflowInput as it is.
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+// WithoutSecrets returns a copy of the workflow that is safe to return to clients,
+// with the webhook secret removed
+func (w *Workflow) WithoutSecrets() *Workflow {
+	if w.Webhook == nil || w.Webhook.Secret == "" {
+		return w
+	}
+	redacted := *w
+	webhook := *w.Webhook
+	webhook.Secret = ""
+	redacted.Webhook = &webhook
+	return &redacted
+}
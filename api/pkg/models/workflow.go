@@ -1,11 +1,16 @@
 package models
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // NodeType represents the type of a workflow node
@@ -19,6 +24,8 @@ const (
 	NodeTypeCondition   NodeType = "condition"
 	NodeTypeEmail       NodeType = "email"
 	NodeTypeEnd         NodeType = "end"
+	NodeTypeState       NodeType = "state"
+	NodeTypeCheckpoint  NodeType = "checkpoint"
 )
 
 // ValidNodeTypes is a map of valid node types
@@ -29,6 +36,8 @@ var ValidNodeTypes = map[NodeType]bool{
 	NodeTypeCondition:   true,
 	NodeTypeEmail:       true,
 	NodeTypeEnd:         true,
+	NodeTypeState:       true,
+	NodeTypeCheckpoint:  true,
 }
 
 // Operator represents the type of comparison operator
@@ -41,6 +50,7 @@ const (
 	OperatorEquals            Operator = "equals"
 	OperatorGreaterThanOrEqual Operator = "greater_than_or_equal"
 	OperatorLessThanOrEqual   Operator = "less_than_or_equal"
+	OperatorNotEquals         Operator = "not_equals"
 )
 
 // ValidOperators is a map of valid operators
@@ -50,6 +60,7 @@ var ValidOperators = map[Operator]bool{
 	OperatorEquals:            true,
 	OperatorGreaterThanOrEqual: true,
 	OperatorLessThanOrEqual:   true,
+	OperatorNotEquals:         true,
 }
 
 // Status represents the status of a workflow execution or step
@@ -60,6 +71,12 @@ const (
 	StatusCompleted Status = "completed"
 	StatusFailed    Status = "failed"
 	StatusRunning   Status = "running"
+	// StatusPartial is a run that reached its end node but had a continueOnError
+	// node fail or a notification suppressed along the way
+	StatusPartial Status = "partial"
+	// StatusQueued is a step whose work was queued to finish after the run, such as
+	// an email queued for retry. Runs are never queued.
+	StatusQueued Status = "queued"
 )
 
 // ValidStatuses is a map of valid status values
@@ -67,17 +84,23 @@ var ValidStatuses = map[Status]bool{
 	StatusCompleted: true,
 	StatusFailed:    true,
 	StatusRunning:   true,
+	StatusPartial:   true,
 }
 
 // Workflow represents a workflow definition in the database
 type Workflow struct {
-	ID         string    `json:"id" db:"id"`
-	Name       string    `json:"name" db:"name"`
-	Version    int       `json:"version" db:"version"`
-	Nodes      []Node    `json:"nodes"`
-	Edges      []Edge    `json:"edges"`
-	CreatedAt  time.Time `json:"-" db:"created_at"`
-	UpdatedAt  time.Time `json:"-" db:"updated_at"`
+	ID           string         `json:"id" db:"id"`
+	Name         string         `json:"name" db:"name"`
+	Version      int            `json:"version" db:"version"`
+	Nodes        []Node         `json:"nodes"`
+	Edges        []Edge         `json:"edges"`
+	DefaultInput *WorkflowInput `json:"defaultInput,omitempty" db:"default_input"` // Fills fields missing from execution input
+	Tags         map[string]string `json:"tags,omitempty" db:"tags"`                // Key-value labels for organizing workflows
+	Webhook      *WebhookConfig `json:"webhook,omitempty" db:"webhook"`                // Signed webhook trigger; disabled when nil
+	Hooks        []ExecutionHook `json:"hooks,omitempty" db:"hooks"`                 // Notifications fired before and after each run
+	MaxDurationMs *int64        `json:"maxDurationMs,omitempty" db:"max_duration_ms"` // Expected longest run; longer runs are flagged slaBreached
+	CreatedAt    time.Time      `json:"-" db:"created_at"`
+	UpdatedAt    time.Time      `json:"-" db:"updated_at"`
 }
 
 // WorkflowExecution represents the execution of a workflow
@@ -90,11 +113,42 @@ type WorkflowExecution struct {
 	TotalDuration int64          `json:"totalDuration,omitempty" db:"total_duration"`
 	Steps         []ExecutionStep `json:"steps" db:"-"`
 	Metadata      JSONB          `json:"metadata,omitempty" db:"metadata"`
+	InputHash     string         `json:"inputHash,omitempty" db:"input_hash"` // WorkflowInput.Hash of the triggering input
 	ExecutedAt    time.Time      `json:"-" db:"executed_at"` // Kept for internal use
+	// Millisecond timestamps separating time waiting to run from time running; nil on
+	// executions recorded before they were tracked
+	EnqueuedAt *time.Time `json:"enqueuedAt,omitempty" db:"enqueued_at"` // when the service accepted the request
+	StartedAt  *time.Time `json:"startedAt,omitempty" db:"started_at"`   // when the engine began the run
+	EndedAt    *time.Time `json:"endedAt,omitempty" db:"ended_at"`       // when the run finished
+	// Input is the triggering input with defaults applied, as returned by WorkflowInput.Redacted;
+	// nil on executions recorded before it was stored
+	Input *WorkflowInput `json:"input,omitempty" db:"input"`
+	// ErrorSummary says why a failed run failed; nil unless Status is failed
+	ErrorSummary *ExecutionErrorSummary `json:"errorSummary,omitempty" db:"error_summary"`
+	// Explanation collects each step's explanation on runs made in explain mode. It is
+	// returned with the run only; the step outputs it is built from are stored as usual
+	Explanation []StepExplanation `json:"explanation,omitempty" db:"-"`
+}
+
+// ExecutionErrorSummary names the node a run failed at and why. NodeID and NodeType
+// are empty when the run failed outside any node, e.g. in a hook.
+type ExecutionErrorSummary struct {
+	NodeID   string   `json:"nodeId,omitempty"`
+	NodeType NodeType `json:"nodeType,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// StepExplanation is a human-readable account of what one step did and why
+type StepExplanation struct {
+	StepNumber  int      `json:"stepNumber"`
+	NodeID      string   `json:"nodeId"`
+	NodeType    NodeType `json:"nodeType"`
+	Explanation string   `json:"explanation"`
 }
 
 // ExecutionStep represents a single step in the workflow execution
 type ExecutionStep struct {
+	ExecutionID string    `json:"-" db:"execution_id"`
 	NodeID      string    `json:"-" db:"node_id"`
 	StepNumber  int       `json:"stepNumber" db:"step_number"`
 	NodeType    NodeType  `json:"nodeType" db:"node_type"`  // Changed from Type
@@ -116,36 +170,175 @@ type WorkflowInput struct {
 	City      string   `json:"city"`
 	Threshold float64  `json:"threshold"`
 	Operator  Operator `json:"operator"`
+	Field     string   `json:"field,omitempty"` // Weather field conditions compare; overrides the node's conditionField
 	Workflow  JSONB    `json:"workflow"`
+	// CallbackURL receives the execution result once the run has finished
+	CallbackURL string `json:"callbackUrl,omitempty"`
+
+	// providedFields records which JSON fields were present when the input was decoded
+	providedFields map[string]bool
+}
+
+// UnmarshalJSON decodes the input and records which fields were provided
+func (w *WorkflowInput) UnmarshalJSON(data []byte) error {
+	type workflowInputAlias WorkflowInput
+	var decoded workflowInputAlias
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*w = WorkflowInput(decoded)
+	w.providedFields = make(map[string]bool, len(raw))
+	for key := range raw {
+		w.providedFields[key] = true
+	}
+	return nil
+}
+
+// isProvided reports whether a field was supplied. Inputs that were not decoded
+// from JSON fall back to treating zero values as missing.
+func (w *WorkflowInput) isProvided(field string, isZero bool) bool {
+	if w.providedFields != nil {
+		return w.providedFields[field]
+	}
+	return !isZero
+}
+
+// ApplyDefaults fills fields missing from the input with values from defaults.
+// Fields present in the input always take precedence.
+func (w *WorkflowInput) ApplyDefaults(defaults WorkflowInput) {
+	if !w.isProvided("name", w.Name == "") {
+		w.Name = defaults.Name
+	}
+	if !w.isProvided("email", w.Email == "") {
+		w.Email = defaults.Email
+	}
+	if !w.isProvided("city", w.City == "") {
+		w.City = defaults.City
+	}
+	if !w.isProvided("threshold", w.Threshold == 0) {
+		w.Threshold = defaults.Threshold
+	}
+	if !w.isProvided("operator", w.Operator == "") {
+		w.Operator = defaults.Operator
+	}
+	if !w.isProvided("field", w.Field == "") {
+		w.Field = defaults.Field
+	}
+}
+
+// Hash returns a hex SHA-256 digest of the normalized input, so triggers that
+// differ only in letter case of the email or surrounding whitespace hash alike.
+// The attached workflow definition is not part of the input and is excluded.
+func (w *WorkflowInput) Hash() string {
+	// Map keys are marshaled in sorted order, keeping the encoding stable
+	fields := map[string]any{
+		"name":      strings.TrimSpace(w.Name),
+		"email":     strings.ToLower(strings.TrimSpace(w.Email)),
+		"city":      strings.TrimSpace(w.City),
+		"threshold": w.Threshold,
+		"operator":  w.Operator,
+	}
+	// Only included when set, so hashes of inputs without a field are unchanged
+	if w.Field != "" {
+		fields["field"] = w.Field
+	}
+	normalized, _ := json.Marshal(fields)
+	sum := sha256.Sum256(normalized)
+	return hex.EncodeToString(sum[:])
+}
+
+// Redacted returns a copy of the input that is safe to store with an execution: the
+// email address is masked, the callback URL, which may carry a token, is dropped, and
+// so is the embedded workflow definition, which can be large and is stored with the
+// workflow itself
+func (w *WorkflowInput) Redacted() WorkflowInput {
+	redacted := *w
+	redacted.Email = MaskEmail(w.Email)
+	redacted.Workflow = nil
+	redacted.CallbackURL = ""
+	redacted.providedFields = nil
+	return redacted
+}
+
+// MaskEmail hides all but the first character of an address's local part, e.g.
+// "j***@example.com", so a stored input shows which domain was alerted but not who
+func MaskEmail(email string) string {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ""
+	}
+	local, domain, found := strings.Cut(email, "@")
+	if !found || local == "" {
+		return "***"
+	}
+	first, _ := utf8.DecodeRuneInString(local)
+	return string(first) + "***@" + domain
 }
 
-// Validate validates the workflow input
+// Validate validates the workflow input against the InputValidation profile. It
+// strips control characters and surrounding whitespace from the name before checking it.
 func (w *WorkflowInput) Validate() error {
-	if w.Name == "" {
+	return w.ValidateWith(InputValidation)
+}
+
+// ValidateWith validates the workflow input against the given profile
+func (w *WorkflowInput) ValidateWith(profile InputValidationProfile) error {
+	w.Name = sanitizeName(w.Name)
+	if w.Name == "" && profile.RequireName {
 		return fmt.Errorf("name is required")
 	}
-	if w.Email == "" {
+	if utf8.RuneCountInString(w.Name) > profile.MaxNameLength {
+		return fmt.Errorf("name must be at most %d characters", profile.MaxNameLength)
+	}
+	if w.Email == "" && profile.RequireEmail {
 		return fmt.Errorf("email is required")
 	}
-	// Basic email validation
-	if !strings.Contains(w.Email, "@") || !strings.Contains(w.Email, ".") {
+	if w.Email != "" && !profile.checkEmail(w.Email) {
 		return fmt.Errorf("invalid email format")
 	}
-	if w.City == "" {
+	if w.City == "" && profile.RequireCity {
 		return fmt.Errorf("city is required")
 	}
 	if !ValidOperators[w.Operator] {
 		return fmt.Errorf("invalid operator: %s", w.Operator)
 	}
-	if w.Threshold < 0 {
-		return fmt.Errorf("temperature cannot be negative")
+	if w.Field != "" && !ValidConditionFields[w.Field] {
+		return fmt.Errorf("invalid field: %s", w.Field)
 	}
-	if w.Threshold > 100 {
-		return fmt.Errorf("temperature must be below 100°C")
+	if w.Threshold < profile.MinThreshold {
+		return fmt.Errorf("threshold must be at least %g", profile.MinThreshold)
+	}
+	if w.Threshold > profile.MaxThreshold {
+		return fmt.Errorf("threshold must be at most %g", profile.MaxThreshold)
 	}
 	return nil
 }
 
+// sanitizeName drops control characters such as newlines and escape codes, keeping
+// letters from any script
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	return strings.TrimSpace(name)
+}
+
+// ValidConditionFields are the weather output fields a condition can compare
+var ValidConditionFields = map[string]bool{
+	string(OutputKeyTemperature): true,
+	string(OutputKeyWindspeed):   true,
+	string(OutputKeyHumidity):    true,
+}
+
 // JSONB is a custom type for handling JSONB data
 type JSONB map[string]any
 
@@ -182,6 +375,111 @@ func (o Operator) IsValid() bool {
 	return ok
 }
 
+// operatorSymbols maps every valid Operator to its mathematical symbol
+var operatorSymbols = map[Operator]string{
+	OperatorGreaterThan:        ">",
+	OperatorLessThan:           "<",
+	OperatorEquals:             "=",
+	OperatorGreaterThanOrEqual: "≥",
+	OperatorLessThanOrEqual:    "≤",
+	OperatorNotEquals:          "≠",
+}
+
+// Symbol returns the mathematical symbol for the Operator, or an empty string if it is not valid
+func (o Operator) Symbol() string {
+	return operatorSymbols[o]
+}
+
+// LookupSymbol returns the mathematical symbol for the Operator, or an error
+// when it has none so callers never render a guessed symbol
+func (o Operator) LookupSymbol() (string, error) {
+	symbol, ok := operatorSymbols[o]
+	if !ok {
+		return "", fmt.Errorf("unsupported operator: %q", o)
+	}
+	return symbol, nil
+}
+
+// OperatorAliases maps other spellings clients use to the canonical Operator.
+// Matching ignores letter case and surrounding whitespace.
+var OperatorAliases = map[string]Operator{
+	"gt":  OperatorGreaterThan,
+	">":   OperatorGreaterThan,
+	"lt":  OperatorLessThan,
+	"<":   OperatorLessThan,
+	"eq":  OperatorEquals,
+	"=":   OperatorEquals,
+	"==":  OperatorEquals,
+	"gte": OperatorGreaterThanOrEqual,
+	">=":  OperatorGreaterThanOrEqual,
+	"≥":   OperatorGreaterThanOrEqual,
+	"lte": OperatorLessThanOrEqual,
+	"<=":  OperatorLessThanOrEqual,
+	"≤":   OperatorLessThanOrEqual,
+	"ne":  OperatorNotEquals,
+	"neq": OperatorNotEquals,
+	"!=":  OperatorNotEquals,
+	"≠":   OperatorNotEquals,
+}
+
+// NormalizeOperator returns the canonical Operator named by value, which may be a
+// canonical name or an alias, and false when it is neither
+func NormalizeOperator(value string) (Operator, bool) {
+	key := strings.ToLower(strings.TrimSpace(value))
+	if ValidOperators[Operator(key)] {
+		return Operator(key), true
+	}
+	operator, ok := OperatorAliases[key]
+	return operator, ok
+}
+
+// Aliases returns the alternative spellings accepted for the Operator, sorted
+func (o Operator) Aliases() []string {
+	aliases := []string{}
+	for alias, operator := range OperatorAliases {
+		if operator == o {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// UnmarshalJSON decodes an operator, replacing aliases with the canonical name.
+// Unknown operators are kept as sent so validation can report them.
+func (o *Operator) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if operator, ok := NormalizeOperator(value); ok {
+		*o = operator
+		return nil
+	}
+	*o = Operator(value)
+	return nil
+}
+
+// Describe returns a human readable description of the Operator
+func (o Operator) Describe() string {
+	switch o {
+	case OperatorGreaterThan:
+		return "Greater than"
+	case OperatorLessThan:
+		return "Less than"
+	case OperatorEquals:
+		return "Equal to"
+	case OperatorGreaterThanOrEqual:
+		return "Greater than or equal to"
+	case OperatorLessThanOrEqual:
+		return "Less than or equal to"
+	case OperatorNotEquals:
+		return "Not equal to"
+	default:
+		return "Unknown operator"
+	}
+}
+
 // NodeData represents the data associated with a node
 type NodeData struct {
 	Label       string         `json:"label"`
@@ -217,22 +515,34 @@ type OutputKey string
 
 // Valid output keys
 const (
-	OutputKeyName         OutputKey = "name"
-	OutputKeyEmail        OutputKey = "email"
-	OutputKeyCity         OutputKey = "city"
-	OutputKeyTemperature  OutputKey = "temperature"
-	OutputKeyLocation     OutputKey = "location"
-	OutputKeyConditionMet OutputKey = "conditionMet"
-	OutputKeyError        OutputKey = "error"
+	OutputKeyName               OutputKey = "name"
+	OutputKeyEmail              OutputKey = "email"
+	OutputKeyCity               OutputKey = "city"
+	OutputKeyTemperature        OutputKey = "temperature"
+	OutputKeyLocation           OutputKey = "location"
+	OutputKeyWindspeed          OutputKey = "windspeed"
+	OutputKeyWindMessage        OutputKey = "windMessage"
+	OutputKeyHumidity           OutputKey = "humidity"
+	OutputKeyTime               OutputKey = "time"
+	OutputKeyWeatherCode        OutputKey = "weathercode"
+	OutputKeyWeatherDescription OutputKey = "weatherDescription"
+	OutputKeyConditionMet       OutputKey = "conditionMet"
+	OutputKeyError              OutputKey = "error"
 )
 
 // ValidOutputKeys is a map of valid output keys
 var ValidOutputKeys = map[OutputKey]bool{
-	OutputKeyName:         true,
-	OutputKeyEmail:        true,
-	OutputKeyCity:         true,
-	OutputKeyTemperature:  true,
-	OutputKeyLocation:     true,
-	OutputKeyConditionMet: true,
-	OutputKeyError:        true,
+	OutputKeyName:               true,
+	OutputKeyEmail:              true,
+	OutputKeyCity:               true,
+	OutputKeyTemperature:        true,
+	OutputKeyLocation:           true,
+	OutputKeyWindspeed:          true,
+	OutputKeyWindMessage:        true,
+	OutputKeyHumidity:           true,
+	OutputKeyTime:               true,
+	OutputKeyWeatherCode:        true,
+	OutputKeyWeatherDescription: true,
+	OutputKeyConditionMet:       true,
+	OutputKeyError:              true,
 }
\ No newline at end of file
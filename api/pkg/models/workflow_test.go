@@ -1,7 +1,10 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -72,6 +75,30 @@ func TestWorkflowInput_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "windspeed field",
+			input: WorkflowInput{
+				Name:      "John Doe",
+				Email:     "john@example.com",
+				City:      "Sydney",
+				Operator:  OperatorGreaterThan,
+				Threshold: 20,
+				Field:     "windspeed",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown field",
+			input: WorkflowInput{
+				Name:      "John Doe",
+				Email:     "john@example.com",
+				City:      "Sydney",
+				Operator:  OperatorGreaterThan,
+				Threshold: 20,
+				Field:     "pressure",
+			},
+			wantErr: true,
+		},
 		{
 			name: "negative threshold",
 			input: WorkflowInput{
@@ -101,6 +128,194 @@ func TestWorkflowInput_Validate(t *testing.T) {
 	}
 }
 
+func TestWorkflowInput_ValidateName(t *testing.T) {
+	newInput := func(name string) WorkflowInput {
+		return WorkflowInput{
+			Name:      name,
+			Email:     "john@example.com",
+			City:      "Sydney",
+			Operator:  OperatorGreaterThan,
+			Threshold: 20,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  string
+	}{
+		{name: "plain name", input: "John Doe", expected: "John Doe"},
+		{name: "unicode name", input: "Zoë Łukasz 山田太郎", expected: "Zoë Łukasz 山田太郎"},
+		{name: "control characters stripped", input: "John\x00 Doe\r\n\x1b[31m", expected: "John Doe[31m"},
+		{name: "surrounding whitespace trimmed", input: "  John Doe\t", expected: "John Doe"},
+		{name: "only control characters", input: "\x07\x1b\n", wantErr: "name is required"},
+		{name: "at the limit", input: strings.Repeat("山", DefaultMaxNameLength), expected: strings.Repeat("山", DefaultMaxNameLength)},
+		{name: "too long", input: strings.Repeat("a", DefaultMaxNameLength+1), wantErr: fmt.Sprintf("name must be at most %d characters", DefaultMaxNameLength)},
+		{name: "too long before stripping only", input: strings.Repeat("a", DefaultMaxNameLength) + "\n\n", expected: strings.Repeat("a", DefaultMaxNameLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := newInput(tt.input)
+			err := input.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Validate() = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if input.Name != tt.expected {
+				t.Errorf("Name = %q, want %q", input.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWorkflowInput_Hash(t *testing.T) {
+	input := WorkflowInput{Name: "Alice", Email: "alice@example.com", City: "Sydney", Threshold: 25, Operator: OperatorGreaterThan}
+	hash := input.Hash()
+	if len(hash) != 64 {
+		t.Fatalf("Hash() = %q, want 64 hex characters", hash)
+	}
+
+	equivalent := WorkflowInput{Name: " Alice ", Email: "Alice@Example.COM", City: "Sydney", Threshold: 25, Operator: OperatorGreaterThan, Workflow: JSONB{"id": "ignored"}}
+	if got := equivalent.Hash(); got != hash {
+		t.Errorf("normalized input should hash alike: got %s, want %s", got, hash)
+	}
+
+	different := input
+	different.Threshold = 26
+	if different.Hash() == hash {
+		t.Error("inputs with different thresholds should not hash alike")
+	}
+
+	windInput := input
+	windInput.Field = "windspeed"
+	if windInput.Hash() == hash {
+		t.Error("inputs comparing different fields should not hash alike")
+	}
+}
+
+func TestWorkflowInput_Redacted(t *testing.T) {
+	input := WorkflowInput{Name: "Alice", Email: "alice@example.com", City: "Sydney", Threshold: 25, Operator: OperatorGreaterThan, Field: "windspeed", Workflow: JSONB{"id": "embedded"}, CallbackURL: "https://hooks.example.com/done?token=secret"}
+	redacted := input.Redacted()
+
+	want := WorkflowInput{Name: "Alice", Email: "a***@example.com", City: "Sydney", Threshold: 25, Operator: OperatorGreaterThan, Field: "windspeed"}
+	if !reflect.DeepEqual(redacted, want) {
+		t.Errorf("Redacted() = %+v, want %+v", redacted, want)
+	}
+	if input.Email != "alice@example.com" || input.Workflow == nil {
+		t.Error("Redacted() must not modify the original input")
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := map[string]string{
+		"alice@example.com":  "a***@example.com",
+		" Émile@example.fr ": "É***@example.fr",
+		"x@example.com":      "x***@example.com",
+		"not-an-email":       "***",
+		"@example.com":       "***",
+		"":                   "",
+	}
+	for email, want := range tests {
+		if got := MaskEmail(email); got != want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", email, got, want)
+		}
+	}
+}
+
+func TestWorkflowInput_ApplyDefaults(t *testing.T) {
+	defaults := WorkflowInput{
+		Name:      "Default User",
+		Email:     "default@example.com",
+		City:      "Sydney",
+		Threshold: 15,
+		Operator:  OperatorLessThan,
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected WorkflowInput
+	}{
+		{
+			name: "empty request uses all defaults",
+			body: `{}`,
+			expected: WorkflowInput{
+				Name:      "Default User",
+				Email:     "default@example.com",
+				City:      "Sydney",
+				Threshold: 15,
+				Operator:  OperatorLessThan,
+			},
+		},
+		{
+			name: "request fields take precedence",
+			body: `{"city":"Melbourne","operator":"greater_than"}`,
+			expected: WorkflowInput{
+				Name:      "Default User",
+				Email:     "default@example.com",
+				City:      "Melbourne",
+				Threshold: 15,
+				Operator:  OperatorGreaterThan,
+			},
+		},
+		{
+			name: "explicit zero threshold is kept",
+			body: `{"threshold":0}`,
+			expected: WorkflowInput{
+				Name:      "Default User",
+				Email:     "default@example.com",
+				City:      "Sydney",
+				Threshold: 0,
+				Operator:  OperatorLessThan,
+			},
+		},
+		{
+			name: "full request ignores defaults",
+			body: `{"name":"John","email":"john@example.com","city":"Perth","threshold":30,"operator":"equals"}`,
+			expected: WorkflowInput{
+				Name:      "John",
+				Email:     "john@example.com",
+				City:      "Perth",
+				Threshold: 30,
+				Operator:  OperatorEquals,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input WorkflowInput
+			if err := json.Unmarshal([]byte(tt.body), &input); err != nil {
+				t.Fatalf("failed to decode input: %v", err)
+			}
+			input.ApplyDefaults(defaults)
+
+			if input.Name != tt.expected.Name ||
+				input.Email != tt.expected.Email ||
+				input.City != tt.expected.City ||
+				input.Threshold != tt.expected.Threshold ||
+				input.Operator != tt.expected.Operator {
+				t.Errorf("ApplyDefaults() = %+v, want %+v", input, tt.expected)
+			}
+		})
+	}
+
+	t.Run("input not decoded from JSON treats zero values as missing", func(t *testing.T) {
+		input := WorkflowInput{City: "Brisbane"}
+		input.ApplyDefaults(defaults)
+		if input.City != "Brisbane" || input.Name != "Default User" || input.Threshold != 15 {
+			t.Errorf("ApplyDefaults() = %+v", input)
+		}
+	})
+}
+
 func TestNodeType_IsValid(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -137,6 +352,11 @@ func TestNodeType_IsValid(t *testing.T) {
 			nodeType: NodeTypeEnd,
 			want:     true,
 		},
+		{
+			name:     "valid state node",
+			nodeType: NodeTypeState,
+			want:     true,
+		},
 		{
 			name:     "invalid node type",
 			nodeType: "invalid_type",
@@ -200,6 +420,135 @@ func TestOperator_IsValid(t *testing.T) {
 	}
 }
 
+func TestOperator_SymbolAndDescribe(t *testing.T) {
+	tests := []struct {
+		operator    Operator
+		symbol      string
+		description string
+	}{
+		{OperatorGreaterThan, ">", "Greater than"},
+		{OperatorLessThan, "<", "Less than"},
+		{OperatorEquals, "=", "Equal to"},
+		{OperatorGreaterThanOrEqual, "≥", "Greater than or equal to"},
+		{OperatorLessThanOrEqual, "≤", "Less than or equal to"},
+		{OperatorNotEquals, "≠", "Not equal to"},
+		{"invalid_operator", "", "Unknown operator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.operator), func(t *testing.T) {
+			if got := tt.operator.Symbol(); got != tt.symbol {
+				t.Errorf("Operator.Symbol() = %v, want %v", got, tt.symbol)
+			}
+			if got := tt.operator.Describe(); got != tt.description {
+				t.Errorf("Operator.Describe() = %v, want %v", got, tt.description)
+			}
+			symbol, err := tt.operator.LookupSymbol()
+			if (err != nil) != (tt.symbol == "") || symbol != tt.symbol {
+				t.Errorf("Operator.LookupSymbol() = %v, %v, want %v", symbol, err, tt.symbol)
+			}
+		})
+	}
+}
+
+func TestNormalizeOperator(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Operator
+	}{
+		{"greater_than", OperatorGreaterThan},
+		{"gt", OperatorGreaterThan},
+		{">", OperatorGreaterThan},
+		{"less_than", OperatorLessThan},
+		{"lt", OperatorLessThan},
+		{"<", OperatorLessThan},
+		{"equals", OperatorEquals},
+		{"eq", OperatorEquals},
+		{"=", OperatorEquals},
+		{"==", OperatorEquals},
+		{"greater_than_or_equal", OperatorGreaterThanOrEqual},
+		{"gte", OperatorGreaterThanOrEqual},
+		{">=", OperatorGreaterThanOrEqual},
+		{"≥", OperatorGreaterThanOrEqual},
+		{"less_than_or_equal", OperatorLessThanOrEqual},
+		{"lte", OperatorLessThanOrEqual},
+		{"<=", OperatorLessThanOrEqual},
+		{"≤", OperatorLessThanOrEqual},
+		{"not_equals", OperatorNotEquals},
+		{"ne", OperatorNotEquals},
+		{"!=", OperatorNotEquals},
+		{"≠", OperatorNotEquals},
+		{" GT ", OperatorGreaterThan},
+		{"Greater_Than", OperatorGreaterThan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := NormalizeOperator(tt.value)
+			if !ok || got != tt.want {
+				t.Errorf("NormalizeOperator(%q) = %v, %v, want %v", tt.value, got, ok, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "greater", "=>", "not_equal", "<>"} {
+		if got, ok := NormalizeOperator(value); ok {
+			t.Errorf("NormalizeOperator(%q) = %v, want no match", value, got)
+		}
+	}
+}
+
+func TestOperatorAliasesAreCanonical(t *testing.T) {
+	for alias, operator := range OperatorAliases {
+		if !operator.IsValid() {
+			t.Errorf("alias %q maps to invalid operator %q", alias, operator)
+		}
+		if ValidOperators[Operator(alias)] {
+			t.Errorf("alias %q shadows a canonical operator", alias)
+		}
+	}
+	if got := OperatorGreaterThan.Aliases(); strings.Join(got, ",") != ">,gt" {
+		t.Errorf("OperatorGreaterThan.Aliases() = %v, want [> gt]", got)
+	}
+}
+
+func TestWorkflowInputOperatorAliases(t *testing.T) {
+	var input WorkflowInput
+	if err := json.Unmarshal([]byte(`{"name":"Alice","email":"alice@example.com","city":"Sydney","threshold":25,"operator":">="}`), &input); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if input.Operator != OperatorGreaterThanOrEqual {
+		t.Errorf("Operator = %q, want %q", input.Operator, OperatorGreaterThanOrEqual)
+	}
+	if err := input.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	canonical := input
+	canonical.Operator = OperatorGreaterThanOrEqual
+	if input.Hash() != canonical.Hash() {
+		t.Error("an alias should hash like its canonical operator")
+	}
+
+	if err := json.Unmarshal([]byte(`{"name":"Alice","email":"alice@example.com","city":"Sydney","operator":"greater"}`), &input); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := input.Validate(); err == nil || err.Error() != "invalid operator: greater" {
+		t.Errorf("Validate() = %v, want invalid operator: greater", err)
+	}
+}
+
+func TestOperatorSymbolsCoverValidOperators(t *testing.T) {
+	for operator := range ValidOperators {
+		if _, err := operator.LookupSymbol(); err != nil {
+			t.Errorf("valid operator %s has no symbol: %v", operator, err)
+		}
+	}
+	if _, err := Operator("").LookupSymbol(); err == nil {
+		t.Error("an unset operator should have no symbol")
+	}
+}
+
 func validateWorkflowStructure(nodes []Node, edges []Edge) error {
 	if len(nodes) == 0 {
 		return fmt.Errorf("workflow must have at least one node")
@@ -413,4 +762,23 @@ func TestValidateWorkflowStructure(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestWorkflowWithoutSecrets(t *testing.T) {
+	workflow := &Workflow{ID: "wf", Webhook: &WebhookConfig{Secret: "s3cret", Mapping: map[string]string{"city": "city"}}}
+
+	redacted := workflow.WithoutSecrets()
+	if redacted.Webhook.Secret != "" {
+		t.Errorf("secret should be removed, got %q", redacted.Webhook.Secret)
+	}
+	if redacted.Webhook.Mapping["city"] != "city" {
+		t.Errorf("mapping should be kept, got %v", redacted.Webhook.Mapping)
+	}
+	if workflow.Webhook.Secret != "s3cret" {
+		t.Errorf("original workflow should keep its secret")
+	}
+
+	plain := &Workflow{ID: "wf"}
+	if plain.WithoutSecrets() != plain {
+		t.Errorf("workflow without secrets should be returned as is")
+	}
+}
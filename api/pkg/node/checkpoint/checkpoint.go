@@ -0,0 +1,77 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// Node implements a checkpoint node. Like an end node it records a summary of the
+// run so far, but execution carries on along its outgoing edge.
+type Node struct {
+	node.BaseNode
+}
+
+// NewNode creates a checkpoint node from a model
+func NewNode(model models.Node) (node.Node, error) {
+	return &Node{
+		BaseNode: node.BaseNode{
+			ID:          model.ID,
+			Label:       model.Data.Label,
+			Description: model.Data.Description,
+		},
+	}, nil
+}
+
+// Type returns the node type
+func (n *Node) Type() models.NodeType {
+	return models.NodeTypeCheckpoint
+}
+
+// GetBaseInfo returns the base node information
+func (n *Node) GetBaseInfo() node.BaseNode {
+	return n.BaseNode
+}
+
+// Execute records which nodes have completed and the values they shared
+func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	started := time.Now()
+
+	completed := make([]string, 0, len(inputs.PriorOutputs))
+	for id, output := range inputs.PriorOutputs {
+		if output.Status == models.StatusCompleted {
+			completed = append(completed, id)
+		}
+	}
+	slices.Sort(completed)
+
+	name := n.Label
+	if name == "" {
+		name = n.ID
+	}
+	summary := map[string]any{
+		"message":        fmt.Sprintf("Checkpoint %s reached", name),
+		"completedNodes": completed,
+	}
+	if len(inputs.NodeData) > 0 {
+		summary["shared"] = maps.Clone(inputs.NodeData)
+	}
+
+	return node.NodeOutputs{
+		Data:        map[string]any{"summary": summary},
+		Status:      models.StatusCompleted,
+		StartedAt:   started.Format(time.RFC3339),
+		EndedAt:     time.Now().Format(time.RFC3339),
+		Explanation: fmt.Sprintf("Reached checkpoint %s after %d completed nodes and carried on", name, len(completed)),
+	}, nil
+}
+
+// Validate ensures the node is properly configured
+func (n *Node) Validate() error {
+	// Checkpoints have no configuration to validate
+	return nil
+}
@@ -0,0 +1,49 @@
+package checkpoint
+
+import (
+	"context"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute(t *testing.T) {
+	n, err := NewNode(models.Node{
+		ID:   "checkpoint-1",
+		Type: models.NodeTypeCheckpoint,
+		Data: models.NodeData{Label: "Weather fetched"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, n.Validate())
+	assert.Equal(t, models.NodeTypeCheckpoint, n.Type())
+
+	outputs, err := n.Execute(context.Background(), node.NodeInputs{
+		NodeData: map[string]any{"temperature": 28.5},
+		PriorOutputs: map[string]node.NodeOutputs{
+			"start":       {Status: models.StatusCompleted},
+			"weather-api": {Status: models.StatusCompleted},
+			"webhook":     {Status: models.StatusFailed},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, outputs.Status)
+	assert.Empty(t, outputs.NextNodeID, "a checkpoint follows its outgoing edge")
+	assert.Equal(t, map[string]any{
+		"message":        "Checkpoint Weather fetched reached",
+		"completedNodes": []string{"start", "weather-api"},
+		"shared":         map[string]any{"temperature": 28.5},
+	}, outputs.Data["summary"])
+}
+
+func TestExecuteWithoutPriorOutputs(t *testing.T) {
+	n := &Node{BaseNode: node.BaseNode{ID: "checkpoint-1"}}
+
+	outputs, err := n.Execute(context.Background(), node.NodeInputs{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"message":        "Checkpoint checkpoint-1 reached",
+		"completedNodes": []string{},
+	}, outputs.Data["summary"])
+}
@@ -3,10 +3,12 @@ package condition
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 	"workflow-code-test/api/pkg/models"
 	"workflow-code-test/api/pkg/node"
-	"workflow-code-test/api/pkg/node/integration/weather"
 )
 
 // Node implements a condition node
@@ -15,13 +17,37 @@ type Node struct {
     config Config
 }
 
+// Weather fields a condition can compare against the threshold
+const (
+    FieldTemperature = "temperature"
+    FieldWindspeed   = "windspeed"
+    FieldHumidity    = "humidity"
+)
+
 // Config holds condition node configuration
 type Config struct {
     ConditionExpression string
+    Field               string
     TrueRoute           string
     FalseRoute          string
+    // Threshold and Operator override the workflow input so chained conditions
+    // can each apply their own comparison
+    Threshold *float64
+    Operator  models.Operator
+    // CompareField switches the node to comparing Field against a second field's
+    // value instead of a threshold
+    CompareField string
+    // Value switches the node to comparing Field as text, e.g. a form's city
+    // against "Sydney", with equals or not_equals instead of a threshold
+    Value *string
+    // Precision adds the compared numbers to conditionResult as strings with this
+    // many decimal places, under "formatted", for consistent display
+    Precision *int
 }
 
+// MaxPrecision is the most decimal places Precision may ask for
+const MaxPrecision = 6
+
 // NewNode creates a condition node from a model
 func NewNode(model models.Node) (node.Node, error) {
     // Parse model.Data.Metadata into Config
@@ -32,12 +58,46 @@ func NewNode(model models.Node) (node.Node, error) {
         if expr, exists := metadata["conditionExpression"].(string); exists {
             config.ConditionExpression = expr
         }
+        if field, exists := metadata["conditionField"].(string); exists {
+            config.Field = field
+        }
+        if threshold, exists := metadata["threshold"].(float64); exists {
+            config.Threshold = &threshold
+        }
+        if operator, exists := metadata["operator"].(string); exists {
+            config.Operator = models.Operator(operator)
+            if canonical, ok := models.NormalizeOperator(operator); ok {
+                config.Operator = canonical
+            }
+        }
+        if compareField, exists := metadata["compareField"].(string); exists {
+            config.CompareField = compareField
+        }
+        if value, exists := metadata["value"].(string); exists {
+            config.Value = &value
+        }
+        if precision, exists := metadata["precision"].(float64); exists {
+            // A fractional precision is kept out of range so Validate rejects it
+            places := -1
+            if precision == math.Trunc(precision) && math.Abs(precision) <= MaxPrecision {
+                places = int(precision)
+            }
+            config.Precision = &places
+        }
+        // Routes saved in metadata are provisional; the engine replaces them
+        // with the node's true/false edges when the workflow runs
+        if route, exists := metadata["trueRoute"].(string); exists {
+            config.TrueRoute = route
+        }
+        if route, exists := metadata["falseRoute"].(string); exists {
+            config.FalseRoute = route
+        }
         
         // Check for true/false handles in the metadata
         if handles, exists := metadata["hasHandles"].(map[string]any); exists {
             if sourceHandles, exists := handles["source"].([]any); exists {
                 for _, handle := range sourceHandles {
-                    if handle.(string) == "true" || handle.(string) == "false" {
+                    if h, _ := handle.(string); h == "true" || h == "false" {
                         // Found a conditional handle, this is just to verify the node is set up correctly
                     }
                 }
@@ -74,78 +134,110 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
         StartedAt: started.Format(time.RFC3339),
     }
     
-    // Get temperature from prior integration node output
-    tempNode := inputs.PriorOutputs["weather-api"]
-    temperature, ok := tempNode.Data["temperature"].(float64)
-    if !ok {
+    field := n.fieldFor(inputs.WorkflowInput)
+    var compared any
+    var operator models.Operator
+    var evaluation Evaluation
+    var err error
+    if n.config.Value != nil {
+        // Text comparisons read a form field such as city and don't use the run's operator
+        text, ok := resolveText(inputs, field)
+        if !ok {
+            outputs.Status = models.StatusFailed
+            outputs.Data["error"] = fmt.Sprintf("Failed to get %s", field)
+            outputs.Explanation = fmt.Sprintf("No %s value was available to compare, so no route was taken", field)
+            outputs.EndedAt = time.Now().Format(time.RFC3339)
+            return outputs, fmt.Errorf("missing %s", field)
+        }
+        compared = text
+        operator = n.textOperator()
+        evaluation, err = EvaluateText(field, text, operator, *n.config.Value)
+    } else {
+        // Get the compared value from prior integration node output
+        value, ok := resolveField(inputs, field)
+        if !ok {
+            outputs.Status = models.StatusFailed
+            outputs.Data["error"] = fmt.Sprintf("Failed to get %s", field)
+            outputs.Explanation = fmt.Sprintf("No %s value was available to compare, so no route was taken", field)
+            outputs.EndedAt = time.Now().Format(time.RFC3339)
+            return outputs, fmt.Errorf("missing %s", field)
+        }
+        compared = value
+        
+        operator = inputs.WorkflowInput.Operator
+        if n.config.Operator != "" {
+            operator = n.config.Operator
+        }
+        
+        // Evaluate condition against the second field, or the threshold
+        if compareField := n.config.CompareField; compareField != "" {
+            compareValue, ok := resolveField(inputs, compareField)
+            if !ok {
+                outputs.Status = models.StatusFailed
+                outputs.Data["error"] = fmt.Sprintf("Failed to get %s", compareField)
+                outputs.Explanation = fmt.Sprintf("No %s value was available to compare against, so no route was taken", compareField)
+                outputs.EndedAt = time.Now().Format(time.RFC3339)
+                return outputs, fmt.Errorf("missing %s", compareField)
+            }
+            evaluation, err = EvaluateFields(field, value, operator, compareField, compareValue)
+        } else {
+            threshold := inputs.WorkflowInput.Threshold
+            if n.config.Threshold != nil {
+                threshold = *n.config.Threshold
+            }
+            evaluation, err = Evaluate(field, value, operator, threshold)
+        }
+    }
+    if err != nil {
         outputs.Status = models.StatusFailed
-        outputs.Data["error"] = "Failed to get temperature"
+        outputs.Data["error"] = node.Capitalize(err.Error())
         outputs.EndedAt = time.Now().Format(time.RFC3339)
-        return outputs, fmt.Errorf("missing temperature")
-    }
-    
-    threshold := inputs.WorkflowInput.Threshold
-    operator := inputs.WorkflowInput.Operator
-    
-    // Evaluate condition
-    var conditionMet bool
-    switch operator {
-    case models.OperatorGreaterThan:
-        conditionMet = temperature > threshold
-    case models.OperatorLessThan:
-        conditionMet = temperature < threshold
-    case models.OperatorEquals:
-        conditionMet = temperature == threshold
-    case models.OperatorGreaterThanOrEqual:
-        conditionMet = temperature >= threshold
-    case models.OperatorLessThanOrEqual:
-        conditionMet = temperature <= threshold
+        return outputs, err
     }
     
     // Set next node based on condition
-    if conditionMet {
+    if evaluation.Met {
         outputs.NextNodeID = n.config.TrueRoute
     } else {
         outputs.NextNodeID = n.config.FalseRoute
     }
+    outputs.Explanation = explain(evaluation, outputs.NextNodeID)
     
-    // Set outputs
-    weatherEmoji := weather.WeatherEmoji{}
-    emoji := weatherEmoji.Emoji(temperature)
-    
-    // Get operator symbol for display
-    operatorSymbol := ">"
-    switch operator {
-    case models.OperatorLessThan:
-        operatorSymbol = "<"
-    case models.OperatorEquals:
-        operatorSymbol = "="
-    case models.OperatorGreaterThanOrEqual:
-        operatorSymbol = "≥"
-    case models.OperatorLessThanOrEqual:
-        operatorSymbol = "≤"
-    }
-
-    message := fmt.Sprintf("Temperature %.1f°C %s %.1f°C %s - condition %s", 
-               temperature, operatorSymbol, threshold, emoji, 
-               map[bool]string{true: "met", false: "not met"}[conditionMet])
-    
-    // Prepare the expression for displaying in the frontend
-    expression := fmt.Sprintf("temperature %s threshold", operatorSymbol)
-    
+    conditionResult := map[string]any{
+        "expression": evaluation.Expression,
+        "result":     evaluation.Met,
+        field:        compared,
+        "operator":   string(operator),
+    }
+    details := map[string]any{
+        "conditionType": field,
+        "evaluatedAt":   time.Now().Format(time.RFC3339),
+    }
+    switch {
+    case n.config.Value != nil:
+        conditionResult["value"] = evaluation.ExpectedText
+    case evaluation.CompareField != "":
+        // Both resolved values are recorded under their field names
+        conditionResult[evaluation.CompareField] = evaluation.Threshold
+        details["compareField"] = evaluation.CompareField
+    default:
+        conditionResult["threshold"] = evaluation.Threshold
+    }
+    if n.config.Precision != nil && n.config.Value == nil {
+        // Raw numbers stay as they are; formatted copies sit alongside them
+        compareKey := "threshold"
+        if evaluation.CompareField != "" {
+            compareKey = evaluation.CompareField
+        }
+        conditionResult["formatted"] = map[string]string{
+            field:      formatNumber(evaluation.Value, *n.config.Precision),
+            compareKey: formatNumber(evaluation.Threshold, *n.config.Precision),
+        }
+    }
     outputs.Data = map[string]any{
-        "message": message,
-        "conditionResult": map[string]any{
-            "expression": expression,
-            "result":     conditionMet,
-            "temperature": temperature,
-            "operator":   string(operator),
-            "threshold":  threshold,
-        },
-        "details": map[string]any{
-            "conditionType": "temperature",
-            "evaluatedAt":   time.Now().Format(time.RFC3339),
-        },
+        "message":         evaluation.Message,
+        "conditionResult": conditionResult,
+        "details":         details,
     }
     
     outputs.Status = models.StatusCompleted
@@ -158,9 +250,142 @@ func (n *Node) Validate() error {
     if n.config.TrueRoute == "" || n.config.FalseRoute == "" {
         return fmt.Errorf("condition node requires both true and false routes")
     }
+    if n.config.Operator != "" && !models.ValidOperators[n.config.Operator] {
+        return fmt.Errorf("unsupported operator: %s", n.config.Operator)
+    }
+    if precision := n.config.Precision; precision != nil && (*precision < 0 || *precision > MaxPrecision) {
+        return fmt.Errorf("precision must be a whole number from 0 to %d", MaxPrecision)
+    }
+    // Text comparisons may name any form or node output, but only test equality
+    if n.config.Value != nil {
+        if n.config.Field == "" {
+            return fmt.Errorf("conditionField is required to compare against a value")
+        }
+        if n.config.CompareField != "" {
+            return fmt.Errorf("value and compareField cannot both be set")
+        }
+        if !isTextOperator(n.textOperator()) {
+            return fmt.Errorf("operator %s cannot compare text; use equals or not_equals", n.config.Operator)
+        }
+        return nil
+    }
+    // Comparing two fields accepts any numeric output, so only threshold
+    // comparisons are limited to the known weather fields
+    if n.config.CompareField == "" {
+        if _, err := Unit(n.field()); err != nil {
+            return err
+        }
+    }
     return nil
 }
 
+// explain describes the comparison made and the route it led to
+func explain(evaluation Evaluation, nextNodeID string) string {
+    route := fmt.Sprintf("the %t route", evaluation.Met)
+    if nextNodeID != "" {
+        route += " to " + nextNodeID
+    }
+    return fmt.Sprintf("%s, so %s was taken", evaluation.Message, route)
+}
+
+// Result reads the outcome a condition node recorded in its output data
+func Result(data map[string]any) (met bool, ok bool) {
+    conditionResult, ok := data["conditionResult"].(map[string]any)
+    if !ok {
+        return false, false
+    }
+    met, ok = conditionResult["result"].(bool)
+    return met, ok
+}
+
+// resolveField reads a numeric operand. "<nodeId>.<key>" reads key from that node's
+// output, so values derived by other nodes (e.g. a wind chill) can be compared too.
+// A plain name is read from the values shared by earlier nodes, falling back to
+// the weather node's output when no node shared it.
+func resolveField(inputs node.NodeInputs, name string) (float64, bool) {
+    if prefix, key, found := strings.Cut(name, "."); found {
+        if output, exists := inputs.PriorOutputs[prefix]; exists {
+            return output.Float(key)
+        }
+    }
+    if shared, exists := inputs.Shared(name); exists {
+        value, ok := shared.(float64)
+        return value, ok
+    }
+    return inputs.PriorOutputs[string(models.NodeIDWeatherAPI)].Float(name)
+}
+
+// resolveText reads a text operand such as the form's city. "<nodeId>.<key>" reads
+// key from that node's output; a plain name is read from the shared values, then
+// the form's output, then the weather node's. Numbers are formatted as text.
+func resolveText(inputs node.NodeInputs, name string) (string, bool) {
+    if prefix, key, found := strings.Cut(name, "."); found {
+        if output, exists := inputs.PriorOutputs[prefix]; exists {
+            return formatText(output.Data[key])
+        }
+    }
+    if shared, exists := inputs.Shared(name); exists {
+        return formatText(shared)
+    }
+    for _, nodeID := range []models.NodeID{models.NodeIDForm, models.NodeIDWeatherAPI} {
+        if value, exists := inputs.PriorOutputs[string(nodeID)].Data[name]; exists {
+            return formatText(value)
+        }
+    }
+    return "", false
+}
+
+// formatText converts a string or number to text, reporting false for anything else
+func formatText(value any) (string, bool) {
+    switch v := value.(type) {
+    case string:
+        return v, true
+    case float64:
+        return strconv.FormatFloat(v, 'f', -1, 64), true
+    case int:
+        return strconv.Itoa(v), true
+    default:
+        return "", false
+    }
+}
+
+// formatNumber renders value with a fixed number of decimal places, e.g. "20.50"
+func formatNumber(value float64, precision int) string {
+    return strconv.FormatFloat(value, 'f', precision, 64)
+}
+
+// textOperator returns the operator for a text comparison, which defaults to
+// equals rather than the run's operator since that is meant for weather values
+func (n *Node) textOperator() models.Operator {
+    if n.config.Operator == "" {
+        return models.OperatorEquals
+    }
+    return n.config.Operator
+}
+
+// fieldFor returns the weather field to compare for a run. A field named in the
+// workflow input takes precedence over the node's configured field, except in
+// text comparisons, whose field isn't a weather field.
+func (n *Node) fieldFor(input models.WorkflowInput) string {
+    if input.Field != "" && n.config.Value == nil {
+        return input.Field
+    }
+    return n.field()
+}
+
+// field returns the weather field configured on the node, defaulting to temperature
+func (n *Node) field() string {
+    if n.config.Field == "" {
+        return FieldTemperature
+    }
+    return n.config.Field
+}
+
+// Routes returns the node IDs taken when the condition is true and false
+func (n *Node) Routes() (trueRoute, falseRoute string) {
+    return n.config.TrueRoute, n.config.FalseRoute
+}
+
 // SetTrueRoute sets the target node ID for when condition is true
 func (n *Node) SetTrueRoute(nodeID string) {
     n.config.TrueRoute = nodeID
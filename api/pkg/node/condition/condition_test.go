@@ -2,6 +2,7 @@ package condition
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 	"workflow-code-test/api/pkg/models"
@@ -261,12 +262,233 @@ func TestExecuteWithMissingTemperature(t *testing.T) {
 	assert.False(t, ok, "conditionResult should not be present when there's an error")
 }
 
+func TestExecuteRecordsEvaluationError(t *testing.T) {
+	conditionNode := &Node{
+		BaseNode: node.BaseNode{ID: "condition-1"},
+		config:   Config{Field: "pressure", TrueRoute: "email-node", FalseRoute: "end-node"},
+	}
+	inputs := node.NodeInputs{
+		WorkflowInput: models.WorkflowInput{Threshold: 1000, Operator: models.OperatorGreaterThan},
+		PriorOutputs: map[string]node.NodeOutputs{
+			"weather-api": {Data: map[string]any{"pressure": 1012.0}},
+		},
+	}
+
+	outputs, err := conditionNode.Execute(context.Background(), inputs)
+	assert.EqualError(t, err, "unsupported condition field: pressure")
+	assert.Equal(t, models.StatusFailed, outputs.Status)
+	assert.Equal(t, "Unsupported condition field: pressure", outputs.Data["error"])
+}
+
+func TestExecuteWithUnsupportedOperator(t *testing.T) {
+	// Create condition node
+	conditionNode := &Node{
+		BaseNode: node.BaseNode{
+			ID:          "condition-1",
+			Label:       "Temperature Check",
+			Description: "Check if temperature meets threshold",
+		},
+		config: Config{
+			TrueRoute:  "email-node",
+			FalseRoute: "end-node",
+		},
+	}
+	
+	// Test with an operator the node does not understand
+	inputs := node.NodeInputs{
+		WorkflowInput: models.WorkflowInput{
+			Threshold: 20.0,
+			Operator:  "invalid_operator",
+		},
+		PriorOutputs: map[string]node.NodeOutputs{
+			"weather-api": {
+				Data: map[string]any{
+					"temperature": 15.0,
+				},
+			},
+		},
+	}
+	
+	// Execute the node
+	outputs, err := conditionNode.Execute(context.Background(), inputs)
+	
+	// Verify the step fails instead of routing to the false branch
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported operator")
+	assert.Equal(t, models.StatusFailed, outputs.Status)
+	assert.Contains(t, outputs.Data["error"], "Unsupported operator")
+	assert.Empty(t, outputs.NextNodeID, "No route should be selected for an unsupported operator")
+	_, ok := outputs.Data["conditionResult"]
+	assert.False(t, ok, "conditionResult should not be present when there's an error")
+}
+
+func TestExecuteWithWindspeedField(t *testing.T) {
+	// Create condition node that compares windspeed instead of temperature
+	conditionNode := &Node{
+		BaseNode: node.BaseNode{
+			ID:          "condition-1",
+			Label:       "Wind Check",
+			Description: "Check if windspeed meets threshold",
+		},
+		config: Config{
+			Field:      FieldWindspeed,
+			TrueRoute:  "email-node",
+			FalseRoute: "end-node",
+		},
+	}
+	
+	inputs := node.NodeInputs{
+		WorkflowInput: models.WorkflowInput{
+			Threshold: 20.0,
+			Operator:  models.OperatorGreaterThan,
+		},
+		PriorOutputs: map[string]node.NodeOutputs{
+			"weather-api": {
+				Data: map[string]any{
+					"temperature": 15.0,
+					"windspeed":   25.0,
+				},
+			},
+		},
+	}
+	
+	outputs, err := conditionNode.Execute(context.Background(), inputs)
+	
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, outputs.Status)
+	assert.Equal(t, "email-node", outputs.NextNodeID)
+	assert.Equal(t, "Windy: 25 km/h 💨 > 20.0 km/h - condition met", outputs.Data["message"])
+	
+	conditionResult, ok := outputs.Data["conditionResult"].(map[string]any)
+	assert.True(t, ok, "conditionResult should be a map")
+	assert.Equal(t, 25.0, conditionResult["windspeed"])
+	assert.NotContains(t, conditionResult, "temperature")
+	assert.Equal(t, "windspeed > threshold", conditionResult["expression"])
+	
+	// Missing windspeed fails the step
+	delete(inputs.PriorOutputs["weather-api"].Data, "windspeed")
+	outputs, err = conditionNode.Execute(context.Background(), inputs)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing windspeed")
+	assert.Equal(t, models.StatusFailed, outputs.Status)
+}
+
+func TestExecuteWithNodeThreshold(t *testing.T) {
+	threshold := 40.0
+	conditionNode := &Node{
+		BaseNode: node.BaseNode{ID: "wind-check"},
+		config: Config{
+			Field:      FieldWindspeed,
+			TrueRoute:  "email-node",
+			FalseRoute: "end-node",
+			Threshold:  &threshold,
+			Operator:   models.OperatorGreaterThanOrEqual,
+		},
+	}
+	
+	// The workflow input would route to true; the node's own comparison wins
+	inputs := node.NodeInputs{
+		WorkflowInput: models.WorkflowInput{
+			Threshold: 20.0,
+			Operator:  models.OperatorGreaterThan,
+		},
+		PriorOutputs: map[string]node.NodeOutputs{
+			"weather-api": {Data: map[string]any{"windspeed": 25.0}},
+		},
+	}
+	
+	outputs, err := conditionNode.Execute(context.Background(), inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, "end-node", outputs.NextNodeID)
+	
+	conditionResult := outputs.Data["conditionResult"].(map[string]any)
+	assert.Equal(t, 40.0, conditionResult["threshold"])
+	assert.Equal(t, string(models.OperatorGreaterThanOrEqual), conditionResult["operator"])
+	
+	met, ok := Result(outputs.Data)
+	assert.True(t, ok)
+	assert.False(t, met)
+}
+
+func TestExecuteExplanation(t *testing.T) {
+	conditionNode := &Node{
+		BaseNode: node.BaseNode{ID: "condition"},
+		config:   Config{TrueRoute: "email", FalseRoute: "end"},
+	}
+	inputsFor := func(temperature any) node.NodeInputs {
+		return node.NodeInputs{
+			WorkflowInput: models.WorkflowInput{Threshold: 30, Operator: models.OperatorGreaterThan},
+			PriorOutputs: map[string]node.NodeOutputs{
+				"weather-api": {Data: map[string]any{"temperature": temperature}},
+			},
+		}
+	}
+
+	t.Run("names the comparison and the route taken", func(t *testing.T) {
+		outputs, err := conditionNode.Execute(context.Background(), inputsFor(35.0))
+		assert.NoError(t, err)
+		assert.Contains(t, outputs.Explanation, "Temperature 35.0°C > 30.0°C")
+		assert.Contains(t, outputs.Explanation, "so the true route to email was taken")
+
+		outputs, err = conditionNode.Execute(context.Background(), inputsFor(25.0))
+		assert.NoError(t, err)
+		assert.Contains(t, outputs.Explanation, "condition not met, so the false route to end was taken")
+	})
+
+	t.Run("says why no route was taken", func(t *testing.T) {
+		outputs, err := conditionNode.Execute(context.Background(), inputsFor(nil))
+		assert.Error(t, err)
+		assert.Equal(t, "No temperature value was available to compare, so no route was taken", outputs.Explanation)
+	})
+}
+
+func TestNewNodeWithThresholdOverride(t *testing.T) {
+	n, err := NewNode(models.Node{
+		ID:   "wind-check",
+		Type: models.NodeTypeCondition,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"conditionField": "windspeed",
+				"threshold":      40.0,
+				"operator":       "greater_than",
+			},
+		},
+	})
+	assert.NoError(t, err)
+	
+	conditionNode := n.(*Node)
+	assert.Equal(t, 40.0, *conditionNode.config.Threshold)
+	assert.Equal(t, models.OperatorGreaterThan, conditionNode.config.Operator)
+}
+
+func TestResult(t *testing.T) {
+	met, ok := Result(map[string]any{"conditionResult": map[string]any{"result": true}})
+	assert.True(t, ok)
+	assert.True(t, met)
+	
+	_, ok = Result(map[string]any{"conditionMet": true})
+	assert.False(t, ok)
+	
+	_, ok = Result(map[string]any{"conditionResult": map[string]any{"result": "yes"}})
+	assert.False(t, ok)
+}
+
+func TestResultOfStoredV1Output(t *testing.T) {
+	// Condition steps stored before outputSchemaVersion 2 recorded a top-level conditionMet
+	stored := models.JSONB{"message": "Temperature 12.0°C is greater than 25.0°C - condition not met", "conditionMet": false}
+
+	met, ok := Result(models.UpgradeOutput(models.NodeTypeCondition, stored))
+	assert.True(t, ok)
+	assert.False(t, met)
+}
+
 func TestValidate(t *testing.T) {
 	// Test cases for validation
 	testCases := []struct {
 		name          string
 		config        Config
 		expectedError bool
+		errorContains string
 	}{
 		{
 			name: "Valid config",
@@ -293,6 +515,26 @@ func TestValidate(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "Unsupported field",
+			config: Config{
+				Field:      "pressure",
+				TrueRoute:  "email-node",
+				FalseRoute: "end-node",
+			},
+			expectedError: true,
+			errorContains: "unsupported condition field",
+		},
+		{
+			name: "Unsupported operator override",
+			config: Config{
+				Operator:   "roughly",
+				TrueRoute:  "email-node",
+				FalseRoute: "end-node",
+			},
+			expectedError: true,
+			errorContains: "unsupported operator",
+		},
 		{
 			name: "Missing Both Routes",
 			config: Config{
@@ -316,7 +558,11 @@ func TestValidate(t *testing.T) {
 			err := node.Validate()
 			if tc.expectedError {
 				assert.Error(t, err)
-				assert.Contains(t, err.Error(), "requires both true and false routes")
+				errorContains := tc.errorContains
+				if errorContains == "" {
+					errorContains = "requires both true and false routes"
+				}
+				assert.Contains(t, err.Error(), errorContains)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -347,3 +593,443 @@ func TestSetRoutes(t *testing.T) {
 	assert.Equal(t, "email-node", node.config.TrueRoute)
 	assert.Equal(t, "end-node", node.config.FalseRoute)
 }
+
+func TestExecuteExpressionForEachOperator(t *testing.T) {
+	expected := map[models.Operator]string{
+		models.OperatorGreaterThan:        "temperature > threshold",
+		models.OperatorLessThan:           "temperature < threshold",
+		models.OperatorEquals:             "temperature = threshold",
+		models.OperatorGreaterThanOrEqual: "temperature ≥ threshold",
+		models.OperatorLessThanOrEqual:    "temperature ≤ threshold",
+		models.OperatorNotEquals:          "temperature ≠ threshold",
+	}
+	assert.Len(t, expected, len(models.ValidOperators), "every valid operator should be covered")
+
+	for operator, expression := range expected {
+		t.Run(string(operator), func(t *testing.T) {
+			conditionNode := &Node{config: Config{TrueRoute: "email", FalseRoute: "end"}}
+			inputs := node.NodeInputs{
+				WorkflowInput: models.WorkflowInput{Threshold: 20.0, Operator: operator},
+				PriorOutputs: map[string]node.NodeOutputs{
+					"weather-api": {Data: map[string]any{"temperature": 15.0}},
+				},
+			}
+
+			outputs, err := conditionNode.Execute(context.Background(), inputs)
+			assert.NoError(t, err)
+			conditionResult := outputs.Data["conditionResult"].(map[string]any)
+			assert.Equal(t, expression, conditionResult["expression"])
+		})
+	}
+
+	t.Run("unset operator fails instead of defaulting", func(t *testing.T) {
+		conditionNode := &Node{config: Config{TrueRoute: "email", FalseRoute: "end"}}
+		inputs := node.NodeInputs{
+			WorkflowInput: models.WorkflowInput{Threshold: 20.0},
+			PriorOutputs: map[string]node.NodeOutputs{
+				"weather-api": {Data: map[string]any{"temperature": 15.0}},
+			},
+		}
+
+		outputs, err := conditionNode.Execute(context.Background(), inputs)
+		assert.ErrorContains(t, err, "unsupported operator")
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+		assert.NotContains(t, outputs.Data, "conditionResult")
+	})
+}
+
+func TestNewNodeWithMalformedHandles(t *testing.T) {
+	model := models.Node{
+		ID:   "condition",
+		Type: models.NodeTypeCondition,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"hasHandles": map[string]any{"source": []any{"true", 1.0, nil}},
+			},
+		},
+	}
+
+	assert.NotPanics(t, func() {
+		_, err := NewNode(model)
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewNodeReadsMetadataRoutes(t *testing.T) {
+	n, err := NewNode(models.Node{
+		ID:   "condition",
+		Type: models.NodeTypeCondition,
+		Data: models.NodeData{Metadata: map[string]any{"trueRoute": "email", "falseRoute": "end"}},
+	})
+	assert.NoError(t, err)
+
+	trueRoute, falseRoute := n.(*Node).Routes()
+	assert.Equal(t, "email", trueRoute)
+	assert.Equal(t, "end", falseRoute)
+}
+
+func TestExecuteWithInputField(t *testing.T) {
+	weatherOutputs := map[string]node.NodeOutputs{
+		"weather-api": {Data: map[string]any{"temperature": 18.0, "windspeed": 45.0}},
+	}
+
+	tests := []struct {
+		name          string
+		nodeField     string
+		inputField    string
+		expectedField string
+		expectedRoute string
+	}{
+		{"input selects windspeed over node default", "", FieldWindspeed, FieldWindspeed, "email-node"},
+		{"input selects temperature over node windspeed", FieldWindspeed, FieldTemperature, FieldTemperature, "end-node"},
+		{"node field applies when input has none", FieldWindspeed, "", FieldWindspeed, "email-node"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditionNode := &Node{
+				BaseNode: node.BaseNode{ID: "condition"},
+				config: Config{
+					Field:      tt.nodeField,
+					TrueRoute:  "email-node",
+					FalseRoute: "end-node",
+				},
+			}
+
+			outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{
+				WorkflowInput: models.WorkflowInput{
+					Threshold: 30.0,
+					Operator:  models.OperatorGreaterThan,
+					Field:     tt.inputField,
+				},
+				PriorOutputs: weatherOutputs,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRoute, outputs.NextNodeID)
+
+			details := outputs.Data["details"].(map[string]any)
+			assert.Equal(t, tt.expectedField, details["conditionType"])
+			conditionResult := outputs.Data["conditionResult"].(map[string]any)
+			assert.Equal(t, weatherOutputs["weather-api"].Data[tt.expectedField], conditionResult[tt.expectedField])
+		})
+	}
+}
+
+// windChill is the Environment Canada wind chill in °C for a temperature in °C and windspeed in km/h
+func windChill(temperature, windspeed float64) float64 {
+	v := math.Pow(windspeed, 0.16)
+	return 13.12 + 0.6215*temperature - 11.37*v + 0.3965*temperature*v
+}
+
+func TestExecuteComparingFields(t *testing.T) {
+	n, err := NewNode(models.Node{
+		ID:   "feels-colder",
+		Type: models.NodeTypeCondition,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"compareField": "wind-chill.windChill",
+				"operator":     string(models.OperatorGreaterThan),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	conditionNode := n.(*Node)
+	conditionNode.SetTrueRoute("email-node")
+	conditionNode.SetFalseRoute("end-node")
+	assert.NoError(t, conditionNode.Validate())
+
+	run := func(temperature, windspeed float64) node.NodeOutputs {
+		outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{
+			// The threshold is ignored when comparing fields
+			WorkflowInput: models.WorkflowInput{Threshold: 100},
+			PriorOutputs: map[string]node.NodeOutputs{
+				"weather-api": {Data: map[string]any{"temperature": temperature, "windspeed": windspeed}},
+				"wind-chill":  {Data: map[string]any{"windChill": windChill(temperature, windspeed)}},
+			},
+		})
+		assert.NoError(t, err)
+		return outputs
+	}
+
+	// A strong wind makes it feel colder than the air temperature
+	outputs := run(5, 30)
+	assert.Equal(t, "email-node", outputs.NextNodeID)
+	conditionResult := outputs.Data["conditionResult"].(map[string]any)
+	assert.Equal(t, 5.0, conditionResult["temperature"])
+	assert.InDelta(t, windChill(5, 30), conditionResult["wind-chill.windChill"], 1e-9)
+	assert.Equal(t, "temperature > wind-chill.windChill", conditionResult["expression"])
+	assert.NotContains(t, conditionResult, "threshold")
+	assert.Equal(t, "wind-chill.windChill", outputs.Data["details"].(map[string]any)["compareField"])
+
+	// Wind chill is above the air temperature in calm, mild weather
+	outputs = run(20, 2)
+	assert.Equal(t, "end-node", outputs.NextNodeID)
+
+	t.Run("compares two weather fields", func(t *testing.T) {
+		conditionNode := &Node{config: Config{
+			Field:        FieldWindspeed,
+			CompareField: FieldTemperature,
+			Operator:     models.OperatorGreaterThan,
+			TrueRoute:    "email-node",
+			FalseRoute:   "end-node",
+		}}
+		outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{
+			PriorOutputs: map[string]node.NodeOutputs{
+				"weather-api": {Data: map[string]any{"temperature": 12.0, "windspeed": 25.0}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "email-node", outputs.NextNodeID)
+		conditionResult := outputs.Data["conditionResult"].(map[string]any)
+		assert.Equal(t, 25.0, conditionResult["windspeed"])
+		assert.Equal(t, 12.0, conditionResult["temperature"])
+	})
+
+	t.Run("missing compare field fails", func(t *testing.T) {
+		outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{
+			WorkflowInput: models.WorkflowInput{Operator: models.OperatorGreaterThan},
+			PriorOutputs: map[string]node.NodeOutputs{
+				"weather-api": {Data: map[string]any{"temperature": 5.0}},
+			},
+		})
+		assert.ErrorContains(t, err, "missing wind-chill.windChill")
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+	})
+}
+
+func TestExecutePrefersSharedData(t *testing.T) {
+	conditionNode := &Node{config: Config{TrueRoute: "email-node", FalseRoute: "end-node"}}
+	inputs := node.NodeInputs{
+		WorkflowInput: models.WorkflowInput{Threshold: 30, Operator: models.OperatorGreaterThan},
+		NodeData:      map[string]any{"temperature": 35.0},
+		PriorOutputs: map[string]node.NodeOutputs{
+			"weather-api": {Data: map[string]any{"temperature": 20.0}},
+		},
+	}
+
+	outputs, err := conditionNode.Execute(context.Background(), inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, "email-node", outputs.NextNodeID)
+	assert.Equal(t, 35.0, outputs.Data["conditionResult"].(map[string]any)["temperature"])
+
+	// Without shared data the weather node's output is read
+	inputs.NodeData = nil
+	outputs, err = conditionNode.Execute(context.Background(), inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, "end-node", outputs.NextNodeID)
+}
+
+func TestExecuteComparingText(t *testing.T) {
+	sydney := "Sydney"
+	formOutputs := func(city string) map[string]node.NodeOutputs {
+		return map[string]node.NodeOutputs{
+			"form": {Data: map[string]any{"name": "Alice", "email": "alice@example.com", "city": city}},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		config        Config
+		city          string
+		expectedRoute string
+		expectedMsg   string
+	}{
+		{
+			name:          "equal cities",
+			config:        Config{Field: "city", Value: &sydney},
+			city:          "Sydney",
+			expectedRoute: "sydney-email",
+			expectedMsg:   `city "Sydney" = "Sydney" - condition met`,
+		},
+		{
+			name:          "case and whitespace are ignored",
+			config:        Config{Field: "city", Value: &sydney},
+			city:          " sydney",
+			expectedRoute: "sydney-email",
+			expectedMsg:   `city " sydney" = "Sydney" - condition met`,
+		},
+		{
+			name:          "different cities",
+			config:        Config{Field: "city", Value: &sydney},
+			city:          "Melbourne",
+			expectedRoute: "other-email",
+			expectedMsg:   `city "Melbourne" = "Sydney" - condition not met`,
+		},
+		{
+			name:          "not equals",
+			config:        Config{Field: "city", Value: &sydney, Operator: models.OperatorNotEquals},
+			city:          "Melbourne",
+			expectedRoute: "sydney-email",
+			expectedMsg:   `city "Melbourne" ≠ "Sydney" - condition met`,
+		},
+		{
+			name:          "node-qualified field",
+			config:        Config{Field: "form.city", Value: &sydney},
+			city:          "Sydney",
+			expectedRoute: "sydney-email",
+			expectedMsg:   `form.city "Sydney" = "Sydney" - condition met`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.TrueRoute = "sydney-email"
+			tt.config.FalseRoute = "other-email"
+			conditionNode := &Node{BaseNode: node.BaseNode{ID: "city-check"}, config: tt.config}
+			assert.NoError(t, conditionNode.Validate())
+
+			// The run's weather operator and field don't apply to text comparisons
+			outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{
+				WorkflowInput: models.WorkflowInput{Operator: models.OperatorGreaterThan, Field: FieldWindspeed},
+				PriorOutputs:  formOutputs(tt.city),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, outputs.Status)
+			assert.Equal(t, tt.expectedRoute, outputs.NextNodeID)
+			assert.Equal(t, tt.expectedMsg, outputs.Data["message"])
+
+			conditionResult := outputs.Data["conditionResult"].(map[string]any)
+			assert.Equal(t, tt.city, conditionResult[tt.config.Field])
+			assert.Equal(t, "Sydney", conditionResult["value"])
+			assert.NotContains(t, conditionResult, "threshold")
+		})
+	}
+
+	t.Run("missing field fails", func(t *testing.T) {
+		conditionNode := &Node{config: Config{Field: "city", Value: &sydney, TrueRoute: "a", FalseRoute: "b"}}
+		outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{})
+		assert.ErrorContains(t, err, "missing city")
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+	})
+}
+
+func TestNewNodeReadsTextValue(t *testing.T) {
+	n, err := NewNode(models.Node{ID: "city-check", Type: models.NodeTypeCondition, Data: models.NodeData{
+		Metadata: map[string]any{
+			"conditionField": "city",
+			"value":          "Sydney",
+			"operator":       "!=",
+			"trueRoute":      "a",
+			"falseRoute":     "b",
+		},
+	}})
+	assert.NoError(t, err)
+	conditionNode := n.(*Node)
+	if assert.NotNil(t, conditionNode.config.Value) {
+		assert.Equal(t, "Sydney", *conditionNode.config.Value)
+	}
+	assert.Equal(t, models.OperatorNotEquals, conditionNode.config.Operator)
+	assert.NoError(t, n.Validate())
+}
+
+func TestValidateTextComparison(t *testing.T) {
+	sydney := "Sydney"
+	tests := []struct {
+		name          string
+		config        Config
+		errorContains string
+	}{
+		{"no field", Config{Value: &sydney}, "conditionField is required"},
+		{"ordering operator", Config{Field: "city", Value: &sydney, Operator: models.OperatorGreaterThan}, "cannot compare text"},
+		{"with compare field", Config{Field: "city", Value: &sydney, CompareField: "location"}, "cannot both be set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.TrueRoute = "a"
+			tt.config.FalseRoute = "b"
+			conditionNode := &Node{config: tt.config}
+			assert.ErrorContains(t, conditionNode.Validate(), tt.errorContains)
+		})
+	}
+}
+
+func TestExecuteFormattedPrecision(t *testing.T) {
+	two := 2
+	zero := 0
+	threshold := 20.0
+
+	tests := []struct {
+		name     string
+		config   Config
+		weather  map[string]any
+		expected map[string]string
+	}{
+		{
+			name:     "threshold comparison",
+			config:   Config{Precision: &two, Threshold: &threshold},
+			weather:  map[string]any{"temperature": 20.5},
+			expected: map[string]string{"temperature": "20.50", "threshold": "20.00"},
+		},
+		{
+			name:     "rounded to whole numbers",
+			config:   Config{Precision: &zero, Threshold: &threshold},
+			weather:  map[string]any{"temperature": 20.5},
+			expected: map[string]string{"temperature": "20", "threshold": "20"},
+		},
+		{
+			name:     "field comparison",
+			config:   Config{Precision: &two, CompareField: "wind-chill.windChill"},
+			weather:  map[string]any{"temperature": 18.0},
+			expected: map[string]string{"temperature": "18.00", "wind-chill.windChill": "14.33"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.TrueRoute = "email"
+			tt.config.FalseRoute = "end"
+			conditionNode := &Node{config: tt.config}
+			assert.NoError(t, conditionNode.Validate())
+
+			outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{
+				WorkflowInput: models.WorkflowInput{Operator: models.OperatorGreaterThan},
+				PriorOutputs: map[string]node.NodeOutputs{
+					"weather-api": {Data: tt.weather},
+					"wind-chill":  {Data: map[string]any{"windChill": 14.3333}},
+				},
+			})
+			assert.NoError(t, err)
+			conditionResult := outputs.Data["conditionResult"].(map[string]any)
+			assert.Equal(t, tt.expected, conditionResult["formatted"])
+			// The raw numbers are still recorded
+			assert.Equal(t, tt.weather["temperature"], conditionResult["temperature"])
+		})
+	}
+
+	t.Run("omitted by default", func(t *testing.T) {
+		conditionNode := &Node{config: Config{Threshold: &threshold, TrueRoute: "email", FalseRoute: "end"}}
+		outputs, err := conditionNode.Execute(context.Background(), node.NodeInputs{
+			WorkflowInput: models.WorkflowInput{Operator: models.OperatorGreaterThan},
+			PriorOutputs:  map[string]node.NodeOutputs{"weather-api": {Data: map[string]any{"temperature": 20.5}}},
+		})
+		assert.NoError(t, err)
+		assert.NotContains(t, outputs.Data["conditionResult"], "formatted")
+	})
+}
+
+func TestNewNodeReadsPrecision(t *testing.T) {
+	tests := []struct {
+		precision any
+		valid     bool
+	}{
+		{2.0, true},
+		{0.0, true},
+		{float64(MaxPrecision), true},
+		{1.5, false},
+		{-1.0, false},
+		{7.0, false},
+	}
+
+	for _, tt := range tests {
+		n, err := NewNode(models.Node{ID: "check", Type: models.NodeTypeCondition, Data: models.NodeData{
+			Metadata: map[string]any{"precision": tt.precision, "trueRoute": "a", "falseRoute": "b"},
+		}})
+		assert.NoError(t, err)
+		if tt.valid {
+			assert.NoError(t, n.Validate(), "precision %v", tt.precision)
+		} else {
+			assert.ErrorContains(t, n.Validate(), "precision must be a whole number", "precision %v", tt.precision)
+		}
+	}
+}
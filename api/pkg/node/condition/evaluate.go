@@ -0,0 +1,189 @@
+package condition
+
+import (
+    "fmt"
+    "strings"
+    "workflow-code-test/api/pkg/models"
+    "workflow-code-test/api/pkg/node/integration/weather"
+)
+
+// Units the weather node reports each field in
+const (
+    UnitCelsius = "°C"
+    UnitKmh     = "km/h"
+    UnitPercent = "%"
+)
+
+// Evaluation is the outcome of comparing a weather value against a threshold, or
+// against a second field's value when CompareField is set
+type Evaluation struct {
+    Field        string          `json:"field"`
+    Value        float64         `json:"value"`
+    Unit         string          `json:"unit"`
+    Operator     models.Operator `json:"operator"`
+    Threshold    float64         `json:"threshold"`
+    CompareField string          `json:"compareField,omitempty"`
+    // Text and ExpectedText replace Value and Threshold in text comparisons
+    Text         string          `json:"text,omitempty"`
+    ExpectedText string          `json:"expectedText,omitempty"`
+    Met          bool            `json:"result"`
+    Expression   string          `json:"expression"`
+    Message      string          `json:"message"`
+    Emoji        string          `json:"emoji"`
+}
+
+// Unit returns the unit a condition field is measured in
+func Unit(field string) (string, error) {
+    switch field {
+    case FieldTemperature:
+        return UnitCelsius, nil
+    case FieldWindspeed:
+        return UnitKmh, nil
+    case FieldHumidity:
+        return UnitPercent, nil
+    default:
+        return "", fmt.Errorf("unsupported condition field: %s", field)
+    }
+}
+
+// Evaluate compares value against threshold with operator, producing the same
+// result and message a condition node records. An empty field means temperature.
+func Evaluate(field string, value float64, operator models.Operator, threshold float64) (Evaluation, error) {
+    if field == "" {
+        field = FieldTemperature
+    }
+    unit, err := Unit(field)
+    if err != nil {
+        return Evaluation{}, err
+    }
+    
+    // Resolve the symbol up front so an unknown operator fails before any output is built
+    operatorSymbol, err := operator.LookupSymbol()
+    if err != nil {
+        return Evaluation{}, err
+    }
+    
+    conditionMet, err := compare(value, operator, threshold)
+    if err != nil {
+        return Evaluation{}, err
+    }
+    
+    outcome := map[bool]string{true: "met", false: "not met"}[conditionMet]
+    var message, emoji string
+    switch field {
+    case FieldWindspeed:
+        windEmoji := weather.WindEmoji{}
+        emoji = windEmoji.Emoji(value)
+        message = fmt.Sprintf("%s %s %.1f km/h - condition %s",
+                  windEmoji.Message(value), operatorSymbol, threshold, outcome)
+    case FieldHumidity:
+        emoji = "💧"
+        message = fmt.Sprintf("Humidity %.0f%% %s %.0f%% %s - condition %s",
+                  value, operatorSymbol, threshold, emoji, outcome)
+    default:
+        weatherEmoji := weather.WeatherEmoji{}
+        emoji = weatherEmoji.Emoji(value)
+        message = fmt.Sprintf("Temperature %.1f°C %s %.1f°C %s - condition %s", 
+                  value, operatorSymbol, threshold, emoji, outcome)
+    }
+    
+    return Evaluation{
+        Field:      field,
+        Value:      value,
+        Unit:       unit,
+        Operator:   operator,
+        Threshold:  threshold,
+        Met:        conditionMet,
+        Expression: fmt.Sprintf("%s %s threshold", field, operatorSymbol),
+        Message:    message,
+        Emoji:      emoji,
+    }, nil
+}
+
+// EvaluateFields compares the value of one field against the value of another, e.g.
+// temperature against a wind chill, instead of against a fixed threshold. Field
+// names are free-form since they name values resolved from prior node outputs.
+func EvaluateFields(field string, value float64, operator models.Operator, compareField string, compareValue float64) (Evaluation, error) {
+    operatorSymbol, err := operator.LookupSymbol()
+    if err != nil {
+        return Evaluation{}, err
+    }
+    conditionMet, err := compare(value, operator, compareValue)
+    if err != nil {
+        return Evaluation{}, err
+    }
+    
+    // Values only share a unit when both fields are known and measured alike
+    unit, _ := Unit(field)
+    if compareUnit, _ := Unit(compareField); compareUnit != unit {
+        unit = ""
+    }
+    
+    outcome := map[bool]string{true: "met", false: "not met"}[conditionMet]
+    return Evaluation{
+        Field:        field,
+        Value:        value,
+        Unit:         unit,
+        Operator:     operator,
+        Threshold:    compareValue,
+        CompareField: compareField,
+        Met:          conditionMet,
+        Expression:   fmt.Sprintf("%s %s %s", field, operatorSymbol, compareField),
+        Message: fmt.Sprintf("%s %.1f%s %s %s %.1f%s - condition %s",
+            field, value, unit, operatorSymbol, compareField, compareValue, unit, outcome),
+    }, nil
+}
+
+// EvaluateText compares a text field such as the form's city against an expected
+// value. Only equals and not_equals apply; letter case and surrounding whitespace
+// are ignored, so "sydney " equals "Sydney".
+func EvaluateText(field, text string, operator models.Operator, expected string) (Evaluation, error) {
+    if !isTextOperator(operator) {
+        return Evaluation{}, fmt.Errorf("unsupported operator for text: %s", operator)
+    }
+    operatorSymbol, err := operator.LookupSymbol()
+    if err != nil {
+        return Evaluation{}, err
+    }
+    
+    conditionMet := strings.EqualFold(strings.TrimSpace(text), strings.TrimSpace(expected))
+    if operator == models.OperatorNotEquals {
+        conditionMet = !conditionMet
+    }
+    
+    outcome := map[bool]string{true: "met", false: "not met"}[conditionMet]
+    return Evaluation{
+        Field:        field,
+        Operator:     operator,
+        Text:         text,
+        ExpectedText: expected,
+        Met:          conditionMet,
+        Expression:   fmt.Sprintf("%s %s %q", field, operatorSymbol, expected),
+        Message:      fmt.Sprintf("%s %q %s %q - condition %s", field, text, operatorSymbol, expected, outcome),
+    }, nil
+}
+
+// isTextOperator reports whether operator can compare text
+func isTextOperator(operator models.Operator) bool {
+    return operator == models.OperatorEquals || operator == models.OperatorNotEquals
+}
+
+// compare applies operator to value and threshold
+func compare(value float64, operator models.Operator, threshold float64) (bool, error) {
+    switch operator {
+    case models.OperatorGreaterThan:
+        return value > threshold, nil
+    case models.OperatorLessThan:
+        return value < threshold, nil
+    case models.OperatorEquals:
+        return value == threshold, nil
+    case models.OperatorGreaterThanOrEqual:
+        return value >= threshold, nil
+    case models.OperatorLessThanOrEqual:
+        return value <= threshold, nil
+    case models.OperatorNotEquals:
+        return value != threshold, nil
+    default:
+        return false, fmt.Errorf("unsupported operator: %s", operator)
+    }
+}
@@ -0,0 +1,103 @@
+package condition
+
+import (
+	"testing"
+	"workflow-code-test/api/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		field     string
+		value     float64
+		operator  models.Operator
+		threshold float64
+		expected  bool
+		unit      string
+		emoji     string
+	}{
+		{"cold day fires less than", "", 6, models.OperatorLessThan, 10, true, UnitCelsius, "🧥"},
+		{"warm day does not fire less than", FieldTemperature, 26, models.OperatorLessThan, 10, false, UnitCelsius, "😎"},
+		{"equal boundary", FieldTemperature, 10, models.OperatorGreaterThanOrEqual, 10, true, UnitCelsius, "🧥"},
+		{"windspeed", FieldWindspeed, 25, models.OperatorGreaterThan, 20, true, UnitKmh, "💨"},
+		{"humidity", FieldHumidity, 85, models.OperatorGreaterThanOrEqual, 80, true, UnitPercent, "💧"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluation, err := Evaluate(tc.field, tc.value, tc.operator, tc.threshold)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, evaluation.Met)
+			assert.Equal(t, tc.unit, evaluation.Unit)
+			assert.Equal(t, tc.emoji, evaluation.Emoji)
+			assert.Contains(t, evaluation.Message, tc.emoji)
+			assert.Contains(t, evaluation.Message, map[bool]string{true: "condition met", false: "condition not met"}[tc.expected])
+		})
+	}
+
+	t.Run("unsupported operator", func(t *testing.T) {
+		_, err := Evaluate(FieldTemperature, 6, "invalid_operator", 10)
+		assert.ErrorContains(t, err, "unsupported operator")
+	})
+
+	t.Run("unsupported field", func(t *testing.T) {
+		_, err := Evaluate("pressure", 6, models.OperatorLessThan, 10)
+		assert.ErrorContains(t, err, "unsupported condition field")
+	})
+}
+
+func TestEvaluateFields(t *testing.T) {
+	evaluation, err := EvaluateFields(FieldTemperature, 5, models.OperatorGreaterThan, "windChill", 0.8)
+	assert.NoError(t, err)
+	assert.True(t, evaluation.Met)
+	assert.Equal(t, "windChill", evaluation.CompareField)
+	assert.Equal(t, 0.8, evaluation.Threshold)
+	assert.Equal(t, "temperature > windChill", evaluation.Expression)
+	assert.Empty(t, evaluation.Unit, "a unit is only given when both fields share one")
+	assert.Equal(t, "temperature 5.0 > windChill 0.8 - condition met", evaluation.Message)
+
+	t.Run("shared unit", func(t *testing.T) {
+		evaluation, err := EvaluateFields(FieldTemperature, 12, models.OperatorLessThanOrEqual, FieldTemperature, 12)
+		assert.NoError(t, err)
+		assert.True(t, evaluation.Met)
+		assert.Equal(t, UnitCelsius, evaluation.Unit)
+	})
+
+	t.Run("unsupported operator", func(t *testing.T) {
+		_, err := EvaluateFields(FieldTemperature, 5, "invalid_operator", FieldWindspeed, 10)
+		assert.ErrorContains(t, err, "unsupported operator")
+	})
+}
+
+func TestEvaluateText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		operator models.Operator
+		expected bool
+	}{
+		{"same city", "Sydney", models.OperatorEquals, true},
+		{"different case", "SYDNEY", models.OperatorEquals, true},
+		{"different city", "Melbourne", models.OperatorEquals, false},
+		{"not equals a different city", "Melbourne", models.OperatorNotEquals, true},
+		{"not equals the same city", "Sydney", models.OperatorNotEquals, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluation, err := EvaluateText("city", tc.text, tc.operator, "Sydney")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, evaluation.Met)
+			assert.Equal(t, tc.text, evaluation.Text)
+			assert.Equal(t, "Sydney", evaluation.ExpectedText)
+			assert.Equal(t, "city "+tc.operator.Symbol()+` "Sydney"`, evaluation.Expression)
+		})
+	}
+
+	t.Run("ordering operators are rejected", func(t *testing.T) {
+		_, err := EvaluateText("city", "Sydney", models.OperatorGreaterThan, "Melbourne")
+		assert.ErrorContains(t, err, "unsupported operator for text")
+	})
+}
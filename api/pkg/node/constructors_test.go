@@ -0,0 +1,65 @@
+package node_test
+
+import (
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+	"workflow-code-test/api/pkg/node/checkpoint"
+	"workflow-code-test/api/pkg/node/condition"
+	"workflow-code-test/api/pkg/node/email"
+	"workflow-code-test/api/pkg/node/end"
+	"workflow-code-test/api/pkg/node/form"
+	"workflow-code-test/api/pkg/node/integration"
+	"workflow-code-test/api/pkg/node/start"
+	"workflow-code-test/api/pkg/node/state"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewNodeWithoutMetadata constructs every node type with nil and empty metadata.
+// Types that need configuration fail with ErrMissingMetadata; the rest use defaults.
+func TestNewNodeWithoutMetadata(t *testing.T) {
+	tests := []struct {
+		nodeType         models.NodeType
+		factory          node.NodeFactory
+		requiresMetadata bool
+	}{
+		{models.NodeTypeStart, start.NewNode, false},
+		{models.NodeTypeForm, form.NewNode, false},
+		{models.NodeTypeIntegration, integration.NewNode, true},
+		{models.NodeTypeCondition, condition.NewNode, false},
+		{models.NodeTypeEmail, email.NewNode, true},
+		{models.NodeTypeEnd, end.NewNode, false},
+		{models.NodeTypeState, state.NewNodeFactory(nil), true},
+		{models.NodeTypeCheckpoint, checkpoint.NewNode, false},
+	}
+
+	for _, tt := range tests {
+		for name, metadata := range map[string]map[string]any{"nil": nil, "empty": {}} {
+			t.Run(string(tt.nodeType)+"/"+name, func(t *testing.T) {
+				model := models.Node{
+					ID:   "node-1",
+					Type: tt.nodeType,
+					Data: models.NodeData{Label: "Node", Metadata: metadata},
+				}
+
+				n, err := tt.factory(model)
+				if tt.requiresMetadata {
+					assert.ErrorIs(t, err, node.ErrMissingMetadata)
+					assert.ErrorContains(t, err, string(tt.nodeType)+" node node-1")
+					assert.Nil(t, n)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.nodeType, n.Type())
+			})
+		}
+	}
+}
+
+func TestRequireMetadata(t *testing.T) {
+	metadata := map[string]any{"apiEndpoint": "https://example.com"}
+	got, err := node.RequireMetadata(models.Node{Data: models.NodeData{Metadata: metadata}})
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, got)
+}
@@ -0,0 +1,23 @@
+package email
+
+import (
+	"context"
+	"time"
+)
+
+// AlertHistory looks up alerts already sent, so a sustained condition doesn't
+// email the same person on every run
+type AlertHistory interface {
+	// LastAlertSent returns when an alert about city last went to recipient,
+	// considering only alerts sent at or after since
+	LastAlertSent(ctx context.Context, recipient, city string, since time.Time) (time.Time, bool, error)
+}
+
+// recentAlert reports when an alert to recipient about city went out inside the
+// node's dedup window. Deduplication is off without a history or a window.
+func (n *Node) recentAlert(ctx context.Context, recipient, city string) (time.Time, bool, error) {
+	if n.history == nil || n.DedupWindow <= 0 {
+		return time.Time{}, false, nil
+	}
+	return n.history.LastAlertSent(ctx, recipient, city, n.currentTime().Add(-n.DedupWindow))
+}
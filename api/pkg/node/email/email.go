@@ -3,6 +3,8 @@ package email
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 	"workflow-code-test/api/pkg/mailer"
 	"workflow-code-test/api/pkg/models"
@@ -12,22 +14,123 @@ import (
 // Node implements an email node
 type Node struct {
 	node.BaseNode
-	InputVariables []string            `json:"inputVariables"`
-	EmailTemplate  mailer.EmailTemplate `json:"emailTemplate"`
+	InputVariables   []string          `json:"inputVariables"`
+	VariableMappings []VariableMapping `json:"variableMappings"`
+	// AllowedVariables are the only variables templates may render; nil allows any.
+	// Nodes built from metadata default to DefaultAllowedVariables plus the
+	// variables they declare
+	AllowedVariables []string             `json:"allowedVariables,omitempty"`
+	EmailTemplate    mailer.EmailTemplate `json:"emailTemplate"`
+	// FalseTemplate is sent when the condition isn't met; without it that branch sends nothing
+	FalseTemplate    *mailer.EmailTemplate `json:"falseTemplate,omitempty"`
+	// ConditionNode names the condition whose result picks the template. Empty uses
+	// the latest condition the run went through.
+	ConditionNode    string                `json:"conditionNode,omitempty"`
+	AttachReport     bool                  `json:"attachReport"`
+	UnresolvedPolicy string                `json:"unresolvedPolicy"`
+	QuietHours       *QuietHours           `json:"quietHours,omitempty"`
+	// DeferDuringQuietHours records a deferred output instead of sending inside quiet hours
+	DeferDuringQuietHours bool `json:"deferDuringQuietHours"`
+	// UsesDefaultTemplate is set when the node had no template and uses the fallback
+	UsesDefaultTemplate bool `json:"usesDefaultTemplate,omitempty"`
+	// DedupWindow suppresses an alert when one about the same city went to the
+	// same recipient within it; zero sends every alert
+	DedupWindow time.Duration `json:"dedupWindow,omitempty"`
+	// Disabled records every email as suppressed without rendering or sending it
+	Disabled bool `json:"disabled,omitempty"`
+
+	history AlertHistory       // sent alerts, for deduplication
+	sender  mailer.Sender      // delivers emails; nil stub-sends them
+	retries *mailer.RetryQueue // re-sends emails whose delivery failed transiently
+	now     func() time.Time // overridable clock for tests
+	send    sendFunc         // overridable mailer for tests
+}
+
+// sendFunc renders and sends an email, matching mailer.PrepareAndStubSendEmail
+type sendFunc func(to string, variables map[string]any, template mailer.EmailTemplate, attachments ...mailer.Attachment) (map[string]any, error)
+
+// Policies for template placeholders that have no matching variable
+const (
+	UnresolvedPolicyWarn = "warn" // send anyway and report the placeholders in the output details
+	UnresolvedPolicyFail = "fail" // fail the step without sending
+)
+
+// VariableMapping binds a template variable to a key in a specific prior node's output
+type VariableMapping struct {
+	As       string `json:"as"`
+	FromNode string `json:"fromNode"`
+	Key      string `json:"key"`
+}
+
+// DefaultAllowedVariables are the variables an email node's templates may render,
+// along with its inputVariables and mapped names, unless its metadata lists
+// allowedVariables
+var DefaultAllowedVariables = []string{"city", "temperature", "name", "emoji"}
+
+// Built-in template for email nodes created without one, such as minimal imports
+const (
+	DefaultSubject = "Weather alert for {{city}}"
+	DefaultBody    = "Weather alert for {{city}}: {{temperature}}°C"
+)
+
+// DefaultTemplate returns the built-in fallback template
+func DefaultTemplate() mailer.EmailTemplate {
+	return mailer.EmailTemplate{Subject: DefaultSubject, Body: DefaultBody}
 }
 
-// NewNode creates an email node from a model
+// FactoryConfig holds the settings shared by every email node a factory creates
+type FactoryConfig struct {
+	// DefaultTemplate is used by nodes without a template; leave it empty to
+	// keep the template required
+	DefaultTemplate mailer.EmailTemplate
+	// History enables alert deduplication; nil sends every alert
+	History AlertHistory
+	// DedupWindow applies to nodes whose metadata doesn't set dedupWindowMinutes
+	DedupWindow time.Duration
+	// Disabled turns off sending for every node, e.g. in staging; unlike a dry run
+	// it applies to all executions and nothing is rendered
+	Disabled bool
+	// Sender delivers emails, e.g. over SMTP; nil stub-sends them
+	Sender mailer.Sender
+	// Retries re-sends emails whose delivery failed transiently, so the step is
+	// queued rather than failed; nil fails the step
+	Retries *mailer.RetryQueue
+}
+
+// NewNode creates an email node from a model, using DefaultTemplate when the
+// model has no template of its own
 func NewNode(model models.Node) (node.Node, error) {
+	return newNode(model, FactoryConfig{DefaultTemplate: DefaultTemplate()})
+}
+
+// NewNodeFactory returns a constructor for email nodes configured by config
+func NewNodeFactory(config FactoryConfig) node.NodeFactory {
+	return func(model models.Node) (node.Node, error) {
+		return newNode(model, config)
+	}
+}
+
+func newNode(model models.Node, config FactoryConfig) (node.Node, error) {
+	metadata, err := node.RequireMetadata(model)
+	if err != nil {
+		return nil, err
+	}
+	
 	emailNode := &Node{
 		BaseNode: node.BaseNode{
 			ID:          model.ID,
 			Label:       model.Data.Label,
 			Description: model.Data.Description,
 		},
+		DedupWindow: config.DedupWindow,
+		Disabled:    config.Disabled,
+		history:     config.History,
+		sender:      config.Sender,
+		retries:     config.Retries,
 	}
 	
 	// Extract metadata fields if available
-	if meta, ok := model.Data.Metadata["inputVariables"]; ok {
+	if meta, ok := metadata["inputVariables"]; ok {
 		// Get input variables
 		if inputVars, ok := meta.([]any); ok {
 			for _, v := range inputVars {
@@ -36,19 +139,91 @@ func NewNode(model models.Node) (node.Node, error) {
 				}
 			}
 		}
-		
-		// Get email template
-		if templateData, ok := model.Data.Metadata["emailTemplate"]; ok {
-			if template, ok := templateData.(map[string]any); ok {
-				if subject, ok := template["subject"].(string); ok {
-					emailNode.EmailTemplate.Subject = subject
-				}
-				if body, ok := template["body"].(string); ok {
-					emailNode.EmailTemplate.Body = body
-				}
+	}
+
+	// Get explicit variable mappings
+	if mappings, ok := metadata["variableMappings"].([]any); ok {
+		for _, m := range mappings {
+			if mapping, ok := m.(map[string]any); ok {
+				as, _ := mapping["as"].(string)
+				fromNode, _ := mapping["fromNode"].(string)
+				key, _ := mapping["key"].(string)
+				emailNode.VariableMappings = append(emailNode.VariableMappings, VariableMapping{
+					As:       as,
+					FromNode: fromNode,
+					Key:      key,
+				})
 			}
 		}
 	}
+
+	// Get the variables templates may render
+	emailNode.AllowedVariables = defaultAllowedVariables(emailNode.InputVariables, emailNode.VariableMappings)
+	if allowed, ok := metadata["allowedVariables"].([]any); ok {
+		emailNode.AllowedVariables = make([]string, 0, len(allowed))
+		for _, v := range allowed {
+			name, _ := v.(string)
+			emailNode.AllowedVariables = append(emailNode.AllowedVariables, name)
+		}
+	}
+
+	// Get email template
+	if template, ok := metadata["emailTemplate"].(map[string]any); ok {
+		emailNode.EmailTemplate = parseTemplate(template)
+	}
+	// Get per-branch templates; "true" takes the place of emailTemplate
+	branchTemplates, hasBranchTemplates := metadata["emailTemplates"].(map[string]any)
+	if template, ok := branchTemplates["true"].(map[string]any); ok {
+		emailNode.EmailTemplate = parseTemplate(template)
+	}
+	if template, ok := branchTemplates["false"].(map[string]any); ok {
+		falseTemplate := parseTemplate(template)
+		emailNode.FalseTemplate = &falseTemplate
+	}
+	if conditionNode, ok := metadata["conditionNode"].(string); ok {
+		emailNode.ConditionNode = conditionNode
+	}
+	if engine, ok := metadata["templateEngine"].(string); ok {
+		emailNode.EmailTemplate.Engine = engine
+		if emailNode.FalseTemplate != nil {
+			emailNode.FalseTemplate.Engine = engine
+		}
+	}
+	// Only a node with no template at all falls back; a half-written one, or branch
+	// templates missing the true branch, are left for Validate to reject
+	if defaultTemplate := config.DefaultTemplate; !hasBranchTemplates &&
+		emailNode.EmailTemplate.Subject == "" && emailNode.EmailTemplate.Body == "" &&
+		defaultTemplate.Subject != "" && defaultTemplate.Body != "" {
+		emailNode.EmailTemplate = defaultTemplate
+		emailNode.UsesDefaultTemplate = true
+	}
+
+	// Check whether a weather report should be attached
+	if attachReport, ok := metadata["attachReport"].(bool); ok {
+		emailNode.AttachReport = attachReport
+	}
+
+	// Get the policy for unresolved template placeholders
+	emailNode.UnresolvedPolicy = UnresolvedPolicyWarn
+	if policy, ok := metadata["unresolvedPolicy"].(string); ok && policy != "" {
+		emailNode.UnresolvedPolicy = policy
+	}
+
+	// Get the quiet hours window
+	if quietHours, ok := metadata["quietHours"].(map[string]any); ok {
+		start, _ := quietHours["start"].(string)
+		end, _ := quietHours["end"].(string)
+		timezone, _ := quietHours["timezone"].(string)
+		emailNode.QuietHours = &QuietHours{Start: start, End: end, Timezone: timezone}
+	}
+	if deferDuringQuietHours, ok := metadata["deferDuringQuietHours"].(bool); ok {
+		emailNode.DeferDuringQuietHours = deferDuringQuietHours
+	}
+
+	// Get the alert deduplication window; 0 turns deduplication off for the node
+	if minutes, ok := metadata["dedupWindowMinutes"].(float64); ok {
+		emailNode.DedupWindow = time.Duration(minutes * float64(time.Minute))
+	}
 	
 	return emailNode, nil
 }
@@ -72,36 +247,62 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 		StartedAt: started.Format(time.RFC3339),
 	}
 	
-	// Check if condition was met from prior condition node
-	conditionNodeOutput, ok := inputs.PriorOutputs[string(models.NodeIDCondition)]
-	if !ok {
-		outputs.Status = models.StatusFailed
-		outputs.Data["message"] = "Failed to process email"
-		outputs.Data["error"] = "Failed to get condition result"
-		outputs.EndedAt = time.Now().Format(time.RFC3339)
-		return outputs, fmt.Errorf("failed to get condition result")
-	}
-	
-	// Get the condition result from the new structure
-	conditionResult, ok := conditionNodeOutput.Data["conditionResult"].(map[string]any)
-	if !ok {
-		outputs.Status = models.StatusFailed
-		outputs.Data["message"] = "Failed to process email"
-		outputs.Data["error"] = "Failed to get condition result"
+	// A disabled mailer suppresses the email whatever the condition decided
+	if n.Disabled {
+		outputs.Data = map[string]any{
+			"message": "Email suppressed (disabled)",
+			"details": map[string]any{
+				"reason":     "Email disabled",
+				"suppressed": true,
+			},
+		}
+		outputs.Explanation = "Email not sent because sending is disabled for this deployment"
+		outputs.Suppressed = true
+		outputs.Status = models.StatusCompleted
 		outputs.EndedAt = time.Now().Format(time.RFC3339)
-		return outputs, fmt.Errorf("invalid condition result format")
+		return outputs, nil
 	}
 	
-	conditionMet, ok := conditionResult["result"].(bool)
-	if !ok {
-		outputs.Status = models.StatusFailed
-		outputs.Data["message"] = "Failed to process email"
-		outputs.Data["error"] = "Failed to get condition result"
-		outputs.EndedAt = time.Now().Format(time.RFC3339)
-		return outputs, fmt.Errorf("invalid condition result format")
+	// Check whether the condition whose branch the run is on was met. Without a
+	// condition upstream the email is unconditional and takes the true branch.
+	conditionMet := true
+	if conditionNodeID := n.conditionNodeID(inputs); conditionNodeID != "" {
+		conditionNodeOutput, ok := inputs.PriorOutputs[conditionNodeID]
+		if !ok {
+			outputs.Status = models.StatusFailed
+			outputs.Data["message"] = "Failed to process email"
+			outputs.Data["error"] = "Failed to get condition result"
+			outputs.EndedAt = time.Now().Format(time.RFC3339)
+			return outputs, fmt.Errorf("failed to get condition result from node %s", conditionNodeID)
+		}
+		
+		// Get the condition result from the new structure
+		conditionResult, ok := conditionNodeOutput.Map("conditionResult")
+		if !ok {
+			outputs.Status = models.StatusFailed
+			outputs.Data["message"] = "Failed to process email"
+			outputs.Data["error"] = "Failed to get condition result"
+			outputs.EndedAt = time.Now().Format(time.RFC3339)
+			return outputs, fmt.Errorf("invalid condition result format")
+		}
+		
+		conditionMet, ok = conditionResult["result"].(bool)
+		if !ok {
+			outputs.Status = models.StatusFailed
+			outputs.Data["message"] = "Failed to process email"
+			outputs.Data["error"] = "Failed to get condition result"
+			outputs.EndedAt = time.Now().Format(time.RFC3339)
+			return outputs, fmt.Errorf("invalid condition result format")
+		}
 	}
 	
-	if conditionMet {
+	// Send the template for the condition's branch; the false branch only sends when it has one
+	if conditionMet || n.FalseTemplate != nil {
+		template := n.EmailTemplate
+		if !conditionMet {
+			template = *n.FalseTemplate
+		}
+		
 		// Get required info from form outputs
 		formOutput, ok := inputs.PriorOutputs[string(models.NodeIDForm)]
 		if !ok {
@@ -113,7 +314,7 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 		}
 		
 		// Get email recipient
-		email, ok := formOutput.Data["email"].(string)
+		email, ok := formOutput.String("email")
 		if !ok {
 			outputs.Status = models.StatusFailed
 			outputs.Data["message"] = "Failed to process email"
@@ -122,33 +323,133 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 			return outputs, fmt.Errorf("missing email")
 		}
 		
-		// Collect all template variables from various node outputs
-		templateVars := make(map[string]any)
-		
-		// Collect all required input variables from prior outputs
-		for _, varName := range n.InputVariables {
-			// For each input variable, check in all prior outputs
-			found := false
-			
-			for _, output := range inputs.PriorOutputs {
-				if value, ok := output.Data[varName]; ok {
-					templateVars[varName] = value
-					found = true
-					break
+		// Skip the alert when the recipient already heard about this city recently;
+		// false-branch emails aren't alerts and are never suppressed
+		city, _ := formOutput.String("city")
+		if conditionMet {
+			lastSent, recent, err := n.recentAlert(ctx, email, city)
+			if err != nil {
+				// A failed lookup shouldn't cost the recipient their alert
+				inputs.Log().Warn("Alert history lookup failed, sending anyway", "error", err)
+			} else if recent {
+				outputs.Data = map[string]any{
+					"message": "Email not sent - suppressed (recent alert)",
+					"details": map[string]any{
+						"reason":      "Suppressed (recent alert)",
+						"suppressed":  true,
+						"lastSentAt":  lastSent.Format(time.RFC3339),
+						"dedupWindow": n.DedupWindow.String(),
+						"to":          email,
+						"city":        city,
+					},
 				}
+				outputs.Explanation = fmt.Sprintf("Email to %s not sent because an alert about %s already went to them at %s, within the %s deduplication window",
+					email, city, lastSent.Format(time.RFC3339), n.DedupWindow)
+				outputs.Suppressed = true
+				outputs.Status = models.StatusCompleted
+				outputs.EndedAt = time.Now().Format(time.RFC3339)
+				return outputs, nil
 			}
-			
-			if !found {
+		}
+		
+		// Hold the email back when it would arrive during quiet hours
+		if n.DeferDuringQuietHours && n.QuietHours != nil {
+			quiet, endsAt, err := n.QuietHours.Contains(n.currentTime())
+			if err != nil {
 				outputs.Status = models.StatusFailed
 				outputs.Data["message"] = "Failed to process email"
-				outputs.Data["error"] = fmt.Sprintf("Missing required variable: %s", varName)
+				outputs.Data["error"] = node.Capitalize(err.Error())
 				outputs.EndedAt = time.Now().Format(time.RFC3339)
-				return outputs, fmt.Errorf("missing required variable: %s", varName)
+				return outputs, err
 			}
+			if quiet {
+				outputs.Data = map[string]any{
+					"message": "Email deferred - quiet hours",
+					"details": map[string]any{
+						"reason":        "Quiet hours",
+						"deferred":      true,
+						"deferredUntil": endsAt.Format(time.RFC3339),
+						"to":            email,
+					},
+				}
+				outputs.Explanation = fmt.Sprintf("Email to %s deferred until %s because it would arrive during quiet hours", email, endsAt.Format(time.RFC3339))
+				outputs.Suppressed = true
+				outputs.Status = models.StatusCompleted
+				outputs.EndedAt = time.Now().Format(time.RFC3339)
+				return outputs, nil
+			}
+		}
+		
+		// Collect all template variables from various node outputs
+		templateVars, err := n.collectVariables(inputs.PriorOutputs)
+		if err != nil {
+			outputs.Status = models.StatusFailed
+			outputs.Data["message"] = "Failed to process email"
+			outputs.Data["error"] = node.Capitalize(err.Error())
+			outputs.EndedAt = time.Now().Format(time.RFC3339)
+			return outputs, err
+		}
+		
+		// Keep variables off the allowlist out of the email, e.g. sensitive fields of a prior output
+		templateVars, blocked := mailer.AllowedVariables(templateVars, n.AllowedVariables)
+		
+		// Check for placeholders the collected variables can't fill
+		unresolved := mailer.UnresolvedPlaceholders(template, templateVars)
+		if len(unresolved) > 0 && n.UnresolvedPolicy == UnresolvedPolicyFail {
+			outputs.Status = models.StatusFailed
+			outputs.Data["message"] = "Failed to process email"
+			outputs.Data["error"] = fmt.Sprintf("Unresolved template placeholders: %s", strings.Join(unresolved, ", "))
+			outputs.Data["details"] = map[string]any{"unresolvedPlaceholders": unresolved}
+			outputs.EndedAt = time.Now().Format(time.RFC3339)
+			return outputs, fmt.Errorf("unresolved template placeholders: %s", strings.Join(unresolved, ", "))
+		}
+		
+		// Generate the weather report attachment if requested
+		var attachments []mailer.Attachment
+		if n.AttachReport {
+			report, err := buildReport(inputs.PriorOutputs)
+			if err != nil {
+				outputs.Status = models.StatusFailed
+				outputs.Data["message"] = "Failed to process email"
+				outputs.Data["error"] = fmt.Sprintf("Failed to generate report: %v", err)
+				outputs.EndedAt = time.Now().Format(time.RFC3339)
+				return outputs, fmt.Errorf("report generation failed: %w", err)
+			}
+			attachments = append(attachments, report)
 		}
 		
 		// Use the mailer with template support
-		emailPayload, err := mailer.PrepareAndStubSendEmail(email, templateVars, n.EmailTemplate)
+		emailPayload, err := n.sendEmail(ctx, email, templateVars, template, attachments...)
+		if err != nil && n.retries != nil && emailPayload != nil && mailer.IsTransient(err) {
+			subject, _ := emailPayload["subject"].(string)
+			body, _ := emailPayload["body"].(string)
+			position, queueErr := n.retries.Enqueue(ctx, mailer.QueuedEmail{To: email, Subject: subject, Body: body, Attachments: attachments})
+			if queueErr == nil {
+				outputs.Data = map[string]any{
+					"message": "Email queued for retry",
+					"details": map[string]any{
+						"reason":         "Delivery failed transiently",
+						"queuedForRetry": true,
+						"queuePosition":  position,
+						"deliveryError":  err.Error(),
+						"recipient":      mailer.RecipientKey(email),
+						"city":           city,
+						"branch":         fmt.Sprint(conditionMet),
+					},
+					"emailContent": map[string]any{
+						"to":        email,
+						"subject":   subject,
+						"body":      body,
+						"timestamp": time.Now().Format(time.RFC3339),
+					},
+				}
+				outputs.Explanation = fmt.Sprintf("Email to %s queued for retry at position %d because delivery failed transiently: %v", email, position, err)
+				outputs.Status = models.StatusQueued
+				outputs.EndedAt = time.Now().Format(time.RFC3339)
+				return outputs, nil
+			}
+			inputs.Log().Warn("Could not queue email for retry", "error", queueErr)
+		}
 		if err != nil {
 			outputs.Status = models.StatusFailed
 			outputs.Data["error"] = fmt.Sprintf("Failed to send email: %v", err)
@@ -161,18 +462,43 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 		body, _ := emailPayload["body"].(string)
 		timestamp := time.Now().Format(time.RFC3339)
 		
+		emailContent := map[string]any{
+			"to":        email,
+			"subject":   subject,
+			"body":      body,
+			"timestamp": timestamp,
+		}
+		if attachmentMeta, ok := emailPayload["attachments"]; ok {
+			emailContent["attachments"] = attachmentMeta
+		}
+		
+		details := map[string]any{
+			"outputVariables": []string{"emailSent"},
+			// Let later runs find this alert for deduplication once the address is masked
+			"recipient": mailer.RecipientKey(email),
+			"city":      city,
+			"branch":    fmt.Sprint(conditionMet),
+		}
+		if len(unresolved) > 0 {
+			details["unresolvedPlaceholders"] = unresolved
+		}
+		if len(blocked) > 0 {
+			details["blockedVariables"] = blocked
+		}
+		if n.UsesDefaultTemplate {
+			details["defaultTemplate"] = true
+		}
+		
 		// Set the output data using the response from the mailer to match frontend expectations
 		outputs.Data = map[string]any{
-			"message": "Email sent successfully",
-			"details": map[string]any{
-				"outputVariables": []string{"emailSent"},
-			},
-			"emailContent": map[string]any{
-				"to":        email,
-				"subject":   subject,
-				"body":      body,
-				"timestamp": timestamp,
-			},
+			"message":      "Email sent successfully",
+			"details":      details,
+			"emailContent": emailContent,
+		}
+		if conditionMet {
+			outputs.Explanation = fmt.Sprintf("Email sent to %s because the condition was met", email)
+		} else {
+			outputs.Explanation = fmt.Sprintf("Email sent to %s with the false-branch template because the condition was not met", email)
 		}
 	} else {
 		outputs.Data = map[string]any{
@@ -181,6 +507,7 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 				"reason": "Condition not met",
 			},
 		}
+		outputs.Explanation = "Email not sent because the condition was not met and the node has no false-branch template"
 	}
 	
 	outputs.Status = models.StatusCompleted
@@ -190,14 +517,169 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 
 // Validate ensures the node is properly configured
 func (n *Node) Validate() error {
-	// Ensure we have at least some input variables and a template
-	if len(n.InputVariables) == 0 {
+	// Ensure we have at least some input variables and a template; nodes created
+	// without a template already carry the default one when it is configured
+	if len(n.InputVariables) == 0 && len(n.VariableMappings) == 0 {
 		return fmt.Errorf("email node requires at least one input variable")
 	}
 	
+	switch n.UnresolvedPolicy {
+	case "", UnresolvedPolicyWarn, UnresolvedPolicyFail:
+	default:
+		return fmt.Errorf("unsupported unresolved placeholder policy: %s", n.UnresolvedPolicy)
+	}
+	
+	for i, mapping := range n.VariableMappings {
+		if mapping.As == "" || mapping.FromNode == "" || mapping.Key == "" {
+			return fmt.Errorf("variable mapping %d requires as, fromNode and key", i)
+		}
+	}
+	
+	for i, name := range n.AllowedVariables {
+		if name == "" {
+			return fmt.Errorf("allowed variable %d must be a non-empty string", i)
+		}
+	}
+	
+	if n.FalseTemplate != nil && n.EmailTemplate.Subject == "" && n.EmailTemplate.Body == "" {
+		return fmt.Errorf("email node requires a true-branch template")
+	}
+	
 	if n.EmailTemplate.Subject == "" || n.EmailTemplate.Body == "" {
 		return fmt.Errorf("email node requires both subject and body templates")
 	}
 	
+	if err := mailer.ValidateTemplate(n.EmailTemplate); err != nil {
+		return err
+	}
+	
+	if n.FalseTemplate != nil {
+		if n.FalseTemplate.Subject == "" || n.FalseTemplate.Body == "" {
+			return fmt.Errorf("false-branch template requires both subject and body")
+		}
+		if err := mailer.ValidateTemplate(*n.FalseTemplate); err != nil {
+			return fmt.Errorf("false-branch template: %w", err)
+		}
+	}
+	
+	if n.QuietHours != nil {
+		if err := n.QuietHours.Validate(); err != nil {
+			return err
+		}
+	}
+	
+	if n.DedupWindow < 0 {
+		return fmt.Errorf("dedup window cannot be negative")
+	}
+	
 	return nil
-}
\ No newline at end of file
+}
+
+// collectVariables resolves template variables from prior outputs. Explicit mappings
+// read from exactly one node; without them each input variable is taken from the first
+// prior output that has a matching key.
+func (n *Node) collectVariables(priorOutputs map[string]node.NodeOutputs) (map[string]any, error) {
+	templateVars := make(map[string]any)
+
+	if len(n.VariableMappings) > 0 {
+		for _, mapping := range n.VariableMappings {
+			output, ok := priorOutputs[mapping.FromNode]
+			if !ok {
+				return nil, fmt.Errorf("missing output from node %s for variable %s", mapping.FromNode, mapping.As)
+			}
+			value, ok := output.Data[mapping.Key]
+			if !ok {
+				return nil, fmt.Errorf("missing required variable: %s (%s.%s)", mapping.As, mapping.FromNode, mapping.Key)
+			}
+			templateVars[mapping.As] = value
+		}
+		return templateVars, nil
+	}
+
+	// Collect all required input variables from prior outputs
+	for _, varName := range n.InputVariables {
+		// For each input variable, check in all prior outputs
+		found := false
+		
+		for _, output := range priorOutputs {
+			if value, ok := output.Data[varName]; ok {
+				templateVars[varName] = value
+				found = true
+				break
+			}
+		}
+		
+		if !found {
+			return nil, fmt.Errorf("missing required variable: %s", varName)
+		}
+	}
+
+	return templateVars, nil
+}
+
+// conditionNodeID returns the node whose condition result picks the template: the
+// configured conditionNode, else the latest condition the engine ran. Callers
+// outside the engine that don't say get the default "condition" node when it ran.
+// It returns "" when no condition applies.
+func (n *Node) conditionNodeID(inputs node.NodeInputs) string {
+	if n.ConditionNode != "" {
+		return n.ConditionNode
+	}
+	if inputs.ConditionNodeID != "" {
+		return inputs.ConditionNodeID
+	}
+	if _, ok := inputs.PriorOutputs[string(models.NodeIDCondition)]; ok {
+		return string(models.NodeIDCondition)
+	}
+	return ""
+}
+
+// defaultAllowedVariables returns DefaultAllowedVariables extended with the names a
+// node declares, so variables it asks for by name still render
+func defaultAllowedVariables(inputVariables []string, mappings []VariableMapping) []string {
+	allowed := slices.Clone(DefaultAllowedVariables)
+	add := func(name string) {
+		if name != "" && !slices.Contains(allowed, name) {
+			allowed = append(allowed, name)
+		}
+	}
+	for _, name := range inputVariables {
+		add(name)
+	}
+	for _, mapping := range mappings {
+		add(mapping.As)
+	}
+	return allowed
+}
+
+// parseTemplate reads a subject and body template from metadata
+func parseTemplate(template map[string]any) mailer.EmailTemplate {
+	var parsed mailer.EmailTemplate
+	if subject, ok := template["subject"].(string); ok {
+		parsed.Subject = subject
+	}
+	if body, ok := template["body"].(string); ok {
+		parsed.Body = body
+	}
+	return parsed
+}
+
+// sendEmail sends through the node's mailer: its sender when it has one, and the
+// stub mailer otherwise
+func (n *Node) sendEmail(ctx context.Context, to string, variables map[string]any, template mailer.EmailTemplate, attachments ...mailer.Attachment) (map[string]any, error) {
+	if n.send != nil {
+		return n.send(to, variables, template, attachments...)
+	}
+	if n.sender != nil {
+		return mailer.PrepareAndSendEmail(ctx, n.sender, to, variables, template, attachments...)
+	}
+	return mailer.PrepareAndStubSendEmail(to, variables, template, attachments...)
+}
+
+// currentTime returns the node's clock, defaulting to the wall clock
+func (n *Node) currentTime() time.Time {
+	if n.now != nil {
+		return n.now()
+	}
+	return time.Now()
+}
@@ -2,6 +2,9 @@ package email
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
 	"testing"
 	"time"
 	"workflow-code-test/api/pkg/mailer"
@@ -9,6 +12,7 @@ import (
 	"workflow-code-test/api/pkg/node"
 
 	"github.com/stretchr/testify/assert"
+	mail "gopkg.in/gomail.v2"
 )
 
 func TestNewNode(t *testing.T) {
@@ -19,6 +23,10 @@ func TestNewNode(t *testing.T) {
 		Data: models.NodeData{
 			Label:       "Send Weather Alert",
 			Description: "Sends an email alert about the weather",
+			Metadata: map[string]any{
+				"inputVariables": []any{"city"},
+				"emailTemplate":  map[string]any{"subject": "Alert", "body": "Alert for {{city}}"},
+			},
 		},
 	}
 
@@ -168,6 +176,7 @@ func TestExecute(t *testing.T) {
 				details, ok := outputs.Data["details"].(map[string]any)
 				assert.True(t, ok, "Should have details")
 				assert.Equal(t, expectedDetails["outputVariables"], details["outputVariables"])
+				assert.Equal(t, mailer.RecipientKey(expectedContent["to"].(string)), details["recipient"])
 			} else {
 				// Verify message for condition not met
 				assert.Equal(t, tc.expectedOutput["message"], outputs.Data["message"])
@@ -182,6 +191,435 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteWithReportAttachment(t *testing.T) {
+	emailNode := &Node{
+		BaseNode: node.BaseNode{
+			ID:          "email-1",
+			Label:       "Send Alert",
+			Description: "Email weather alert notification",
+		},
+		InputVariables: []string{"city", "temperature"},
+		EmailTemplate: mailer.EmailTemplate{
+			Subject: "Weather Alert",
+			Body:    "Weather alert for {{city}}! Temperature is {{temperature}}°C!",
+		},
+		AttachReport: true,
+	}
+
+	inputs := node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {
+				Data: map[string]any{
+					"email": "atopu95@gmail.com",
+					"city":  "Sydney",
+				},
+			},
+			string(models.NodeIDWeatherAPI): {
+				Data: map[string]any{
+					"temperature": 6.1,
+				},
+			},
+			string(models.NodeIDCondition): {
+				Data: map[string]any{
+					"conditionResult": map[string]any{
+						"result":    true,
+						"operator":  "less_than",
+						"threshold": 10.0,
+					},
+				},
+			},
+		},
+	}
+
+	outputs, err := emailNode.Execute(context.Background(), inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, outputs.Status)
+
+	emailContent, ok := outputs.Data["emailContent"].(map[string]any)
+	assert.True(t, ok, "Should have emailContent")
+	attachments, ok := emailContent["attachments"].([]map[string]any)
+	assert.True(t, ok, "Should have attachment metadata")
+	assert.Len(t, attachments, 1)
+	assert.Equal(t, reportFileName, attachments[0]["name"])
+	assert.Equal(t, "text/csv", attachments[0]["contentType"])
+	assert.Greater(t, attachments[0]["size"], 0)
+
+	// The report itself should contain the weather data
+	report, err := buildReport(inputs.PriorOutputs)
+	assert.NoError(t, err)
+	assert.Contains(t, string(report.Data), "city,Sydney")
+	assert.Contains(t, string(report.Data), "temperature,6.1")
+	assert.Contains(t, string(report.Data), "operator,less_than")
+	assert.Contains(t, string(report.Data), "result,true")
+}
+
+func TestNewNodeWithVariableMappings(t *testing.T) {
+	model := models.Node{
+		ID:   "email-1",
+		Type: models.NodeTypeEmail,
+		Data: models.NodeData{
+			Label: "Send Weather Alert",
+			Metadata: map[string]any{
+				"variableMappings": []any{
+					map[string]any{"as": "userCity", "fromNode": "form", "key": "city"},
+				},
+				"emailTemplate": map[string]any{
+					"subject": "Weather Alert",
+					"body":    "Alert for {{userCity}}",
+				},
+			},
+		},
+	}
+
+	n, err := NewNode(model)
+	assert.NoError(t, err)
+
+	emailNode := n.(*Node)
+	assert.Equal(t, []VariableMapping{{As: "userCity", FromNode: "form", Key: "city"}}, emailNode.VariableMappings)
+	assert.Equal(t, "Alert for {{userCity}}", emailNode.EmailTemplate.Body)
+	assert.NoError(t, emailNode.Validate())
+}
+
+func TestExecuteWithVariableMappings(t *testing.T) {
+	// Both the form and weather nodes output "location"; mappings pick the right one
+	priorOutputs := map[string]node.NodeOutputs{
+		string(models.NodeIDForm): {
+			Data: map[string]any{
+				"email":    "test@example.com",
+				"location": "Home",
+			},
+		},
+		string(models.NodeIDWeatherAPI): {
+			Data: map[string]any{
+				"location":    "Sydney",
+				"temperature": 31.0,
+			},
+		},
+		string(models.NodeIDCondition): {
+			Data: map[string]any{
+				"conditionResult": map[string]any{"result": true},
+			},
+		},
+	}
+
+	newEmailNode := func(mappings []VariableMapping) *Node {
+		return &Node{
+			BaseNode: node.BaseNode{
+				ID:          "email-1",
+				Label:       "Send Alert",
+				Description: "Email weather alert notification",
+			},
+			InputVariables:   []string{"location"},
+			VariableMappings: mappings,
+			EmailTemplate: mailer.EmailTemplate{
+				Subject: "Weather Alert",
+				Body:    "It is {{temp}}°C in {{weatherCity}}",
+			},
+		}
+	}
+
+	t.Run("Explicit mapping", func(t *testing.T) {
+		emailNode := newEmailNode([]VariableMapping{
+			{As: "weatherCity", FromNode: string(models.NodeIDWeatherAPI), Key: "location"},
+			{As: "temp", FromNode: string(models.NodeIDWeatherAPI), Key: "temperature"},
+		})
+
+		for i := 0; i < 20; i++ {
+			outputs, err := emailNode.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+			assert.NoError(t, err)
+			emailContent := outputs.Data["emailContent"].(map[string]any)
+			assert.Equal(t, "It is 31.0°C in Sydney", emailContent["body"])
+		}
+	})
+
+	t.Run("Mapped key missing", func(t *testing.T) {
+		emailNode := newEmailNode([]VariableMapping{
+			{As: "weatherCity", FromNode: string(models.NodeIDWeatherAPI), Key: "city"},
+		})
+
+		outputs, err := emailNode.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "missing required variable: weatherCity")
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+		assert.Contains(t, outputs.Data["error"], "Missing required variable")
+	})
+
+	t.Run("Mapped node missing", func(t *testing.T) {
+		emailNode := newEmailNode([]VariableMapping{
+			{As: "weatherCity", FromNode: "unknown-node", Key: "location"},
+		})
+
+		_, err := emailNode.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "missing output from node unknown-node")
+	})
+}
+
+func TestExecuteWithUnresolvedPlaceholders(t *testing.T) {
+	priorOutputs := map[string]node.NodeOutputs{
+		string(models.NodeIDForm): {
+			Data: map[string]any{
+				"email": "test@example.com",
+				"city":  "Sydney",
+			},
+		},
+		string(models.NodeIDCondition): {
+			Data: map[string]any{
+				"conditionResult": map[string]any{"result": true},
+			},
+		},
+	}
+
+	newEmailNode := func(policy string) *Node {
+		return &Node{
+			BaseNode: node.BaseNode{
+				ID:          "email-1",
+				Label:       "Send Alert",
+				Description: "Email weather alert notification",
+			},
+			InputVariables: []string{"city"},
+			EmailTemplate: mailer.EmailTemplate{
+				Subject: "{{alertLevel}} alert for {{city}}",
+				Body:    "Hi {{name}}, it is {{temperature}}°C in {{city}}",
+			},
+			UnresolvedPolicy: policy,
+		}
+	}
+
+	t.Run("Warn policy reports placeholders", func(t *testing.T) {
+		outputs, err := newEmailNode(UnresolvedPolicyWarn).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+
+		details := outputs.Data["details"].(map[string]any)
+		assert.Equal(t, []string{"alertLevel", "name", "temperature"}, details["unresolvedPlaceholders"])
+	})
+
+	t.Run("Fail policy fails the step", func(t *testing.T) {
+		outputs, err := newEmailNode(UnresolvedPolicyFail).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "alertLevel, name, temperature")
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+		assert.NotContains(t, outputs.Data, "emailContent")
+	})
+
+	t.Run("No unresolved placeholders", func(t *testing.T) {
+		emailNode := newEmailNode(UnresolvedPolicyFail)
+		emailNode.EmailTemplate = mailer.EmailTemplate{Subject: "Alert", Body: "Alert for {{city}}"}
+
+		outputs, err := emailNode.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		details := outputs.Data["details"].(map[string]any)
+		assert.NotContains(t, details, "unresolvedPlaceholders")
+	})
+}
+
+func TestExecuteDuringQuietHours(t *testing.T) {
+	priorOutputs := map[string]node.NodeOutputs{
+		string(models.NodeIDForm): {
+			Data: map[string]any{
+				"email": "test@example.com",
+				"city":  "Sydney",
+			},
+		},
+		string(models.NodeIDCondition): {
+			Data: map[string]any{
+				"conditionResult": map[string]any{"result": true},
+			},
+		},
+	}
+
+	newEmailNode := func(deferDuringQuietHours bool, at time.Time) *Node {
+		return &Node{
+			BaseNode:       node.BaseNode{ID: "email-1", Label: "Send Alert"},
+			InputVariables: []string{"city"},
+			EmailTemplate: mailer.EmailTemplate{
+				Subject: "Weather Alert",
+				Body:    "Weather alert for {{city}}",
+			},
+			QuietHours:            &QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"},
+			DeferDuringQuietHours: deferDuringQuietHours,
+			now:                   func() time.Time { return at },
+		}
+	}
+	night := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+
+	t.Run("Deferred inside quiet hours", func(t *testing.T) {
+		outputs, err := newEmailNode(true, night).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+		assert.Equal(t, "Email deferred - quiet hours", outputs.Data["message"])
+		assert.NotContains(t, outputs.Data, "emailContent")
+		assert.True(t, outputs.Suppressed)
+
+		details := outputs.Data["details"].(map[string]any)
+		assert.Equal(t, true, details["deferred"])
+		assert.Equal(t, "2024-05-01T07:00:00Z", details["deferredUntil"])
+	})
+
+	t.Run("Sent outside quiet hours", func(t *testing.T) {
+		noon := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		outputs, err := newEmailNode(true, noon).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+	})
+
+	t.Run("Sent when deferral is off", func(t *testing.T) {
+		outputs, err := newEmailNode(false, night).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+	})
+}
+
+// fakeAlertHistory answers LastAlertSent from a fixed send time and records the query
+type fakeAlertHistory struct {
+	sentAt    time.Time
+	sent      bool
+	err       error
+	recipient string
+	city      string
+	since     time.Time
+}
+
+func (h *fakeAlertHistory) LastAlertSent(_ context.Context, recipient, city string, since time.Time) (time.Time, bool, error) {
+	h.recipient, h.city, h.since = recipient, city, since
+	if h.err != nil || !h.sent || h.sentAt.Before(since) {
+		return time.Time{}, false, h.err
+	}
+	return h.sentAt, true, nil
+}
+
+func TestExecuteWithDedupWindow(t *testing.T) {
+	priorOutputs := map[string]node.NodeOutputs{
+		string(models.NodeIDForm): {
+			Data: map[string]any{
+				"email": "test@example.com",
+				"city":  "Sydney",
+			},
+		},
+		string(models.NodeIDCondition): {
+			Data: map[string]any{
+				"conditionResult": map[string]any{"result": true},
+			},
+		},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	newEmailNode := func(history AlertHistory, window time.Duration) *Node {
+		return &Node{
+			BaseNode:       node.BaseNode{ID: "email-1", Label: "Send Alert"},
+			InputVariables: []string{"city"},
+			EmailTemplate: mailer.EmailTemplate{
+				Subject: "Weather Alert",
+				Body:    "Weather alert for {{city}}",
+			},
+			DedupWindow: window,
+			history:     history,
+			now:         func() time.Time { return now },
+		}
+	}
+
+	t.Run("Suppressed after a recent alert", func(t *testing.T) {
+		history := &fakeAlertHistory{sentAt: now.Add(-10 * time.Minute), sent: true}
+		outputs, err := newEmailNode(history, time.Hour).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+		assert.Equal(t, "Email not sent - suppressed (recent alert)", outputs.Data["message"])
+		assert.NotContains(t, outputs.Data, "emailContent")
+		assert.True(t, outputs.Suppressed)
+
+		details := outputs.Data["details"].(map[string]any)
+		assert.Equal(t, "Suppressed (recent alert)", details["reason"])
+		assert.Equal(t, true, details["suppressed"])
+		assert.Equal(t, "2024-05-01T11:50:00Z", details["lastSentAt"])
+		assert.Equal(t, "1h0m0s", details["dedupWindow"])
+
+		assert.Equal(t, "test@example.com", history.recipient)
+		assert.Equal(t, "Sydney", history.city)
+		assert.Equal(t, now.Add(-time.Hour), history.since)
+	})
+
+	t.Run("Sent once the window has passed", func(t *testing.T) {
+		history := &fakeAlertHistory{sentAt: now.Add(-2 * time.Hour), sent: true}
+		outputs, err := newEmailNode(history, time.Hour).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+		assert.Equal(t, "Sydney", outputs.Data["details"].(map[string]any)["city"])
+		assert.False(t, outputs.Suppressed)
+	})
+
+	t.Run("Sent without a window", func(t *testing.T) {
+		history := &fakeAlertHistory{sentAt: now.Add(-time.Minute), sent: true}
+		outputs, err := newEmailNode(history, 0).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+		assert.Empty(t, history.recipient, "history should not be queried")
+	})
+
+	t.Run("Sent without a history", func(t *testing.T) {
+		outputs, err := newEmailNode(nil, time.Hour).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+	})
+
+	t.Run("Sent when the lookup fails", func(t *testing.T) {
+		history := &fakeAlertHistory{err: errors.New("database unavailable")}
+		outputs, err := newEmailNode(history, time.Hour).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+	})
+}
+
+func TestNewNodeWithDedupWindow(t *testing.T) {
+	modelWith := func(metadata map[string]any) models.Node {
+		return models.Node{ID: "email-1", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: metadata}}
+	}
+	history := &fakeAlertHistory{}
+	factory := NewNodeFactory(FactoryConfig{DefaultTemplate: DefaultTemplate(), History: history, DedupWindow: time.Hour})
+
+	t.Run("factory window applies by default", func(t *testing.T) {
+		n, err := factory(modelWith(map[string]any{"inputVariables": []any{"city"}}))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Hour, n.(*Node).DedupWindow)
+		assert.Equal(t, history, n.(*Node).history)
+	})
+
+	t.Run("metadata overrides the factory window", func(t *testing.T) {
+		n, err := factory(modelWith(map[string]any{"inputVariables": []any{"city"}, "dedupWindowMinutes": float64(15)}))
+		assert.NoError(t, err)
+		assert.Equal(t, 15*time.Minute, n.(*Node).DedupWindow)
+	})
+
+	t.Run("negative window is invalid", func(t *testing.T) {
+		n, err := factory(modelWith(map[string]any{"inputVariables": []any{"city"}, "dedupWindowMinutes": float64(-5)}))
+		assert.NoError(t, err)
+		assert.ErrorContains(t, n.Validate(), "dedup window cannot be negative")
+	})
+}
+
+func TestNewNodeWithQuietHours(t *testing.T) {
+	model := models.Node{
+		ID:   "email",
+		Type: models.NodeTypeEmail,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"quietHours": map[string]any{
+					"start":    "22:00",
+					"end":      "07:00",
+					"timezone": "Australia/Sydney",
+				},
+				"deferDuringQuietHours": true,
+			},
+		},
+	}
+
+	n, err := NewNode(model)
+	assert.NoError(t, err)
+	emailNode := n.(*Node)
+	assert.Equal(t, &QuietHours{Start: "22:00", End: "07:00", Timezone: "Australia/Sydney"}, emailNode.QuietHours)
+	assert.True(t, emailNode.DeferDuringQuietHours)
+}
+
 func TestExecuteErrors(t *testing.T) {
 	// Create email node with required input variables
 	emailNode := &Node{
@@ -199,18 +637,20 @@ func TestExecuteErrors(t *testing.T) {
 	
 	// Test cases for error scenarios
 	testCases := []struct {
-		name           string
-		priorOutputs   map[string]node.NodeOutputs
-		expectedError  string
-		expectedStatus models.Status
+		name            string
+		priorOutputs    map[string]node.NodeOutputs
+		conditionNodeID string
+		expectedError   string
+		expectedStatus  models.Status
 	}{
 		{
 			name: "Missing Condition Output",
 			priorOutputs: map[string]node.NodeOutputs{
-				// No condition output
+				// No output from the condition the run went through
 			},
-			expectedError:  "failed to get condition result",
-			expectedStatus: models.StatusFailed,
+			conditionNodeID: "check-temperature",
+			expectedError:   "failed to get condition result from node check-temperature",
+			expectedStatus:  models.StatusFailed,
 		},
 		{
 			name: "Invalid Condition Output Format",
@@ -342,7 +782,8 @@ func TestExecuteErrors(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Create inputs
 			inputs := node.NodeInputs{
-				PriorOutputs: tc.priorOutputs,
+				PriorOutputs:    tc.priorOutputs,
+				ConditionNodeID: tc.conditionNodeID,
 			}
 			
 			// Execute the node
@@ -397,6 +838,28 @@ func TestValidate(t *testing.T) {
 		assert.Contains(t, err.Error(), "email node requires at least one input variable")
 	})
 	
+	t.Run("Variable Mappings Only", func(t *testing.T) {
+		emailNode := &Node{
+			BaseNode: node.BaseNode{
+				ID:          "email-1",
+				Label:       "Send Alert",
+				Description: "Email weather alert notification",
+			},
+			VariableMappings: []VariableMapping{{As: "city", FromNode: "form", Key: "city"}},
+			EmailTemplate: mailer.EmailTemplate{
+				Subject: "Weather Alert",
+				Body:    "Weather alert for {{city}}!",
+			},
+		}
+		
+		assert.NoError(t, emailNode.Validate())
+		
+		emailNode.VariableMappings = []VariableMapping{{As: "city", Key: "city"}}
+		err := emailNode.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "requires as, fromNode and key")
+	})
+	
 	t.Run("Missing Email Template", func(t *testing.T) {
 		emailNode := &Node{
 			BaseNode: node.BaseNode{
@@ -415,4 +878,526 @@ func TestValidate(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "email node requires both subject and body templates")
 	})
+	
+	t.Run("Invalid Quiet Hours", func(t *testing.T) {
+		emailNode := &Node{
+			BaseNode:       node.BaseNode{ID: "email-1"},
+			InputVariables: []string{"city"},
+			EmailTemplate: mailer.EmailTemplate{
+				Subject: "Weather Alert",
+				Body:    "Weather alert for {{city}}!",
+			},
+			QuietHours: &QuietHours{Start: "22:00", End: "07:00", Timezone: "Nowhere/Special"},
+		}
+		
+		err := emailNode.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown quiet hours timezone")
+	})	
+	t.Run("Template Engine", func(t *testing.T) {
+		emailNode := &Node{
+			BaseNode:       node.BaseNode{ID: "email-1"},
+			InputVariables: []string{"temperature"},
+			EmailTemplate: mailer.EmailTemplate{
+				Subject: "Weather Alert",
+				Body:    "{{if gt .temperature 30}}Hot!{{end}}",
+				Engine:  mailer.TemplateEngineGo,
+			},
+		}
+		assert.NoError(t, emailNode.Validate())
+		
+		emailNode.EmailTemplate.Body = "{{if gt .temperature 30}}Hot!"
+		assert.ErrorContains(t, emailNode.Validate(), "invalid body template")
+		
+		emailNode.EmailTemplate.Engine = "handlebars"
+		assert.ErrorContains(t, emailNode.Validate(), "unsupported template engine")
+	})
+}
+
+func TestExecuteWithGoTemplate(t *testing.T) {
+	model := models.Node{
+		ID: "email-1",
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"inputVariables": []any{"city", "temperature"},
+				"templateEngine": "gotemplate",
+				"emailTemplate": map[string]any{
+					"subject": "Weather in {{.city}}",
+					"body":    "{{if gt .temperature 30}}Hot!{{else}}Mild.{{end}} {{printf \"%.1f\" .temperature}}°C",
+				},
+			},
+		},
+	}
+	emailNode, err := NewNode(model)
+	assert.NoError(t, err)
+	assert.NoError(t, emailNode.Validate())
+
+	priorOutputs := func(temperature float64) map[string]node.NodeOutputs {
+		return map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"email": "test@example.com", "city": "Sydney"}},
+			string(models.NodeIDWeatherAPI):  {Data: map[string]any{"temperature": temperature}},
+			string(models.NodeIDCondition): {Data: map[string]any{"conditionResult": map[string]any{"result": true}}},
+		}
+	}
+
+	outputs, err := emailNode.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs(32.5)})
+	assert.NoError(t, err)
+	emailContent := outputs.Data["emailContent"].(map[string]any)
+	assert.Equal(t, "Weather in Sydney", emailContent["subject"])
+	assert.Equal(t, "Hot! 32.5°C", emailContent["body"])
+
+	outputs, err = emailNode.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs(18)})
+	assert.NoError(t, err)
+	emailContent = outputs.Data["emailContent"].(map[string]any)
+	assert.Equal(t, "Mild. 18.0°C", emailContent["body"])
+}
+
+func TestDefaultTemplateFallback(t *testing.T) {
+	modelWith := func(metadata map[string]any) models.Node {
+		return models.Node{ID: "email-1", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: metadata}}
+	}
+	priorOutputs := map[string]node.NodeOutputs{
+		string(models.NodeIDForm):       {Data: map[string]any{"email": "test@example.com", "city": "Sydney"}},
+		string(models.NodeIDWeatherAPI): {Data: map[string]any{"temperature": 31.5}},
+		string(models.NodeIDCondition):  {Data: map[string]any{"conditionResult": map[string]any{"result": true}}},
+	}
+
+	t.Run("node without a template uses the built-in default", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city", "temperature"}}))
+		assert.NoError(t, err)
+		assert.NoError(t, n.Validate())
+
+		emailNode := n.(*Node)
+		assert.True(t, emailNode.UsesDefaultTemplate)
+		assert.Equal(t, DefaultTemplate(), emailNode.EmailTemplate)
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		emailContent := outputs.Data["emailContent"].(map[string]any)
+		assert.Equal(t, "Weather alert for Sydney", emailContent["subject"])
+		assert.Equal(t, "Weather alert for Sydney: 31.5°C", emailContent["body"])
+		assert.Equal(t, true, outputs.Data["details"].(map[string]any)["defaultTemplate"])
+	})
+
+	t.Run("configured default replaces the built-in one", func(t *testing.T) {
+		factory := NewNodeFactory(FactoryConfig{DefaultTemplate: mailer.EmailTemplate{Subject: "Heads up, {{city}}", Body: "It is {{temperature}} degrees"}})
+		n, err := factory(modelWith(map[string]any{"inputVariables": []any{"city", "temperature"}}))
+		assert.NoError(t, err)
+		assert.NoError(t, n.Validate())
+		assert.Equal(t, "Heads up, {{city}}", n.(*Node).EmailTemplate.Subject)
+	})
+
+	t.Run("own template is kept", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{
+			"inputVariables": []any{"city"},
+			"emailTemplate":  map[string]any{"subject": "Alert", "body": "Alert for {{city}}"},
+		}))
+		assert.NoError(t, err)
+		assert.False(t, n.(*Node).UsesDefaultTemplate)
+		assert.Equal(t, "Alert", n.(*Node).EmailTemplate.Subject)
+	})
+
+	t.Run("partial template is not filled in", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{
+			"inputVariables": []any{"city"},
+			"emailTemplate":  map[string]any{"subject": "Alert"},
+		}))
+		assert.NoError(t, err)
+		assert.ErrorContains(t, n.Validate(), "requires both subject and body templates")
+	})
+
+	t.Run("without a default the template is required", func(t *testing.T) {
+		n, err := NewNodeFactory(FactoryConfig{})(modelWith(map[string]any{"inputVariables": []any{"city"}}))
+		assert.NoError(t, err)
+		assert.ErrorContains(t, n.Validate(), "requires both subject and body templates")
+	})
+
+	t.Run("input variables are still required", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"unresolvedPolicy": "warn"}))
+		assert.NoError(t, err)
+		assert.ErrorContains(t, n.Validate(), "requires at least one input variable")
+	})
+}
+
+func TestBranchTemplates(t *testing.T) {
+	modelWith := func(metadata map[string]any) models.Node {
+		return models.Node{ID: "email-1", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: metadata}}
+	}
+	priorOutputsFor := func(conditionMet bool) map[string]node.NodeOutputs {
+		return map[string]node.NodeOutputs{
+			string(models.NodeIDForm):       {Data: map[string]any{"email": "test@example.com", "city": "Sydney"}},
+			string(models.NodeIDWeatherAPI): {Data: map[string]any{"temperature": 18.0}},
+			string(models.NodeIDCondition):  {Data: map[string]any{"conditionResult": map[string]any{"result": conditionMet}}},
+		}
+	}
+	branchTemplates := map[string]any{
+		"true":  map[string]any{"subject": "Alert for {{city}}", "body": "It is {{temperature}} degrees"},
+		"false": map[string]any{"subject": "All clear in {{city}}", "body": "Only {{temperature}} degrees"},
+	}
+
+	n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city", "temperature"}, "emailTemplates": branchTemplates}))
+	assert.NoError(t, err)
+	assert.NoError(t, n.Validate())
+
+	t.Run("true branch sends the true template", func(t *testing.T) {
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(true)})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+		emailContent := outputs.Data["emailContent"].(map[string]any)
+		assert.Equal(t, "Alert for Sydney", emailContent["subject"])
+		assert.Equal(t, "It is 18.0 degrees", emailContent["body"])
+		assert.Equal(t, "true", outputs.Data["details"].(map[string]any)["branch"])
+	})
+
+	t.Run("false branch sends the false template", func(t *testing.T) {
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(false)})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+		emailContent := outputs.Data["emailContent"].(map[string]any)
+		assert.Equal(t, "All clear in Sydney", emailContent["subject"])
+		assert.Equal(t, "Only 18.0 degrees", emailContent["body"])
+		assert.Equal(t, "false", outputs.Data["details"].(map[string]any)["branch"])
+	})
+
+	t.Run("the condition the run went through picks the template", func(t *testing.T) {
+		priorOutputs := priorOutputsFor(true)
+		priorOutputs["check-wind"] = node.NodeOutputs{Data: map[string]any{"conditionResult": map[string]any{"result": false}}}
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs, ConditionNodeID: "check-wind"})
+		assert.NoError(t, err)
+		assert.Equal(t, "All clear in Sydney", outputs.Data["emailContent"].(map[string]any)["subject"])
+	})
+
+	t.Run("a configured conditionNode picks the template", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city", "temperature"},
+			"emailTemplates": branchTemplates, "conditionNode": "check-wind"}))
+		assert.NoError(t, err)
+		priorOutputs := priorOutputsFor(true)
+		priorOutputs["check-wind"] = node.NodeOutputs{Data: map[string]any{"conditionResult": map[string]any{"result": false}}}
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs, ConditionNodeID: string(models.NodeIDCondition)})
+		assert.NoError(t, err)
+		assert.Equal(t, "All clear in Sydney", outputs.Data["emailContent"].(map[string]any)["subject"])
+	})
+
+	t.Run("without a condition the true template is sent", func(t *testing.T) {
+		priorOutputs := priorOutputsFor(false)
+		delete(priorOutputs, string(models.NodeIDCondition))
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		assert.Equal(t, "Alert for Sydney", outputs.Data["emailContent"].(map[string]any)["subject"])
+		assert.Equal(t, "true", outputs.Data["details"].(map[string]any)["branch"])
+	})
+
+	t.Run("false branch is not deduplicated", func(t *testing.T) {
+		history := &fakeAlertHistory{sentAt: time.Now(), sent: true}
+		n, err := NewNodeFactory(FactoryConfig{History: history, DedupWindow: time.Hour})(
+			modelWith(map[string]any{"inputVariables": []any{"city", "temperature"}, "emailTemplates": branchTemplates}))
+		assert.NoError(t, err)
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(false)})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+		assert.Empty(t, history.recipient, "history should not be queried")
+	})
+
+	t.Run("without a false template the false branch sends nothing", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{
+			"inputVariables": []any{"city"},
+			"emailTemplates": map[string]any{"true": branchTemplates["true"]},
+		}))
+		assert.NoError(t, err)
+		assert.Nil(t, n.(*Node).FalseTemplate)
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(false)})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email not sent - condition not met", outputs.Data["message"])
+	})
+
+	t.Run("true branch template is required", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{
+			"inputVariables": []any{"city"},
+			"emailTemplates": map[string]any{"false": branchTemplates["false"]},
+		}))
+		assert.NoError(t, err)
+		assert.False(t, n.(*Node).UsesDefaultTemplate)
+		assert.ErrorContains(t, n.Validate(), "requires a true-branch template")
+	})
+
+	t.Run("false branch template must be complete", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{
+			"inputVariables": []any{"city"},
+			"emailTemplates": map[string]any{
+				"true":  branchTemplates["true"],
+				"false": map[string]any{"subject": "All clear"},
+			},
+		}))
+		assert.NoError(t, err)
+		assert.ErrorContains(t, n.Validate(), "false-branch template requires both subject and body")
+	})
+}
+
+func TestExecuteWhenDisabled(t *testing.T) {
+	modelWith := func(metadata map[string]any) models.Node {
+		return models.Node{ID: "email-1", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: metadata}}
+	}
+	priorOutputsFor := func(conditionMet bool) map[string]node.NodeOutputs {
+		return map[string]node.NodeOutputs{
+			string(models.NodeIDForm):       {Data: map[string]any{"email": "test@example.com", "city": "Sydney"}},
+			string(models.NodeIDWeatherAPI): {Data: map[string]any{"temperature": 31.5}},
+			string(models.NodeIDCondition):  {Data: map[string]any{"conditionResult": map[string]any{"result": conditionMet}}},
+		}
+	}
+	newEmailNode := func(t *testing.T, disabled bool, sends *int) *Node {
+		n, err := NewNodeFactory(FactoryConfig{DefaultTemplate: DefaultTemplate(), Disabled: disabled})(
+			modelWith(map[string]any{"inputVariables": []any{"city", "temperature"}}))
+		assert.NoError(t, err)
+		emailNode := n.(*Node)
+		emailNode.send = func(to string, variables map[string]any, template mailer.EmailTemplate, attachments ...mailer.Attachment) (map[string]any, error) {
+			*sends++
+			return mailer.PrepareAndStubSendEmail(to, variables, template, attachments...)
+		}
+		return emailNode
+	}
+
+	for _, conditionMet := range []bool{true, false} {
+		t.Run(fmt.Sprintf("suppressed when condition is %v", conditionMet), func(t *testing.T) {
+			sends := 0
+			outputs, err := newEmailNode(t, true, &sends).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(conditionMet)})
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, outputs.Status)
+			assert.Equal(t, "Email suppressed (disabled)", outputs.Data["message"])
+			assert.NotContains(t, outputs.Data, "emailContent")
+			assert.Equal(t, true, outputs.Data["details"].(map[string]any)["suppressed"])
+			assert.True(t, outputs.Suppressed)
+			assert.Zero(t, sends, "mailer should not be called")
+		})
+	}
+
+	t.Run("suppressed without a condition result", func(t *testing.T) {
+		sends := 0
+		outputs, err := newEmailNode(t, true, &sends).Execute(context.Background(), node.NodeInputs{})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email suppressed (disabled)", outputs.Data["message"])
+		assert.Zero(t, sends)
+	})
+
+	t.Run("sent when enabled", func(t *testing.T) {
+		sends := 0
+		outputs, err := newEmailNode(t, false, &sends).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(true)})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+		assert.Equal(t, 1, sends)
+	})
+}
+
+// fakeSender records the messages it is given and fails with err when set
+type fakeSender struct {
+	messages []*mail.Message
+	err      error
+}
+
+func (s *fakeSender) Send(_ context.Context, messages ...*mail.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func TestExecuteWithSender(t *testing.T) {
+	model := models.Node{ID: "email-1", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: map[string]any{"inputVariables": []any{"city", "temperature"}}}}
+	inputs := node.NodeInputs{PriorOutputs: map[string]node.NodeOutputs{
+		string(models.NodeIDForm):       {Data: map[string]any{"email": "test@example.com", "city": "Sydney"}},
+		string(models.NodeIDWeatherAPI): {Data: map[string]any{"temperature": 31.5}},
+		string(models.NodeIDCondition):  {Data: map[string]any{"conditionResult": map[string]any{"result": true}}},
+	}}
+
+	t.Run("delivered through the sender", func(t *testing.T) {
+		sender := &fakeSender{}
+		n, err := NewNodeFactory(FactoryConfig{DefaultTemplate: DefaultTemplate(), Sender: sender})(model)
+		assert.NoError(t, err)
+
+		outputs, err := n.Execute(context.Background(), inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, "Email sent successfully", outputs.Data["message"])
+		if assert.Len(t, sender.messages, 1) {
+			assert.Equal(t, []string{"test@example.com"}, sender.messages[0].GetHeader("To"))
+			assert.Equal(t, []string{"Weather alert for Sydney"}, sender.messages[0].GetHeader("Subject"))
+		}
+	})
+
+	t.Run("failed delivery fails the step", func(t *testing.T) {
+		sender := &fakeSender{err: errors.New("connection reset")}
+		n, err := NewNodeFactory(FactoryConfig{DefaultTemplate: DefaultTemplate(), Sender: sender})(model)
+		assert.NoError(t, err)
+
+		outputs, err := n.Execute(context.Background(), inputs)
+		assert.ErrorContains(t, err, "connection reset")
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+	})
+	t.Run("transient failure queued for retry", func(t *testing.T) {
+		sender := &fakeSender{err: &textproto.Error{Code: 421, Msg: "service not available"}}
+		retries := mailer.NewRetryQueue(mailer.SendVia(sender), 10, 3, time.Minute)
+		_, err := retries.Enqueue(context.Background(), mailer.QueuedEmail{To: "earlier@example.com"})
+		assert.NoError(t, err)
+		n, err := NewNodeFactory(FactoryConfig{DefaultTemplate: DefaultTemplate(), Sender: sender, Retries: retries})(model)
+		assert.NoError(t, err)
+
+		outputs, err := n.Execute(context.Background(), inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusQueued, outputs.Status)
+		assert.Equal(t, "Email queued for retry", outputs.Data["message"])
+		details := outputs.Data["details"].(map[string]any)
+		assert.Equal(t, true, details["queuedForRetry"])
+		assert.Equal(t, 2, details["queuePosition"])
+		assert.Contains(t, details["deliveryError"], "service not available")
+		assert.NotContains(t, outputs.Data, "error")
+		assert.Equal(t, "Weather alert for Sydney", outputs.Data["emailContent"].(map[string]any)["subject"])
+		assert.Equal(t, 2, retries.Len())
+	})
+
+	t.Run("permanent failure still fails with a retry queue", func(t *testing.T) {
+		sender := &fakeSender{err: &textproto.Error{Code: 550, Msg: "mailbox unavailable"}}
+		retries := mailer.NewRetryQueue(mailer.SendVia(sender), 10, 3, time.Minute)
+		n, err := NewNodeFactory(FactoryConfig{DefaultTemplate: DefaultTemplate(), Sender: sender, Retries: retries})(model)
+		assert.NoError(t, err)
+
+		outputs, err := n.Execute(context.Background(), inputs)
+		assert.Error(t, err)
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+		assert.Zero(t, retries.Len())
+	})
+}
+
+func TestExecuteExplanation(t *testing.T) {
+	priorOutputsFor := func(conditionMet bool) map[string]node.NodeOutputs {
+		return map[string]node.NodeOutputs{
+			string(models.NodeIDForm):       {Data: map[string]any{"email": "test@example.com", "city": "Sydney"}},
+			string(models.NodeIDWeatherAPI): {Data: map[string]any{"temperature": 31.5}},
+			string(models.NodeIDCondition):  {Data: map[string]any{"conditionResult": map[string]any{"result": conditionMet}}},
+		}
+	}
+	newEmailNode := func(t *testing.T, config FactoryConfig) *Node {
+		config.DefaultTemplate = DefaultTemplate()
+		n, err := NewNodeFactory(config)(models.Node{
+			ID:   "email-1",
+			Type: models.NodeTypeEmail,
+			Data: models.NodeData{Metadata: map[string]any{"inputVariables": []any{"city", "temperature"}}},
+		})
+		assert.NoError(t, err)
+		return n.(*Node)
+	}
+
+	tests := []struct {
+		name         string
+		config       FactoryConfig
+		conditionMet bool
+		expected     string
+	}{
+		{"sent", FactoryConfig{}, true, "Email sent to test@example.com because the condition was met"},
+		{"skipped", FactoryConfig{}, false, "Email not sent because the condition was not met and the node has no false-branch template"},
+		{"disabled", FactoryConfig{Disabled: true}, true, "Email not sent because sending is disabled for this deployment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs, err := newEmailNode(t, tt.config).Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(tt.conditionMet)})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, outputs.Explanation)
+		})
+	}
+
+	t.Run("suppressed by a recent alert", func(t *testing.T) {
+		sentAt := time.Date(2024, 5, 1, 11, 50, 0, 0, time.UTC)
+		history := &fakeAlertHistory{sentAt: sentAt, sent: true}
+		emailNode := newEmailNode(t, FactoryConfig{History: history, DedupWindow: time.Hour})
+		emailNode.now = func() time.Time { return sentAt.Add(10 * time.Minute) }
+
+		outputs, err := emailNode.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputsFor(true)})
+		assert.NoError(t, err)
+		assert.Equal(t, "Email to test@example.com not sent because an alert about Sydney already went to them at 2024-05-01T11:50:00Z, within the 1h0m0s deduplication window", outputs.Explanation)
+	})
+}
+
+func TestAllowedVariables(t *testing.T) {
+	modelWith := func(metadata map[string]any) models.Node {
+		return models.Node{ID: "email-1", Type: models.NodeTypeEmail, Data: models.NodeData{Metadata: metadata}}
+	}
+	priorOutputs := map[string]node.NodeOutputs{
+		string(models.NodeIDForm):       {Data: map[string]any{"email": "test@example.com", "city": "Sydney"}},
+		string(models.NodeIDWeatherAPI): {Data: map[string]any{"temperature": 18.0, "apiKey": "secret-key"}},
+		string(models.NodeIDCondition):  {Data: map[string]any{"conditionResult": map[string]any{"result": true}}},
+	}
+	template := map[string]any{"subject": "Alert for {{city}}", "body": "It is {{temperature}} degrees ({{apiKey}})"}
+
+	t.Run("default allowlist includes declared variables", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city", "temperature", "apiKey"}, "emailTemplate": template}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"city", "temperature", "name", "emoji", "apiKey"}, n.(*Node).AllowedVariables)
+		assert.Equal(t, []string{"city", "temperature", "name", "emoji"}, DefaultAllowedVariables, "the default itself is left alone")
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		emailContent := outputs.Data["emailContent"].(map[string]any)
+		assert.Equal(t, "Alert for Sydney", emailContent["subject"])
+		assert.Equal(t, "It is 18.0 degrees (secret-key)", emailContent["body"])
+		assert.Empty(t, outputs.Data["details"].(map[string]any)["blockedVariables"])
+	})
+
+	t.Run("default allowlist includes mapped names", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"emailTemplate": template, "variableMappings": []any{
+			map[string]any{"as": "temperature", "fromNode": string(models.NodeIDWeatherAPI), "key": "temperature"},
+			map[string]any{"as": "windspeed", "fromNode": string(models.NodeIDWeatherAPI), "key": "windspeed"},
+		}}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"city", "temperature", "name", "emoji", "windspeed"}, n.(*Node).AllowedVariables)
+	})
+
+	t.Run("allowlist leaves other variables unsubstituted", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city", "temperature", "apiKey"},
+			"emailTemplate": template, "allowedVariables": []any{"city", "temperature"}}))
+		assert.NoError(t, err)
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		emailContent := outputs.Data["emailContent"].(map[string]any)
+		assert.Equal(t, "Alert for Sydney", emailContent["subject"])
+		assert.Equal(t, "It is 18.0 degrees ({{apiKey}})", emailContent["body"])
+		assert.NotContains(t, emailContent["body"], "secret-key")
+		details := outputs.Data["details"].(map[string]any)
+		assert.Equal(t, []string{"apiKey"}, details["blockedVariables"])
+		assert.Equal(t, []string{"apiKey"}, details["unresolvedPlaceholders"])
+	})
+
+	t.Run("fail policy rejects templates using blocked variables", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city", "temperature", "apiKey"},
+			"emailTemplate": template, "allowedVariables": []any{"city", "temperature"}, "unresolvedPolicy": UnresolvedPolicyFail}))
+		assert.NoError(t, err)
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.Error(t, err)
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+		assert.Contains(t, outputs.Data["error"], "apiKey")
+	})
+
+	t.Run("allowlist can name any variable", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city", "temperature", "apiKey"},
+			"emailTemplate": template, "allowedVariables": []any{"temperature", "apiKey"}}))
+		assert.NoError(t, err)
+		assert.NoError(t, n.Validate())
+
+		outputs, err := n.Execute(context.Background(), node.NodeInputs{PriorOutputs: priorOutputs})
+		assert.NoError(t, err)
+		emailContent := outputs.Data["emailContent"].(map[string]any)
+		assert.Equal(t, "Alert for {{city}}", emailContent["subject"])
+		assert.Equal(t, "It is 18.0 degrees (secret-key)", emailContent["body"])
+		assert.Equal(t, []string{"city"}, outputs.Data["details"].(map[string]any)["blockedVariables"])
+	})
+
+	t.Run("empty names are invalid", func(t *testing.T) {
+		n, err := NewNode(modelWith(map[string]any{"inputVariables": []any{"city"},
+			"emailTemplate": template, "allowedVariables": []any{"city", 7}}))
+		assert.NoError(t, err)
+		assert.EqualError(t, n.Validate(), "allowed variable 1 must be a non-empty string")
+	})
 }
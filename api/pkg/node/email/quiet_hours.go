@@ -0,0 +1,78 @@
+package email
+
+import (
+	"fmt"
+	"time"
+)
+
+// clockLayout is the HH:MM format used for quiet hours boundaries
+const clockLayout = "15:04"
+
+// QuietHours is a daily window, in a given timezone, during which emails are not sent.
+// A window whose end is earlier than its start wraps past midnight (e.g. 22:00-07:00).
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+// Validate checks the boundaries and timezone can be parsed
+func (q QuietHours) Validate() error {
+	_, _, _, err := q.parse()
+	return err
+}
+
+// Contains reports whether t falls inside the quiet window. The start is inclusive and
+// the end exclusive; when quiet, it also returns the moment the window ends.
+func (q QuietHours) Contains(t time.Time) (bool, time.Time, error) {
+	start, end, location, err := q.parse()
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	var quiet bool
+	switch {
+	case start == end:
+		quiet = false // an empty window never silences anything
+	case start < end:
+		quiet = minute >= start && minute < end
+	default:
+		// The window wraps past midnight
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return false, time.Time{}, nil
+	}
+
+	// The window ends today unless that moment has already passed, i.e. we're in the
+	// part of a wrapping window before midnight
+	endsAt := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, location)
+	if !endsAt.After(local) {
+		endsAt = time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, location)
+	}
+	return true, endsAt, nil
+}
+
+// parse returns the window boundaries as minutes after midnight along with its location
+func (q QuietHours) parse() (int, int, *time.Location, error) {
+	start, err := time.Parse(clockLayout, q.Start)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("quiet hours start must be HH:MM: %q", q.Start)
+	}
+	end, err := time.Parse(clockLayout, q.End)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("quiet hours end must be HH:MM: %q", q.End)
+	}
+
+	location := time.UTC
+	if q.Timezone != "" {
+		if location, err = time.LoadLocation(q.Timezone); err != nil {
+			return 0, 0, nil, fmt.Errorf("unknown quiet hours timezone: %s", q.Timezone)
+		}
+	}
+
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), location, nil
+}
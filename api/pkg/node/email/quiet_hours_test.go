@@ -0,0 +1,108 @@
+package email
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	assert.NoError(t, err)
+
+	overnight := QuietHours{Start: "22:00", End: "07:00", Timezone: "Australia/Sydney"}
+	daytime := QuietHours{Start: "09:00", End: "17:30"}
+
+	tests := []struct {
+		name          string
+		quietHours    QuietHours
+		at            time.Time
+		expectQuiet   bool
+		expectedUntil time.Time
+	}{
+		{
+			name:          "overnight window before midnight",
+			quietHours:    overnight,
+			at:            time.Date(2024, 5, 1, 23, 15, 0, 0, sydney),
+			expectQuiet:   true,
+			expectedUntil: time.Date(2024, 5, 2, 7, 0, 0, 0, sydney),
+		},
+		{
+			name:          "overnight window after midnight",
+			quietHours:    overnight,
+			at:            time.Date(2024, 5, 2, 3, 0, 0, 0, sydney),
+			expectQuiet:   true,
+			expectedUntil: time.Date(2024, 5, 2, 7, 0, 0, 0, sydney),
+		},
+		{
+			name:          "overnight window start is inclusive",
+			quietHours:    overnight,
+			at:            time.Date(2024, 5, 1, 22, 0, 0, 0, sydney),
+			expectQuiet:   true,
+			expectedUntil: time.Date(2024, 5, 2, 7, 0, 0, 0, sydney),
+		},
+		{
+			name:        "overnight window end is exclusive",
+			quietHours:  overnight,
+			at:          time.Date(2024, 5, 2, 7, 0, 0, 0, sydney),
+			expectQuiet: false,
+		},
+		{
+			name:        "outside overnight window",
+			quietHours:  overnight,
+			at:          time.Date(2024, 5, 1, 12, 0, 0, 0, sydney),
+			expectQuiet: false,
+		},
+		{
+			name:          "timezone is applied to UTC times",
+			quietHours:    overnight,
+			at:            time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC), // midnight in Sydney
+			expectQuiet:   true,
+			expectedUntil: time.Date(2024, 5, 2, 7, 0, 0, 0, sydney),
+		},
+		{
+			name:          "same day window defaults to UTC",
+			quietHours:    daytime,
+			at:            time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			expectQuiet:   true,
+			expectedUntil: time.Date(2024, 5, 1, 17, 30, 0, 0, time.UTC),
+		},
+		{
+			name:        "before same day window",
+			quietHours:  daytime,
+			at:          time.Date(2024, 5, 1, 8, 59, 0, 0, time.UTC),
+			expectQuiet: false,
+		},
+		{
+			name:        "empty window",
+			quietHours:  QuietHours{Start: "08:00", End: "08:00"},
+			at:          time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+			expectQuiet: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiet, until, err := tt.quietHours.Contains(tt.at)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectQuiet, quiet)
+			if tt.expectQuiet {
+				assert.True(t, tt.expectedUntil.Equal(until), "expected %v, got %v", tt.expectedUntil, until)
+			}
+		})
+	}
+}
+
+func TestQuietHoursValidate(t *testing.T) {
+	assert.NoError(t, QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/London"}.Validate())
+
+	err := QuietHours{Start: "10pm", End: "07:00"}.Validate()
+	assert.ErrorContains(t, err, "start must be HH:MM")
+
+	err = QuietHours{Start: "22:00", End: "25:00"}.Validate()
+	assert.ErrorContains(t, err, "end must be HH:MM")
+
+	err = QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}.Validate()
+	assert.ErrorContains(t, err, "unknown quiet hours timezone")
+}
@@ -0,0 +1,58 @@
+package email
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+	"workflow-code-test/api/pkg/mailer"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// reportFileName is the name of the generated weather report attachment
+const reportFileName = "weather-report.csv"
+
+// buildReport generates a CSV report of the weather data collected by prior nodes
+func buildReport(priorOutputs map[string]node.NodeOutputs) (mailer.Attachment, error) {
+	rows := [][]string{{"field", "value"}}
+
+	if formOutput, ok := priorOutputs[string(models.NodeIDForm)]; ok {
+		if city, ok := formOutput.Data[string(models.OutputKeyCity)]; ok {
+			rows = append(rows, []string{"city", fmt.Sprint(city)})
+		}
+	}
+
+	if weatherOutput, ok := priorOutputs[string(models.NodeIDWeatherAPI)]; ok {
+		if temperature, ok := weatherOutput.Float(string(models.OutputKeyTemperature)); ok {
+			rows = append(rows, []string{"temperature", fmt.Sprintf("%.1f", temperature)})
+		}
+		if windspeed, ok := weatherOutput.Float(string(models.OutputKeyWindspeed)); ok {
+			rows = append(rows, []string{"windspeed", fmt.Sprintf("%.1f", windspeed)})
+		}
+	}
+
+	if conditionOutput, ok := priorOutputs[string(models.NodeIDCondition)]; ok {
+		if conditionResult, ok := conditionOutput.Map("conditionResult"); ok {
+			for _, key := range []string{"operator", "threshold", "result"} {
+				if value, ok := conditionResult[key]; ok {
+					rows = append(rows, []string{key, fmt.Sprint(value)})
+				}
+			}
+		}
+	}
+
+	rows = append(rows, []string{"generatedAt", time.Now().Format(time.RFC3339)})
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return mailer.Attachment{}, fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return mailer.Attachment{
+		Name:        reportFileName,
+		ContentType: "text/csv",
+		Data:        buf.Bytes(),
+	}, nil
+}
@@ -39,10 +39,11 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 	
 	// End nodes don't do much - they just mark the end of the workflow
 	outputs := node.NodeOutputs{
-		Data:      make(map[string]any),
-		Status:    models.StatusCompleted,
-		StartedAt: started.Format(time.RFC3339),
-		EndedAt:   time.Now().Format(time.RFC3339),
+		Data:        make(map[string]any),
+		Status:      models.StatusCompleted,
+		StartedAt:   started.Format(time.RFC3339),
+		EndedAt:     time.Now().Format(time.RFC3339),
+		Explanation: "Reached the end of the workflow",
 	}
 	
 	// Collect simplified summary data from all the workflow steps
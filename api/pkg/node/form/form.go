@@ -2,6 +2,7 @@ package form
 
 import (
 	"context"
+	"fmt"
 	"time"
 	"workflow-code-test/api/pkg/models"
 	"workflow-code-test/api/pkg/node"
@@ -61,9 +62,10 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 			string(models.OutputKeyEmail): inputs.WorkflowInput.Email,
 			string(models.OutputKeyCity):  inputs.WorkflowInput.City,
 		},
-		Status:    models.StatusCompleted,
-		StartedAt: started.Format(time.RFC3339),
-		EndedAt:   time.Now().Format(time.RFC3339),
+		Status:      models.StatusCompleted,
+		StartedAt:   started.Format(time.RFC3339),
+		EndedAt:     time.Now().Format(time.RFC3339),
+		Explanation: fmt.Sprintf("Collected %d form fields for %s", len(formData), inputs.WorkflowInput.City),
 	}
 
 	return outputs, nil
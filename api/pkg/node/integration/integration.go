@@ -2,8 +2,9 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"log/slog"
 	"time"
 	"workflow-code-test/api/pkg/models"
 	"workflow-code-test/api/pkg/node"
@@ -18,24 +19,56 @@ type Node struct {
 
 // Config holds integration node configuration
 type Config struct {
-	APIEndpoint string
-	Options     []weather.WeatherOption
+	APIEndpoint  string
+	Options      []weather.WeatherOption
+	FallbackCity string // used only when the requested city has no matching option
+	// TemperatureBounds rejects provider readings outside a plausible range
+	TemperatureBounds weather.TemperatureBounds
+	// FieldPaths locate values in the provider response; empty paths use the defaults
+	FieldPaths weather.FieldPaths
+	// APIUnits are requested from the provider; TemperatureBounds are in the requested unit
+	APIUnits weather.APIUnits
+	// CacheTTL overrides how long responses are reused; nil uses weather.DefaultCacheTTL
+	// and 0 disables caching for this node
+	CacheTTL *time.Duration
+}
+
+// responseCache is shared by all integration nodes. Entries are keyed by the
+// request URL, so nodes calling different providers or coordinates never collide.
+var responseCache = weather.NewCache()
+
+// ErrCityNotFound is returned by Lookup for a city with no location option
+var ErrCityNotFound = errors.New("city not found")
+
+// requestLimiter caps provider requests across all integration nodes; nil is unlimited
+var requestLimiter *weather.Limiter
+
+// SetMaxConcurrentRequests limits how many weather API requests all integration
+// nodes may have in flight at once; a non-positive limit removes the cap. Call it
+// before any workflow runs.
+func SetMaxConcurrentRequests(limit int) {
+	requestLimiter = weather.NewLimiter(limit)
 }
 
 // NewNode creates an integration node from a model
 func NewNode(model models.Node) (node.Node, error) {
-	// Parse model.Data.Metadata into Config
-	var config Config
+	metadata, err := node.RequireMetadata(model)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Parse metadata into Config
+	config := Config{TemperatureBounds: weather.DefaultTemperatureBounds()}
 	
 	// Extract API endpoint
-	apiEndpoint, ok := model.Data.Metadata["apiEndpoint"].(string)
+	apiEndpoint, ok := metadata["apiEndpoint"].(string)
 	if !ok {
 		return nil, fmt.Errorf("missing API endpoint")
 	}
 	config.APIEndpoint = apiEndpoint
 	
 	// Extract location options
-	optionsRaw, ok := model.Data.Metadata["options"].([]any)
+	optionsRaw, ok := metadata["options"].([]any)
 	if ok {
 		for _, opt := range optionsRaw {
 			option, ok := opt.(map[string]any)
@@ -55,6 +88,53 @@ func NewNode(model models.Node) (node.Node, error) {
 		}
 	}
 	
+	// Extract optional fallback city
+	if fallbackCity, ok := metadata["fallbackCity"].(string); ok {
+		config.FallbackCity = fallbackCity
+	}
+	
+	// Extract optional response field paths for providers with different nesting
+	if path, ok := metadata["temperaturePath"].(string); ok {
+		config.FieldPaths.Temperature = path
+	}
+	if path, ok := metadata["windPath"].(string); ok {
+		config.FieldPaths.Windspeed = path
+	}
+	if path, ok := metadata["humidityPath"].(string); ok {
+		config.FieldPaths.Humidity = path
+	}
+	if path, ok := metadata["timePath"].(string); ok {
+		config.FieldPaths.Time = path
+	}
+	if path, ok := metadata["weatherCodePath"].(string); ok {
+		config.FieldPaths.WeatherCode = path
+	}
+	
+	// Extract optional per-node cache TTL in milliseconds
+	if ttlMs, ok := metadata["cacheTtlMs"].(float64); ok {
+		ttl := time.Duration(ttlMs * float64(time.Millisecond))
+		config.CacheTTL = &ttl
+	}
+	
+	// Extract optional units to request from the provider. Default bounds follow the
+	// requested temperature unit so they stay comparable with the response.
+	if units, ok := metadata["apiUnits"].(map[string]any); ok {
+		config.APIUnits = weather.ParseAPIUnits(units)
+		if config.APIUnits.Temperature == weather.TemperatureUnitFahrenheit {
+			config.TemperatureBounds = config.TemperatureBounds.Fahrenheit()
+		}
+	}
+	
+	// Extract optional plausible temperature range; either end may be overridden alone
+	if bounds, ok := metadata["temperatureBounds"].(map[string]any); ok {
+		if min, ok := bounds["min"].(float64); ok {
+			config.TemperatureBounds.Min = min
+		}
+		if max, ok := bounds["max"].(float64); ok {
+			config.TemperatureBounds.Max = max
+		}
+	}
+	
 	return &Node{
 		BaseNode: node.BaseNode{
 			ID:          model.ID,
@@ -75,6 +155,13 @@ func (n *Node) GetBaseInfo() node.BaseNode {
 	return n.BaseNode
 }
 
+// CacheKey lets the engine reuse this node's output within an execution. The
+// output depends only on the node's configuration and the city from the form.
+func (n *Node) CacheKey(inputs node.NodeInputs) (string, bool) {
+	city, ok := inputs.PriorOutputs[string(models.NodeIDForm)].String("city")
+	return city, ok
+}
+
 // Execute implements the integration node logic
 func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
 	started := time.Now()
@@ -93,28 +180,20 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 		return outputs, fmt.Errorf("missing form data")
 	}
 	
-	city, ok := formOutput.Data["city"].(string)
+	city, ok := formOutput.String("city")
 	if !ok {
 		outputs.Status = models.StatusFailed
 		outputs.Data["error"] = "Failed to get city from form output"
 		outputs.EndedAt = time.Now().Format(time.RFC3339)
 		return outputs, fmt.Errorf("missing city")
 	}
-	// Update the node description with the actual city name
-	if strings.Contains(n.Description, "{{city}}") {
-		n.Description = strings.ReplaceAll(n.Description, "{{city}}", city)
-	}
-
 	// Find location coordinates for the city
-	var lat, lon float64
-	found := false
-	for _, option := range n.config.Options {
-		if option.City == city {
-			lat = option.Lat
-			lon = option.Lon
-			found = true
-			break
-		}
+	requestedCity := city
+	usedFallback := false
+	option, found := n.findOption(city)
+	if !found && n.config.FallbackCity != "" {
+		option, found = n.findOption(n.config.FallbackCity)
+		usedFallback = found
 	}
 	
 	if !found {
@@ -123,10 +202,12 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 		outputs.EndedAt = time.Now().Format(time.RFC3339)
 		return outputs, fmt.Errorf("city not found: %s", city)
 	}
+	city = option.City
+	lat, lon := option.Lat, option.Lon
 	
 	// Call the weather API using the client
-	weatherClient := weather.NewClient(10 * time.Second)
-	weatherData, err := weatherClient.GetWeather(ctx, n.config.APIEndpoint, lat, lon, city)
+	client := n.client(inputs.Log())
+	weatherData, err := client.GetWeather(ctx, n.config.APIEndpoint, lat, lon, city)
 	if err != nil {
 		outputs.Status = models.StatusFailed
 		outputs.Data["error"] = fmt.Sprintf("Weather API error: %v", err)
@@ -136,12 +217,22 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 	}
 	
 	temperature := weatherData.Temperature
+	
+	// Don't act on readings no real location could produce
+	if err := n.temperatureBounds().Check(temperature); err != nil {
+		outputs.Status = models.StatusFailed
+		outputs.Data["error"] = fmt.Sprintf("Weather API error: %v", err)
+		outputs.Data["message"] = "Weather API returned an implausible value"
+		outputs.EndedAt = time.Now().Format(time.RFC3339)
+		return outputs, fmt.Errorf("weather API error: %w", err)
+	}
 
 	outputs.Status = models.StatusCompleted
+	outputs.DisplayLabel = fmt.Sprintf("Weather for %s", city)
 	outputs.Data = map[string]any{
-		"message": fmt.Sprintf("Retrieved temperature for %s: %.1f°C", city, temperature),
+		"message": fmt.Sprintf("Retrieved temperature for %s: %.1f%s", city, temperature, n.config.APIUnits.TemperatureSymbol()),
 		"apiResponse": map[string]any{
-			"endpoint": n.config.APIEndpoint,
+			"endpoint": weather.SanitizeURL(client.RequestURL(n.config.APIEndpoint, lat, lon)),
 			"method": "GET",
 			"data": map[string]any{
 				"temperature": temperature,
@@ -151,6 +242,40 @@ func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOu
 		string(models.OutputKeyTemperature): temperature,
 		string(models.OutputKeyLocation):    city,
 	}
+	if !n.config.APIUnits.IsZero() {
+		outputs.Data["apiUnits"] = n.config.APIUnits.Map()
+	}
+	if usedFallback {
+		outputs.Data["usedFallback"] = true
+		outputs.Data["requestedCity"] = requestedCity
+		outputs.Data["warning"] = fmt.Sprintf("City not found: %s; used fallback city %s", requestedCity, city)
+	}
+	outputs.Explanation = fmt.Sprintf("Looked up the weather for %s: %.1f%s", city, temperature, n.config.APIUnits.TemperatureSymbol())
+	if usedFallback {
+		outputs.Explanation = fmt.Sprintf("%s was not a known city, so looked up the weather for the fallback city %s: %.1f%s",
+			requestedCity, city, temperature, n.config.APIUnits.TemperatureSymbol())
+	}
+	// Readings are shared so later nodes needn't know this node's ID
+	outputs.Shared = map[string]any{string(models.OutputKeyTemperature): temperature}
+	if weatherData.Windspeed != nil {
+		windEmoji := weather.WindEmoji{}
+		outputs.Data[string(models.OutputKeyWindspeed)] = *weatherData.Windspeed
+		outputs.Data[string(models.OutputKeyWindMessage)] = windEmoji.Message(*weatherData.Windspeed)
+		outputs.Shared[string(models.OutputKeyWindspeed)] = *weatherData.Windspeed
+	}
+	// Every other field the response carried is exposed too, so downstream nodes
+	// pick what they need without another API call
+	if weatherData.Humidity != nil {
+		outputs.Data[string(models.OutputKeyHumidity)] = *weatherData.Humidity
+		outputs.Shared[string(models.OutputKeyHumidity)] = *weatherData.Humidity
+	}
+	if weatherData.Time != "" {
+		outputs.Data[string(models.OutputKeyTime)] = weatherData.Time
+	}
+	if weatherData.WeatherCode != nil {
+		outputs.Data[string(models.OutputKeyWeatherCode)] = *weatherData.WeatherCode
+		outputs.Data[string(models.OutputKeyWeatherDescription)] = weatherData.WeatherDescription
+	}
 	outputs.EndedAt = time.Now().Format(time.RFC3339)
 	
 	return outputs, nil
@@ -164,5 +289,90 @@ func (n *Node) Validate() error {
 	if len(n.config.Options) == 0 {
 		return fmt.Errorf("no location options configured")
 	}
+	for _, option := range n.config.Options {
+		if err := option.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := n.config.APIUnits.Validate(); err != nil {
+		return err
+	}
+	if n.config.CacheTTL != nil && *n.config.CacheTTL < 0 {
+		return fmt.Errorf("cacheTtlMs cannot be negative")
+	}
+	if err := n.temperatureBounds().Validate(); err != nil {
+		return err
+	}
+	if n.config.FallbackCity != "" {
+		if _, ok := n.findOption(n.config.FallbackCity); !ok {
+			return fmt.Errorf("fallback city %s is not a configured option", n.config.FallbackCity)
+		}
+	}
 	return nil
 }
+
+// Lookup fetches the current weather for a configured city outside any workflow,
+// through the same client, shared cache and request limit as Execute. The fallback
+// city isn't applied, so an unknown city returns ErrCityNotFound.
+func (n *Node) Lookup(ctx context.Context, city string) (*weather.WeatherData, error) {
+	return n.lookup(ctx, n.client(slog.Default()), city)
+}
+
+// Probe is Lookup without the response cache, so it always calls the weather API.
+// It lets authors check that the node's endpoint, field paths and units work.
+func (n *Node) Probe(ctx context.Context, city string) (*weather.WeatherData, error) {
+	return n.lookup(ctx, n.uncachedClient(slog.Default()), city)
+}
+
+// lookup fetches and checks the weather for a configured city through client
+func (n *Node) lookup(ctx context.Context, client *weather.Client, city string) (*weather.WeatherData, error) {
+	option, found := n.findOption(city)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrCityNotFound, city)
+	}
+	weatherData, err := client.GetWeather(ctx, n.config.APIEndpoint, option.Lat, option.Lon, option.City)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.temperatureBounds().Check(weatherData.Temperature); err != nil {
+		return nil, err
+	}
+	return weatherData, nil
+}
+
+// client returns a weather client configured for this node
+func (n *Node) client(logger *slog.Logger) *weather.Client {
+	return n.uncachedClient(logger).WithCache(responseCache, n.cacheTTL())
+}
+
+// uncachedClient returns a weather client configured for this node that always calls the API
+func (n *Node) uncachedClient(logger *slog.Logger) *weather.Client {
+	return weather.NewClient(10 * time.Second).WithLogger(logger).WithFieldPaths(n.config.FieldPaths).
+		WithUnits(n.config.APIUnits).WithLimiter(requestLimiter)
+}
+
+// cacheTTL returns the node's cache TTL, or -1 so the client applies its default
+func (n *Node) cacheTTL() time.Duration {
+	if n.config.CacheTTL == nil {
+		return -1
+	}
+	return *n.config.CacheTTL
+}
+
+// temperatureBounds returns the configured plausible range, or the default when unset
+func (n *Node) temperatureBounds() weather.TemperatureBounds {
+	if n.config.TemperatureBounds == (weather.TemperatureBounds{}) {
+		return weather.DefaultTemperatureBounds()
+	}
+	return n.config.TemperatureBounds
+}
+
+// findOption returns the configured location option for city
+func (n *Node) findOption(city string) (weather.WeatherOption, bool) {
+	for _, option := range n.config.Options {
+		if option.City == city {
+			return option, true
+		}
+	}
+	return weather.WeatherOption{}, false
+}
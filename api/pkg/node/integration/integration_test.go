@@ -1,10 +1,15 @@
 package integration
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 	"workflow-code-test/api/pkg/models"
@@ -120,6 +125,36 @@ func TestNodeValidate(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "Fallback city not in options",
+			config: Config{
+				APIEndpoint: "https://api.example.com/weather",
+				Options: []weather.WeatherOption{
+					{
+						City: "New York",
+						Lat:  40.7128,
+						Lon:  -74.0060,
+					},
+				},
+				FallbackCity: "Boston",
+			},
+			expectedError: true,
+		},
+		{
+			name: "Inverted temperature bounds",
+			config: Config{
+				APIEndpoint: "https://api.example.com/weather",
+				Options: []weather.WeatherOption{
+					{
+						City: "New York",
+						Lat:  40.7128,
+						Lon:  -74.0060,
+					},
+				},
+				TemperatureBounds: weather.TemperatureBounds{Min: 50, Max: -50},
+			},
+			expectedError: true,
+		},
 		{
 			name: "No location options",
 			config: Config{
@@ -151,6 +186,44 @@ func TestNodeValidate(t *testing.T) {
 	}
 }
 
+func TestNodeValidateCoordinates(t *testing.T) {
+	validate := func(option weather.WeatherOption) error {
+		n := &Node{config: Config{
+			APIEndpoint: "https://api.example.com/weather",
+			Options: []weather.WeatherOption{
+				{City: "Sydney", Lat: -33.8688, Lon: 151.2093},
+				option,
+			},
+		}}
+		return n.Validate()
+	}
+
+	valid := []weather.WeatherOption{
+		{City: "Perth", Lat: -31.9505, Lon: 115.8605},
+		{City: "North Pole", Lat: 90, Lon: 0},
+		{City: "South Pole", Lat: -90, Lon: 0},
+		{City: "Date Line East", Lat: 0, Lon: 180},
+		{City: "Date Line West", Lat: 0, Lon: -180},
+	}
+	for _, option := range valid {
+		assert.NoError(t, validate(option), option.City)
+	}
+
+	invalid := []struct {
+		option   weather.WeatherOption
+		expected string
+	}{
+		{weather.WeatherOption{City: "Melbourne", Lat: 400, Lon: 144.9631}, "city Melbourne has latitude 400 outside [-90, 90]"},
+		{weather.WeatherOption{City: "Hobart", Lat: -90.5, Lon: 147.3272}, "city Hobart has latitude -90.5 outside [-90, 90]"},
+		{weather.WeatherOption{City: "Brisbane", Lat: -27.4698, Lon: 1530.21}, "city Brisbane has longitude 1530.21 outside [-180, 180]"},
+		{weather.WeatherOption{City: "Darwin", Lat: -12.4634, Lon: -180.1}, "city Darwin has longitude -180.1 outside [-180, 180]"},
+		{weather.WeatherOption{City: "Nowhere", Lat: math.NaN(), Lon: 0}, "city Nowhere has latitude NaN outside [-90, 90]"},
+	}
+	for _, tt := range invalid {
+		assert.EqualError(t, validate(tt.option), tt.expected)
+	}
+}
+
 func TestExecute(t *testing.T) {
 	// Create a test server to mock the weather API
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -170,6 +243,11 @@ func TestExecute(t *testing.T) {
 			return
 		}
 		
+		if r.URL.Path == "/windy" {
+			fmt.Fprintln(w, `{"current_weather": {"temperature": 20.5, "windspeed": 25.0}}`)
+			return
+		}
+		
 		// Default success case
 		fmt.Fprintln(w, `{"current_weather": {"temperature": 20.5}}`)
 	}))
@@ -190,6 +268,13 @@ func TestExecute(t *testing.T) {
 			cityInOptions:  true,
 			expectedStatus: models.StatusCompleted,
 		},
+		{
+			name:           "Successful API call with windspeed",
+			apiPath:        "/windy",
+			city:           "New York",
+			cityInOptions:  true,
+			expectedStatus: models.StatusCompleted,
+		},
 		{
 			name:           "API error response",
 			apiPath:        "/error",
@@ -265,6 +350,14 @@ func TestExecute(t *testing.T) {
 				assert.Contains(t, outputs.Data, string(models.OutputKeyLocation))
 				assert.Equal(t, 20.5, outputs.Data[string(models.OutputKeyTemperature)])
 				assert.Equal(t, tc.city, outputs.Data[string(models.OutputKeyLocation)])
+				assert.Equal(t, 20.5, outputs.Shared[string(models.OutputKeyTemperature)])
+				if tc.apiPath == "/windy" {
+					assert.Equal(t, 25.0, outputs.Data[string(models.OutputKeyWindspeed)])
+					assert.Equal(t, 25.0, outputs.Shared[string(models.OutputKeyWindspeed)])
+					assert.Equal(t, "Windy: 25 km/h 💨", outputs.Data[string(models.OutputKeyWindMessage)])
+				} else {
+					assert.NotContains(t, outputs.Data, string(models.OutputKeyWindspeed))
+				}
 			} else {
 				if !tc.cityInOptions {
 					assert.Contains(t, outputs.Data["error"], "City not found")
@@ -274,6 +367,214 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteWithFallbackCity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 20.5}}`)
+	}))
+	defer server.Close()
+
+	newNode := func(fallbackCity string) *Node {
+		return &Node{
+			BaseNode: node.BaseNode{
+				ID:          "integration-test",
+				Label:       "Test Integration",
+				Description: "Test integration node",
+			},
+			config: Config{
+				APIEndpoint: server.URL,
+				Options: []weather.WeatherOption{
+					{City: "New York", Lat: 40.7128, Lon: -74.0060},
+				},
+				FallbackCity: fallbackCity,
+			},
+		}
+	}
+
+	inputs := node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {
+				Data: map[string]any{
+					"city": "Atlantis",
+				},
+			},
+		},
+	}
+
+	t.Run("Unknown city uses fallback", func(t *testing.T) {
+		outputs, err := newNode("New York").Execute(context.Background(), inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+		assert.Equal(t, true, outputs.Data["usedFallback"])
+		assert.Equal(t, "Atlantis", outputs.Data["requestedCity"])
+		assert.Equal(t, "New York", outputs.Data[string(models.OutputKeyLocation)])
+		assert.Contains(t, outputs.Data["warning"], "used fallback city New York")
+		assert.Equal(t, "Weather for New York", outputs.DisplayLabel)
+	})
+
+	t.Run("Known city ignores fallback", func(t *testing.T) {
+		knownInputs := node.NodeInputs{
+			PriorOutputs: map[string]node.NodeOutputs{
+				string(models.NodeIDForm): {Data: map[string]any{"city": "New York"}},
+			},
+		}
+		outputs, err := newNode("New York").Execute(context.Background(), knownInputs)
+		assert.NoError(t, err)
+		assert.NotContains(t, outputs.Data, "usedFallback")
+	})
+
+	t.Run("No fallback configured fails", func(t *testing.T) {
+		outputs, err := newNode("").Execute(context.Background(), inputs)
+		assert.Error(t, err)
+		assert.Equal(t, models.StatusFailed, outputs.Status)
+		assert.Empty(t, outputs.DisplayLabel)
+		assert.Contains(t, outputs.Data["error"], "City not found")
+	})
+}
+
+func TestExecuteRejectsImplausibleTemperature(t *testing.T) {
+	newServer := func(temperature float64) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"current_weather": {"temperature": %v}}`, temperature)
+		}))
+	}
+
+	inputs := node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"city": "New York"}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		temperature float64
+		bounds      weather.TemperatureBounds
+		expectError bool
+	}{
+		{name: "default maximum is accepted", temperature: 60},
+		{name: "default minimum is accepted", temperature: -90},
+		{name: "above default maximum", temperature: 999, expectError: true},
+		{name: "below default minimum", temperature: -500, expectError: true},
+		{name: "custom bounds", temperature: 45, bounds: weather.TemperatureBounds{Min: -10, Max: 40}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(tt.temperature)
+			defer server.Close()
+
+			n := &Node{
+				BaseNode: node.BaseNode{ID: "integration-test"},
+				config: Config{
+					APIEndpoint:       server.URL,
+					Options:           []weather.WeatherOption{{City: "New York", Lat: 40.7128, Lon: -74.0060}},
+					TemperatureBounds: tt.bounds,
+				},
+			}
+
+			outputs, err := n.Execute(context.Background(), inputs)
+			if tt.expectError {
+				assert.ErrorIs(t, err, weather.ErrImplausibleWeather)
+				assert.Equal(t, models.StatusFailed, outputs.Status)
+				assert.Contains(t, outputs.Data["error"], "implausible weather value")
+				assert.NotContains(t, outputs.Data, string(models.OutputKeyTemperature))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.temperature, outputs.Data[string(models.OutputKeyTemperature)])
+			}
+		})
+	}
+}
+
+func TestNewNodeTemperatureBounds(t *testing.T) {
+	model := models.Node{
+		ID:   "weather-api",
+		Type: models.NodeTypeIntegration,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"apiEndpoint":       "https://api.example.com/weather",
+				"temperatureBounds": map[string]any{"max": 50.0},
+			},
+		},
+	}
+
+	n, err := NewNode(model)
+	assert.NoError(t, err)
+	assert.Equal(t, weather.TemperatureBounds{Min: weather.DefaultMinTemperature, Max: 50}, n.(*Node).config.TemperatureBounds)
+}
+
+func TestExecuteLogsWeatherURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 20.5}}`)
+	}))
+	defer server.Close()
+
+	n := &Node{
+		BaseNode: node.BaseNode{
+			ID:          "weather-api",
+			Label:       "Test Integration",
+			Description: "Test integration node",
+		},
+		config: Config{
+			APIEndpoint: server.URL + "?lat={lat}&lon={lon}",
+			Options: []weather.WeatherOption{
+				{City: "New York", Lat: 40.7128, Lon: -74.0060},
+			},
+		},
+	}
+
+	// Capture debug logs from a logger enriched the way the engine does it
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})).
+		With("workflowId", "wf-1", "executionId", "exec-1", "nodeId", "weather-api")
+
+	inputs := node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"city": "New York"}},
+		},
+		Logger: logger,
+	}
+
+	_, err := n.Execute(context.Background(), inputs)
+	assert.NoError(t, err)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "DEBUG", record["level"])
+	assert.Equal(t, server.URL+"?lat=40.712800&lon=-74.006000", record["url"])
+	assert.Equal(t, "exec-1", record["executionId"])
+	assert.Equal(t, "weather-api", record["nodeId"])
+}
+
+func TestExecuteOutputHidesEndpointSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("appid"), "the provider still gets the key")
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 20.5}}`)
+	}))
+	defer server.Close()
+
+	n := &Node{
+		BaseNode: node.BaseNode{ID: "weather-api"},
+		config: Config{
+			APIEndpoint: server.URL + "?lat={lat}&lon={lon}&appid=secret",
+			Options:     []weather.WeatherOption{{City: "Sydney", Lat: -33.8688, Lon: 151.2093}},
+		},
+	}
+
+	var buf bytes.Buffer
+	outputs, err := n.Execute(context.Background(), node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"city": "Sydney"}},
+		},
+		Logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	assert.NoError(t, err)
+
+	// The output shows the URL that was requested, with the key redacted
+	apiResponse, _ := outputs.Data["apiResponse"].(map[string]any)
+	assert.Equal(t, server.URL+"?appid=REDACTED&lat=-33.868800&lon=151.209300", apiResponse["endpoint"])
+	assert.NotContains(t, buf.String(), "secret")
+}
+
 func TestExecuteMissingFormData(t *testing.T) {
 	n := &Node{
 		BaseNode: node.BaseNode{
@@ -371,3 +672,265 @@ func TestAPIRequestTimeout(t *testing.T) {
 	assert.Contains(t, outputs.Data["error"], "Weather API error")
 	assert.Contains(t, err.Error(), "context deadline exceeded")
 }
+
+func TestExecuteWithFieldPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"main": {"temp": 27.5}, "wind": {"speed": 22.0}}`)
+	}))
+	defer server.Close()
+
+	model := models.Node{
+		ID:   "weather-api",
+		Type: models.NodeTypeIntegration,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"apiEndpoint":     server.URL,
+				"options":         []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+				"temperaturePath": "main.temp",
+				"windPath":        "wind.speed",
+			},
+		},
+	}
+	n, err := NewNode(model)
+	assert.NoError(t, err)
+	assert.Equal(t, weather.FieldPaths{Temperature: "main.temp", Windspeed: "wind.speed"}, n.(*Node).config.FieldPaths)
+
+	outputs, err := n.Execute(context.Background(), node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"city": "Sydney"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 27.5, outputs.Data[string(models.OutputKeyTemperature)])
+	assert.Equal(t, 22.0, outputs.Data[string(models.OutputKeyWindspeed)])
+}
+
+func TestExecuteCacheTTL(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 25.0}}`)
+	}))
+	defer server.Close()
+
+	newNode := func(t *testing.T, metadata map[string]any) node.Node {
+		metadata["apiEndpoint"] = server.URL + "?latitude={lat}&longitude={lon}"
+		metadata["options"] = []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}}
+		n, err := NewNode(models.Node{ID: "weather-api", Type: models.NodeTypeIntegration, Data: models.NodeData{Metadata: metadata}})
+		assert.NoError(t, err)
+		return n
+	}
+	inputs := node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"city": "Sydney"}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		metadata      map[string]any
+		expectedCalls int32
+	}{
+		{name: "default TTL caches", metadata: map[string]any{}, expectedCalls: 1},
+		{name: "per-node TTL caches", metadata: map[string]any{"cacheTtlMs": float64(60000)}, expectedCalls: 1},
+		{name: "zero TTL disables caching", metadata: map[string]any{"cacheTtlMs": float64(0)}, expectedCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responseCache = weather.NewCache()
+			calls.Store(0)
+			n := newNode(t, tt.metadata)
+			for range 3 {
+				outputs, err := n.Execute(context.Background(), inputs)
+				assert.NoError(t, err)
+				assert.Equal(t, 25.0, outputs.Data[string(models.OutputKeyTemperature)])
+			}
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+		})
+	}
+
+	t.Run("nodes with different TTLs share entries for the same endpoint", func(t *testing.T) {
+		responseCache = weather.NewCache()
+		calls.Store(0)
+		caching := newNode(t, map[string]any{"cacheTtlMs": float64(60000)})
+		uncached := newNode(t, map[string]any{"cacheTtlMs": float64(0)})
+
+		_, err := caching.Execute(context.Background(), inputs)
+		assert.NoError(t, err)
+		_, err = uncached.Execute(context.Background(), inputs)
+		assert.NoError(t, err)
+		_, err = caching.Execute(context.Background(), inputs)
+		assert.NoError(t, err)
+
+		assert.Equal(t, int32(2), calls.Load(), "only the zero-TTL node should bypass the cache")
+	})
+
+	t.Run("negative TTL is rejected", func(t *testing.T) {
+		n := newNode(t, map[string]any{"cacheTtlMs": float64(-1)})
+		assert.EqualError(t, n.Validate(), "cacheTtlMs cannot be negative")
+	})
+}
+
+func TestExecuteWithAPIUnits(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 95.0, "windspeed": 10.0}}`)
+	}))
+	defer server.Close()
+
+	n, err := NewNode(models.Node{
+		ID:   "weather-api",
+		Type: models.NodeTypeIntegration,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"apiEndpoint": server.URL + "?latitude={lat}&longitude={lon}",
+				"options":     []any{map[string]any{"city": "Phoenix", "lat": 33.45, "lon": -112.07}},
+				"apiUnits":    map[string]any{"temperature": "fahrenheit", "windspeed": "mph"},
+				"cacheTtlMs":  float64(0),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, n.Validate())
+
+	// 95 is above the default 60°C limit; the default bounds are converted to °F
+	outputs, err := n.Execute(context.Background(), node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"city": "Phoenix"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fahrenheit"}, query["temperature_unit"])
+	assert.Equal(t, []string{"mph"}, query["windspeed_unit"])
+	assert.Equal(t, 95.0, outputs.Data[string(models.OutputKeyTemperature)])
+	assert.Equal(t, map[string]any{"temperature": "fahrenheit", "windspeed": "mph"}, outputs.Data["apiUnits"])
+	assert.Equal(t, "Retrieved temperature for Phoenix: 95.0°F", outputs.Data["message"])
+}
+
+func TestValidateRejectsUnknownAPIUnits(t *testing.T) {
+	n, err := NewNode(models.Node{
+		ID:   "weather-api",
+		Type: models.NodeTypeIntegration,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"apiEndpoint": "https://example.com",
+				"options":     []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+				"apiUnits":    map[string]any{"temperature": "kelvin"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.EqualError(t, n.Validate(), "unsupported temperature unit: kelvin")
+}
+
+func TestLookup(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 21.5, "windspeed": 12.0}}`)
+	}))
+	defer server.Close()
+
+	n, err := NewNode(models.Node{
+		ID:   "weather-api",
+		Type: models.NodeTypeIntegration,
+		Data: models.NodeData{Metadata: map[string]any{
+			"apiEndpoint":  server.URL,
+			"options":      []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+			"fallbackCity": "Sydney",
+			"cacheTtlMs":   float64(0),
+		}},
+	})
+	assert.NoError(t, err)
+	lookup := n.(*Node)
+
+	data, err := lookup.Lookup(context.Background(), "Sydney")
+	assert.NoError(t, err)
+	assert.Equal(t, 21.5, data.Temperature)
+	assert.Equal(t, "Sydney", data.Location)
+
+	// The fallback city only applies inside workflows
+	_, err = lookup.Lookup(context.Background(), "Atlantis")
+	assert.ErrorIs(t, err, ErrCityNotFound)
+
+	status = http.StatusInternalServerError
+	_, err = lookup.Lookup(context.Background(), "Sydney")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCityNotFound)
+}
+
+func TestProbeBypassesCache(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		temperature := 20 + calls.Add(1)
+		fmt.Fprintf(w, `{"current_weather": {"temperature": %d}}`, temperature)
+	}))
+	defer server.Close()
+
+	n, err := NewNode(models.Node{
+		ID:   "weather-api",
+		Type: models.NodeTypeIntegration,
+		Data: models.NodeData{Metadata: map[string]any{
+			"apiEndpoint": server.URL + "/probe",
+			"options":     []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+			"cacheTtlMs":  float64(60000),
+		}},
+	})
+	assert.NoError(t, err)
+	weatherNode := n.(*Node)
+
+	// A cached lookup doesn't stop the probe reaching the API
+	data, err := weatherNode.Lookup(context.Background(), "Sydney")
+	assert.NoError(t, err)
+	assert.Equal(t, 21.0, data.Temperature)
+
+	data, err = weatherNode.Probe(context.Background(), "Sydney")
+	assert.NoError(t, err)
+	assert.Equal(t, 22.0, data.Temperature)
+	assert.Equal(t, int32(2), calls.Load())
+
+	_, err = weatherNode.Probe(context.Background(), "Atlantis")
+	assert.ErrorIs(t, err, ErrCityNotFound)
+}
+
+func TestExecuteExposesAllWeatherFields(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprintln(w, `{
+			"current_weather": {"temperature": 21.5, "windspeed": 12.0, "time": "2026-10-17T09:00", "weathercode": 63},
+			"current": {"relative_humidity_2m": 78}
+		}`)
+	}))
+	defer server.Close()
+
+	n, err := NewNode(models.Node{
+		ID:   "weather-api",
+		Type: models.NodeTypeIntegration,
+		Data: models.NodeData{
+			Metadata: map[string]any{
+				"apiEndpoint": server.URL + "?latitude={lat}&longitude={lon}&current_weather=true&current=relative_humidity_2m",
+				"options":     []any{map[string]any{"city": "Sydney", "lat": -33.8688, "lon": 151.2093}},
+				"cacheTtlMs":  float64(0),
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	outputs, err := n.Execute(context.Background(), node.NodeInputs{
+		PriorOutputs: map[string]node.NodeOutputs{
+			string(models.NodeIDForm): {Data: map[string]any{"city": "Sydney"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "every field should come from one request")
+	assert.Equal(t, 21.5, outputs.Data[string(models.OutputKeyTemperature)])
+	assert.Equal(t, 12.0, outputs.Data[string(models.OutputKeyWindspeed)])
+	assert.Equal(t, 78.0, outputs.Data[string(models.OutputKeyHumidity)])
+	assert.Equal(t, "2026-10-17T09:00", outputs.Data[string(models.OutputKeyTime)])
+	assert.Equal(t, 63, outputs.Data[string(models.OutputKeyWeatherCode)])
+	assert.Equal(t, "Moderate rain", outputs.Data[string(models.OutputKeyWeatherDescription)])
+	assert.Equal(t, map[string]any{"temperature": 21.5, "windspeed": 12.0, "humidity": 78.0}, outputs.Shared)
+}
@@ -0,0 +1,48 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Default limits for a plausible air temperature in °C, just beyond recorded extremes
+const (
+	DefaultMinTemperature = -90.0
+	DefaultMaxTemperature = 60.0
+)
+
+// ErrImplausibleWeather marks provider data outside physically plausible ranges
+var ErrImplausibleWeather = errors.New("implausible weather value")
+
+// TemperatureBounds is the inclusive range of temperatures accepted from a provider
+type TemperatureBounds struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// DefaultTemperatureBounds returns the default plausible temperature range
+func DefaultTemperatureBounds() TemperatureBounds {
+	return TemperatureBounds{Min: DefaultMinTemperature, Max: DefaultMaxTemperature}
+}
+
+// Fahrenheit converts bounds given in °C to °F
+func (b TemperatureBounds) Fahrenheit() TemperatureBounds {
+	return TemperatureBounds{Min: celsiusToFahrenheit(b.Min), Max: celsiusToFahrenheit(b.Max)}
+}
+
+// Validate checks the range is not empty
+func (b TemperatureBounds) Validate() error {
+	if b.Min >= b.Max {
+		return fmt.Errorf("temperature bounds min %.1f must be below max %.1f", b.Min, b.Max)
+	}
+	return nil
+}
+
+// Check returns ErrImplausibleWeather when temperature falls outside the bounds.
+// The bounds and temperature must be in the same unit.
+func (b TemperatureBounds) Check(temperature float64) error {
+	if temperature < b.Min || temperature > b.Max {
+		return fmt.Errorf("%w: temperature %.1f is outside %.1f..%.1f", ErrImplausibleWeather, temperature, b.Min, b.Max)
+	}
+	return nil
+}
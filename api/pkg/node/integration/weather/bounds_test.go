@@ -0,0 +1,42 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemperatureBoundsCheck(t *testing.T) {
+	bounds := DefaultTemperatureBounds()
+
+	tests := []struct {
+		name        string
+		temperature float64
+		plausible   bool
+	}{
+		{"minimum is inclusive", -90, true},
+		{"maximum is inclusive", 60, true},
+		{"typical value", 21.5, true},
+		{"just below minimum", -90.1, false},
+		{"just above maximum", 60.1, false},
+		{"provider garbage high", 999, false},
+		{"provider garbage low", -500, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bounds.Check(tt.temperature)
+			if tt.plausible {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrImplausibleWeather)
+			}
+		})
+	}
+}
+
+func TestTemperatureBoundsValidate(t *testing.T) {
+	assert.NoError(t, DefaultTemperatureBounds().Validate())
+	assert.Error(t, TemperatureBounds{Min: 10, Max: 10}.Validate())
+	assert.Error(t, TemperatureBounds{Min: 20, Max: 10}.Validate())
+}
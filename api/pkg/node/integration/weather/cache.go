@@ -0,0 +1,70 @@
+package weather
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a provider response is reused when a client has a
+// cache but no TTL of its own
+const DefaultCacheTTL = time.Minute
+
+// Cache holds decoded provider responses keyed by request URL, which covers both
+// the endpoint and the coordinates, so nodes calling different providers or
+// locations never share an entry. It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time // overridable clock for tests
+}
+
+type cacheEntry struct {
+	response  map[string]any
+	expiresAt time.Time
+}
+
+// NewCache creates an empty response cache
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]cacheEntry), now: time.Now}
+}
+
+// Get returns the cached response for key if it hasn't expired
+func (c *Cache) Get(key string) (map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// Set stores response under key for ttl; a non-positive ttl stores nothing
+func (c *Cache) Set(key string, response map[string]any, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired entries so locations that are no longer requested don't linger
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{response: response, expiresAt: now.Add(ttl)}
+}
+
+// Len returns the number of stored entries, including any not yet swept after expiring
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
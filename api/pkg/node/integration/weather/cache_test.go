@@ -0,0 +1,105 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewCache()
+	cache.now = func() time.Time { return now }
+
+	cache.Set("key", map[string]any{"temperature": 21.0}, time.Minute)
+	response, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, 21.0, response["temperature"])
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("key")
+	assert.False(t, ok, "entry should expire once its TTL has elapsed")
+	assert.Equal(t, 0, cache.Len())
+
+	cache.Set("disabled", map[string]any{}, 0)
+	assert.Equal(t, 0, cache.Len(), "a zero TTL should store nothing")
+}
+
+func TestGetWeatherCache(t *testing.T) {
+	var calls atomic.Int32
+	newServer := func(temperature float64) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			fmt.Fprintf(w, `{"current_weather": {"temperature": %v}}`, temperature)
+		}))
+	}
+	first := newServer(10)
+	defer first.Close()
+	second := newServer(20)
+	defer second.Close()
+	endpoint := func(server *httptest.Server) string {
+		return server.URL + "?latitude={lat}&longitude={lon}"
+	}
+
+	t.Run("repeat calls within TTL reuse the response", func(t *testing.T) {
+		calls.Store(0)
+		client := NewClient(time.Second).WithCache(NewCache(), time.Minute)
+		for range 3 {
+			data, err := client.GetWeather(context.Background(), endpoint(first), 1, 2, "A")
+			assert.NoError(t, err)
+			assert.Equal(t, 10.0, data.Temperature)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("zero TTL disables caching", func(t *testing.T) {
+		calls.Store(0)
+		cache := NewCache()
+		client := NewClient(time.Second).WithCache(cache, 0)
+		for range 2 {
+			_, err := client.GetWeather(context.Background(), endpoint(first), 1, 2, "A")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("entries are isolated by endpoint and coordinates", func(t *testing.T) {
+		calls.Store(0)
+		cache := NewCache()
+		client := NewClient(time.Second).WithCache(cache, time.Minute)
+
+		data, err := client.GetWeather(context.Background(), endpoint(first), 1, 2, "A")
+		assert.NoError(t, err)
+		assert.Equal(t, 10.0, data.Temperature)
+
+		data, err = client.GetWeather(context.Background(), endpoint(second), 1, 2, "A")
+		assert.NoError(t, err)
+		assert.Equal(t, 20.0, data.Temperature, "a different provider must not get the first provider's response")
+
+		_, err = client.GetWeather(context.Background(), endpoint(first), 3, 4, "B")
+		assert.NoError(t, err)
+
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, 3, cache.Len())
+	})
+
+	t.Run("failed responses are not cached", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		cache := NewCache()
+		client := NewClient(time.Second).WithCache(cache, time.Minute)
+
+		_, err := client.GetWeather(context.Background(), failing.URL, 1, 2, "A")
+		assert.Error(t, err)
+		assert.Equal(t, 0, cache.Len())
+	})
+}
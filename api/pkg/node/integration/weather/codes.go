@@ -0,0 +1,43 @@
+package weather
+
+// weatherCodeDescriptions names the WMO weather interpretation codes that
+// Open-Meteo reports as weathercode
+var weatherCodeDescriptions = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Depositing rime fog",
+	51: "Light drizzle",
+	53: "Moderate drizzle",
+	55: "Dense drizzle",
+	56: "Light freezing drizzle",
+	57: "Dense freezing drizzle",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Heavy freezing rain",
+	71: "Slight snowfall",
+	73: "Moderate snowfall",
+	75: "Heavy snowfall",
+	77: "Snow grains",
+	80: "Slight rain showers",
+	81: "Moderate rain showers",
+	82: "Violent rain showers",
+	85: "Slight snow showers",
+	86: "Heavy snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with slight hail",
+	99: "Thunderstorm with heavy hail",
+}
+
+// DescribeWeatherCode returns a short description of a WMO weather code such as
+// "Moderate rain", or "Unknown" for a code outside the table
+func DescribeWeatherCode(code int) string {
+	if description, ok := weatherCodeDescriptions[code]; ok {
+		return description
+	}
+	return "Unknown"
+}
@@ -1,5 +1,7 @@
 package weather
 
+import "fmt"
+
 // WeatherEmoji provides an emoji based on temperature.
 type WeatherEmoji struct{}
 
@@ -18,3 +20,43 @@ func (*WeatherEmoji) Emoji(temp float64) string {
         return "🥶" // cold
     }
 }
+
+// WindEmoji provides an indicator based on windspeed in km/h.
+type WindEmoji struct{}
+
+// Emoji returns an emoji string based on the given windspeed.
+func (*WindEmoji) Emoji(speed float64) string {
+    switch {
+    case speed >= 62:
+        return "🌪️" // storm
+    case speed >= 40:
+        return "🌬️" // gale
+    case speed >= 20:
+        return "💨" // windy
+    case speed >= 10:
+        return "🍃" // breezy
+    default:
+        return "🪁" // calm
+    }
+}
+
+// Label returns a short description of the given windspeed.
+func (*WindEmoji) Label(speed float64) string {
+    switch {
+    case speed >= 62:
+        return "Stormy"
+    case speed >= 40:
+        return "Gale"
+    case speed >= 20:
+        return "Windy"
+    case speed >= 10:
+        return "Breezy"
+    default:
+        return "Calm"
+    }
+}
+
+// Message returns a human readable windspeed summary such as "Windy: 25 km/h 💨".
+func (w *WindEmoji) Message(speed float64) string {
+    return fmt.Sprintf("%s: %.0f km/h %s", w.Label(speed), speed, w.Emoji(speed))
+}
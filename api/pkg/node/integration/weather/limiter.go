@@ -0,0 +1,44 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+)
+
+// Limiter caps how many provider requests are in flight at once across every
+// client sharing it, so a batch of executions can't flood the weather API. A nil
+// Limiter doesn't limit. It is safe for concurrent use.
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter allows up to limit simultaneous requests; a non-positive limit
+// returns nil, which doesn't limit
+func NewLimiter(limit int) *Limiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &Limiter{slots: make(chan struct{}, limit)}
+}
+
+// Limit returns the request cap, or zero when requests are unlimited
+func (l *Limiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+	return cap(l.slots)
+}
+
+// Acquire waits for a free slot, giving up when ctx is done. On success the
+// caller must call release once its request has finished.
+func (l *Limiter) Acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a weather API request slot: %w", ctx.Err())
+	}
+}
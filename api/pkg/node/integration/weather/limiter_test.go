@@ -0,0 +1,84 @@
+package weather
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLimiter(t *testing.T) {
+	assert.Nil(t, NewLimiter(0))
+	assert.Nil(t, NewLimiter(-1))
+	assert.Equal(t, 0, (*Limiter)(nil).Limit())
+	assert.Equal(t, 3, NewLimiter(3).Limit())
+
+	// A nil limiter never blocks
+	release, err := (*Limiter)(nil).Acquire(context.Background())
+	assert.NoError(t, err)
+	release()
+}
+
+func TestGetWeatherConcurrencyLimit(t *testing.T) {
+	const limit = 3
+	const calls = 20
+
+	var inFlight, maxInFlight atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewBufferString(`{"current_weather": {"temperature": 18.4}}`)),
+			Request:    req,
+		}, nil
+	})
+	limiter := NewLimiter(limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := NewClientWithHTTPClient(&http.Client{Transport: transport}, time.Second).WithLimiter(limiter)
+			_, err := client.GetWeather(context.Background(), "https://weather.test/forecast?lat={lat}&lon={lon}", 1, 2, "Sydney")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(limit))
+	assert.Equal(t, int32(limit), maxInFlight.Load(), "calls should run up to the limit in parallel")
+}
+
+func TestGetWeatherLimiterRespectsCancellation(t *testing.T) {
+	limiter := NewLimiter(1)
+	release, err := limiter.Acquire(context.Background())
+	assert.NoError(t, err)
+	defer release()
+
+	var requests []*http.Request
+	httpClient := &http.Client{Transport: cannedResponse(http.StatusOK, `{"current_weather": {"temperature": 18.4}}`, &requests)}
+	client := NewClientWithHTTPClient(httpClient, time.Second).WithLimiter(limiter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.GetWeather(ctx, "https://weather.test/forecast?lat={lat}&lon={lon}", 1, 2, "Sydney")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, requests, "no request should be sent without a slot")
+}
@@ -0,0 +1,151 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Default locations of values in an Open-Meteo style response
+const (
+	DefaultTemperaturePath = "current_weather.temperature"
+	DefaultWindspeedPath   = "current_weather.windspeed"
+	DefaultTimePath        = "current_weather.time"
+	DefaultWeatherCodePath = "current_weather.weathercode"
+	// current_weather has no humidity; Open-Meteo reports it when the request
+	// asks for current=relative_humidity_2m
+	DefaultHumidityPath = "current.relative_humidity_2m"
+)
+
+// Where Open-Meteo's newer "current" block and some of its mirrors report values,
+// named after the measurement height. Tried in order when the default path is
+// missing and no path was configured.
+var (
+	fallbackTemperaturePaths = []string{"current.temperature_2m", "current_weather.temperature_2m"}
+	fallbackWindspeedPaths   = []string{"current.wind_speed_10m", "current_weather.wind_speed_10m"}
+	fallbackHumidityPaths    = []string{"current_weather.relative_humidity_2m", "current_weather.humidity"}
+	fallbackTimePaths        = []string{"current.time"}
+	fallbackWeatherCodePaths = []string{"current.weather_code", "current_weather.weather_code"}
+)
+
+// FieldPaths locates values in a decoded provider response using dotted paths
+// such as "main.temp". Numeric segments index into arrays ("data.0.temp").
+// Empty paths fall back to the defaults.
+type FieldPaths struct {
+	Temperature string `json:"temperaturePath,omitempty"`
+	Windspeed   string `json:"windPath,omitempty"`
+	Humidity    string `json:"humidityPath,omitempty"`
+	Time        string `json:"timePath,omitempty"`
+	WeatherCode string `json:"weatherCodePath,omitempty"`
+}
+
+func (p FieldPaths) temperature() string {
+	if p.Temperature == "" {
+		return DefaultTemperaturePath
+	}
+	return p.Temperature
+}
+
+func (p FieldPaths) windspeed() string {
+	if p.Windspeed == "" {
+		return DefaultWindspeedPath
+	}
+	return p.Windspeed
+}
+
+func (p FieldPaths) humidity() string {
+	if p.Humidity == "" {
+		return DefaultHumidityPath
+	}
+	return p.Humidity
+}
+
+func (p FieldPaths) time() string {
+	if p.Time == "" {
+		return DefaultTimePath
+	}
+	return p.Time
+}
+
+func (p FieldPaths) weatherCode() string {
+	if p.WeatherCode == "" {
+		return DefaultWeatherCodePath
+	}
+	return p.WeatherCode
+}
+
+// lookupFirst returns the value at path, or when path is the default the value
+// at the first fallback path present in the response
+func lookupFirst(data any, path, defaultPath string, fallbacks []string) (any, bool) {
+	if value, ok := LookupPath(data, path); ok {
+		return value, true
+	}
+	if path != defaultPath {
+		return nil, false
+	}
+	for _, fallback := range fallbacks {
+		if value, ok := LookupPath(data, fallback); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// ParseNumber converts a numeric value from a decoded response to a float64.
+// JSON numbers, json.Number and numeric strings such as "21.5" are accepted;
+// anything else, including NaN and infinities, is an error.
+func ParseNumber(value any) (float64, error) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%q is not numeric", v.String())
+		}
+		number = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not numeric", v)
+		}
+		number = parsed
+	default:
+		return 0, fmt.Errorf("%v (%T) is not numeric", value, value)
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("%v is not a finite number", value)
+	}
+	return number, nil
+}
+
+// LookupPath walks a dotted path through nested maps and arrays, returning the
+// value found and whether every segment resolved
+func LookupPath(data any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	current := data
+	for _, segment := range strings.Split(path, ".") {
+		switch value := current.(type) {
+		case map[string]any:
+			next, ok := value[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, false
+			}
+			current = value[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,61 @@
+package weather
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DefaultSecretQueryParams are the query parameters treated as secrets unless
+// SetSecretQueryParams adds more
+var DefaultSecretQueryParams = []string{"apikey", "api_key", "key", "appid", "token", "access_token"}
+
+// secretQueryParams are query parameters whose values SanitizeURL redacts
+var secretQueryParams = DefaultSecretQueryParams
+
+// SetSecretQueryParams treats the given query parameters as secrets in addition to
+// DefaultSecretQueryParams, for providers that name their credentials differently.
+// Names are matched case-insensitively. Call it before any workflow runs.
+func SetSecretQueryParams(names []string) {
+	params := append([]string(nil), DefaultSecretQueryParams...)
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			params = append(params, name)
+		}
+	}
+	secretQueryParams = params
+}
+
+// SanitizeURL hides credentials in a request URL so it can be logged or shown in
+// step outputs: user info and the values of secret query parameters are replaced
+// with REDACTED. Pass the resolved URL, as built by Client.RequestURL, so readers
+// see the coordinates that were requested rather than placeholders.
+func SanitizeURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "[unparseable URL]"
+	}
+	if parsed.User != nil {
+		parsed.User = url.User("REDACTED")
+	}
+	query := parsed.Query()
+	redacted := false
+	for name := range query {
+		if isSecretQueryParam(name) {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if redacted {
+		parsed.RawQuery = query.Encode()
+	}
+	return parsed.String()
+}
+
+func isSecretQueryParam(name string) bool {
+	for _, secret := range secretQueryParams {
+		if strings.EqualFold(name, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package weather
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // WeatherOption represents a location for weather data
@@ -11,6 +12,19 @@ type WeatherOption struct {
 	Lon  float64 `json:"lon"`
 }
 
+// Validate checks the coordinates are on the globe, so a typo such as lat 400 is
+// caught before it produces a bad API request
+func (o WeatherOption) Validate() error {
+	// Written so that NaN is out of range too
+	if !(o.Lat >= -90 && o.Lat <= 90) {
+		return fmt.Errorf("city %s has latitude %g outside [-90, 90]", o.City, o.Lat)
+	}
+	if !(o.Lon >= -180 && o.Lon <= 180) {
+		return fmt.Errorf("city %s has longitude %g outside [-180, 180]", o.City, o.Lon)
+	}
+	return nil
+}
+
 // IntegrationNodeMeta holds configuration for weather integration nodes
 type IntegrationNodeMeta struct {
 	APIEndpoint string         `json:"apiEndpoint"`
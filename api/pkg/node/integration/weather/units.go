@@ -0,0 +1,107 @@
+package weather
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Query parameters Open-Meteo style providers accept to choose response units
+const (
+	temperatureUnitParam = "temperature_unit"
+	windspeedUnitParam   = "windspeed_unit"
+)
+
+// Units a provider can be asked to report in
+const (
+	TemperatureUnitCelsius    = "celsius"
+	TemperatureUnitFahrenheit = "fahrenheit"
+)
+
+var validTemperatureUnits = map[string]bool{
+	TemperatureUnitCelsius:    true,
+	TemperatureUnitFahrenheit: true,
+}
+
+var validWindspeedUnits = map[string]bool{
+	"kmh": true,
+	"ms":  true,
+	"mph": true,
+	"kn":  true,
+}
+
+// APIUnits are the units requested from the provider so its response needs no
+// conversion. Empty fields leave the provider's default unit in place.
+type APIUnits struct {
+	Temperature string `json:"temperature,omitempty"`
+	Windspeed   string `json:"windspeed,omitempty"`
+}
+
+// IsZero reports whether no units are requested
+func (u APIUnits) IsZero() bool {
+	return u == APIUnits{}
+}
+
+// Validate checks the requested units are ones the provider understands
+func (u APIUnits) Validate() error {
+	if u.Temperature != "" && !validTemperatureUnits[u.Temperature] {
+		return fmt.Errorf("unsupported temperature unit: %s", u.Temperature)
+	}
+	if u.Windspeed != "" && !validWindspeedUnits[u.Windspeed] {
+		return fmt.Errorf("unsupported windspeed unit: %s", u.Windspeed)
+	}
+	return nil
+}
+
+// Apply adds the unit query parameters to requestURL, replacing any already present
+func (u APIUnits) Apply(requestURL string) string {
+	if u.IsZero() {
+		return requestURL
+	}
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return requestURL
+	}
+	query := parsed.Query()
+	if u.Temperature != "" {
+		query.Set(temperatureUnitParam, u.Temperature)
+	}
+	if u.Windspeed != "" {
+		query.Set(windspeedUnitParam, u.Windspeed)
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// Map returns the requested units keyed by field, omitting unset ones
+func (u APIUnits) Map() map[string]any {
+	units := make(map[string]any)
+	if u.Temperature != "" {
+		units["temperature"] = u.Temperature
+	}
+	if u.Windspeed != "" {
+		units["windspeed"] = u.Windspeed
+	}
+	return units
+}
+
+// TemperatureSymbol returns the symbol for the requested temperature unit, defaulting to °C
+func (u APIUnits) TemperatureSymbol() string {
+	if u.Temperature == TemperatureUnitFahrenheit {
+		return "°F"
+	}
+	return "°C"
+}
+
+// ParseAPIUnits reads requested units from node metadata of the form
+// {"temperature": "fahrenheit", "windspeed": "mph"}
+func ParseAPIUnits(raw map[string]any) APIUnits {
+	var units APIUnits
+	units.Temperature, _ = raw["temperature"].(string)
+	units.Windspeed, _ = raw["windspeed"].(string)
+	return units
+}
+
+// celsiusToFahrenheit converts a temperature in °C to °F
+func celsiusToFahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
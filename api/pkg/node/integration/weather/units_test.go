@@ -0,0 +1,69 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUnitsApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		units    APIUnits
+		url      string
+		expected string
+	}{
+		{
+			name:     "no units leaves URL unchanged",
+			url:      "https://api.open-meteo.com/v1/forecast?latitude=1&longitude=2",
+			expected: "https://api.open-meteo.com/v1/forecast?latitude=1&longitude=2",
+		},
+		{
+			name:     "both units appended",
+			units:    APIUnits{Temperature: "fahrenheit", Windspeed: "mph"},
+			url:      "https://api.open-meteo.com/v1/forecast?latitude=1&longitude=2",
+			expected: "https://api.open-meteo.com/v1/forecast?latitude=1&longitude=2&temperature_unit=fahrenheit&windspeed_unit=mph",
+		},
+		{
+			name:     "existing unit replaced",
+			units:    APIUnits{Windspeed: "kn"},
+			url:      "https://example.com/weather?windspeed_unit=kmh",
+			expected: "https://example.com/weather?windspeed_unit=kn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.units.Apply(tt.url))
+		})
+	}
+}
+
+func TestAPIUnitsValidate(t *testing.T) {
+	assert.NoError(t, APIUnits{}.Validate())
+	assert.NoError(t, APIUnits{Temperature: "celsius", Windspeed: "ms"}.Validate())
+	assert.EqualError(t, APIUnits{Temperature: "kelvin"}.Validate(), "unsupported temperature unit: kelvin")
+	assert.EqualError(t, APIUnits{Windspeed: "knots"}.Validate(), "unsupported windspeed unit: knots")
+}
+
+func TestGetWeatherRequestsUnits(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprintln(w, `{"current_weather": {"temperature": 77.0, "windspeed": 12.0}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second).WithUnits(APIUnits{Temperature: "fahrenheit", Windspeed: "mph"})
+	data, err := client.GetWeather(context.Background(), server.URL+"?latitude={lat}&longitude={lon}", 1, 2, "Sydney")
+	assert.NoError(t, err)
+	assert.Equal(t, 77.0, data.Temperature)
+	assert.Equal(t, []string{"fahrenheit"}, query["temperature_unit"])
+	assert.Equal(t, []string{"mph"}, query["windspeed_unit"])
+	assert.Equal(t, []string{"1.000000"}, query["latitude"])
+}
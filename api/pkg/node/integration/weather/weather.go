@@ -3,8 +3,12 @@ package weather
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -12,6 +16,12 @@ import (
 // WeatherData represents the parsed weather API response
 type WeatherData struct {
 	Temperature float64 `json:"temperature"`
+	Windspeed   *float64 `json:"windspeed,omitempty"`
+	Humidity    *float64 `json:"humidity,omitempty"` // relative humidity in %
+	Time        string   `json:"time,omitempty"`     // observation time as reported by the provider
+	WeatherCode *int     `json:"weathercode,omitempty"`
+	// WeatherDescription describes WeatherCode, e.g. "Light drizzle"
+	WeatherDescription string `json:"weatherDescription,omitempty"`
 	Location    string  `json:"location"`
 	RawResponse map[string]any `json:"rawResponse"`
 }
@@ -20,64 +30,215 @@ type WeatherData struct {
 type Client struct {
 	httpClient *http.Client
 	timeout    time.Duration
+	logger     *slog.Logger
+	paths      FieldPaths
+	cache      *Cache
+	cacheTTL   time.Duration
+	units      APIUnits
+	limiter    *Limiter
 }
 
 // NewClient creates a new weather API client
 func NewClient(timeout time.Duration) *Client {
+	return NewClientWithHTTPClient(&http.Client{}, timeout)
+}
+
+// NewClientWithHTTPClient creates a weather API client that sends requests through
+// the given HTTP client, allowing a custom transport to be injected
+func NewClientWithHTTPClient(httpClient *http.Client, timeout time.Duration) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
 	if timeout == 0 {
 		timeout = 10 * time.Second
 	}
 	
 	return &Client{
-		httpClient: &http.Client{},
+		httpClient: httpClient,
 		timeout:    timeout,
 	}
 }
 
-// GetWeather fetches weather data for the specified location
-func (c *Client) GetWeather(ctx context.Context, endpoint string, lat, lon float64, cityName string) (*WeatherData, error) {
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
+// WithLogger sets the logger used to record API calls, typically the node's
+// execution logger so entries carry workflow and execution IDs
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	c.logger = logger
+	return c
+}
+
+// WithFieldPaths sets where temperature and windspeed are read from in the
+// provider's response, for providers that nest them differently
+func (c *Client) WithFieldPaths(paths FieldPaths) *Client {
+	c.paths = paths
+	return c
+}
+
+// WithUnits asks the provider to report values in the given units
+func (c *Client) WithUnits(units APIUnits) *Client {
+	c.units = units
+	return c
+}
+
+// WithCache reuses provider responses from cache for ttl. A ttl of 0 disables
+// caching for this client; a negative ttl uses DefaultCacheTTL.
+func (c *Client) WithCache(cache *Cache, ttl time.Duration) *Client {
+	if ttl < 0 {
+		ttl = DefaultCacheTTL
+	}
+	c.cache = cache
+	c.cacheTTL = ttl
+	return c
+}
+
+// WithLimiter makes requests to the provider wait for a slot in limiter, which is
+// usually shared by every client. Cached responses don't take a slot.
+func (c *Client) WithLimiter(limiter *Limiter) *Client {
+	c.limiter = limiter
+	return c
+}
+
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.Default()
+	}
+	return c.logger
+}
+
+// cachedResponse returns a cached response for requestURL when caching is enabled
+func (c *Client) cachedResponse(requestURL string) (map[string]any, bool) {
+	if c.cache == nil || c.cacheTTL <= 0 {
+		return nil, false
+	}
+	return c.cache.Get(requestURL)
+}
+
+// fetch calls the provider and decodes its JSON response, returning the HTTP status.
+// It waits for a limiter slot first; the request timeout starts once it has one.
+func (c *Client) fetch(ctx context.Context, requestURL string) (map[string]any, int, error) {
+	release, err := c.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer release()
 	
-	// Format URL with coordinates
-	url := strings.ReplaceAll(endpoint, "{lat}", fmt.Sprintf("%f", lat))
-	url = strings.ReplaceAll(url, "{lon}", fmt.Sprintf("%f", lon))
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 	
-	// Create and execute request
-	req, err := http.NewRequestWithContext(ctxWithTimeout, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
 	}
 	
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call weather API: %w", err)
+		// Transport errors quote the request URL, and they end up in step outputs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = SanitizeURL(urlErr.URL)
+		}
+		return nil, 0, fmt.Errorf("failed to call weather API: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("weather API returned status %d", resp.StatusCode)
+		return nil, resp.StatusCode, fmt.Errorf("weather API returned status %d", resp.StatusCode)
 	}
 	
-	// Parse response
 	var weatherData map[string]any
 	if err := json.NewDecoder(resp.Body).Decode(&weatherData); err != nil {
-		return nil, fmt.Errorf("failed to parse weather API response: %w", err)
+		return nil, resp.StatusCode, fmt.Errorf("failed to parse weather API response: %w", err)
 	}
+	return weatherData, resp.StatusCode, nil
+}
+
+// BuildURL fills the {lat} and {lon} placeholders of an endpoint with coordinates
+func BuildURL(endpoint string, lat, lon float64) string {
+	url := strings.ReplaceAll(endpoint, "{lat}", fmt.Sprintf("%f", lat))
+	return strings.ReplaceAll(url, "{lon}", fmt.Sprintf("%f", lon))
+}
+
+// RequestURL returns the URL GetWeather requests for the coordinates: the endpoint
+// with its placeholders filled and the client's units applied
+func (c *Client) RequestURL(endpoint string, lat, lon float64) string {
+	return c.units.Apply(BuildURL(endpoint, lat, lon))
+}
+
+// GetWeather fetches weather data for the specified location
+func (c *Client) GetWeather(ctx context.Context, endpoint string, lat, lon float64, cityName string) (data *WeatherData, err error) {
+	requestURL := c.RequestURL(endpoint, lat, lon)
+	started := time.Now()
+	status := 0
+	cached := false
+	defer func() {
+		attrs := []any{
+			"url", SanitizeURL(requestURL),
+			"city", cityName,
+			"latencyMs", time.Since(started).Milliseconds(),
+			"status", status,
+			"cached", cached,
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		} else {
+			attrs = append(attrs, "temperature", data.Temperature)
+		}
+		c.log().DebugContext(ctx, "Weather API call", attrs...)
+	}()
 	
-	currentWeather, ok := weatherData["current_weather"].(map[string]any)
-	if !ok {
-		return nil, fmt.Errorf("invalid weather API response format")
+	weatherData, ok := c.cachedResponse(requestURL)
+	if ok {
+		cached = true
+		status = http.StatusOK
+	} else {
+		weatherData, status, err = c.fetch(ctx, requestURL)
+		if err != nil {
+			return nil, err
+		}
+		if c.cache != nil {
+			c.cache.Set(requestURL, weatherData, c.cacheTTL)
+		}
 	}
 	
-	temperature, ok := currentWeather["temperature"].(float64)
+	rawTemperature, ok := lookupFirst(weatherData, c.paths.temperature(), DefaultTemperaturePath, fallbackTemperaturePaths)
 	if !ok {
-		return nil, fmt.Errorf("invalid temperature value in API response")
+		return nil, fmt.Errorf("invalid weather API response format: no value at %s", c.paths.temperature())
+	}
+	temperature, err := ParseNumber(rawTemperature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid temperature value in API response: %w", err)
 	}
 	
-	return &WeatherData{
+	data = &WeatherData{
 		Temperature: temperature,
 		Location:    cityName,
 		RawResponse: weatherData,
-	}, nil
+	}
+	
+	// Windspeed is optional; older endpoints only report temperature
+	if rawWindspeed, ok := lookupFirst(weatherData, c.paths.windspeed(), DefaultWindspeedPath, fallbackWindspeedPaths); ok {
+		if windspeed, err := ParseNumber(rawWindspeed); err == nil {
+			data.Windspeed = &windspeed
+		}
+	}
+	
+	// The remaining fields are optional too and are read from the same response
+	if rawHumidity, ok := lookupFirst(weatherData, c.paths.humidity(), DefaultHumidityPath, fallbackHumidityPaths); ok {
+		if humidity, err := ParseNumber(rawHumidity); err == nil {
+			data.Humidity = &humidity
+		}
+	}
+	if rawTime, ok := lookupFirst(weatherData, c.paths.time(), DefaultTimePath, fallbackTimePaths); ok {
+		if observed, ok := rawTime.(string); ok {
+			data.Time = observed
+		}
+	}
+	if rawCode, ok := lookupFirst(weatherData, c.paths.weatherCode(), DefaultWeatherCodePath, fallbackWeatherCodePaths); ok {
+		if code, err := ParseNumber(rawCode); err == nil && code == math.Trunc(code) {
+			weatherCode := int(code)
+			data.WeatherCode = &weatherCode
+			data.WeatherDescription = DescribeWeatherCode(weatherCode)
+		}
+	}
+	
+	return data, nil
 }
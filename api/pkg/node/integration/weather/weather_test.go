@@ -1,8 +1,16 @@
 package weather
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +42,40 @@ func TestWeatherEmoji(t *testing.T) {
 	}
 }
 
+func TestWindEmoji(t *testing.T) {
+	wind := WindEmoji{}
+	
+	testCases := []struct {
+		speed         float64
+		expected      string
+		expectedLabel string
+	}{
+		{0, "🪁", "Calm"},
+		{9, "🪁", "Calm"},
+		{10, "🍃", "Breezy"},
+		{19, "🍃", "Breezy"},
+		{20, "💨", "Windy"},
+		{39, "💨", "Windy"},
+		{40, "🌬️", "Gale"},
+		{61, "🌬️", "Gale"},
+		{62, "🌪️", "Stormy"},
+		{90, "🌪️", "Stormy"},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.expectedLabel, func(t *testing.T) {
+			assert.Equal(t, tc.expected, wind.Emoji(tc.speed))
+			assert.Equal(t, tc.expectedLabel, wind.Label(tc.speed))
+		})
+	}
+}
+
+func TestWindMessage(t *testing.T) {
+	wind := WindEmoji{}
+	assert.Equal(t, "Windy: 25 km/h 💨", wind.Message(25))
+	assert.Equal(t, "Calm: 3 km/h 🪁", wind.Message(3.2))
+}
+
 func TestParseMetadata(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -144,3 +186,383 @@ func TestWeatherOptionMarshaling(t *testing.T) {
 	assert.Equal(t, original.Lat, unmarshaled.Lat)
 	assert.Equal(t, original.Lon, unmarshaled.Lon)
 }
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// cannedResponse returns a RoundTripper that replies with the given status and body
+func cannedResponse(status int, body string, requests *[]*http.Request) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if requests != nil {
+			*requests = append(*requests, req)
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestNewClientWithHTTPClient(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		body          string
+		expectError   bool
+		expectedTemp  float64
+	}{
+		{
+			name:         "Canned success response",
+			status:       http.StatusOK,
+			body:         `{"current_weather": {"temperature": 18.4}}`,
+			expectError:  false,
+			expectedTemp: 18.4,
+		},
+		{
+			name:         "Canned response with windspeed",
+			status:       http.StatusOK,
+			body:         `{"current_weather": {"temperature": 18.4, "windspeed": 25.0}}`,
+			expectError:  false,
+			expectedTemp: 18.4,
+		},
+		{
+			name:        "Canned error status",
+			status:      http.StatusServiceUnavailable,
+			body:        ``,
+			expectError: true,
+		},
+		{
+			name:        "Canned malformed body",
+			status:      http.StatusOK,
+			body:        `not-json`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []*http.Request
+			httpClient := &http.Client{Transport: cannedResponse(tc.status, tc.body, &requests)}
+			client := NewClientWithHTTPClient(httpClient, time.Second)
+
+			data, err := client.GetWeather(context.Background(), "https://weather.test/forecast?lat={lat}&lon={lon}", -33.8688, 151.2093, "Sydney")
+
+			// The injected transport should receive the request with coordinates filled in
+			assert.Len(t, requests, 1)
+			assert.Equal(t, "weather.test", requests[0].URL.Host)
+			assert.Equal(t, "-33.868800", requests[0].URL.Query().Get("lat"))
+			assert.Equal(t, "151.209300", requests[0].URL.Query().Get("lon"))
+
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, data)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTemp, data.Temperature)
+				assert.Equal(t, "Sydney", data.Location)
+				if strings.Contains(tc.body, "windspeed") {
+					assert.NotNil(t, data.Windspeed)
+					assert.Equal(t, 25.0, *data.Windspeed)
+				} else {
+					assert.Nil(t, data.Windspeed, "Windspeed should be absent when not reported")
+				}
+			}
+		})
+	}
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	client := NewClient(0)
+	assert.NotNil(t, client.httpClient)
+	assert.Equal(t, 10*time.Second, client.timeout)
+
+	injected := NewClientWithHTTPClient(nil, 5*time.Second)
+	assert.NotNil(t, injected.httpClient)
+	assert.Equal(t, 5*time.Second, injected.timeout)
+}
+
+func TestGetWeatherErrorHidesSecrets(t *testing.T) {
+	client := NewClientWithHTTPClient(&http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}, time.Second)
+
+	_, err := client.GetWeather(context.Background(), "https://weather.test/forecast?lat={lat}&lon={lon}&apikey=secret", 1, 2, "Sydney")
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "secret")
+		assert.Contains(t, err.Error(), "apikey=REDACTED")
+	}
+}
+
+func TestGetWeatherLogsCall(t *testing.T) {
+	decodeLog := func(t *testing.T, buf *bytes.Buffer) map[string]any {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		return entry
+	}
+	newLogger := func(buf *bytes.Buffer) *slog.Logger {
+		return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	endpoint := "https://weather.test/forecast?lat={lat}&lon={lon}&apikey=secret"
+
+	t.Run("successful call", func(t *testing.T) {
+		var buf bytes.Buffer
+		httpClient := &http.Client{Transport: cannedResponse(http.StatusOK, `{"current_weather": {"temperature": 18.4}}`, nil)}
+		client := NewClientWithHTTPClient(httpClient, time.Second).WithLogger(newLogger(&buf))
+
+		_, err := client.GetWeather(context.Background(), endpoint, 1, 2, "Sydney")
+		assert.NoError(t, err)
+
+		entry := decodeLog(t, &buf)
+		assert.Equal(t, "Weather API call", entry["msg"])
+		assert.Equal(t, float64(http.StatusOK), entry["status"])
+		assert.Equal(t, 18.4, entry["temperature"])
+		assert.Contains(t, entry, "latencyMs")
+		assert.Contains(t, entry["url"], "apikey=REDACTED")
+		assert.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("failed call", func(t *testing.T) {
+		var buf bytes.Buffer
+		httpClient := &http.Client{Transport: cannedResponse(http.StatusServiceUnavailable, ``, nil)}
+		client := NewClientWithHTTPClient(httpClient, time.Second).WithLogger(newLogger(&buf))
+
+		_, err := client.GetWeather(context.Background(), endpoint, 1, 2, "Sydney")
+		assert.Error(t, err)
+
+		entry := decodeLog(t, &buf)
+		assert.Equal(t, float64(http.StatusServiceUnavailable), entry["status"])
+		assert.Contains(t, entry["error"], "status 503")
+		assert.NotContains(t, entry, "temperature")
+	})
+}
+
+func TestLookupPath(t *testing.T) {
+	var data map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(`{"main": {"temp": 12.5}, "data": [{"temp": 3.0}], "flat": 7.0}`), &data))
+
+	tests := []struct {
+		path     string
+		expected any
+		found    bool
+	}{
+		{"main.temp", 12.5, true},
+		{"data.0.temp", 3.0, true},
+		{"flat", 7.0, true},
+		{"main.missing", nil, false},
+		{"data.1.temp", nil, false},
+		{"data.x.temp", nil, false},
+		{"flat.deeper", nil, false},
+		{"", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found := LookupPath(data, tt.path)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestGetWeatherWithFieldPaths(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         string
+		paths        FieldPaths
+		expectError  bool
+		expectedTemp float64
+		expectedWind *float64
+	}{
+		{
+			name:         "default paths",
+			body:         `{"current_weather": {"temperature": 18.4, "windspeed": 12.0}}`,
+			expectedTemp: 18.4,
+			expectedWind: ptrFloat(12.0),
+		},
+		{
+			name:         "OpenWeatherMap style nesting",
+			body:         `{"main": {"temp": 21.0}, "wind": {"speed": 30.0}}`,
+			paths:        FieldPaths{Temperature: "main.temp", Windspeed: "wind.speed"},
+			expectedTemp: 21.0,
+			expectedWind: ptrFloat(30.0),
+		},
+		{
+			name:         "array nesting without wind",
+			body:         `{"data": [{"app_temp": -4.5}]}`,
+			paths:        FieldPaths{Temperature: "data.0.app_temp", Windspeed: "data.0.wind_spd"},
+			expectedTemp: -4.5,
+		},
+		{
+			name:        "path not in response",
+			body:        `{"current_weather": {"temperature": 18.4}}`,
+			paths:       FieldPaths{Temperature: "main.temp"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: cannedResponse(http.StatusOK, tc.body, nil)}
+			client := NewClientWithHTTPClient(httpClient, time.Second).WithFieldPaths(tc.paths)
+
+			data, err := client.GetWeather(context.Background(), "https://weather.test/forecast", 1, 2, "Sydney")
+			if tc.expectError {
+				assert.ErrorContains(t, err, "no value at main.temp")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTemp, data.Temperature)
+			assert.Equal(t, tc.expectedWind, data.Windspeed)
+		})
+	}
+}
+
+func TestGetWeatherReadsAllFields(t *testing.T) {
+	testCases := []struct {
+		name                string
+		body                string
+		paths               FieldPaths
+		expectedHumidity    *float64
+		expectedTime        string
+		expectedCode        *int
+		expectedDescription string
+	}{
+		{
+			name: "Open-Meteo current_weather with current humidity",
+			body: `{"current_weather": {"temperature": 18.4, "windspeed": 12.0, "time": "2026-10-17T09:00", "weathercode": 61},
+				"current": {"relative_humidity_2m": 82}}`,
+			expectedHumidity:    ptrFloat(82),
+			expectedTime:        "2026-10-17T09:00",
+			expectedCode:        ptrInt(61),
+			expectedDescription: "Slight rain",
+		},
+		{
+			name: "Open-Meteo current block only",
+			body: `{"current": {"temperature_2m": 18.4, "wind_speed_10m": 12.0, "relative_humidity_2m": 40,
+				"time": "2026-10-17T09:00", "weather_code": 3}}`,
+			expectedHumidity:    ptrFloat(40),
+			expectedTime:        "2026-10-17T09:00",
+			expectedCode:        ptrInt(3),
+			expectedDescription: "Overcast",
+		},
+		{
+			name:                "configured paths",
+			body:                `{"main": {"temp": 21.0, "humidity": "55"}, "dt_txt": "2026-10-17 09:00:00", "weather": [{"id": 100}]}`,
+			paths:               FieldPaths{Temperature: "main.temp", Humidity: "main.humidity", Time: "dt_txt", WeatherCode: "weather.0.id"},
+			expectedHumidity:    ptrFloat(55),
+			expectedTime:        "2026-10-17 09:00:00",
+			expectedCode:        ptrInt(100),
+			expectedDescription: "Unknown",
+		},
+		{
+			name: "optional fields missing or malformed",
+			body: `{"current_weather": {"temperature": 18.4, "time": 1760691600, "weathercode": 2.5}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: cannedResponse(http.StatusOK, tc.body, nil)}
+			client := NewClientWithHTTPClient(httpClient, time.Second).WithFieldPaths(tc.paths)
+
+			data, err := client.GetWeather(context.Background(), "https://weather.test/forecast", 1, 2, "Sydney")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedHumidity, data.Humidity)
+			assert.Equal(t, tc.expectedTime, data.Time)
+			assert.Equal(t, tc.expectedCode, data.WeatherCode)
+			assert.Equal(t, tc.expectedDescription, data.WeatherDescription)
+		})
+	}
+}
+
+func TestDescribeWeatherCode(t *testing.T) {
+	assert.Equal(t, "Clear sky", DescribeWeatherCode(0))
+	assert.Equal(t, "Thunderstorm with heavy hail", DescribeWeatherCode(99))
+	assert.Equal(t, "Unknown", DescribeWeatherCode(4))
+}
+
+func ptrInt(v int) *int {
+	return &v
+}
+
+func ptrFloat(v float64) *float64 {
+	return &v
+}
+
+func TestParseNumber(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       any
+		expected    float64
+		expectError bool
+	}{
+		{"float", 21.5, 21.5, false},
+		{"json number", json.Number("-3"), -3, false},
+		{"numeric string", "18.2", 18.2, false},
+		{"padded string", " 7 ", 7, false},
+		{"word", "warm", 0, true},
+		{"empty string", "", 0, true},
+		{"NaN string", "NaN", 0, true},
+		{"bool", true, 0, true},
+		{"null", nil, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			number, err := ParseNumber(tc.value)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, number)
+		})
+	}
+}
+
+func TestGetWeatherTemperatureRepresentations(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         string
+		expectedTemp float64
+		expectedWind *float64
+		expectError  string
+	}{
+		{"integer", `{"current_weather": {"temperature": 18}}`, 18, nil, ""},
+		{"decimal", `{"current_weather": {"temperature": 18.5}}`, 18.5, nil, ""},
+		{"string", `{"current_weather": {"temperature": "18.5", "windspeed": "12"}}`, 18.5, ptrFloat(12), ""},
+		{"temperature_2m", `{"current": {"temperature_2m": -2.1, "wind_speed_10m": 30.0}}`, -2.1, ptrFloat(30), ""},
+		{"non-numeric string", `{"current_weather": {"temperature": "n/a"}}`, 0, nil, `invalid temperature value in API response: "n/a" is not numeric`},
+		{"object", `{"current_weather": {"temperature": {"value": 18}}}`, 0, nil, "invalid temperature value in API response"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: cannedResponse(http.StatusOK, tc.body, nil)}
+			client := NewClientWithHTTPClient(httpClient, time.Second)
+
+			data, err := client.GetWeather(context.Background(), "https://weather.test/forecast", 1, 2, "Sydney")
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTemp, data.Temperature)
+			assert.Equal(t, tc.expectedWind, data.Windspeed)
+		})
+	}
+
+	t.Run("fallbacks only apply to the default path", func(t *testing.T) {
+		httpClient := &http.Client{Transport: cannedResponse(http.StatusOK, `{"current": {"temperature_2m": 5.0}}`, nil)}
+		client := NewClientWithHTTPClient(httpClient, time.Second).WithFieldPaths(FieldPaths{Temperature: "main.temp"})
+
+		_, err := client.GetWeather(context.Background(), "https://weather.test/forecast", 1, 2, "Sydney")
+		assert.ErrorContains(t, err, "no value at main.temp")
+	})
+}
@@ -2,6 +2,10 @@ package node
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"workflow-code-test/api/pkg/models"
 )
 
@@ -20,6 +24,15 @@ type Node interface {
 	GetBaseInfo() BaseNode
 }
 
+// CacheableNode is implemented by node types that opt in to having their output
+// reused when they run again within one execution. CacheKey summarizes the inputs
+// the node reads, reporting false when it can't; the engine reuses a completed
+// output for the same node ID and key instead of executing the node again.
+type CacheableNode interface {
+	Node
+	CacheKey(inputs NodeInputs) (key string, ok bool)
+}
+
 // BaseNode provides common node functionality
 type BaseNode struct {
 	ID          string
@@ -32,11 +45,41 @@ func (n BaseNode) GetBaseInfo() BaseNode {
 	return n
 }
 
+// ephemeralContextKey marks a context whose runs are never persisted
+type ephemeralContextKey struct{}
+
+// WithEphemeral returns a context for runs that are never persisted, such as ad-hoc
+// executions. Nodes that keep data between runs leave their stores untouched under it.
+func WithEphemeral(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ephemeralContextKey{}, true)
+}
+
+// Ephemeral reports whether ctx belongs to a run that is never persisted
+func Ephemeral(ctx context.Context) bool {
+	ephemeral, _ := ctx.Value(ephemeralContextKey{}).(bool)
+	return ephemeral
+}
+
 // NodeInputs contains all inputs available to a node during execution
 type NodeInputs struct {
+	WorkflowID    string // ID of the workflow being run, for state scoped to it
 	WorkflowInput models.WorkflowInput
-	NodeData      map[string]any
-	PriorOutputs  map[string]NodeOutputs
+	// NodeData holds the values earlier completed nodes shared through
+	// NodeOutputs.Shared. It is read-only to nodes; the engine owns it.
+	NodeData     map[string]any
+	PriorOutputs map[string]NodeOutputs
+	// ConditionNodeID is the latest condition node to have run, whose branch the
+	// run is on; empty until a condition has run
+	ConditionNodeID string
+	Logger          *slog.Logger // Enriched with workflow, execution and node IDs by the engine
+}
+
+// Log returns the node's logger, falling back to the default logger when none was provided
+func (in NodeInputs) Log() *slog.Logger {
+	if in.Logger == nil {
+		return slog.Default()
+	}
+	return in.Logger
 }
 
 // NodeOutputs represents the output of a node's execution
@@ -46,7 +89,82 @@ type NodeOutputs struct {
 	StartedAt  string
 	EndedAt    string
 	NextNodeID string // For conditional routing
+	// DisplayLabel replaces the node's base label in the execution trace when set,
+	// so the step can reflect runtime data such as the city looked up
+	DisplayLabel string
+	// Explanation optionally says in plain words what the node did and why, such as
+	// the route taken or why an email wasn't sent. The engine records it only for
+	// runs in explain mode.
+	Explanation string
+	// Suppressed marks a completed step that deliberately held back its notification,
+	// such as an email skipped by deduplication. The run then finishes as partial.
+	Suppressed bool
+	// Shared lists values for later nodes to read from NodeData without knowing
+	// which node produced them. The engine merges it once the step completes, so
+	// a failed step shares nothing, and a later node's value for a key wins.
+	Shared map[string]any
+}
+
+// Float returns the number stored under key in Data. Integers are converted; it
+// reports false when the key is missing or holds anything else.
+func (o NodeOutputs) Float(key string) (float64, bool) {
+	switch value := o.Data[key].(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	default:
+		return 0, false
+	}
+}
+
+// String returns the string stored under key in Data, reporting false when the key
+// is missing or holds another type
+func (o NodeOutputs) String(key string) (string, bool) {
+	value, ok := o.Data[key].(string)
+	return value, ok
+}
+
+// Bool returns the bool stored under key in Data, reporting false when the key is
+// missing or holds another type
+func (o NodeOutputs) Bool(key string) (bool, bool) {
+	value, ok := o.Data[key].(bool)
+	return value, ok
+}
+
+// Map returns the object stored under key in Data, reporting false when the key is
+// missing or holds another type
+func (o NodeOutputs) Map(key string) (map[string]any, bool) {
+	value, ok := o.Data[key].(map[string]any)
+	return value, ok
+}
+
+// Shared returns a value an earlier node contributed to NodeData
+func (in NodeInputs) Shared(key string) (any, bool) {
+	value, ok := in.NodeData[key]
+	return value, ok
 }
 
 // NodeFactory is a function that creates a node from a model
-type NodeFactory func(models.Node) (Node, error)
\ No newline at end of file
+type NodeFactory func(models.Node) (Node, error)
+
+// ErrMissingMetadata is returned by constructors of node types that can't work without configuration
+var ErrMissingMetadata = errors.New("node metadata is required")
+
+// Capitalize upper-cases the first letter of an error message for display in a step's output
+func Capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// RequireMetadata returns the model's metadata, or ErrMissingMetadata naming the
+// node when it is nil or empty. Node types whose configuration is optional read
+// model.Data.Metadata directly; indexing a nil map is safe.
+func RequireMetadata(model models.Node) (map[string]any, error) {
+	if len(model.Data.Metadata) == 0 {
+		return nil, fmt.Errorf("%w: %s node %s", ErrMissingMetadata, model.Type, model.ID)
+	}
+	return model.Data.Metadata, nil
+}
@@ -0,0 +1,77 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeOutputsAccessors(t *testing.T) {
+	outputs := NodeOutputs{Data: map[string]any{
+		"temperature": 28.5,
+		"count":       3,
+		"city":        "Sydney",
+		"sent":        true,
+		"details":     map[string]any{"branch": "true"},
+	}}
+
+	t.Run("float", func(t *testing.T) {
+		value, ok := outputs.Float("temperature")
+		assert.True(t, ok)
+		assert.Equal(t, 28.5, value)
+
+		value, ok = outputs.Float("count")
+		assert.True(t, ok, "integers are converted")
+		assert.Equal(t, 3.0, value)
+
+		_, ok = outputs.Float("city")
+		assert.False(t, ok)
+		_, ok = outputs.Float("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		value, ok := outputs.String("city")
+		assert.True(t, ok)
+		assert.Equal(t, "Sydney", value)
+
+		_, ok = outputs.String("temperature")
+		assert.False(t, ok)
+		_, ok = outputs.String("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		value, ok := outputs.Bool("sent")
+		assert.True(t, ok)
+		assert.True(t, value)
+
+		_, ok = outputs.Bool("city")
+		assert.False(t, ok)
+		_, ok = outputs.Bool("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		value, ok := outputs.Map("details")
+		assert.True(t, ok)
+		assert.Equal(t, map[string]any{"branch": "true"}, value)
+
+		_, ok = outputs.Map("sent")
+		assert.False(t, ok)
+		_, ok = outputs.Map("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("nil data", func(t *testing.T) {
+		var empty NodeOutputs
+		_, ok := empty.Float("temperature")
+		assert.False(t, ok)
+		_, ok = empty.String("city")
+		assert.False(t, ok)
+		_, ok = empty.Bool("sent")
+		assert.False(t, ok)
+		_, ok = empty.Map("details")
+		assert.False(t, ok)
+	})
+}
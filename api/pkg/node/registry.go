@@ -2,11 +2,15 @@ package node
 
 import (
 	"fmt"
+	"slices"
+	"sync"
 	"workflow-code-test/api/pkg/models"
 )
 
-// Registry holds all registered node types
+// Registry holds all registered node types. It is safe for concurrent use, so
+// node types can be reloaded while workflows run.
 type Registry struct {
+    mu        sync.RWMutex
     factories map[models.NodeType]NodeFactory
 }
 
@@ -19,14 +23,43 @@ func NewRegistry() *Registry {
 
 // Register adds a node factory for the given type
 func (r *Registry) Register(nodeType models.NodeType, factory NodeFactory) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
     r.factories[nodeType] = factory
 }
 
 // Create instantiates a node from its model definition
 func (r *Registry) Create(nodeModel models.Node) (Node, error) {
+    r.mu.RLock()
     factory, exists := r.factories[nodeModel.Type]
+    r.mu.RUnlock()
     if !exists {
         return nil, fmt.Errorf("no factory registered for node type %s", nodeModel.Type)
     }
     return factory(nodeModel)
-}
\ No newline at end of file
+}
+
+// Types returns the registered node types in name order
+func (r *Registry) Types() []models.NodeType {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    types := make([]models.NodeType, 0, len(r.factories))
+    for nodeType := range r.factories {
+        types = append(types, nodeType)
+    }
+    slices.Sort(types)
+    return types
+}
+
+// Reload re-registers every node type by running register against an empty
+// registry, then swaps the result in at once. A concurrent Create sees either the
+// old factories or the new ones, never a mix. It returns the registered types.
+func (r *Registry) Reload(register func(*Registry)) []models.NodeType {
+    fresh := NewRegistry()
+    register(fresh)
+
+    r.mu.Lock()
+    r.factories = fresh.factories
+    r.mu.Unlock()
+    return r.Types()
+}
@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"workflow-code-test/api/pkg/models"
 
@@ -219,3 +220,75 @@ func TestRegistryValidationPassthrough(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, validationError, err)
 }
+
+func TestRegistryTypes(t *testing.T) {
+	registry := NewRegistry()
+	assert.Empty(t, registry.Types())
+
+	registry.Register(models.NodeTypeStart, mockFactory(models.NodeTypeStart, nil))
+	registry.Register(models.NodeTypeEnd, mockFactory(models.NodeTypeEnd, nil))
+	assert.Equal(t, []models.NodeType{models.NodeTypeEnd, models.NodeTypeStart}, registry.Types())
+}
+
+func TestRegistryReload(t *testing.T) {
+	// versionedFactory builds nodes whose ID records the factory version
+	versionedFactory := func(version string) NodeFactory {
+		return func(model models.Node) (Node, error) {
+			return &mockNode{id: version, nodeType: model.Type}, nil
+		}
+	}
+	registerV1 := func(r *Registry) {
+		r.Register(models.NodeTypeStart, versionedFactory("v1"))
+		r.Register(models.NodeTypeForm, versionedFactory("v1"))
+	}
+	registerV2 := func(r *Registry) {
+		r.Register(models.NodeTypeStart, versionedFactory("v2"))
+		r.Register(models.NodeTypeEnd, versionedFactory("v2"))
+	}
+
+	registry := NewRegistry()
+	registerV1(registry)
+
+	t.Run("replaces every factory", func(t *testing.T) {
+		types := registry.Reload(registerV2)
+		assert.Equal(t, []models.NodeType{models.NodeTypeEnd, models.NodeTypeStart}, types)
+
+		_, err := registry.Create(models.Node{ID: "form", Type: models.NodeTypeForm})
+		assert.Error(t, err, "types missing from the reload are no longer registered")
+		n, err := registry.Create(models.Node{ID: "start", Type: models.NodeTypeStart})
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", n.GetBaseInfo().ID)
+	})
+
+	t.Run("concurrent creates see a whole set of factories", func(t *testing.T) {
+		var wg sync.WaitGroup
+		stop := make(chan struct{})
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					// start is registered by both versions, so it must always resolve
+					n, err := registry.Create(models.Node{ID: "start", Type: models.NodeTypeStart})
+					if assert.NoError(t, err) {
+						assert.Contains(t, []string{"v1", "v2"}, n.GetBaseInfo().ID)
+					}
+				}
+			}()
+		}
+		for i := 0; i < 200; i++ {
+			if i%2 == 0 {
+				registry.Reload(registerV1)
+			} else {
+				registry.Reload(registerV2)
+			}
+		}
+		close(stop)
+		wg.Wait()
+	})
+}
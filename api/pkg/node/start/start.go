@@ -2,6 +2,7 @@ package start
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"workflow-code-test/api/pkg/models"
@@ -37,17 +38,34 @@ func (n *Node) GetBaseInfo() node.BaseNode {
 func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
 	started := time.Now()
 	
-	// Start nodes don't do much - they just start the workflow
+	// Start nodes record what triggered the run, so the first step of the trace shows it
 	outputs := node.NodeOutputs{
-		Data:      make(map[string]any),
-		Status:    models.StatusCompleted,
-		StartedAt: started.Format(time.RFC3339),
-		EndedAt:   time.Now().Format(time.RFC3339),
+		Data:        map[string]any{"input": inputSnapshot(inputs.WorkflowInput)},
+		Status:      models.StatusCompleted,
+		StartedAt:   started.Format(time.RFC3339),
+		EndedAt:     time.Now().Format(time.RFC3339),
+		Explanation: fmt.Sprintf("Run started for %s", inputs.WorkflowInput.City),
 	}
 	
 	return outputs, nil
 }
 
+// inputSnapshot returns the fields of the triggering input worth showing in the
+// trace. The email address is left out as personal data, and an embedded workflow
+// definition as it is stored with the workflow itself.
+func inputSnapshot(input models.WorkflowInput) map[string]any {
+	snapshot := map[string]any{
+		"name":      input.Name,
+		"city":      input.City,
+		"operator":  string(input.Operator),
+		"threshold": input.Threshold,
+	}
+	if input.Field != "" {
+		snapshot["field"] = input.Field
+	}
+	return snapshot
+}
+
 // Validate ensures the node is properly configured
 func (n *Node) Validate() error {
 	// Start nodes don't have any special configuration to validate
@@ -0,0 +1,251 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+)
+
+// Store keeps values between executions, scoped to a workflow
+type Store interface {
+	GetState(ctx context.Context, workflowID, key string) (any, bool, error)
+	SetState(ctx context.Context, workflowID, key string, value any) error
+}
+
+// Operations a state node can perform
+const (
+	OperationGet = "get" // read the value into the step output and, with As, share it
+	OperationSet = "set" // store Value, or the value named by ValueFrom
+)
+
+// Node implements a state node that reads or writes one key of its workflow's store
+type Node struct {
+	node.BaseNode
+	Operation string `json:"operation"`
+	// Key may contain {{placeholders}} filled from the workflow input (name, email,
+	// city) and values shared by earlier nodes, e.g. "lastAlert:{{city}}"
+	Key string `json:"key"`
+	// Value is stored as-is by a set; HasValue tells a null value from none
+	Value    any  `json:"value,omitempty"`
+	HasValue bool `json:"-"`
+	// ValueFrom names the value a set stores instead: "<nodeId>.<key>" reads a prior
+	// node's output, and a plain name reads a value shared by earlier nodes
+	ValueFrom string `json:"valueFrom,omitempty"`
+	// As shares the value a get found under this name for later nodes
+	As string `json:"as,omitempty"`
+
+	store Store
+}
+
+// NewNodeFactory returns a constructor for state nodes backed by store
+func NewNodeFactory(store Store) node.NodeFactory {
+	return func(model models.Node) (node.Node, error) {
+		return newNode(model, store)
+	}
+}
+
+func newNode(model models.Node, store Store) (node.Node, error) {
+	metadata, err := node.RequireMetadata(model)
+	if err != nil {
+		return nil, err
+	}
+
+	stateNode := &Node{
+		BaseNode: node.BaseNode{
+			ID:          model.ID,
+			Label:       model.Data.Label,
+			Description: model.Data.Description,
+		},
+		store: store,
+	}
+	stateNode.Operation, _ = metadata["operation"].(string)
+	stateNode.Key, _ = metadata["key"].(string)
+	stateNode.Value, stateNode.HasValue = metadata["value"]
+	stateNode.ValueFrom, _ = metadata["valueFrom"].(string)
+	stateNode.As, _ = metadata["as"].(string)
+
+	return stateNode, nil
+}
+
+// Type returns the node type
+func (n *Node) Type() models.NodeType {
+	return models.NodeTypeState
+}
+
+// GetBaseInfo returns the base node information
+func (n *Node) GetBaseInfo() node.BaseNode {
+	return n.BaseNode
+}
+
+// Execute reads or writes the node's key for the running workflow
+func (n *Node) Execute(ctx context.Context, inputs node.NodeInputs) (node.NodeOutputs, error) {
+	started := time.Now()
+	outputs := node.NodeOutputs{
+		Data:      make(map[string]any),
+		Status:    models.StatusRunning,
+		StartedAt: started.Format(time.RFC3339),
+	}
+	fail := func(err error) (node.NodeOutputs, error) {
+		outputs.Status = models.StatusFailed
+		outputs.Data["message"] = "Failed to access workflow state"
+		outputs.Data["error"] = node.Capitalize(err.Error())
+		outputs.EndedAt = time.Now().Format(time.RFC3339)
+		return outputs, err
+	}
+
+	if n.store == nil {
+		return fail(fmt.Errorf("state store not configured"))
+	}
+	if inputs.WorkflowID == "" {
+		return fail(fmt.Errorf("state requires a stored workflow"))
+	}
+
+	key, err := n.resolveKey(inputs)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Ephemeral runs, such as ad-hoc executions, neither see nor change stored state
+	ephemeral := node.Ephemeral(ctx)
+
+	switch n.Operation {
+	case OperationGet:
+		var value any
+		var found bool
+		if !ephemeral {
+			value, found, err = n.store.GetState(ctx, inputs.WorkflowID, key)
+			if err != nil {
+				return fail(err)
+			}
+		}
+		outputs.Data = map[string]any{
+			"message":   fmt.Sprintf("Read state %s", key),
+			"operation": n.Operation,
+			"key":       key,
+			"found":     found,
+			"value":     value,
+		}
+		if !found {
+			outputs.Data["message"] = fmt.Sprintf("No state stored for %s", key)
+		}
+		if found && n.As != "" {
+			outputs.Shared = map[string]any{n.As: value}
+		}
+	case OperationSet:
+		value, err := n.valueToStore(inputs)
+		if err != nil {
+			return fail(err)
+		}
+		if !ephemeral {
+			if err := n.store.SetState(ctx, inputs.WorkflowID, key, value); err != nil {
+				return fail(err)
+			}
+		}
+		outputs.Data = map[string]any{
+			"message":   fmt.Sprintf("Stored state %s", key),
+			"operation": n.Operation,
+			"key":       key,
+			"value":     value,
+		}
+		if ephemeral {
+			outputs.Data["message"] = fmt.Sprintf("State %s not stored: ad-hoc runs aren't persisted", key)
+		}
+	default:
+		return fail(fmt.Errorf("unsupported state operation: %s", n.Operation))
+	}
+
+	outputs.Status = models.StatusCompleted
+	outputs.EndedAt = time.Now().Format(time.RFC3339)
+	return outputs, nil
+}
+
+// Validate ensures the node is properly configured
+func (n *Node) Validate() error {
+	if n.Key == "" {
+		return fmt.Errorf("state node requires a key")
+	}
+
+	switch n.Operation {
+	case OperationGet:
+	case OperationSet:
+		if n.HasValue == (n.ValueFrom != "") {
+			return fmt.Errorf("state set requires exactly one of value or valueFrom")
+		}
+	default:
+		return fmt.Errorf("unsupported state operation: %q", n.Operation)
+	}
+
+	return nil
+}
+
+// keyPlaceholderPattern matches {{name}} placeholders in a key
+var keyPlaceholderPattern = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// resolveKey fills the key's placeholders, failing when one has no value so runs
+// never share a half-rendered key
+func (n *Node) resolveKey(inputs node.NodeInputs) (string, error) {
+	variables := make(map[string]any, len(inputs.NodeData)+3)
+	for name, value := range inputs.NodeData {
+		variables[name] = value
+	}
+	variables["name"] = inputs.WorkflowInput.Name
+	variables["email"] = inputs.WorkflowInput.Email
+	variables["city"] = inputs.WorkflowInput.City
+
+	var unresolved []string
+	key := keyPlaceholderPattern.ReplaceAllStringFunc(n.Key, func(placeholder string) string {
+		name := placeholder[2 : len(placeholder)-2]
+		value, ok := variables[name]
+		if !ok {
+			if !slices.Contains(unresolved, name) {
+				unresolved = append(unresolved, name)
+			}
+			return placeholder
+		}
+		return formatKeyValue(value)
+	})
+	if len(unresolved) > 0 {
+		return "", fmt.Errorf("unresolved key placeholders: %s", strings.Join(unresolved, ", "))
+	}
+	return key, nil
+}
+
+// formatKeyValue writes a value into a key as plain text, numbers in their shortest
+// exact form, e.g. 30 and 31.25 rather than a display rounding such as "31.2"
+func formatKeyValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// valueToStore returns the literal value or reads the one ValueFrom names
+func (n *Node) valueToStore(inputs node.NodeInputs) (any, error) {
+	if n.ValueFrom == "" {
+		return n.Value, nil
+	}
+	if prefix, key, found := strings.Cut(n.ValueFrom, "."); found {
+		if output, exists := inputs.PriorOutputs[prefix]; exists {
+			if value, ok := output.Data[key]; ok {
+				return value, nil
+			}
+			return nil, fmt.Errorf("missing value %s", n.ValueFrom)
+		}
+	}
+	if value, ok := inputs.Shared(n.ValueFrom); ok {
+		return value, nil
+	}
+	return nil, fmt.Errorf("missing value %s", n.ValueFrom)
+}
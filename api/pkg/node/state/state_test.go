@@ -0,0 +1,197 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"workflow-code-test/api/pkg/models"
+	"workflow-code-test/api/pkg/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore is an in-memory Store keyed by workflow ID and key
+type memoryStore struct {
+	values map[string]map[string]any
+	err    error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[string]map[string]any)}
+}
+
+func (s *memoryStore) GetState(_ context.Context, workflowID, key string) (any, bool, error) {
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	value, ok := s.values[workflowID][key]
+	return value, ok, nil
+}
+
+func (s *memoryStore) SetState(_ context.Context, workflowID, key string, value any) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.values[workflowID] == nil {
+		s.values[workflowID] = make(map[string]any)
+	}
+	s.values[workflowID][key] = value
+	return nil
+}
+
+func newStateNode(t *testing.T, store Store, metadata map[string]any) node.Node {
+	n, err := NewNodeFactory(store)(models.Node{ID: "state-1", Type: models.NodeTypeState, Data: models.NodeData{Metadata: metadata}})
+	assert.NoError(t, err)
+	assert.NoError(t, n.Validate())
+	return n
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+	inputs := node.NodeInputs{
+		WorkflowID:    "workflow-1",
+		WorkflowInput: models.WorkflowInput{City: "Sydney"},
+		PriorOutputs: map[string]node.NodeOutputs{
+			"email": {Data: map[string]any{"timestamp": "2024-05-01T12:00:00Z"}},
+		},
+		NodeData: map[string]any{"temperature": 31.5},
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		n := newStateNode(t, newMemoryStore(), map[string]any{"operation": "get", "key": "lastAlert:{{city}}", "as": "lastAlert"})
+		outputs, err := n.Execute(ctx, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+		assert.Equal(t, "lastAlert:Sydney", outputs.Data["key"])
+		assert.Equal(t, false, outputs.Data["found"])
+		assert.Nil(t, outputs.Data["value"])
+		assert.Empty(t, outputs.Shared, "nothing is shared for a missing key")
+	})
+
+	t.Run("read after write", func(t *testing.T) {
+		store := newMemoryStore()
+		set := newStateNode(t, store, map[string]any{"operation": "set", "key": "lastAlert:{{city}}", "valueFrom": "email.timestamp"})
+		outputs, err := set.Execute(ctx, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+		assert.Equal(t, "2024-05-01T12:00:00Z", outputs.Data["value"])
+
+		get := newStateNode(t, store, map[string]any{"operation": "get", "key": "lastAlert:{{city}}", "as": "lastAlert"})
+		outputs, err = get.Execute(ctx, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, true, outputs.Data["found"])
+		assert.Equal(t, "2024-05-01T12:00:00Z", outputs.Data["value"])
+		assert.Equal(t, map[string]any{"lastAlert": "2024-05-01T12:00:00Z"}, outputs.Shared)
+	})
+
+	t.Run("ephemeral runs leave the store untouched", func(t *testing.T) {
+		store := newMemoryStore()
+		set := newStateNode(t, store, map[string]any{"operation": "set", "key": "reading", "value": 12.0})
+		_, err := set.Execute(ctx, inputs)
+		assert.NoError(t, err)
+
+		ephemeral := node.WithEphemeral(ctx)
+		get := newStateNode(t, store, map[string]any{"operation": "get", "key": "reading", "as": "reading"})
+		outputs, err := get.Execute(ephemeral, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+		assert.Equal(t, false, outputs.Data["found"], "stored state isn't visible to ephemeral runs")
+		assert.Empty(t, outputs.Shared)
+
+		overwrite := newStateNode(t, store, map[string]any{"operation": "set", "key": "reading", "value": 30.0})
+		outputs, err = overwrite.Execute(ephemeral, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, outputs.Status)
+		assert.Contains(t, outputs.Data["message"], "not stored")
+
+		outputs, err = get.Execute(ctx, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.0, outputs.Data["value"])
+	})
+
+	t.Run("keys are scoped by workflow", func(t *testing.T) {
+		store := newMemoryStore()
+		set := newStateNode(t, store, map[string]any{"operation": "set", "key": "reading", "valueFrom": "temperature"})
+		_, err := set.Execute(ctx, inputs)
+		assert.NoError(t, err)
+
+		otherInputs := inputs
+		otherInputs.WorkflowID = "workflow-2"
+		get := newStateNode(t, store, map[string]any{"operation": "get", "key": "reading"})
+		outputs, err := get.Execute(ctx, otherInputs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, outputs.Data["found"])
+
+		outputs, err = get.Execute(ctx, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, 31.5, outputs.Data["value"])
+	})
+
+	t.Run("numbers in keys keep their exact value", func(t *testing.T) {
+		numberInputs := inputs
+		numberInputs.NodeData = map[string]any{"temperature": 31.25, "threshold": 30.0}
+		n := newStateNode(t, newMemoryStore(), map[string]any{"operation": "get", "key": "reading:{{temperature}}:{{threshold}}"})
+		outputs, err := n.Execute(ctx, numberInputs)
+		assert.NoError(t, err)
+		assert.Equal(t, "reading:31.25:30", outputs.Data["key"])
+	})
+
+	t.Run("literal value", func(t *testing.T) {
+		store := newMemoryStore()
+		set := newStateNode(t, store, map[string]any{"operation": "set", "key": "alerting", "value": true})
+		_, err := set.Execute(ctx, inputs)
+		assert.NoError(t, err)
+		assert.Equal(t, true, store.values["workflow-1"]["alerting"])
+	})
+
+	failures := []struct {
+		name     string
+		store    Store
+		metadata map[string]any
+		inputs   node.NodeInputs
+		err      string
+	}{
+		{"no store", nil, map[string]any{"operation": "get", "key": "k"}, inputs, "state store not configured"},
+		{"no workflow", newMemoryStore(), map[string]any{"operation": "get", "key": "k"}, node.NodeInputs{}, "state requires a stored workflow"},
+		{"unresolved key", newMemoryStore(), map[string]any{"operation": "get", "key": "k:{{station}}"}, inputs, "unresolved key placeholders: station"},
+		{"missing value", newMemoryStore(), map[string]any{"operation": "set", "key": "k", "valueFrom": "email.subject"}, inputs, "missing value email.subject"},
+		{"store error", &memoryStore{err: errors.New("database unavailable")}, map[string]any{"operation": "get", "key": "k"}, inputs, "database unavailable"},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newStateNode(t, tt.store, tt.metadata)
+			outputs, err := n.Execute(ctx, tt.inputs)
+			assert.ErrorContains(t, err, tt.err)
+			assert.Equal(t, models.StatusFailed, outputs.Status)
+			assert.Equal(t, "Failed to access workflow state", outputs.Data["message"])
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]any
+		err      string
+	}{
+		{"get", map[string]any{"operation": "get", "key": "k"}, ""},
+		{"set value", map[string]any{"operation": "set", "key": "k", "value": nil}, ""},
+		{"set valueFrom", map[string]any{"operation": "set", "key": "k", "valueFrom": "temperature"}, ""},
+		{"missing key", map[string]any{"operation": "get"}, "state node requires a key"},
+		{"unknown operation", map[string]any{"operation": "delete", "key": "k"}, `unsupported state operation: "delete"`},
+		{"set without value", map[string]any{"operation": "set", "key": "k"}, "exactly one of value or valueFrom"},
+		{"set with both", map[string]any{"operation": "set", "key": "k", "value": 1.0, "valueFrom": "temperature"}, "exactly one of value or valueFrom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewNodeFactory(newMemoryStore())(models.Node{ID: "state-1", Type: models.NodeTypeState, Data: models.NodeData{Metadata: tt.metadata}})
+			assert.NoError(t, err)
+			if tt.err == "" {
+				assert.NoError(t, n.Validate())
+				return
+			}
+			assert.ErrorContains(t, n.Validate(), tt.err)
+		})
+	}
+}
@@ -58,7 +58,7 @@ WITH node_inserts AS (
             '{
                 "hasHandles": {"source": true, "target": true},
                 "inputVariables": ["city"],
-                "apiEndpoint": "https://api.open-meteo.com/v1/forecast?latitude={lat}&longitude={lon}&current_weather=true",
+                "apiEndpoint": "https://api.open-meteo.com/v1/forecast?latitude={lat}&longitude={lon}&current_weather=true&current=relative_humidity_2m",
                 "options": [
                     {"city": "Sydney", "lat": -33.8688, "lon": 151.2093},
                     {"city": "Melbourne", "lat": -37.8136, "lon": 144.9631},
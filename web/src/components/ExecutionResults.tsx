@@ -44,6 +44,7 @@ export const ExecutionResultsComponent: React.FC<ExecutionResultsProps> = ({
       equals: '=',
       greater_than_or_equal: '≥',
       less_than_or_equal: '≤',
+      not_equals: '≠',
     };
     return symbols[operator as keyof typeof symbols] || '>';
   };
@@ -62,6 +63,7 @@ export const ExecutionResultsComponent: React.FC<ExecutionResultsProps> = ({
 
   const getOverallStatusColor = (status: string) => {
     if (status === 'completed') return 'green';
+    if (status === 'partial') return 'amber';
     if (status === 'cancelled') return 'orange';
     return 'red';
   };
@@ -70,6 +72,9 @@ export const ExecutionResultsComponent: React.FC<ExecutionResultsProps> = ({
     if (status === 'completed') {
       return <CheckIcon style={{ color: 'var(--green-9)' }} />;
     }
+    if (status === 'partial') {
+      return <CheckIcon style={{ color: 'var(--amber-9)' }} />;
+    }
     return <Cross2Icon style={{ color: 'var(--red-9)' }} />;
   };
 
@@ -33,6 +33,7 @@ const workflowFormSchema = z.object({
     'equals',
     'greater_than_or_equal',
     'less_than_or_equal',
+    'not_equals',
   ]),
   threshold: z
     .number()
@@ -75,6 +76,7 @@ export const UserInputForm: React.FC<UserInputFormProps> = ({ onExecute, isExecu
     equals: 'equals exactly',
     greater_than_or_equal: 'is at least',
     less_than_or_equal: 'is at most',
+    not_equals: 'is not',
   };
 
   const getOperatorSymbol = (operator: keyof typeof operatorLabels) => {
@@ -84,6 +86,7 @@ export const UserInputForm: React.FC<UserInputFormProps> = ({ onExecute, isExecu
       equals: '=',
       greater_than_or_equal: '≥',
       less_than_or_equal: '≤',
+      not_equals: '≠',
     } as const;
     return symbols[operator] || '>';
   };
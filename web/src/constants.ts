@@ -38,7 +38,7 @@ export const WORKFLOW_NODES: WorkflowNode[] = [
         hasHandles: { source: true, target: true },
         inputVariables: ['city'],
         apiEndpoint:
-          'https://api.open-meteo.com/v1/forecast?latitude={lat}&longitude={lon}&current_weather=true',
+          'https://api.open-meteo.com/v1/forecast?latitude={lat}&longitude={lon}&current_weather=true&current=relative_humidity_2m',
         options: [
           {
             city: 'Sydney',
@@ -7,7 +7,8 @@ export interface WorkflowFormData {
     | 'less_than'
     | 'equals'
     | 'greater_than_or_equal'
-    | 'less_than_or_equal';
+    | 'less_than_or_equal'
+    | 'not_equals';
   threshold: number;
 }
 
@@ -72,6 +73,7 @@ interface ExecutionStep {
       threshold: number;
     };
     formData?: WorkflowFormData;
+    outputSchemaVersion?: number;
   };
   timestamp: string;
   error?: string;
@@ -79,7 +81,7 @@ interface ExecutionStep {
 
 export interface ExecutionResults {
   id: string;
-  status: 'completed' | 'failed' | 'cancelled';
+  status: 'completed' | 'partial' | 'failed' | 'cancelled';
   startTime: string;
   endTime: string;
   totalDuration?: number;
@@ -89,4 +91,9 @@ export interface ExecutionResults {
     triggeredBy?: string;
     environment?: string;
   };
+  errorSummary?: {
+    nodeId?: string;
+    nodeType?: string;
+    message: string;
+  };
 }